@@ -3,7 +3,6 @@ package protocol
 import (
 	"bufio"
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -47,6 +46,7 @@ const (
 // Client represents the MangaHub CLI client
 type Client struct {
 	Token        string
+	RefreshToken string
 	Username     string
 	Email        string
 	UserID       string
@@ -200,8 +200,12 @@ func (c *Client) Login() {
 	password := c.readInput()
 
 	data := map[string]string{
-		"email":    email,
-		"password": password,
+		"email":       email,
+		"password":    password,
+		"device_name": "MangaHub CLI",
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		data["device_name"] = "MangaHub CLI on " + hostname
 	}
 
 	resp, err := c.makeRequest("POST", apiURL+"/auth/login", data, false)
@@ -216,8 +220,30 @@ func (c *Client) Login() {
 		return
 	}
 
+	// Accounts with two-factor authentication confirm the login with a code
+	if required, _ := result["two_factor_required"].(bool); required {
+		fmt.Print("Authenticator code (or recovery code): ")
+		code := c.readInput()
+
+		resp, err = c.makeRequest("POST", apiURL+"/auth/login/2fa", map[string]string{
+			"challenge_token": fmt.Sprint(result["challenge_token"]),
+			"code":            code,
+			"device_name":     data["device_name"],
+		}, false)
+		if err != nil {
+			fmt.Println(colorRed + "❌ Login failed: " + err.Error() + colorReset)
+			return
+		}
+		result = nil
+		if err := json.Unmarshal(resp, &result); err != nil {
+			fmt.Println(colorRed + "❌ Error parsing response" + colorReset)
+			return
+		}
+	}
+
 	if token, ok := result["token"].(string); ok {
 		c.Token = token
+		c.RefreshToken, _ = result["refresh_token"].(string)
 		if user, ok := result["user"].(map[string]interface{}); ok {
 			c.Username = user["username"].(string)
 			c.Email = user["email"].(string)
@@ -319,7 +345,7 @@ func (c *Client) BrowseManga() {
 
 		// Try gRPC first, fallback to REST API
 		if c.grpcEnabled && c.grpcClient != nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := c.grpcContext()
 			resp, err := c.grpcClient.SearchManga(ctx, "", int32(limit), int32(offset), "popular")
 			cancel()
 
@@ -479,7 +505,7 @@ func (c *Client) SearchManga() {
 
 		// Try gRPC first, fallback to REST API
 		if c.grpcEnabled && c.grpcClient != nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := c.grpcContext()
 			resp, err := c.grpcClient.SearchManga(ctx, query, int32(limit), int32(offset), "")
 			cancel()
 
@@ -626,7 +652,7 @@ func (c *Client) SearchManga() {
 func (c *Client) ViewMangaDetails(manga Manga) {
 	// Try to get fresh details via gRPC
 	if c.grpcEnabled && c.grpcClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := c.grpcContext()
 		resp, err := c.grpcClient.GetManga(ctx, manga.ID)
 		cancel()
 
@@ -697,7 +723,7 @@ func (c *Client) AddToLibrary(mangaID string) {
 
 	// Try gRPC first, fallback to REST API
 	if c.grpcEnabled && c.grpcClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := c.grpcContext()
 		resp, err := c.grpcClient.AddToLibrary(ctx, c.UserID, mangaID, status)
 		cancel()
 
@@ -735,7 +761,7 @@ func (c *Client) MyLibrary() {
 
 	// Try gRPC first, fallback to REST API
 	if c.grpcEnabled && c.grpcClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := c.grpcContext()
 		resp, err := c.grpcClient.GetLibrary(ctx, c.UserID)
 		cancel()
 
@@ -916,7 +942,7 @@ func (c *Client) ViewLibraryStats() {
 
 	// Try gRPC first, fallback to REST API
 	if c.grpcEnabled && c.grpcClient != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := c.grpcContext()
 		resp, err := c.grpcClient.GetLibraryStats(ctx, c.UserID)
 		cancel()
 
@@ -968,7 +994,13 @@ func (c *Client) DisplayManga(num int, manga Manga) {
 }
 
 func (c *Client) Logout() {
+	// Revoke the session on the server, so its tokens stop working
+	if _, err := c.makeRequest("POST", apiURL+"/auth/logout", nil, true); err != nil {
+		fmt.Println(colorYellow + "⚠️  Could not end the session on the server: " + err.Error() + colorReset)
+	}
+
 	c.Token = ""
+	c.RefreshToken = ""
 	c.Username = ""
 	c.Email = ""
 	c.UserID = ""
@@ -1013,18 +1045,49 @@ func (c *Client) readInput() string {
 }
 
 func (c *Client) makeRequest(method, url string, data interface{}, auth bool) ([]byte, error) {
+	respBody, status, err := c.sendRequest(method, url, data, auth)
+	// Access tokens are short-lived: renew it with the refresh token and retry once
+	if status == http.StatusUnauthorized && auth && c.RefreshToken != "" && c.refreshSession() == nil {
+		respBody, _, err = c.sendRequest(method, url, data, auth)
+	}
+	return respBody, err
+}
+
+// refreshSession exchanges the refresh token for new tokens. The refresh token
+// rotates, so the old one is replaced.
+func (c *Client) refreshSession() error {
+	data := map[string]string{"refresh_token": c.RefreshToken}
+	resp, _, err := c.sendRequest("POST", apiURL+"/auth/refresh", data, false)
+	if err != nil {
+		// The session has ended, the user has to log in again
+		c.RefreshToken = ""
+		return err
+	}
+
+	var result struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	c.Token, c.RefreshToken = result.Token, result.RefreshToken
+	return nil
+}
+
+func (c *Client) sendRequest(method, url string, data interface{}, auth bool) ([]byte, int, error) {
 	var body io.Reader
 	if data != nil {
 		jsonData, err := json.Marshal(data)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		body = bytes.NewBuffer(jsonData)
 	}
 
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -1035,24 +1098,24 @@ func (c *Client) makeRequest(method, url string, data interface{}, auth bool) ([
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if resp.StatusCode >= 400 {
 		var errResp map[string]interface{}
 		if err := json.Unmarshal(respBody, &errResp); err == nil {
 			if errMsg, ok := errResp["error"].(string); ok {
-				return nil, fmt.Errorf("%s", errMsg)
+				return nil, resp.StatusCode, fmt.Errorf("%s", errMsg)
 			}
 		}
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return nil, resp.StatusCode, fmt.Errorf("request failed with status %d", resp.StatusCode)
 	}
 
-	return respBody, nil
+	return respBody, resp.StatusCode, nil
 }
@@ -1,8 +1,10 @@
 package protocol
 
 import (
+	"context"
 	"fmt"
 	grpcClient "mangahub/internal/grpc"
+	"time"
 )
 
 // ConnectGRPC connects to the gRPC server
@@ -18,3 +20,9 @@ func (c *Client) ConnectGRPC() {
 	c.grpcEnabled = true
 	fmt.Println(colorGreen + "✅ Connected to gRPC server" + colorReset)
 }
+
+// grpcContext returns the context of a gRPC call, carrying the access token the
+// server checks for calls made on the user's behalf
+func (c *Client) grpcContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(grpcClient.WithToken(context.Background(), c.Token), 5*time.Second)
+}
@@ -53,6 +53,12 @@ func (c *Client) ConnectTCP() {
 	c.tcpEnabled = true
 	fmt.Println(colorGreen + "✅ Connected to real-time sync server" + colorReset)
 
+	// Authenticate, so the server attributes our updates to the logged-in user
+	if c.Token != "" {
+		conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		conn.Write([]byte("AUTH " + c.Token + "\n"))
+	}
+
 	// Start listening for updates in background
 	go c.listenTCPUpdates()
 
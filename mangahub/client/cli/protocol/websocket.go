@@ -3,6 +3,7 @@ package protocol
 import (
 	"bufio"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -43,8 +44,12 @@ func (c *Client) JoinChatHub(baseURL, mangaID, mangaTitle string) {
 	// Build WebSocket URL with room and token
 	wsURL := fmt.Sprintf("%s/api/v1/ws/chat?token=%s&room=%s", wsBase, c.Token, mangaID)
 
-	// Connect to WebSocket
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	// Connect to WebSocket, renewing an expired access token once
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusUnauthorized && c.RefreshToken != "" && c.refreshSession() == nil {
+		wsURL = fmt.Sprintf("%s/api/v1/ws/chat?token=%s&room=%s", wsBase, c.Token, mangaID)
+		conn, _, err = websocket.DefaultDialer.Dial(wsURL, nil)
+	}
 	if err != nil {
 		fmt.Printf("%s❌ Failed to connect to chat: %s%s\n", colorRed, err.Error(), colorReset)
 		return
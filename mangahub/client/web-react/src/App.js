@@ -14,6 +14,9 @@ import ChatHub from './pages/ChatHub';
 import GeneralChat from './pages/GeneralChat';
 import Profile from './pages/Profile';
 import SyncManga from './pages/SyncManga';
+import ForgotPassword from './pages/ForgotPassword';
+import ResetPassword from './pages/ResetPassword';
+import VerifyEmail from './pages/VerifyEmail';
 import authService from './services/authService';
 import websocketService from './services/websocketService';
 import './App.css';
@@ -26,7 +29,7 @@ const ProtectedRoute = ({ children }) => {
 // Layout wrapper to use useLocation
 const AppLayout = () => {
   const location = useLocation();
-  const isAuthPage = location.pathname === '/login' || location.pathname === '/register';
+  const isAuthPage = ['/login', '/register', '/forgot-password', '/reset-password', '/verify-email'].includes(location.pathname);
   const isReaderPage = location.pathname.startsWith('/read');
   const [notifications, setNotifications] = useState([]);
   const wsInitializedRef = useRef(false);
@@ -90,6 +93,9 @@ const AppLayout = () => {
           <Route path="/" element={<Home />} />
           <Route path="/login" element={<Login />} />
           <Route path="/register" element={<Register />} />
+          <Route path="/forgot-password" element={<ForgotPassword />} />
+          <Route path="/reset-password" element={<ResetPassword />} />
+          <Route path="/verify-email" element={<VerifyEmail />} />
           {/* Public routes - no login required */}
           <Route path="/browse" element={<Browse />} />
           <Route path="/manga/:id" element={<MangaDetail />} />
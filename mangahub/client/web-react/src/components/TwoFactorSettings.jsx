@@ -0,0 +1,157 @@
+import React, { useState, useEffect } from 'react';
+import { ShieldCheck, KeyRound } from 'lucide-react';
+import userService from '../services/userService';
+
+const inputClass = "w-full px-4 py-2 bg-zinc-50 dark:bg-zinc-900 border border-zinc-200 dark:border-zinc-700 rounded-lg focus:ring-2 focus:ring-primary/50 focus:border-primary dark:text-white transition-colors";
+const buttonClass = "bg-primary hover:bg-primary/90 text-white font-medium py-2 px-4 rounded-lg transition-colors disabled:opacity-50 disabled:cursor-not-allowed";
+const secondaryButtonClass = "border border-zinc-300 dark:border-zinc-700 text-zinc-700 dark:text-zinc-300 hover:bg-zinc-100 dark:hover:bg-zinc-800 font-medium py-2 px-4 rounded-lg transition-colors disabled:opacity-50 disabled:cursor-not-allowed";
+
+// Two-factor authentication card of the profile page: enrolls an authenticator
+// app and manages recovery codes. onChange receives whether 2FA is now on.
+const TwoFactorSettings = ({ onChange, onError, onSuccess }) => {
+  const [status, setStatus] = useState(null);
+  const [setup, setSetup] = useState(null);
+  const [recoveryCodes, setRecoveryCodes] = useState(null);
+  const [code, setCode] = useState('');
+  const [busy, setBusy] = useState(false);
+
+  useEffect(() => {
+    fetchStatus();
+  }, []);
+
+  const fetchStatus = async () => {
+    try {
+      const data = await userService.getTwoFactorStatus();
+      setStatus(data);
+      return data;
+    } catch (err) {
+      console.error('Error fetching two-factor status:', err);
+      return null;
+    }
+  };
+
+  const run = async (action, message) => {
+    onError(null);
+    setBusy(true);
+    try {
+      await action();
+      setCode('');
+      if (message) onSuccess(message);
+      const data = await fetchStatus();
+      if (data) onChange(data.enabled);
+    } catch (err) {
+      onError(err.error || 'Two-factor authentication request failed');
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleSetup = () => run(async () => {
+    setRecoveryCodes(null);
+    setSetup(await userService.setupTOTP());
+  });
+
+  const handleEnable = (e) => {
+    e.preventDefault();
+    run(async () => {
+      const data = await userService.enableTOTP(code);
+      setSetup(null);
+      setRecoveryCodes(data.recovery_codes);
+    }, 'Two-factor authentication enabled');
+  };
+
+  const handleDisable = () => run(async () => {
+    await userService.disableTOTP(code);
+    setRecoveryCodes(null);
+  }, 'Two-factor authentication disabled');
+
+  const handleRegenerate = () => run(async () => {
+    const data = await userService.regenerateRecoveryCodes(code);
+    setRecoveryCodes(data.recovery_codes);
+  }, 'New recovery codes generated');
+
+  if (!status) {
+    return null;
+  }
+
+  return (
+    <div className="bg-white dark:bg-[#191022] rounded-2xl border border-zinc-200 dark:border-zinc-800 p-6">
+      <h2 className="text-xl font-bold text-zinc-900 dark:text-white mb-2 flex items-center gap-2">
+        <ShieldCheck className="w-5 h-5" />
+        Two-Factor Authentication
+      </h2>
+      <p className="text-sm text-zinc-600 dark:text-zinc-400 mb-6">
+        {status.enabled
+          ? `Enabled. ${status.recovery_codes_remaining} recovery codes left.`
+          : 'Protect your account with a code from an authenticator app at every login.'}
+      </p>
+
+      {recoveryCodes && (
+        <div className="mb-6 rounded-lg bg-zinc-100 dark:bg-zinc-900/50 p-4">
+          <p className="text-sm font-medium text-zinc-900 dark:text-white mb-3 flex items-center gap-2">
+            <KeyRound className="w-4 h-4" />
+            Save these recovery codes. Each works once, and they are not shown again.
+          </p>
+          <div className="grid grid-cols-2 gap-2 font-mono text-sm text-zinc-700 dark:text-zinc-300">
+            {recoveryCodes.map((recoveryCode) => (
+              <span key={recoveryCode}>{recoveryCode}</span>
+            ))}
+          </div>
+        </div>
+      )}
+
+      {!status.enabled && !setup && (
+        <button type="button" onClick={handleSetup} disabled={busy} className={buttonClass}>
+          Set Up Authenticator App
+        </button>
+      )}
+
+      {!status.enabled && setup && (
+        <form onSubmit={handleEnable} className="space-y-4">
+          <p className="text-sm text-zinc-600 dark:text-zinc-400">
+            Add MangaHub to your authenticator app with the link below (or scan it as a QR code), or enter the key by hand. Then confirm with the code the app shows.
+          </p>
+          <a href={setup.provisioning_uri} className="block text-sm font-semibold text-primary hover:underline break-all">
+            {setup.provisioning_uri}
+          </a>
+          <p className="font-mono text-sm text-zinc-900 dark:text-white break-all">{setup.secret}</p>
+          <input
+            type="text"
+            autoComplete="one-time-code"
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            className={inputClass}
+            placeholder="6-digit code"
+            required
+          />
+          <button type="submit" disabled={busy || !code} className={buttonClass}>
+            Enable
+          </button>
+        </form>
+      )}
+
+      {status.enabled && (
+        <div className="space-y-4">
+          <input
+            type="text"
+            autoComplete="one-time-code"
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            className={inputClass}
+            placeholder="Authenticator or recovery code"
+          />
+          <div className="flex flex-wrap gap-3">
+            <button type="button" onClick={handleRegenerate} disabled={busy || !code} className={secondaryButtonClass}>
+              New Recovery Codes
+            </button>
+            <button type="button" onClick={handleDisable} disabled={busy || !code} className={secondaryButtonClass}>
+              Disable
+            </button>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default TwoFactorSettings;
@@ -27,7 +27,7 @@ const Browse = () => {
     const sortParam = searchParams.get('sort');
     
     // Set sort based on URL parameter, default to 'title' if not specified
-    const newSort = sortParam && ['relevant', 'title', 'newest'].includes(sortParam) ? sortParam : 'title';
+    const newSort = sortParam && ['relevant', 'title', 'newest', 'popular', 'trending', 'rating'].includes(sortParam) ? sortParam : 'title';
     
     // Update state synchronously
     setCurrentPage(pageParam);
@@ -142,7 +142,10 @@ const Browse = () => {
                 <div className="space-y-1">
                   {[
                     { value: 'title', label: 'Title (A-Z)' },
-                    { value: 'newest', label: 'Newest' }
+                    { value: 'newest', label: 'Newest' },
+                    { value: 'popular', label: 'Popular' },
+                    { value: 'trending', label: 'Trending' },
+                    { value: 'rating', label: 'Top Rated' }
                   ].map(({ value, label }) => (
                     <button
                       key={value}
@@ -0,0 +1,79 @@
+import React, { useState } from 'react';
+import { Link } from 'react-router-dom';
+import { AlertCircle, CheckCircle } from 'lucide-react';
+import authService from '../services/authService';
+
+const ForgotPassword = () => {
+  const [email, setEmail] = useState('');
+  const [error, setError] = useState('');
+  const [message, setMessage] = useState('');
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+    setLoading(true);
+
+    try {
+      const data = await authService.forgotPassword(email);
+      setMessage(data.message);
+    } catch (err) {
+      setError(err.error || err.message || 'Could not send the reset email');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center p-6 sm:p-12 bg-background-light dark:bg-background-dark">
+      <div className="flex flex-col w-full max-w-md">
+        <div className="flex min-w-72 flex-col gap-2 mb-8">
+          <p className="text-zinc-900 dark:text-white text-4xl font-black leading-tight tracking-[-0.033em]">Forgot Password?</p>
+          <p className="text-zinc-500 dark:text-[#ab9db9] text-base font-normal leading-normal">Enter the email of your account and we will send you a link to choose a new password.</p>
+        </div>
+
+        {error && (
+          <div className="mb-6 p-4 rounded-lg bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-900/50 flex items-start gap-3">
+            <AlertCircle className="w-5 h-5 text-red-600 dark:text-red-400 mt-0.5" />
+            <p className="text-sm text-red-600 dark:text-red-400 font-medium">{error}</p>
+          </div>
+        )}
+
+        {message ? (
+          <div className="mb-6 p-4 rounded-lg bg-green-50 dark:bg-green-900/20 border border-green-200 dark:border-green-900/50 flex items-start gap-3">
+            <CheckCircle className="w-5 h-5 text-green-600 dark:text-green-400 mt-0.5" />
+            <p className="text-sm text-green-600 dark:text-green-400 font-medium">{message}</p>
+          </div>
+        ) : (
+          <form onSubmit={handleSubmit} className="flex flex-col gap-4">
+            <label className="flex flex-col min-w-40 flex-1">
+              <p className="text-zinc-900 dark:text-white text-base font-medium leading-normal pb-2">Email</p>
+              <input
+                type="email"
+                required
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                className="form-input flex w-full min-w-0 flex-1 rounded-lg text-zinc-900 dark:text-white focus:outline-0 focus:ring-2 focus:ring-primary/50 border border-zinc-300 dark:border-[#473b54] bg-white dark:bg-[#211c27] focus:border-primary h-14 placeholder:text-zinc-400 dark:placeholder:text-[#ab9db9] p-[15px] text-base"
+                placeholder="Enter your email"
+              />
+            </label>
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="flex cursor-pointer items-center justify-center rounded-lg h-14 px-5 bg-primary text-white text-base font-bold hover:bg-primary/90 mt-4 disabled:opacity-70 disabled:cursor-not-allowed"
+            >
+              {loading ? 'Sending...' : 'Send Reset Link'}
+            </button>
+          </form>
+        )}
+
+        <div className="mt-8 text-center text-sm text-zinc-600 dark:text-zinc-400">
+          <Link className="font-semibold text-primary hover:underline" to="/login">Back to Log In</Link>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ForgotPassword;
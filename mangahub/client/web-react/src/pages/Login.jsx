@@ -11,6 +11,8 @@ const Login = () => {
   const [showPassword, setShowPassword] = useState(false);
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
+  const [challengeToken, setChallengeToken] = useState('');
+  const [otpCode, setOtpCode] = useState('');
   const navigate = useNavigate();
 
   const handleChange = (e) => {
@@ -27,12 +29,27 @@ const Login = () => {
     setLoading(true);
 
     try {
-      await authService.login({
+      if (challengeToken) {
+        await authService.loginTwoFactor(challengeToken, otpCode);
+        navigate('/library');
+        return;
+      }
+
+      const data = await authService.login({
         email: formData.email,
         password: formData.password
       });
+      if (data.two_factor_required) {
+        setChallengeToken(data.challenge_token);
+        return;
+      }
       navigate('/library');
     } catch (err) {
+      if (challengeToken && err.error?.startsWith('invalid token')) {
+        // The challenge expired, start over with the password
+        setChallengeToken('');
+        setOtpCode('');
+      }
       setError(err.error || err.message || 'Invalid email or password');
     } finally {
       setLoading(false);
@@ -81,6 +98,32 @@ const Login = () => {
                 </div>
               )}
 
+              {challengeToken ? (
+              <form onSubmit={handleSubmit} className="flex flex-col gap-4">
+                <label className="flex flex-col min-w-40 flex-1">
+                  <p className="text-zinc-900 dark:text-white text-base font-medium leading-normal pb-2">Authentication Code</p>
+                  <input
+                    name="otp_code"
+                    type="text"
+                    autoComplete="one-time-code"
+                    autoFocus
+                    value={otpCode}
+                    onChange={(e) => { setOtpCode(e.target.value); setError(''); }}
+                    className="form-input flex w-full min-w-0 flex-1 resize-none overflow-hidden rounded-lg text-zinc-900 dark:text-white focus:outline-0 focus:ring-2 focus:ring-primary/50 border border-zinc-300 dark:border-[#473b54] bg-white dark:bg-[#211c27] focus:border-primary dark:focus:border-primary h-14 placeholder:text-zinc-400 dark:placeholder:text-[#ab9db9] p-[15px] text-base font-normal leading-normal"
+                    placeholder="6-digit code or a recovery code"
+                  />
+                  <p className="text-zinc-500 dark:text-[#ab9db9] text-sm pt-2">Enter the code from your authenticator app, or one of your recovery codes.</p>
+                </label>
+
+                <button
+                  type="submit"
+                  disabled={loading || !otpCode}
+                  className="flex min-w-[84px] cursor-pointer items-center justify-center overflow-hidden rounded-lg h-14 px-5 flex-1 bg-primary text-white text-base font-bold leading-normal tracking-[0.015em] hover:bg-primary/90 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-primary/50 dark:focus:ring-offset-background-dark mt-4 disabled:opacity-70 disabled:cursor-not-allowed"
+                >
+                  <span className="truncate">{loading ? 'Verifying...' : 'Verify'}</span>
+                </button>
+              </form>
+              ) : (
               <form onSubmit={handleSubmit} className="flex flex-col gap-4">
                 <label className="flex flex-col min-w-40 flex-1">
                   <p className="text-zinc-900 dark:text-white text-base font-medium leading-normal pb-2">Username or Email</p>
@@ -97,7 +140,7 @@ const Login = () => {
                 <div className="flex flex-col min-w-40 flex-1">
                   <div className="flex items-center justify-between pb-2">
                     <label className="text-zinc-900 dark:text-white text-base font-medium leading-normal" htmlFor="password">Password</label>
-                    <a className="text-sm font-semibold text-primary hover:underline" href="#">Forgot Password?</a>
+                    <Link className="text-sm font-semibold text-primary hover:underline" to="/forgot-password">Forgot Password?</Link>
                   </div>
                   <div className="flex w-full flex-1 items-stretch rounded-lg group">
                     <input
@@ -126,6 +169,7 @@ const Login = () => {
                   <span className="truncate">{loading ? 'Logging In...' : 'Log In'}</span>
                 </button>
               </form>
+              )}
 
               <div className="mt-8 text-center text-sm text-zinc-600 dark:text-zinc-400">
                 Don't have an account?{' '}
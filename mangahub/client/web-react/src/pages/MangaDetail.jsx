@@ -577,11 +577,11 @@ const MangaDetail = () => {
                       <div className="text-6xl font-black text-zinc-900 dark:text-white mb-3">
                         {ratingStats.average_rating ? ratingStats.average_rating.toFixed(1) : '0.0'}
                       </div>
-                      <div className="flex gap-1 mb-2">
-                        {[...Array(5)].map((_, i) => (
+                      <div className="flex gap-0.5 mb-2">
+                        {[...Array(10)].map((_, i) => (
                           <Star
                             key={i}
-                            className={`w-5 h-5 ${
+                            className={`w-3.5 h-3.5 ${
                               i < Math.round(ratingStats.average_rating || 0)
                                 ? 'fill-yellow-400 text-yellow-400'
                                 : 'text-zinc-300 dark:text-zinc-700'
@@ -592,11 +592,23 @@ const MangaDetail = () => {
                       <div className="text-sm text-zinc-600 dark:text-zinc-400">
                         {ratingStats.total_ratings.toLocaleString()} rating{ratingStats.total_ratings !== 1 ? 's' : ''}
                       </div>
+                      {ratingStats.weighted_rating > 0 && (
+                        <div className="text-xs text-zinc-500 dark:text-zinc-500 mt-1" title="Average weighted by the number of ratings, used for sorting">
+                          Weighted score {ratingStats.weighted_rating.toFixed(2)}
+                        </div>
+                      )}
+                      {ratingStats.sub_scores && Object.keys(ratingStats.sub_scores).length > 0 && (
+                        <div className="text-xs text-zinc-500 dark:text-zinc-500 mt-1 capitalize">
+                          {Object.entries(ratingStats.sub_scores)
+                            .map(([name, score]) => `${name} ${score.toFixed(1)}`)
+                            .join(' · ')}
+                        </div>
+                      )}
                     </div>
 
                     {/* Right side - Rating Distribution */}
                     <div className="flex-1 w-full md:w-auto space-y-2 min-w-[250px]">
-                      {[5, 4, 3, 2, 1].map((star) => {
+                      {[10, 9, 8, 7, 6, 5, 4, 3, 2, 1].map((star) => {
                         const dist = ratingStats.rating_distribution || {};
                         const count = dist[star] || dist[star.toString()] || 0;
                         const percentage = ratingStats.total_ratings > 0 
@@ -605,7 +617,7 @@ const MangaDetail = () => {
                         
                         return (
                           <div key={star} className="flex items-center gap-3">
-                            <span className="text-sm font-bold text-zinc-700 dark:text-zinc-300 w-4">
+                            <span className="text-sm font-bold text-zinc-700 dark:text-zinc-300 w-5">
                               {star}
                             </span>
                             <div className="flex-1 bg-zinc-200 dark:bg-zinc-800 rounded-full h-3.5 overflow-hidden">
@@ -632,11 +644,11 @@ const MangaDetail = () => {
                   </h3>
                   {userRating && (
                     <p className="text-sm text-zinc-600 dark:text-zinc-400 text-center mb-3">
-                      You rated this manga {userRating}/5
+                      You rated this manga {userRating}/10
                     </p>
                   )}
-                  <div className="flex justify-center gap-2">
-                    {[...Array(5)].map((_, i) => {
+                  <div className="flex justify-center gap-1">
+                    {[...Array(10)].map((_, i) => {
                       const rating = i + 1;
                       const isHovered = hoverRating > 0 && rating <= hoverRating;
                       const isSelected = userRating && rating <= userRating;
@@ -650,10 +662,10 @@ const MangaDetail = () => {
                           onMouseLeave={() => setHoverRating(0)}
                           disabled={submittingRating}
                           className="transition-all duration-200 hover:scale-110 disabled:opacity-50"
-                          title={userRating === rating ? 'Click to remove rating' : `Rate ${rating}/5`}
+                          title={userRating === rating ? 'Click to remove rating' : `Rate ${rating}/10`}
                         >
                           <Star
-                            className={`w-10 h-10 ${
+                            className={`w-7 h-7 ${
                               shouldHighlight
                                 ? 'fill-yellow-400 text-yellow-400'
                                 : 'text-zinc-300 dark:text-zinc-700'
@@ -1,9 +1,10 @@
 import React, { useState, useEffect } from 'react';
 import { motion } from 'framer-motion';
-import { User, Mail, Lock, Save, AlertCircle, CheckCircle } from 'lucide-react';
+import { User, Mail, Lock, Save, AlertCircle, CheckCircle, ShieldCheck } from 'lucide-react';
 import authService from '../services/authService';
 import userService from '../services/userService';
 import LoadingSpinner from '../components/LoadingSpinner';
+import TwoFactorSettings from '../components/TwoFactorSettings';
 
 const Profile = () => {
   const [loading, setLoading] = useState(true);
@@ -15,11 +16,13 @@ const Profile = () => {
   // Profile update form
   const [username, setUsername] = useState('');
   const [email, setEmail] = useState('');
+  const [profileOtpCode, setProfileOtpCode] = useState('');
 
   // Password change form
   const [oldPassword, setOldPassword] = useState('');
   const [newPassword, setNewPassword] = useState('');
   const [confirmPassword, setConfirmPassword] = useState('');
+  const [passwordOtpCode, setPasswordOtpCode] = useState('');
 
   useEffect(() => {
     fetchProfile();
@@ -54,9 +57,10 @@ const Profile = () => {
     }
 
     try {
-      const response = await userService.updateProfile(username, email);
+      const response = await userService.updateProfile(username, email, profileOtpCode);
       setSuccess('Profile updated successfully!');
       setProfile(response.profile);
+      setProfileOtpCode('');
       setTimeout(() => setSuccess(null), 3000);
     } catch (err) {
       console.error('Error updating profile:', err);
@@ -84,11 +88,12 @@ const Profile = () => {
 
     setSaving(true);
     try {
-      await userService.changePassword(oldPassword, newPassword);
+      await userService.changePassword(oldPassword, newPassword, passwordOtpCode);
       setSuccess('Password changed successfully!');
       setOldPassword('');
       setNewPassword('');
       setConfirmPassword('');
+      setPasswordOtpCode('');
       setTimeout(() => setSuccess(null), 3000);
     } catch (err) {
       console.error('Error changing password:', err);
@@ -189,6 +194,27 @@ const Profile = () => {
                 </div>
               </div>
 
+              {/* Second factor, required when two-factor authentication is on */}
+              {profile?.two_factor_enabled && (
+                <div>
+                  <label className="block text-sm font-medium text-zinc-700 dark:text-zinc-300 mb-2">
+                    Authentication Code
+                  </label>
+                  <div className="relative">
+                    <ShieldCheck className="absolute left-3 top-1/2 -translate-y-1/2 w-5 h-5 text-zinc-400" />
+                    <input
+                      type="text"
+                      autoComplete="one-time-code"
+                      value={profileOtpCode}
+                      onChange={(e) => setProfileOtpCode(e.target.value)}
+                      className="w-full pl-10 pr-4 py-2 bg-zinc-50 dark:bg-zinc-900 border border-zinc-200 dark:border-zinc-700 rounded-lg focus:ring-2 focus:ring-primary/50 focus:border-primary dark:text-white transition-colors"
+                      placeholder="Authenticator or recovery code"
+                      required
+                    />
+                  </div>
+                </div>
+              )}
+
               {/* Submit Button */}
               <button
                 type="submit"
@@ -273,6 +299,27 @@ const Profile = () => {
                 </div>
               </div>
 
+              {/* Second factor, required when two-factor authentication is on */}
+              {profile?.two_factor_enabled && (
+                <div>
+                  <label className="block text-sm font-medium text-zinc-700 dark:text-zinc-300 mb-2">
+                    Authentication Code
+                  </label>
+                  <div className="relative">
+                    <ShieldCheck className="absolute left-3 top-1/2 -translate-y-1/2 w-5 h-5 text-zinc-400" />
+                    <input
+                      type="text"
+                      autoComplete="one-time-code"
+                      value={passwordOtpCode}
+                      onChange={(e) => setPasswordOtpCode(e.target.value)}
+                      className="w-full pl-10 pr-4 py-2 bg-zinc-50 dark:bg-zinc-900 border border-zinc-200 dark:border-zinc-700 rounded-lg focus:ring-2 focus:ring-primary/50 focus:border-primary dark:text-white transition-colors"
+                      placeholder="Authenticator or recovery code"
+                      required
+                    />
+                  </div>
+                </div>
+              )}
+
               {/* Submit Button */}
               <button
                 type="submit"
@@ -294,6 +341,13 @@ const Profile = () => {
             </form>
           </div>
 
+          {/* Two-Factor Authentication Card */}
+          <TwoFactorSettings
+            onChange={(enabled) => setProfile((current) => ({ ...current, two_factor_enabled: enabled }))}
+            onError={setError}
+            onSuccess={setSuccess}
+          />
+
           {/* Account Info */}
           {profile && (
             <div className="bg-zinc-100 dark:bg-zinc-900/50 rounded-lg p-4 text-sm">
@@ -0,0 +1,110 @@
+import React, { useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { AlertCircle, CheckCircle } from 'lucide-react';
+import authService from '../services/authService';
+
+const ResetPassword = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+  const [password, setPassword] = useState('');
+  const [confirmPassword, setConfirmPassword] = useState('');
+  const [error, setError] = useState('');
+  const [done, setDone] = useState(false);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError('');
+
+    if (password.length < 6) {
+      setError('Password must be at least 6 characters');
+      return;
+    }
+    if (password !== confirmPassword) {
+      setError('Passwords do not match');
+      return;
+    }
+
+    setLoading(true);
+    try {
+      await authService.resetPassword(token, password);
+      // Every session was signed out, including this browser's
+      authService.logout();
+      setDone(true);
+    } catch (err) {
+      setError(err.error || err.message || 'Could not reset the password');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const inputClass = "form-input flex w-full min-w-0 flex-1 rounded-lg text-zinc-900 dark:text-white focus:outline-0 focus:ring-2 focus:ring-primary/50 border border-zinc-300 dark:border-[#473b54] bg-white dark:bg-[#211c27] focus:border-primary h-14 placeholder:text-zinc-400 dark:placeholder:text-[#ab9db9] p-[15px] text-base";
+
+  return (
+    <div className="min-h-screen flex items-center justify-center p-6 sm:p-12 bg-background-light dark:bg-background-dark">
+      <div className="flex flex-col w-full max-w-md">
+        <div className="flex min-w-72 flex-col gap-2 mb-8">
+          <p className="text-zinc-900 dark:text-white text-4xl font-black leading-tight tracking-[-0.033em]">Choose a New Password</p>
+        </div>
+
+        {!token && (
+          <div className="mb-6 p-4 rounded-lg bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-900/50 flex items-start gap-3">
+            <AlertCircle className="w-5 h-5 text-red-600 dark:text-red-400 mt-0.5" />
+            <p className="text-sm text-red-600 dark:text-red-400 font-medium">This link is missing its token. Open the link from the email again.</p>
+          </div>
+        )}
+
+        {error && (
+          <div className="mb-6 p-4 rounded-lg bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-900/50 flex items-start gap-3">
+            <AlertCircle className="w-5 h-5 text-red-600 dark:text-red-400 mt-0.5" />
+            <p className="text-sm text-red-600 dark:text-red-400 font-medium">{error}</p>
+          </div>
+        )}
+
+        {done ? (
+          <div className="mb-6 p-4 rounded-lg bg-green-50 dark:bg-green-900/20 border border-green-200 dark:border-green-900/50 flex items-start gap-3">
+            <CheckCircle className="w-5 h-5 text-green-600 dark:text-green-400 mt-0.5" />
+            <p className="text-sm text-green-600 dark:text-green-400 font-medium">Your password was reset and all sessions were signed out. Log in with the new password.</p>
+          </div>
+        ) : token && (
+          <form onSubmit={handleSubmit} className="flex flex-col gap-4">
+            <label className="flex flex-col min-w-40 flex-1">
+              <p className="text-zinc-900 dark:text-white text-base font-medium leading-normal pb-2">New Password</p>
+              <input
+                type="password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className={inputClass}
+                placeholder="At least 6 characters"
+              />
+            </label>
+            <label className="flex flex-col min-w-40 flex-1">
+              <p className="text-zinc-900 dark:text-white text-base font-medium leading-normal pb-2">Confirm Password</p>
+              <input
+                type="password"
+                value={confirmPassword}
+                onChange={(e) => setConfirmPassword(e.target.value)}
+                className={inputClass}
+                placeholder="Repeat the new password"
+              />
+            </label>
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="flex cursor-pointer items-center justify-center rounded-lg h-14 px-5 bg-primary text-white text-base font-bold hover:bg-primary/90 mt-4 disabled:opacity-70 disabled:cursor-not-allowed"
+            >
+              {loading ? 'Saving...' : 'Reset Password'}
+            </button>
+          </form>
+        )}
+
+        <div className="mt-8 text-center text-sm text-zinc-600 dark:text-zinc-400">
+          <Link className="font-semibold text-primary hover:underline" to="/login">Back to Log In</Link>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ResetPassword;
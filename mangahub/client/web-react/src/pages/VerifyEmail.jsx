@@ -0,0 +1,62 @@
+import React, { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { AlertCircle, CheckCircle } from 'lucide-react';
+import authService from '../services/authService';
+
+const VerifyEmail = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+  const [status, setStatus] = useState(token ? 'verifying' : 'error');
+  const [message, setMessage] = useState(token ? '' : 'This link is missing its token. Open the link from the email again.');
+  const submittedRef = useRef(false);
+
+  useEffect(() => {
+    // Verify once, also under React strict mode's double effects
+    if (!token || submittedRef.current) return;
+    submittedRef.current = true;
+
+    authService.verifyEmail(token)
+      .then(() => {
+        setStatus('verified');
+        setMessage('Your email address is verified.');
+      })
+      .catch((err) => {
+        setStatus('error');
+        setMessage(err.error || err.message || 'Could not verify the email address');
+      });
+  }, [token]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center p-6 sm:p-12 bg-background-light dark:bg-background-dark">
+      <div className="flex flex-col w-full max-w-md">
+        <div className="flex min-w-72 flex-col gap-2 mb-8">
+          <p className="text-zinc-900 dark:text-white text-4xl font-black leading-tight tracking-[-0.033em]">Email Verification</p>
+        </div>
+
+        {status === 'verifying' && (
+          <p className="text-zinc-500 dark:text-[#ab9db9] text-base">Verifying your email address...</p>
+        )}
+
+        {status === 'verified' && (
+          <div className="mb-6 p-4 rounded-lg bg-green-50 dark:bg-green-900/20 border border-green-200 dark:border-green-900/50 flex items-start gap-3">
+            <CheckCircle className="w-5 h-5 text-green-600 dark:text-green-400 mt-0.5" />
+            <p className="text-sm text-green-600 dark:text-green-400 font-medium">{message}</p>
+          </div>
+        )}
+
+        {status === 'error' && (
+          <div className="mb-6 p-4 rounded-lg bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-900/50 flex items-start gap-3">
+            <AlertCircle className="w-5 h-5 text-red-600 dark:text-red-400 mt-0.5" />
+            <p className="text-sm text-red-600 dark:text-red-400 font-medium">{message}</p>
+          </div>
+        )}
+
+        <div className="mt-8 text-center text-sm text-zinc-600 dark:text-zinc-400">
+          <Link className="font-semibold text-primary hover:underline" to={authService.isAuthenticated() ? '/library' : '/login'}>Continue</Link>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default VerifyEmail;
@@ -9,12 +9,40 @@ const getBaseUrl = () => {
 
 const BASE_URL = getBaseUrl();
 
+// Refresh the access token this long before it expires
+const REFRESH_MARGIN_MS = 60 * 1000;
+
+let refreshTimer = null;
+let refreshPromise = null;
+
+const storeSession = (data) => {
+  localStorage.setItem('token', data.token);
+  if (data.refresh_token) {
+    localStorage.setItem('refresh_token', data.refresh_token);
+  }
+  scheduleRefresh(data.token);
+};
+
+const scheduleRefresh = (token) => {
+  clearTimeout(refreshTimer);
+  let delay = 0;
+  try {
+    const payload = JSON.parse(atob(token.split('.')[1]));
+    delay = Math.max(payload.exp * 1000 - Date.now() - REFRESH_MARGIN_MS, 0);
+  } catch (error) {
+    // Unreadable token, refresh right away
+  }
+  refreshTimer = setTimeout(() => {
+    authService.refresh().catch(() => {});
+  }, delay);
+};
+
 const authService = {
   register: async (userData) => {
     try {
       const response = await axios.post(`${BASE_URL}/register`, userData);
       if (response.data.token) {
-        localStorage.setItem('token', response.data.token);
+        storeSession(response.data);
         localStorage.setItem('user', JSON.stringify(response.data.user));
       }
       return response.data;
@@ -28,11 +56,12 @@ const authService = {
     try {
       const response = await axios.post(`${BASE_URL}/login`, {
         email: credentials.email,
-        password: credentials.password
+        password: credentials.password,
+        device_name: navigator.userAgent.substring(0, 100)
       });
       
       if (response.data.token) {
-        localStorage.setItem('token', response.data.token);
+        storeSession(response.data);
         localStorage.setItem('user', JSON.stringify(response.data.user));
       }
       
@@ -43,11 +72,96 @@ const authService = {
     }
   },
 
+  // Second login step of accounts with two-factor authentication
+  loginTwoFactor: async (challengeToken, code) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/login/2fa`, {
+        challenge_token: challengeToken,
+        code,
+        device_name: navigator.userAgent.substring(0, 100)
+      });
+
+      storeSession(response.data);
+      localStorage.setItem('user', JSON.stringify(response.data.user));
+
+      return response.data;
+    } catch (error) {
+      console.error('Error verifying two-factor code:', error);
+      throw error.response?.data || error;
+    }
+  },
+
   logout: () => {
+    const token = localStorage.getItem('token');
+    if (token && localStorage.getItem('refresh_token')) {
+      // Revoke the session server-side; the local state is cleared either way
+      axios.post(`${BASE_URL}/logout`, null, {
+        headers: { 'Authorization': `Bearer ${token}` }
+      }).catch(() => {});
+    }
+    clearTimeout(refreshTimer);
     localStorage.removeItem('token');
+    localStorage.removeItem('refresh_token');
     localStorage.removeItem('user');
   },
 
+  // Exchanges the refresh token for a new access token. Concurrent callers share one request.
+  refresh: () => {
+    if (!refreshPromise) {
+      refreshPromise = axios.post(`${BASE_URL}/refresh`, {
+        refresh_token: localStorage.getItem('refresh_token')
+      })
+        .then((response) => {
+          storeSession(response.data);
+          return response.data.token;
+        })
+        .catch((error) => {
+          if (error.response?.status === 401) {
+            console.warn('Session ended, logging out...');
+            authService.logout();
+          }
+          throw error.response?.data || error;
+        })
+        .finally(() => {
+          refreshPromise = null;
+        });
+    }
+    return refreshPromise;
+  },
+
+  verifyEmail: async (token) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/verify-email`, { token });
+      return response.data;
+    } catch (error) {
+      console.error('Error verifying email:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  forgotPassword: async (email) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/password/forgot`, { email });
+      return response.data;
+    } catch (error) {
+      console.error('Error requesting password reset:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  resetPassword: async (token, newPassword) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/password/reset`, {
+        token,
+        new_password: newPassword
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error resetting password:', error);
+      throw error.response?.data || error;
+    }
+  },
+
   isTokenExpired: (token) => {
     if (!token) return true;
     
@@ -62,6 +176,9 @@ const authService = {
   },
 
   isAuthenticated: () => {
+    // The access token is renewed as long as the session lasts
+    if (localStorage.getItem('refresh_token')) return true;
+
     const token = localStorage.getItem('token');
     if (!token) return false;
     
@@ -80,6 +197,10 @@ const authService = {
     
     // Validate token before returning
     if (token && authService.isTokenExpired(token)) {
+      if (localStorage.getItem('refresh_token')) {
+        authService.refresh().catch(() => {});
+        return null;
+      }
       console.warn('Token expired, clearing authentication...');
       authService.logout();
       return null;
@@ -94,4 +215,22 @@ const authService = {
   }
 };
 
+// Retry requests rejected with 401 once with a refreshed access token
+axios.interceptors.response.use(null, async (error) => {
+  const request = error.config;
+  if (error.response?.status !== 401 || !request || request._retried ||
+      !request.headers?.Authorization || !localStorage.getItem('refresh_token')) {
+    throw error;
+  }
+  request._retried = true;
+  const token = await authService.refresh();
+  request.headers.Authorization = `Bearer ${token}`;
+  return axios(request);
+});
+
+// Resume refreshing a session left from an earlier visit
+if (localStorage.getItem('refresh_token')) {
+  scheduleRefresh(localStorage.getItem('token') || '');
+}
+
 export default authService;
@@ -116,11 +116,12 @@ const userService = {
     }
   },
 
-  updateProfile: async (username, email) => {
+  updateProfile: async (username, email, otpCode) => {
     try {
       const response = await axios.put(`${BASE_URL}/profile`, {
         username,
-        email
+        email,
+        otp_code: otpCode
       }, {
         headers: getAuthHeaders()
       });
@@ -131,11 +132,12 @@ const userService = {
     }
   },
 
-  changePassword: async (oldPassword, newPassword) => {
+  changePassword: async (oldPassword, newPassword, otpCode) => {
     try {
       const response = await axios.put(`${BASE_URL}/password`, {
         old_password: oldPassword,
-        new_password: newPassword
+        new_password: newPassword,
+        otp_code: otpCode
       }, {
         headers: getAuthHeaders()
       });
@@ -144,6 +146,67 @@ const userService = {
       console.error('Error changing password:', error);
       throw error.response?.data || error;
     }
+  },
+
+  getTwoFactorStatus: async () => {
+    try {
+      const response = await axios.get(`${BASE_URL}/2fa`, {
+        headers: getAuthHeaders()
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error fetching two-factor status:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  // Starts enrolling an authenticator app, returns the secret and provisioning URI
+  setupTOTP: async () => {
+    try {
+      const response = await axios.post(`${BASE_URL}/2fa/totp`, null, {
+        headers: getAuthHeaders()
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error setting up two-factor authentication:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  enableTOTP: async (code) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/2fa/totp/enable`, { code }, {
+        headers: getAuthHeaders()
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error enabling two-factor authentication:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  disableTOTP: async (code) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/2fa/totp/disable`, { code }, {
+        headers: getAuthHeaders()
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error disabling two-factor authentication:', error);
+      throw error.response?.data || error;
+    }
+  },
+
+  regenerateRecoveryCodes: async (code) => {
+    try {
+      const response = await axios.post(`${BASE_URL}/2fa/recovery-codes`, { code }, {
+        headers: getAuthHeaders()
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Error regenerating recovery codes:', error);
+      throw error.response?.data || error;
+    }
   }
 };
 
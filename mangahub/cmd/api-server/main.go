@@ -3,6 +3,9 @@ package main
 import (
 	"log"
 	api "mangahub/internal/api"
+	"mangahub/internal/auth"
+	"mangahub/internal/fakeupstream"
+	"mangahub/internal/user"
 	"mangahub/pkg/database"
 	"os"
 
@@ -22,12 +25,30 @@ func main() {
 		log.Println("Loaded environment variables from .env file")
 	}
 
+	// Point external API clients at the fake upstream server when OFFLINE is set
+	stopFakeUpstream, err := fakeupstream.ConfigureFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure offline mode: %v", err)
+	}
+	defer stopFakeUpstream()
+
 	// Initialize database
 	if err := database.InitDatabase(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer database.Close()
 
+	// Check the JWT signing configuration and create the first signing key
+	if err := auth.InitSigningKeys(); err != nil {
+		log.Fatalf("Failed to initialize JWT signing keys: %v", err)
+	}
+
+	// Make the verified account with the email in BOOTSTRAP_ADMIN the first
+	// admin; ignored once an admin exists
+	if err := user.NewService().BootstrapAdmin(os.Getenv("BOOTSTRAP_ADMIN")); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	// Print current working directory for debugging
 	if cwd, err := os.Getwd(); err == nil {
 		log.Printf("Current working directory: %s", cwd)
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"mangahub/internal/fakeupstream"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		if err := godotenv.Load("../../.env"); err != nil {
+			log.Println("Warning: .env file not found, using environment variables or defaults")
+		}
+	}
+
+	port := os.Getenv("FAKE_UPSTREAM_PORT")
+	if port == "" {
+		port = "9030"
+	}
+	if port[0] != ':' {
+		port = ":" + port
+	}
+
+	// Replay unless OFFLINE=record is set
+	mode, ok := fakeupstream.ModeFromEnv()
+	if !ok {
+		mode = fakeupstream.ModeReplay
+	}
+
+	fixtureDir := fakeupstream.FixtureDir()
+
+	store, err := fakeupstream.NewStore(fixtureDir)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	log.Printf("Fake upstream server starting on port %s (%s mode)", port, mode)
+	log.Printf("  - Fixtures: %v (recorded fixtures in %s)", store.Count(), fixtureDir)
+	for _, svc := range fakeupstream.Services {
+		log.Printf("  - %s=http://localhost%s/%s", svc.EnvVar, port, svc.Name)
+	}
+
+	if err := http.ListenAndServe(port, fakeupstream.NewServer(store, mode)); err != nil {
+		log.Fatalf("Fake upstream server stopped with error: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"mangahub/internal/external"
+	"mangahub/internal/fakeupstream"
 	"mangahub/internal/manga"
 	"mangahub/pkg/database"
 	"mangahub/pkg/models"
@@ -677,6 +678,13 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	// Point external API clients at the fake upstream server when OFFLINE is set
+	stopFakeUpstream, err := fakeupstream.ConfigureFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure offline mode: %v", err)
+	}
+	defer stopFakeUpstream()
+
 	// Initialize database
 	if err := database.InitDatabase(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"mangahub/internal/auth"
+	"mangahub/internal/fakeupstream"
 	"mangahub/internal/grpc"
 	"mangahub/internal/manga"
 	"mangahub/internal/user"
@@ -25,12 +27,24 @@ func main() {
 		log.Println("Loaded environment variables from .env file")
 	}
 
+	// Point external API clients at the fake upstream server when OFFLINE is set
+	stopFakeUpstream, err := fakeupstream.ConfigureFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure offline mode: %v", err)
+	}
+	defer stopFakeUpstream()
+
 	// Initialize database
 	if err := database.InitDatabase(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer database.Close()
 
+	// Check the JWT signing configuration and create the first signing key
+	if err := auth.InitSigningKeys(); err != nil {
+		log.Fatalf("Failed to initialize JWT signing keys: %v", err)
+	}
+
 	// Get gRPC server port from environment or use default
 	grpcPort := os.Getenv("GRPC_SERVER_PORT")
 	if grpcPort == "" {
@@ -47,9 +61,10 @@ func main() {
 	mangaService := manga.NewService()
 	userService := user.NewService()
 	ratingService := manga.NewRatingService()
+	reviewService := manga.NewReviewService()
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer(mangaService, userService, ratingService)
+	grpcServer := grpc.NewServer(mangaService, userService, ratingService, reviewService)
 
 	// Connect to TCP server for broadcasting progress updates
 	if err := grpcServer.ConnectToTCP(tcpAddress); err != nil {
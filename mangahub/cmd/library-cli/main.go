@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"mangahub/internal/user"
+	"mangahub/pkg/database"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `MangaHub library import/export
+
+Usage:
+  library-cli import -user <username> -file <export> [-format mal|anilist] [-overwrite]
+  library-cli export -user <username> -format mal|anilist [-out <file>]
+  library-cli review -user <username>
+
+Formats:
+  mal      MyAnimeList XML export (.xml or .xml.gz)
+  anilist  AniList MediaListCollection JSON
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		godotenv.Load("../../.env")
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	username := flags.String("user", "", "MangaHub username")
+	format := flags.String("format", "", "File format: mal or anilist (import detects it when empty)")
+	file := flags.String("file", "", "Export file to import")
+	out := flags.String("out", "", "Output file for export (default stdout)")
+	overwrite := flags.Bool("overwrite", false, "Overwrite existing library entries on import")
+	flags.Parse(os.Args[2:])
+
+	if command != "import" && command != "export" && command != "review" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if *username == "" {
+		log.Fatal("-user is required")
+	}
+
+	if err := database.InitDatabase(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	userService := user.NewService()
+	profile, err := userService.GetProfileByUsername(*username)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *username, err)
+	}
+
+	switch command {
+	case "import":
+		if *file == "" {
+			log.Fatal("-file is required")
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *file, err)
+		}
+
+		result, err := userService.ImportLibrary(profile.ID, *format, data, *overwrite)
+		if err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+
+		fmt.Printf("Imported %s list for %s\n", result.Format, profile.Username)
+		fmt.Printf("  Total:     %d\n", result.Total)
+		fmt.Printf("  Imported:  %d\n", result.Imported)
+		fmt.Printf("  Updated:   %d\n", result.Updated)
+		fmt.Printf("  Skipped:   %d\n", result.Skipped)
+		fmt.Printf("  Ratings:   %d\n", result.Ratings)
+		fmt.Printf("  Unmatched: %d (see: library-cli review -user %s)\n", result.Unmatched, profile.Username)
+		fmt.Printf("  Failed:    %d\n", result.Failed)
+		for _, e := range result.Errors {
+			fmt.Printf("    - %s\n", e)
+		}
+
+	case "export":
+		if *format == "" {
+			log.Fatal("-format is required")
+		}
+		data, err := userService.ExportLibrary(profile.ID, *format)
+		if err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+
+		if *out == "" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(*out, data, 0644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *out, err)
+		}
+		log.Printf("Exported %s library of %s to %s", *format, profile.Username, *out)
+
+	case "review":
+		items, err := userService.GetImportReviewList(profile.ID)
+		if err != nil {
+			log.Fatalf("Failed to get review list: %v", err)
+		}
+		if len(items) == 0 {
+			fmt.Println("No unmatched entries")
+			return
+		}
+		for _, item := range items {
+			fmt.Printf("#%d  [%s %s] %s - %s, chapter %d, score %d\n",
+				item.ID, item.Source, item.SourceID, item.Title, item.Status, item.CurrentChapter, item.Score)
+		}
+	}
+}
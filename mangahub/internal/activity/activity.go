@@ -0,0 +1,85 @@
+package activity
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Event types of the reading_events log
+const (
+	EventAdded         = "added"    // Manga added to the library
+	EventProgress      = "progress" // Chapter changed, status unchanged
+	EventStatus        = "status"   // Status changed (the chapter may have changed too)
+	EventRemoved       = "removed"  // Manga removed from the library
+	EventRating        = "rating"
+	EventRatingRemoved = "rating_removed"
+)
+
+// Event sources. Only user events count as reading in the stats; imports and
+// MAL pulls replay reading that happened elsewhere, often in bulk.
+const (
+	SourceUser    = "user"
+	SourceImport  = "import"
+	SourceMALSync = "mal_sync"
+)
+
+// Execer is implemented by *sql.DB and *sql.Tx, so events can be written in the
+// transaction of the change they describe
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// RecordProgress logs a library change. fromStatus is empty for a new library
+// entry. Nothing is logged when neither the chapter nor the status changed.
+func RecordProgress(db Execer, source, userID, mangaID, fromStatus string, fromChapter int, toStatus string, toChapter int) error {
+	eventType := EventProgress
+	switch {
+	case fromStatus == "":
+		eventType = EventAdded
+	case fromStatus != toStatus:
+		eventType = EventStatus
+	case fromChapter == toChapter:
+		return nil
+	}
+
+	var chapterFrom, statusFrom interface{}
+	if fromStatus != "" {
+		chapterFrom, statusFrom = fromChapter, fromStatus
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO reading_events (user_id, manga_id, event_type, source, chapter_from, chapter_to, status_from, status_to, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		userID, mangaID, eventType, source, chapterFrom, toChapter, statusFrom, toStatus, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record reading event: %w", err)
+	}
+	return nil
+}
+
+// RecordRemoved logs the removal of a manga from the library
+func RecordRemoved(db Execer, userID, mangaID string) error {
+	return record(db, userID, mangaID, EventRemoved, SourceUser, nil)
+}
+
+// RecordRating logs a new or changed rating
+func RecordRating(db Execer, source, userID, mangaID string, rating int) error {
+	return record(db, userID, mangaID, EventRating, source, rating)
+}
+
+// RecordRatingRemoved logs the deletion of a rating
+func RecordRatingRemoved(db Execer, userID, mangaID string) error {
+	return record(db, userID, mangaID, EventRatingRemoved, SourceUser, nil)
+}
+
+func record(db Execer, userID, mangaID, eventType, source string, rating interface{}) error {
+	_, err := db.Exec(`
+		INSERT INTO reading_events (user_id, manga_id, event_type, source, rating, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		userID, mangaID, eventType, source, rating, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record reading event: %w", err)
+	}
+	return nil
+}
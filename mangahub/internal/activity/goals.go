@@ -0,0 +1,270 @@
+package activity
+
+import (
+	"database/sql"
+	"fmt"
+	"mangahub/pkg/models"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Goal types
+const (
+	GoalChapters  = "chapters"  // Chapters read in the window
+	GoalCompleted = "completed" // Series completed in the window
+	GoalGenre     = "genre"     // Series of one genre completed in the window
+)
+
+// Goal statuses, derived from the window and the achievement date
+const (
+	GoalUpcoming = "upcoming"
+	GoalActive   = "active"
+	GoalAchieved = "achieved"
+	GoalExpired  = "expired"
+)
+
+// maxGoals limits the number of goals per user
+const maxGoals = 50
+
+// GetGoals returns the user's reading goals with their current progress
+func (s *Service) GetGoals(userID string) ([]models.ReadingGoal, error) {
+	goals, _, err := s.evaluateGoals(userID)
+	return goals, err
+}
+
+// CreateGoal adds a reading goal for the user
+func (s *Service) CreateGoal(userID string, req models.CreateReadingGoalRequest) (*models.ReadingGoal, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM reading_goals WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count reading goals: %w", err)
+	}
+	if count >= maxGoals {
+		return nil, fmt.Errorf("invalid goal: at most %d goals are allowed", maxGoals)
+	}
+
+	goal := models.ReadingGoal{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Title:  strings.TrimSpace(req.Title),
+		Type:   req.Type,
+		Genre:  strings.TrimSpace(req.Genre),
+		Target: req.Target,
+	}
+	if goal.Type == GoalGenre && goal.Genre == "" {
+		return nil, fmt.Errorf("invalid goal: genre is required for genre goals")
+	}
+	if goal.Type != GoalGenre {
+		goal.Genre = ""
+	}
+
+	year := time.Now().UTC().Year()
+	goal.StartsOn = fmt.Sprintf("%d-01-01", year)
+	goal.EndsOn = fmt.Sprintf("%d-12-31", year)
+	if req.StartsOn != "" {
+		if _, err := time.Parse(dateFormat, req.StartsOn); err != nil {
+			return nil, fmt.Errorf("invalid starts_on: expected YYYY-MM-DD, got %q", req.StartsOn)
+		}
+		goal.StartsOn = req.StartsOn
+	}
+	if req.EndsOn != "" {
+		if _, err := time.Parse(dateFormat, req.EndsOn); err != nil {
+			return nil, fmt.Errorf("invalid ends_on: expected YYYY-MM-DD, got %q", req.EndsOn)
+		}
+		goal.EndsOn = req.EndsOn
+	}
+	if goal.EndsOn < goal.StartsOn {
+		return nil, fmt.Errorf("invalid goal: ends_on is before starts_on")
+	}
+	if goal.Title == "" {
+		goal.Title = defaultGoalTitle(goal)
+	}
+
+	goal.CreatedAt = time.Now()
+	_, err := s.db.Exec(`
+		INSERT INTO reading_goals (id, user_id, title, goal_type, genre, target, starts_on, ends_on, created_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?)`,
+		goal.ID, userID, goal.Title, goal.Type, goal.Genre, goal.Target, goal.StartsOn, goal.EndsOn, goal.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reading goal: %w", err)
+	}
+
+	// Reading already done in the window counts, so a new goal may be met at once
+	if _, err := s.evaluateGoal(&goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// DeleteGoal removes one of the user's reading goals
+func (s *Service) DeleteGoal(userID, goalID string) error {
+	result, err := s.db.Exec("DELETE FROM reading_goals WHERE id = ? AND user_id = ?", goalID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reading goal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("goal not found")
+	}
+
+	return nil
+}
+
+// CheckGoals evaluates the user's open goals and returns the ones achieved by
+// this call, so each achievement is reported exactly once
+func (s *Service) CheckGoals(userID string) ([]models.ReadingGoal, error) {
+	var open int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM reading_goals
+		WHERE user_id = ? AND achieved_at IS NULL AND starts_on <= ?`,
+		userID, time.Now().UTC().Format(dateFormat)).Scan(&open)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open reading goals: %w", err)
+	}
+	if open == 0 {
+		return nil, nil
+	}
+
+	_, achieved, err := s.evaluateGoals(userID)
+	return achieved, err
+}
+
+// evaluateGoals loads the user's goals with their progress. It also returns
+// the goals whose achievement was recorded by this call.
+func (s *Service) evaluateGoals(userID string) ([]models.ReadingGoal, []models.ReadingGoal, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, title, goal_type, COALESCE(genre, ''), target, starts_on, ends_on, achieved_at, created_at
+		FROM reading_goals
+		WHERE user_id = ?
+		ORDER BY ends_on, created_at`, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get reading goals: %w", err)
+	}
+
+	goals := []models.ReadingGoal{}
+	for rows.Next() {
+		var goal models.ReadingGoal
+		var achievedAt sql.NullTime
+		if err := rows.Scan(&goal.ID, &goal.UserID, &goal.Title, &goal.Type, &goal.Genre, &goal.Target,
+			&goal.StartsOn, &goal.EndsOn, &achievedAt, &goal.CreatedAt); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to scan reading goal: %w", err)
+		}
+		if achievedAt.Valid {
+			goal.AchievedAt = &achievedAt.Time
+		}
+		goals = append(goals, goal)
+	}
+	rows.Close()
+
+	achieved := []models.ReadingGoal{}
+	for i := range goals {
+		reached, err := s.evaluateGoal(&goals[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if reached {
+			achieved = append(achieved, goals[i])
+		}
+	}
+
+	return goals, achieved, nil
+}
+
+// evaluateGoal fills in the progress and status of a goal, recording the
+// achievement date the first time the target is reached. It reports whether
+// this call recorded the achievement.
+func (s *Service) evaluateGoal(goal *models.ReadingGoal) (bool, error) {
+	progress, err := s.goalProgress(goal)
+	if err != nil {
+		return false, err
+	}
+	goal.Progress = progress
+	goal.Percent = math.Min(100, math.Round(float64(progress)*1000/float64(goal.Target))/10)
+
+	today := time.Now().UTC().Format(dateFormat)
+	reached := false
+	if goal.AchievedAt == nil && progress >= goal.Target && goal.StartsOn <= today {
+		// The condition on achieved_at keeps concurrent checks from both reporting it
+		now := time.Now().UTC()
+		result, err := s.db.Exec("UPDATE reading_goals SET achieved_at = ? WHERE id = ? AND achieved_at IS NULL",
+			now, goal.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to record goal achievement: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			goal.AchievedAt = &now
+			reached = true
+		}
+	}
+
+	switch {
+	case goal.AchievedAt != nil:
+		goal.Status = GoalAchieved
+	case today < goal.StartsOn:
+		goal.Status = GoalUpcoming
+	case today > goal.EndsOn:
+		goal.Status = GoalExpired
+	default:
+		goal.Status = GoalActive
+	}
+	return reached, nil
+}
+
+// goalProgress counts the user's reading in the goal window. Like the reading
+// stats, only changes made by the user count; imports and MAL pulls do not.
+func (s *Service) goalProgress(goal *models.ReadingGoal) (int, error) {
+	var query string
+	args := []interface{}{goal.UserID, SourceUser, goal.StartsOn, goal.EndsOn}
+
+	switch goal.Type {
+	case GoalChapters:
+		query = `
+			SELECT COALESCE(SUM(e.chapter_to - COALESCE(e.chapter_from, 0)), 0)
+			FROM reading_events e
+			WHERE e.user_id = ? AND e.source = ? AND date(e.created_at) BETWEEN ? AND ?
+				AND e.chapter_to > COALESCE(e.chapter_from, 0)`
+	case GoalCompleted:
+		query = `
+			SELECT COUNT(DISTINCT e.manga_id)
+			FROM reading_events e
+			WHERE e.user_id = ? AND e.source = ? AND date(e.created_at) BETWEEN ? AND ?
+				AND e.status_to = 'completed'`
+	case GoalGenre:
+		query = `
+			SELECT COUNT(DISTINCT e.manga_id)
+			FROM reading_events e
+			WHERE e.user_id = ? AND e.source = ? AND date(e.created_at) BETWEEN ? AND ?
+				AND e.status_to = 'completed'
+				AND EXISTS (
+					SELECT 1 FROM manga m, json_each(COALESCE(m.genres, '[]')) g
+					WHERE m.id = e.manga_id AND LOWER(g.value) = LOWER(?)
+				)`
+		args = append(args, goal.Genre)
+	default:
+		return 0, fmt.Errorf("invalid goal type %q", goal.Type)
+	}
+
+	var progress int
+	if err := s.db.QueryRow(query, args...).Scan(&progress); err != nil {
+		return 0, fmt.Errorf("failed to evaluate reading goal: %w", err)
+	}
+	return progress, nil
+}
+
+// defaultGoalTitle describes a goal that was created without a title
+func defaultGoalTitle(goal models.ReadingGoal) string {
+	switch goal.Type {
+	case GoalChapters:
+		return fmt.Sprintf("Read %d chapters", goal.Target)
+	case GoalGenre:
+		return fmt.Sprintf("Complete %d %s series", goal.Target, goal.Genre)
+	}
+	return fmt.Sprintf("Complete %d series", goal.Target)
+}
@@ -0,0 +1,300 @@
+package activity
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mangahub/pkg/database"
+	"mangahub/pkg/models"
+	"math"
+	"time"
+)
+
+// Stats periods
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// maxBuckets limits the length of a stats series
+const maxBuckets = 400
+
+const dateFormat = "2006-01-02"
+
+// Service reads the reading activity log
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a new activity service
+func NewService() *Service {
+	return &Service{
+		db: database.GetDB(),
+	}
+}
+
+// GetActivity returns the user's reading events, newest first, optionally for one manga
+func (s *Service) GetActivity(userID, mangaID string, limit, offset int) ([]models.ReadingEvent, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `
+		SELECT e.id, e.user_id, e.manga_id, e.event_type, e.source, e.chapter_from, e.chapter_to,
+			COALESCE(e.status_from, ''), COALESCE(e.status_to, ''), e.rating, e.created_at, COALESCE(m.title, '')
+		FROM reading_events e
+		LEFT JOIN manga m ON m.id = e.manga_id
+		WHERE e.user_id = ?`
+	args := []interface{}{userID}
+	if mangaID != "" {
+		query += " AND e.manga_id = ?"
+		args = append(args, mangaID)
+	}
+	query += " ORDER BY e.id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading activity: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.ReadingEvent{}
+	for rows.Next() {
+		var event models.ReadingEvent
+		var chapterFrom, chapterTo, rating sql.NullInt64
+		if err := rows.Scan(&event.ID, &event.UserID, &event.MangaID, &event.Type, &event.Source,
+			&chapterFrom, &chapterTo, &event.StatusFrom, &event.StatusTo, &rating,
+			&event.CreatedAt, &event.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan reading event: %w", err)
+		}
+		event.ChapterFrom = nullIntPtr(chapterFrom)
+		event.ChapterTo = nullIntPtr(chapterTo)
+		event.Rating = nullIntPtr(rating)
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// GetReadingStats returns chapters read and entries completed per day, week or
+// month between from and to (YYYY-MM-DD, both optional), with the genres read in
+// each bucket, reading streaks and the average days to finish a manga. Only
+// changes made by the user count as reading; imports and MAL pulls do not.
+func (s *Service) GetReadingStats(userID, period, from, to string) (*models.ReadingStats, error) {
+	if period == "" {
+		period = PeriodDay
+	}
+	if period != PeriodDay && period != PeriodWeek && period != PeriodMonth {
+		return nil, fmt.Errorf("invalid period %q: use day, week or month", period)
+	}
+
+	start, end, err := statsRange(period, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buckets from start to end, so charts get a point for every period
+	stats := &models.ReadingStats{
+		Period: period,
+		From:   start.Format(dateFormat),
+		To:     end.Format(dateFormat),
+		Series: []models.ReadingStatsPoint{},
+	}
+	index := make(map[string]int)
+	for bucket := start; !bucket.After(end); bucket = nextBucket(bucket, period) {
+		if len(stats.Series) >= maxBuckets {
+			return nil, fmt.Errorf("invalid range: more than %d %ss", maxBuckets, period)
+		}
+		label := bucketLabel(bucket, period)
+		index[label] = len(stats.Series)
+		stats.Series = append(stats.Series, models.ReadingStatsPoint{Period: label, Genres: map[string]int{}})
+	}
+
+	rows, err := s.db.Query(`
+		SELECT e.created_at, COALESCE(e.chapter_from, 0), COALESCE(e.chapter_to, 0), COALESCE(e.status_to, ''),
+			COALESCE(m.genres, '[]')
+		FROM reading_events e
+		LEFT JOIN manga m ON m.id = e.manga_id
+		WHERE e.user_id = ? AND e.source = ? AND e.event_type IN (?, ?, ?)
+			AND e.created_at >= ? AND e.created_at < ?`,
+		userID, SourceUser, EventAdded, EventProgress, EventStatus, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var createdAt time.Time
+		var chapterFrom, chapterTo int
+		var statusTo, genresJSON string
+		if err := rows.Scan(&createdAt, &chapterFrom, &chapterTo, &statusTo, &genresJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan reading event: %w", err)
+		}
+
+		i, ok := index[bucketLabel(bucketStart(createdAt.UTC(), period), period)]
+		if !ok {
+			continue
+		}
+		point := &stats.Series[i]
+
+		if statusTo == "completed" {
+			point.Completed++
+			stats.TotalCompleted++
+		}
+
+		chapters := chapterTo - chapterFrom
+		if chapters <= 0 {
+			continue
+		}
+		point.Chapters += chapters
+		stats.TotalChapters += chapters
+
+		var genres []string
+		json.Unmarshal([]byte(genresJSON), &genres)
+		for _, genre := range genres {
+			point.Genres[genre] += chapters
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reading events: %w", err)
+	}
+
+	if stats.CurrentStreak, stats.LongestStreak, err = s.readingStreaks(userID); err != nil {
+		return nil, err
+	}
+
+	var average float64
+	err = s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(AVG(julianday(finished_at) - julianday(started_at)), 0)
+		FROM user_progress
+		WHERE user_id = ? AND started_at IS NOT NULL AND finished_at IS NOT NULL
+			AND finished_at >= started_at AND date(finished_at) BETWEEN ? AND ?`,
+		userID, stats.From, stats.To).Scan(&stats.FinishedWithDates, &average)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get average days to finish: %w", err)
+	}
+	stats.AverageDaysToFinish = math.Round(average*10) / 10
+
+	return stats, nil
+}
+
+// readingStreaks returns the current and longest runs of consecutive days (UTC)
+// with chapters read. The current streak counts if it reaches today or yesterday.
+func (s *Service) readingStreaks(userID string) (int, int, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT date(created_at)
+		FROM reading_events
+		WHERE user_id = ? AND source = ? AND COALESCE(chapter_to, 0) > COALESCE(chapter_from, 0)
+		ORDER BY 1`, userID, SourceUser)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get reading days: %w", err)
+	}
+	defer rows.Close()
+
+	var current, longest int
+	var previous time.Time
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return 0, 0, fmt.Errorf("failed to scan reading day: %w", err)
+		}
+		day, err := time.Parse(dateFormat, value)
+		if err != nil {
+			continue
+		}
+
+		if !previous.IsZero() && day.Equal(previous.AddDate(0, 0, 1)) {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+		previous = day
+	}
+
+	today := bucketStart(time.Now().UTC(), PeriodDay)
+	if previous.IsZero() || previous.Before(today.AddDate(0, 0, -1)) {
+		current = 0
+	}
+	return current, longest, nil
+}
+
+// statsRange resolves the first and last bucket of a stats request. Without
+// from, the range covers the last 30 days, 12 weeks or 12 months up to to.
+func statsRange(period, from, to string) (time.Time, time.Time, error) {
+	end := bucketStart(time.Now().UTC(), PeriodDay)
+	if to != "" {
+		parsed, err := time.Parse(dateFormat, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: expected YYYY-MM-DD, got %q", to)
+		}
+		end = parsed
+	}
+
+	var start time.Time
+	if from != "" {
+		parsed, err := time.Parse(dateFormat, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: expected YYYY-MM-DD, got %q", from)
+		}
+		start = bucketStart(parsed, period)
+	} else {
+		switch period {
+		case PeriodWeek:
+			start = bucketStart(end, period).AddDate(0, 0, -7*11)
+		case PeriodMonth:
+			start = bucketStart(end, period).AddDate(0, -11, 0)
+		default:
+			start = end.AddDate(0, 0, -29)
+		}
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range: from is after to")
+	}
+	return start, end, nil
+}
+
+// bucketStart returns the first day of the bucket containing t (weeks start on Monday)
+func bucketStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextBucket(t time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeek:
+		return t.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+func bucketLabel(t time.Time, period string) string {
+	if period == PeriodMonth {
+		return t.Format("2006-01")
+	}
+	return t.Format(dateFormat)
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"mangahub/pkg/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Activity handlers - the reading event log, reading stats over time and reading goals
+
+// Get reading activity endpoint
+func (s *APIServer) getReadingActivity(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	events, err := s.ActivityService.GetActivity(userID, c.Query("manga_id"), limit, offset)
+	if err != nil {
+		log.Printf("Get reading activity error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"total":  len(events),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Get reading stats endpoint - chart series by day, week or month
+func (s *APIServer) getReadingStats(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	stats, err := s.ActivityService.GetReadingStats(userID, c.Query("period"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Get reading stats error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Get reading goals endpoint - goals with their progress against reading history
+func (s *APIServer) getReadingGoals(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	goals, err := s.ActivityService.GetGoals(userID)
+	if err != nil {
+		log.Printf("Get reading goals error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goals": goals,
+		"total": len(goals),
+	})
+}
+
+// Create reading goal endpoint
+func (s *APIServer) createReadingGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.CreateReadingGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, err := s.ActivityService.CreateGoal(userID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Create reading goal error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	if goal.AchievedAt != nil {
+		s.sendGoalNotification(userID, c.GetString("username"), *goal)
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// Delete reading goal endpoint
+func (s *APIServer) deleteReadingGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.ActivityService.DeleteGoal(userID, c.Param("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Delete reading goal error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+// notifyAchievedGoals checks the user's open goals after a library change and
+// announces the ones just reached to the user's WebSocket clients
+func (s *APIServer) notifyAchievedGoals(userID, username string) {
+	goals, err := s.ActivityService.CheckGoals(userID)
+	if err != nil {
+		log.Printf("Check reading goals error: %v", err)
+		return
+	}
+
+	for _, goal := range goals {
+		s.sendGoalNotification(userID, username, goal)
+	}
+}
+
+// sendGoalNotification announces a reached goal to the user's own WebSocket
+// clients. UDP clients are anonymous, so goals are not sent there.
+func (s *APIServer) sendGoalNotification(userID, username string, goal models.ReadingGoal) {
+	message := fmt.Sprintf("You reached the goal \"%s\" (%d/%d)", goal.Title, goal.Progress, goal.Target)
+	s.ChatHub.SendGoalAchieved(userID, username, message)
+}
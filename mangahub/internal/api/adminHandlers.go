@@ -2,8 +2,12 @@ package api
 
 import (
 	"fmt"
+	"log"
+	"mangahub/internal/external"
 	"mangahub/pkg/models"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -182,3 +186,107 @@ func (s *APIServer) bulkDeleteManga(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// Get external API response cache stats endpoint (admin only)
+func (s *APIServer) getResponseCacheStats(c *gin.Context) {
+	stats, err := external.GetResponseCacheStats()
+	if err != nil {
+		log.Printf("Get response cache stats error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"namespaces": stats})
+}
+
+// Purge external API response cache endpoint (admin only)
+// Optional query params: namespace (jikan, mal, mangadex), url_prefix, expired_only=true
+func (s *APIServer) purgeResponseCache(c *gin.Context) {
+	namespace := c.Query("namespace")
+	if namespace != "" && namespace != "jikan" && namespace != "mal" && namespace != "mangadex" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid namespace. Must be one of: jikan, mal, mangadex"})
+		return
+	}
+
+	purged, err := external.PurgeResponseCache(namespace, c.Query("url_prefix"), c.Query("expired_only") == "true")
+	if err != nil {
+		log.Printf("Purge response cache error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Purged %d cached responses", purged),
+		"purged":  purged,
+	})
+}
+
+// Get flagged ratings endpoint (admin only)
+// Optional query params: status (quarantined, cleared, confirmed), limit, offset
+func (s *APIServer) getRatingFlags(c *gin.Context) {
+	limit, offset := reviewPagination(c)
+
+	flags, total, err := s.RatingService.GetRatingFlags(c.Query("status"), limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Get rating flags error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flags":  flags,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Review flagged rating endpoint (admin only)
+func (s *APIServer) reviewRatingFlag(c *gin.Context) {
+	flagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flag ID"})
+		return
+	}
+
+	var req models.ReviewRatingFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag, err := s.RatingService.ReviewRatingFlag(flagID, c.GetString("user_id"), req.Action)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Review rating flag error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// Scan ratings for brigading endpoint (admin only), runs the detector now
+func (s *APIServer) scanRatingAnomalies(c *gin.Context) {
+	quarantined, err := s.RatingService.DetectRatingAnomalies(c.Query("manga_id"))
+	if err != nil {
+		log.Printf("Rating anomaly scan error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     fmt.Sprintf("Quarantined %d ratings", quarantined),
+		"quarantined": quarantined,
+	})
+}
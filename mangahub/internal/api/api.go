@@ -2,15 +2,18 @@ package api
 
 import (
 	"log"
+	"mangahub/internal/activity"
 	"mangahub/internal/external"
 	grpcClient "mangahub/internal/grpc"
 	"mangahub/internal/manga"
 	"mangahub/internal/user"
 	internalWebsocket "mangahub/internal/websocket"
 	"mangahub/pkg/middleware"
+	"mangahub/pkg/utils"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,15 +21,17 @@ import (
 
 // APIServer represents the HTTP API server
 type APIServer struct {
-	Router         *gin.Engine
-	UserService    *user.Service
-	MangaService   *manga.Service
-	ChapterService *manga.ChapterService
-	RatingService  *manga.RatingService
-	SyncService    *manga.SyncService
-	MALClient      *external.MALClient
-	JikanClient    *external.JikanClient
-	Port           string
+	Router          *gin.Engine
+	UserService     *user.Service
+	MangaService    *manga.Service
+	ChapterService  *manga.ChapterService
+	RatingService   *manga.RatingService
+	ReviewService   *manga.ReviewService
+	SyncService     *manga.SyncService
+	ActivityService *activity.Service
+	MALClient       *external.MALClient
+	JikanClient     *external.JikanClient
+	Port            string
 	// WebSocket chat hub for manga-specific chats
 	ChatHub *internalWebsocket.ChatHub
 	// WebSocket upgrader
@@ -52,6 +57,20 @@ func NewAPIServer() *APIServer {
 
 	router := gin.Default()
 
+	// Only proxies listed in TRUSTED_PROXIES (comma separated IPs or CIDRs) may
+	// set the client address with X-Forwarded-For; rate limits and login
+	// throttling count per client address, so it must not be spoofable
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		for _, proxy := range strings.Split(proxies, ",") {
+			trustedProxies = append(trustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Printf("Invalid TRUSTED_PROXIES, trusting no proxies: %v", err)
+		router.SetTrustedProxies(nil)
+	}
+
 	// Add security headers
 	router.Use(middleware.SecurityHeaders())
 
@@ -88,16 +107,18 @@ func NewAPIServer() *APIServer {
 	jikanClient := external.NewJikanClient()
 
 	server := &APIServer{
-		Router:         router,
-		UserService:    user.NewService(),
-		MangaService:   manga.NewService(),
-		ChapterService: manga.NewChapterService(),
-		RatingService:  manga.NewRatingService(),
-		SyncService:    manga.NewSyncService(jikanClient),
-		MALClient:      external.NewMALClient(),
-		JikanClient:    jikanClient,
-		Port:           getPort(),
-		ChatHub:        internalWebsocket.NewChatHub(),
+		Router:          router,
+		UserService:     user.NewService(),
+		MangaService:    manga.NewService(),
+		ChapterService:  manga.NewChapterService(),
+		RatingService:   manga.NewRatingService(),
+		ReviewService:   manga.NewReviewService(),
+		SyncService:     manga.NewSyncService(jikanClient),
+		ActivityService: activity.NewService(),
+		MALClient:       external.NewMALClient(),
+		JikanClient:     jikanClient,
+		Port:            getPort(),
+		ChatHub:         internalWebsocket.NewChatHub(),
 		upgrader: internalWebsocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -129,6 +150,19 @@ func NewAPIServer() *APIServer {
 	// Auto-sync manga from MAL on startup (in background)
 	go server.autoSyncManga()
 
+	// Recompute manga similarity and popularity scores (in background)
+	go server.UserService.StartSimilarityRefresh(utils.DurationFromEnv("RECOMMENDATION_REFRESH_INTERVAL", time.Hour))
+	go server.MangaService.StartPopularityRefresh(utils.DurationFromEnv("POPULARITY_REFRESH_INTERVAL", 15*time.Minute))
+
+	// Scan recent ratings for brigading (in background)
+	go server.RatingService.StartRatingAnomalyScan(utils.DurationFromEnv("RATING_SCAN_INTERVAL", time.Hour))
+
+	// Prune the delta sync change feed (in background)
+	go server.UserService.StartSyncChangePrune(utils.DurationFromEnv("SYNC_PRUNE_INTERVAL", time.Hour))
+
+	// Prune the login audit trail (in background)
+	go server.UserService.StartLoginAttemptPrune(utils.DurationFromEnv("LOGIN_ATTEMPT_PRUNE_INTERVAL", time.Hour))
+
 	// Setup routes
 	server.setupRoutes()
 
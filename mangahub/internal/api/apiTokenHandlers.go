@@ -0,0 +1,188 @@
+package api
+
+import (
+	"log"
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiTokenScopes lists the protected routes personal access tokens may call and
+// the scope each needs. Routes missing here (profile, password, sessions, tokens,
+// MAL linking, chat) are only available with a login session.
+var apiTokenScopes = map[string]string{
+	// Library and progress
+	"GET /api/v1/users/library":                            auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/filtered":                   auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/stats":                      auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/changes":                    auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/tags":                       auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/export":                     auth.ScopeLibraryRead,
+	"GET /api/v1/users/library/import/review":              auth.ScopeLibraryRead,
+	"GET /api/v1/users/progress/conflicts":                 auth.ScopeLibraryRead,
+	"GET /api/v1/users/manga/:manga_id/chapters/read":      auth.ScopeLibraryRead,
+	"GET /api/v1/users/recommendations":                    auth.ScopeLibraryRead,
+	"GET /api/v1/users/activity":                           auth.ScopeLibraryRead,
+	"GET /api/v1/users/stats/reading":                      auth.ScopeLibraryRead,
+	"GET /api/v1/users/goals":                              auth.ScopeLibraryRead,
+	"GET /api/v1/users/collections":                        auth.ScopeLibraryRead,
+	"GET /api/v1/users/collections/:id":                    auth.ScopeLibraryRead,
+	"POST /api/v1/users/library":                           auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/progress":                           auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/progress/batch":                     auth.ScopeLibraryWrite,
+	"POST /api/v1/users/progress/ops":                      auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/progress/conflicts":              auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/library/:manga_id":                  auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/library/:manga_id":               auth.ScopeLibraryWrite,
+	"POST /api/v1/users/library/import":                    auth.ScopeLibraryWrite,
+	"POST /api/v1/users/library/import/review/:id":         auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/library/import/review/:id":       auth.ScopeLibraryWrite,
+	"POST /api/v1/users/import/tachiyomi":                  auth.ScopeLibraryWrite,
+	"POST /api/v1/users/goals":                             auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/goals/:id":                       auth.ScopeLibraryWrite,
+	"POST /api/v1/users/collections":                       auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/collections/order":                  auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/collections/:id":                    auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/collections/:id":                 auth.ScopeLibraryWrite,
+	"POST /api/v1/users/collections/:id/share":             auth.ScopeLibraryWrite,
+	"POST /api/v1/users/collections/:id/items":             auth.ScopeLibraryWrite,
+	"PUT /api/v1/users/collections/:id/items/order":        auth.ScopeLibraryWrite,
+	"DELETE /api/v1/users/collections/:id/items/:manga_id": auth.ScopeLibraryWrite,
+
+	// Ratings and reviews
+	"POST /api/v1/users/manga/:manga_id/rating":   auth.ScopeRatingsWrite,
+	"DELETE /api/v1/users/manga/:manga_id/rating": auth.ScopeRatingsWrite,
+	"GET /api/v1/users/manga/:manga_id/review":    auth.ScopeLibraryRead,
+	"PUT /api/v1/users/manga/:manga_id/review":    auth.ScopeRatingsWrite,
+	"DELETE /api/v1/users/manga/:manga_id/review": auth.ScopeRatingsWrite,
+	"POST /api/v1/reviews/:id/vote":               auth.ScopeRatingsWrite,
+	"DELETE /api/v1/reviews/:id/vote":             auth.ScopeRatingsWrite,
+	"POST /api/v1/reviews/:id/report":             auth.ScopeRatingsWrite,
+
+	// gRPC-backed endpoints
+	"PUT /api/v1/grpc/progress/update":                auth.ScopeLibraryWrite,
+	"POST /api/v1/grpc/progress/ops":                  auth.ScopeLibraryWrite,
+	"GET /api/v1/grpc/progress/conflicts":             auth.ScopeLibraryRead,
+	"GET /api/v1/grpc/library":                        auth.ScopeLibraryRead,
+	"POST /api/v1/grpc/library":                       auth.ScopeLibraryWrite,
+	"DELETE /api/v1/grpc/library/:manga_id":           auth.ScopeLibraryWrite,
+	"GET /api/v1/grpc/library/stats":                  auth.ScopeLibraryRead,
+	"GET /api/v1/grpc/library/changes":                auth.ScopeLibraryRead,
+	"POST /api/v1/grpc/rating":                        auth.ScopeRatingsWrite,
+	"DELETE /api/v1/grpc/rating/:manga_id":            auth.ScopeRatingsWrite,
+	"POST /api/v1/grpc/reviews":                       auth.ScopeRatingsWrite,
+	"POST /api/v1/grpc/reviews/:id/vote":              auth.ScopeRatingsWrite,
+	"DELETE /api/v1/grpc/reviews/manga/:manga_id":     auth.ScopeRatingsWrite,
+	"GET /api/v1/grpc/recommendations":                auth.ScopeLibraryRead,
+	"POST /api/v1/grpc/admin/users/:id/roles":         auth.ScopeAdmin,
+	"DELETE /api/v1/grpc/admin/users/:id/roles/:role": auth.ScopeAdmin,
+
+	// Admin endpoints, still limited by the user's roles
+	"POST /api/v1/manga/":                           auth.ScopeAdmin,
+	"PUT /api/v1/manga/:id":                         auth.ScopeAdmin,
+	"DELETE /api/v1/manga/:id":                      auth.ScopeAdmin,
+	"POST /api/v1/manga/bulk-import":                auth.ScopeAdmin,
+	"POST /api/v1/manga/validate-data":              auth.ScopeAdmin,
+	"GET /api/v1/manga/import-stats":                auth.ScopeAdmin,
+	"DELETE /api/v1/manga/bulk-delete":              auth.ScopeAdmin,
+	"GET /api/v1/admin/cache":                       auth.ScopeAdmin,
+	"DELETE /api/v1/admin/cache":                    auth.ScopeAdmin,
+	"GET /api/v1/admin/reviews/queue":               auth.ScopeAdmin,
+	"POST /api/v1/admin/reviews/:id/moderate":       auth.ScopeAdmin,
+	"GET /api/v1/admin/ratings/flagged":             auth.ScopeAdmin,
+	"POST /api/v1/admin/ratings/flagged/:id/review": auth.ScopeAdmin,
+	"POST /api/v1/admin/ratings/scan":               auth.ScopeAdmin,
+	"GET /api/v1/admin/roles":                       auth.ScopeAdmin,
+	"GET /api/v1/admin/users/:id/roles":             auth.ScopeAdmin,
+	"POST /api/v1/admin/users/:id/roles":            auth.ScopeAdmin,
+	"DELETE /api/v1/admin/users/:id/roles/:role":    auth.ScopeAdmin,
+	"GET /api/v1/admin/login-attempts":              auth.ScopeAdmin,
+	"GET /api/v1/admin/users/:id/lock":              auth.ScopeAdmin,
+	"POST /api/v1/admin/users/:id/unlock":           auth.ScopeAdmin,
+}
+
+// checkAPITokenScope rejects requests made with a personal access token that
+// lacks the route's scope, and requests to routes not open to tokens
+func checkAPITokenScope(c *gin.Context, claims *auth.Claims) bool {
+	if claims.APITokenID == "" {
+		return true
+	}
+
+	scope, ok := apiTokenScopes[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API tokens cannot be used for this endpoint"})
+		return false
+	}
+	if !auth.HasScope(claims, scope) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "API token lacks the required scope",
+			"scope": scope,
+		})
+		return false
+	}
+
+	return true
+}
+
+// Get API tokens endpoint
+func (s *APIServer) getAPITokens(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	tokens, err := s.UserService.GetAPITokens(userID)
+	if err != nil {
+		respondAPITokenError(c, "Get API tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"scopes": auth.Scopes,
+	})
+}
+
+// Create API token endpoint
+func (s *APIServer) createAPIToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := s.UserService.CreateAPIToken(userID, req)
+	if err != nil {
+		respondAPITokenError(c, "Create API token", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// Revoke API token endpoint
+func (s *APIServer) revokeAPIToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.RevokeAPIToken(userID, c.Param("id")); err != nil {
+		respondAPITokenError(c, "Revoke API token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API token revoked successfully"})
+}
+
+func respondAPITokenError(c *gin.Context, action string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mangahub/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// scopeTestRouter serves a route behind checkAPITokenScope with the given claims
+func scopeTestRouter(method, path string, claims *auth.Claims) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, path, func(c *gin.Context) {
+		if !checkAPITokenScope(c, claims) {
+			c.Abort()
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestCheckAPITokenScopeDeniesByDefault(t *testing.T) {
+	token := func(scopes ...string) *auth.Claims {
+		return &auth.Claims{UserID: "user-1", APITokenID: "token-1", Scopes: scopes}
+	}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		claims *auth.Claims
+		want   int
+	}{
+		{"session on a route closed to tokens", http.MethodPut, "/api/v1/users/password", &auth.Claims{UserID: "user-1", SessionID: "s"}, http.StatusNoContent},
+		{"token on a route closed to tokens", http.MethodPut, "/api/v1/users/password", token(auth.ScopeLibraryWrite, auth.ScopeRatingsWrite, auth.ScopeAdmin), http.StatusForbidden},
+		{"token on its own scope", http.MethodGet, "/api/v1/users/library", token(auth.ScopeLibraryRead), http.StatusNoContent},
+		{"write implies read", http.MethodGet, "/api/v1/users/library", token(auth.ScopeLibraryWrite), http.StatusNoContent},
+		{"read does not imply write", http.MethodPost, "/api/v1/users/library", token(auth.ScopeLibraryRead), http.StatusForbidden},
+		{"token without scopes", http.MethodGet, "/api/v1/users/library", token(), http.StatusForbidden},
+		{"other method of a scoped path", http.MethodPatch, "/api/v1/users/library", token(auth.ScopeLibraryWrite), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			scopeTestRouter(tt.method, tt.path, tt.claims).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestAPITokenScopesCoverOnlyRegisteredRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &APIServer{Router: gin.New()}
+	s.setupRoutes()
+
+	registered := map[string]bool{}
+	for _, route := range s.Router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range apiTokenScopes {
+		assert.True(t, registered[route], "%s is not a route", route)
+	}
+
+	// Account, session and token management stay with login sessions
+	for _, route := range []string{
+		"PUT /api/v1/users/password",
+		"GET /api/v1/users/sessions",
+		"POST /api/v1/users/tokens",
+		"DELETE /api/v1/users/tokens/:id",
+	} {
+		assert.True(t, registered[route], "%s is not a route", route)
+		assert.NotContains(t, apiTokenScopes, route)
+	}
+}
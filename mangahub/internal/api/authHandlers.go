@@ -2,6 +2,7 @@ package api
 
 import (
 	"log"
+	"mangahub/internal/auth"
 	"mangahub/pkg/models"
 	"net/http"
 	"strings"
@@ -17,7 +18,7 @@ func (s *APIServer) register(c *gin.Context) {
 		return
 	}
 
-	response, err := s.UserService.Register(req)
+	response, err := s.UserService.Register(req, sessionClient(c))
 	if err != nil {
 		if strings.Contains(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -39,8 +40,11 @@ func (s *APIServer) login(c *gin.Context) {
 		return
 	}
 
-	response, err := s.UserService.Login(req)
+	response, challenge, err := s.UserService.Login(req, sessionClient(c))
 	if err != nil {
+		if respondLoginThrottled(c, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "invalid") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		} else {
@@ -50,5 +54,225 @@ func (s *APIServer) login(c *gin.Context) {
 		return
 	}
 
+	// Accounts with two-factor authentication continue at /auth/login/2fa
+	if challenge != nil {
+		c.JSON(http.StatusOK, challenge)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Two-factor login endpoint (second step of a login challenged for a code)
+func (s *APIServer) loginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := s.UserService.CompleteTwoFactorLogin(req, sessionClient(c))
+	if err != nil {
+		if respondLoginThrottled(c, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("Two-factor login error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
+
+// Refresh endpoint (exchanges a refresh token for new session tokens)
+func (s *APIServer) refreshSession(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := s.UserService.RefreshSession(req.RefreshToken, sessionClient(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("Refresh session error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Verify email endpoint (token from the verification email)
+func (s *APIServer) verifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.UserService.VerifyEmail(req.Token); err != nil {
+		respondAccountError(c, "Verify email", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// Resend verification email endpoint
+func (s *APIServer) resendVerificationEmail(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.SendVerificationEmail(userID); err != nil {
+		respondAccountError(c, "Send verification email", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
+
+// Forgot password endpoint (emails a reset link; the response is the same whether or not the account exists)
+func (s *APIServer) forgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.UserService.RequestPasswordReset(req.Email); err != nil {
+		respondAccountError(c, "Forgot password", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If an account uses this email, a password reset link was sent to it"})
+}
+
+// Reset password endpoint (token from the reset email)
+func (s *APIServer) resetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.UserService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		respondAccountError(c, "Reset password", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully, please log in again"})
+}
+
+// Logout endpoint (revokes the session of the request)
+func (s *APIServer) logout(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.RevokeSession(userID, c.GetString("session_id")); err != nil {
+		respondSessionError(c, "Logout", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// Get sessions endpoint
+func (s *APIServer) getSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	sessions, err := s.UserService.GetSessions(userID, c.GetString("session_id"))
+	if err != nil {
+		respondSessionError(c, "Get sessions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"total":    len(sessions),
+	})
+}
+
+// Revoke session endpoint
+func (s *APIServer) revokeSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.RevokeSession(userID, c.Param("id")); err != nil {
+		respondSessionError(c, "Revoke session", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// Revoke all sessions endpoint (keep_current=true keeps the session of the request)
+func (s *APIServer) revokeAllSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	keep := ""
+	if c.Query("keep_current") == "true" {
+		keep = c.GetString("session_id")
+	}
+
+	revoked, err := s.UserService.RevokeAllSessions(userID, keep)
+	if err != nil {
+		respondSessionError(c, "Revoke all sessions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sessions revoked successfully",
+		"revoked": revoked,
+	})
+}
+
+// JWKS endpoint (public keys that verify access tokens, for the other services)
+func (s *APIServer) getJWKS(c *gin.Context) {
+	set, err := auth.PublicKeySet()
+	if err != nil {
+		log.Printf("Get JWKS error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Verifiers refetch on an unknown key ID, so a short cache is enough
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
+
+// sessionClient describes the client of the request for its login session
+func sessionClient(c *gin.Context) models.SessionClient {
+	return models.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+func respondSessionError(c *gin.Context, action string, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("%s error: %v", action, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
+
+func respondAccountError(c *gin.Context, action string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "already verified"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"mangahub/internal/user"
+	"mangahub/pkg/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Collection handlers - named, manually ordered manga lists with share links
+
+// Get collections endpoint
+func (s *APIServer) getCollections(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	collections, err := s.UserService.GetCollections(userID)
+	if err != nil {
+		log.Printf("Get collections error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	for i := range collections {
+		setShareURL(c, &collections[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"total":       len(collections),
+	})
+}
+
+// Get collection endpoint
+func (s *APIServer) getCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	collection, err := s.UserService.GetCollection(userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, "Get collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Create collection endpoint
+func (s *APIServer) createCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := s.UserService.CreateCollection(userID, req)
+	if err != nil {
+		respondCollectionError(c, "Create collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusCreated, collection)
+}
+
+// Update collection endpoint
+func (s *APIServer) updateCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := s.UserService.UpdateCollection(userID, c.Param("id"), req)
+	if err != nil {
+		respondCollectionError(c, "Update collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Delete collection endpoint
+func (s *APIServer) deleteCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.DeleteCollection(userID, c.Param("id")); err != nil {
+		respondCollectionError(c, "Delete collection", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// Reorder collections endpoint
+func (s *APIServer) reorderCollections(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ReorderCollectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collections, err := s.UserService.ReorderCollections(userID, req.CollectionIDs)
+	if err != nil {
+		respondCollectionError(c, "Reorder collections", err)
+		return
+	}
+
+	for i := range collections {
+		setShareURL(c, &collections[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"total":       len(collections),
+	})
+}
+
+// Regenerate share link endpoint - the previous link stops working
+func (s *APIServer) regenerateShareLink(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	collection, err := s.UserService.RegenerateShareToken(userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, "Regenerate share link", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Add to collection endpoint
+func (s *APIServer) addToCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := s.UserService.AddToCollection(userID, c.Param("id"), req.MangaID)
+	if err != nil {
+		respondCollectionError(c, "Add to collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Remove from collection endpoint
+func (s *APIServer) removeFromCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	collection, err := s.UserService.RemoveFromCollection(userID, c.Param("id"), c.Param("manga_id"))
+	if err != nil {
+		respondCollectionError(c, "Remove from collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Reorder collection items endpoint
+func (s *APIServer) reorderCollection(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ReorderCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := s.UserService.ReorderCollection(userID, c.Param("id"), req.MangaIDs)
+	if err != nil {
+		respondCollectionError(c, "Reorder collection", err)
+		return
+	}
+
+	setShareURL(c, collection)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Get shared collection endpoint (public, read-only)
+func (s *APIServer) getSharedCollection(c *gin.Context) {
+	collection, err := s.UserService.GetSharedCollection(c.Param("token"))
+	if err != nil {
+		respondCollectionError(c, "Get shared collection", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// Get public collections endpoint (public, optionally filtered by username)
+func (s *APIServer) getPublicCollections(c *gin.Context) {
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	collections, err := s.UserService.GetPublicCollections(c.Query("username"), limit, offset)
+	if err != nil {
+		log.Printf("Get public collections error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	for i := range collections {
+		setShareURL(c, &collections[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"total":       len(collections),
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// respondCollectionError maps collection service errors to HTTP responses
+func respondCollectionError(c *gin.Context, action string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "already exists"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	case strings.Contains(msg, "invalid") || strings.Contains(msg, "required"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+// setShareURL fills in the read-only link of collections that are not private
+func setShareURL(c *gin.Context, collection *models.Collection) {
+	if collection.Visibility == user.VisibilityPrivate || collection.ShareToken == "" {
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	collection.ShareURL = fmt.Sprintf("%s://%s/api/v1/collections/shared/%s", scheme, c.Request.Host, collection.ShareToken)
+}
@@ -3,9 +3,12 @@ package api
 import (
 	"context"
 	"log"
+	grpcClient "mangahub/internal/grpc"
+	"mangahub/pkg/models"
 	pb "mangahub/proto"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,7 +31,7 @@ func (s *APIServer) getMangaViaGRPC(c *gin.Context) {
 	}
 
 	// Call gRPC GetManga method
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.GetManga(ctx, mangaID)
@@ -103,7 +106,7 @@ func (s *APIServer) searchMangaViaGRPC(c *gin.Context) {
 	}
 
 	// Call gRPC SearchManga method with all parameters
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.SearchManga(ctx, query, limit, offset, sort)
@@ -173,7 +176,7 @@ func (s *APIServer) updateProgressViaGRPC(c *gin.Context) {
 	}
 
 	// Call gRPC UpdateProgress method
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.UpdateProgress(ctx, userID, req.MangaID, int32(req.CurrentChapter), req.Status)
@@ -198,6 +201,9 @@ func (s *APIServer) updateProgressViaGRPC(c *gin.Context) {
 	// Broadcast progress update to WebSocket clients in the manga's chat room
 	go s.ChatHub.BroadcastProgressUpdate(userID, userName, req.MangaID, req.CurrentChapter)
 
+	// Announce reading goals reached by this update
+	go s.notifyAchievedGoals(userID, userName)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": resp.Message,
 		"source":  "grpc",
@@ -214,7 +220,7 @@ func (s *APIServer) getLibraryViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 10*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.GetLibrary(ctx, userID)
@@ -276,7 +282,7 @@ func (s *APIServer) addToLibraryViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.AddToLibrary(ctx, userID, req.MangaID, req.Status)
@@ -308,7 +314,7 @@ func (s *APIServer) removeFromLibraryViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.RemoveFromLibrary(ctx, userID, mangaID)
@@ -339,7 +345,7 @@ func (s *APIServer) getLibraryStatsViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.GetLibraryStats(ctx, userID)
@@ -367,6 +373,134 @@ func (s *APIServer) getLibraryStatsViaGRPC(c *gin.Context) {
 	})
 }
 
+// getLibraryChangesViaGRPC retrieves user's library changes since a sync token via gRPC service
+func (s *APIServer) getLibraryChangesViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.GetLibraryChanges(ctx, userID, c.Query("since"), int32(limit))
+	if err != nil {
+		log.Printf("gRPC GetLibraryChanges error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get library changes via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		status := http.StatusInternalServerError
+		if strings.Contains(resp.Error, "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sync_token":  resp.SyncToken,
+		"full":        resp.Full,
+		"has_more":    resp.HasMore,
+		"library":     resp.Library,
+		"ratings":     resp.Ratings,
+		"collections": resp.Collections,
+		"deleted":     resp.Deleted,
+		"source":      "grpc",
+	})
+}
+
+// applyProgressOpsViaGRPC applies queued, client-stamped progress ops via gRPC service
+func (s *APIServer) applyProgressOpsViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	var req models.ProgressOpsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ops := make([]*pb.ProgressOp, len(req.Ops))
+	for i, op := range req.Ops {
+		ops[i] = &pb.ProgressOp{
+			DeviceId:   op.DeviceID,
+			Clock:      op.Clock,
+			ClientTime: op.ClientTime.Format(time.RFC3339Nano),
+			MangaId:    op.MangaID,
+			Status:     op.Status,
+		}
+		if op.CurrentChapter != nil {
+			chapter := int32(*op.CurrentChapter)
+			ops[i].CurrentChapter = &chapter
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.ApplyProgressOps(ctx, userID, ops)
+	if err != nil {
+		log.Printf("gRPC ApplyProgressOps error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply progress ops via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   resp.Results,
+		"conflicts": resp.Conflicts,
+		"clock":     resp.Clock,
+		"source":    "grpc",
+	})
+}
+
+// getProgressConflictsViaGRPC retrieves user's progress conflict log via gRPC service
+func (s *APIServer) getProgressConflictsViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	limit, offset := reviewPagination(c)
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.GetProgressConflicts(ctx, userID, c.Query("manga_id"), int32(limit), int32(offset))
+	if err != nil {
+		log.Printf("gRPC GetProgressConflicts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get progress conflicts via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conflicts": resp.Conflicts,
+		"total":     resp.Total,
+		"source":    "grpc",
+	})
+}
+
 // rateMangaViaGRPC submits a manga rating via gRPC service
 func (s *APIServer) rateMangaViaGRPC(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -374,6 +508,7 @@ func (s *APIServer) rateMangaViaGRPC(c *gin.Context) {
 	var req struct {
 		MangaID string `json:"manga_id" binding:"required"`
 		Rating  int    `json:"rating" binding:"required,min=1,max=10"`
+		models.RatingSubScores
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -386,10 +521,11 @@ func (s *APIServer) rateMangaViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
-	resp, err := s.GRPCClient.RateManga(ctx, userID, req.MangaID, int32(req.Rating))
+	resp, err := s.GRPCClient.RateManga(ctx, userID, req.MangaID, int32(req.Rating),
+		subScoreToPB(req.Story), subScoreToPB(req.Art), subScoreToPB(req.Characters))
 	if err != nil {
 		log.Printf("gRPC RateManga error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rate manga via gRPC"})
@@ -402,15 +538,24 @@ func (s *APIServer) rateMangaViaGRPC(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":        resp.Message,
-		"average_rating": resp.AverageRating,
-		"total_ratings":  resp.TotalRatings,
-		"user_rating":    req.Rating,
-		"source":         "grpc",
-		"success":        true,
+		"message":         resp.Message,
+		"average_rating":  resp.AverageRating,
+		"weighted_rating": resp.WeightedRating,
+		"total_ratings":   resp.TotalRatings,
+		"user_rating":     req.Rating,
+		"source":          "grpc",
+		"success":         true,
 	})
 }
 
+// subScoreToPB converts an optional sub-score to its gRPC form, where 0 means not given
+func subScoreToPB(score *int) int32 {
+	if score == nil {
+		return 0
+	}
+	return int32(*score)
+}
+
 // getMangaRatingsViaGRPC retrieves manga ratings via gRPC service
 func (s *APIServer) getMangaRatingsViaGRPC(c *gin.Context) {
 	mangaID := c.Param("manga_id")
@@ -421,7 +566,7 @@ func (s *APIServer) getMangaRatingsViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.GetMangaRatings(ctx, mangaID, userID)
@@ -438,6 +583,8 @@ func (s *APIServer) getMangaRatingsViaGRPC(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"average_rating":      resp.AverageRating,
+		"weighted_rating":     resp.WeightedRating,
+		"sub_scores":          resp.SubScores,
 		"total_ratings":       resp.TotalRatings,
 		"user_rating":         resp.UserRating,
 		"rating_distribution": resp.RatingDistribution,
@@ -455,7 +602,7 @@ func (s *APIServer) deleteRatingViaGRPC(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
 	defer cancel()
 
 	resp, err := s.GRPCClient.DeleteRating(ctx, userID, mangaID)
@@ -476,3 +623,256 @@ func (s *APIServer) deleteRatingViaGRPC(c *gin.Context) {
 		"success": true,
 	})
 }
+
+// Get recommendations via gRPC
+func (s *APIServer) getRecommendationsViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 20 {
+			limit = l
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.GetRecommendations(ctx, userID, int32(limit))
+	if err != nil {
+		log.Printf("gRPC GetRecommendations error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recommendations": resp.Recommendations,
+		"count":           len(resp.Recommendations),
+		"source":          "grpc",
+	})
+}
+
+// getMangaReviewsViaGRPC retrieves the reviews of a manga via gRPC service
+func (s *APIServer) getMangaReviewsViaGRPC(c *gin.Context) {
+	mangaID := c.Param("manga_id")
+	userID := c.GetString("user_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	limit, offset := reviewPagination(c)
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.GetMangaReviews(ctx, mangaID, userID, c.Query("sort"), int32(limit), int32(offset))
+	if err != nil {
+		log.Printf("gRPC GetMangaReviews error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reviews via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": resp.Reviews,
+		"total":   resp.Total,
+		"limit":   limit,
+		"offset":  offset,
+		"source":  "grpc",
+	})
+}
+
+// writeReviewViaGRPC creates or edits the user's review of a manga via gRPC service
+func (s *APIServer) writeReviewViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		MangaID string `json:"manga_id" binding:"required"`
+		models.WriteReviewRequest
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.WriteReview(ctx, userID, req.MangaID, req.Title, req.Body, req.Spoiler)
+	if err != nil {
+		log.Printf("gRPC WriteReview error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write review via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"review": resp.Review,
+		"source": "grpc",
+	})
+}
+
+// voteReviewViaGRPC votes on a review via gRPC service
+func (s *APIServer) voteReviewViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+	reviewID := c.Param("id")
+
+	var req models.ReviewVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.VoteReview(ctx, userID, reviewID, *req.Helpful)
+	if err != nil {
+		log.Printf("gRPC VoteReview error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote on review via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"review": resp.Review,
+		"source": "grpc",
+	})
+}
+
+// deleteReviewViaGRPC deletes the user's review of a manga via gRPC service
+func (s *APIServer) deleteReviewViaGRPC(c *gin.Context) {
+	userID := c.GetString("user_id")
+	mangaID := c.Param("manga_id")
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.DeleteReview(ctx, userID, mangaID)
+	if err != nil {
+		log.Printf("gRPC DeleteReview error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review via gRPC"})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": resp.Message,
+		"source":  "grpc",
+		"success": true,
+	})
+}
+
+// grantRoleViaGRPC grants a role to a user via gRPC service (admin only)
+func (s *APIServer) grantRoleViaGRPC(c *gin.Context) {
+	var req models.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.GrantRole(ctx, c.Param("id"), req.Role)
+	if err != nil {
+		log.Printf("gRPC GrantRole error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant role via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": resp.UserId,
+		"roles":   resp.Roles,
+		"source":  "grpc",
+	})
+}
+
+// revokeRoleViaGRPC revokes a role of a user via gRPC service (admin only)
+func (s *APIServer) revokeRoleViaGRPC(c *gin.Context) {
+	if s.GRPCClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "gRPC service unavailable"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(grpcContext(c), 5*time.Second)
+	defer cancel()
+
+	resp, err := s.GRPCClient.RevokeRole(ctx, c.Param("id"), c.Param("role"))
+	if err != nil {
+		log.Printf("gRPC RevokeRole error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke role via gRPC"})
+		return
+	}
+
+	if resp.Error != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": resp.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": resp.UserId,
+		"roles":   resp.Roles,
+		"source":  "grpc",
+	})
+}
+
+// grpcContext carries the caller's access token to the gRPC server, which checks
+// it for calls made on behalf of a user
+func grpcContext(c *gin.Context) context.Context {
+	return grpcClient.WithToken(context.Background(), c.GetString("token"))
+}
@@ -13,6 +13,7 @@ func (s *APIServer) enrichMangaWithRatings(mangaList []*models.Manga, userID str
 		if err != nil {
 			// If there's an error, set rating to 0 (no rating)
 			manga.Rating = 0
+			manga.WeightedRating = 0
 			manga.RatingCount = 0
 			manga.UserRating = nil
 			continue
@@ -24,6 +25,7 @@ func (s *APIServer) enrichMangaWithRatings(mangaList []*models.Manga, userID str
 		} else {
 			manga.Rating = 0
 		}
+		manga.WeightedRating = stats.WeightedRating
 		manga.RatingCount = stats.TotalRatings
 		manga.UserRating = stats.UserRating
 	}
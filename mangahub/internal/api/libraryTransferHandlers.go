@@ -0,0 +1,249 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mangahub/internal/user"
+	"mangahub/pkg/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Library import/export handlers (MAL XML, AniList JSON, Tachiyomi backups)
+
+// Import library endpoint - accepts a multipart "file" upload or the raw export as body
+func (s *APIServer) importLibrary(c *gin.Context) {
+	userID := c.GetString("user_id")
+	format := strings.ToLower(c.Query("format"))
+	overwrite := c.Query("overwrite") == "true"
+
+	data, ok := readUploadedFile(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.UserService.ImportLibrary(userID, format, data, overwrite)
+	if err != nil {
+		if strings.Contains(err.Error(), "too large") {
+			respondUploadTooLarge(c)
+			return
+		}
+		if strings.Contains(err.Error(), "unsupported") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Import library error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Export library endpoint
+func (s *APIServer) exportLibrary(c *gin.Context) {
+	userID := c.GetString("user_id")
+	format := strings.ToLower(c.DefaultQuery("format", user.LibraryFormatMAL))
+
+	data, err := s.UserService.ExportLibrary(userID, format)
+	if err != nil {
+		if strings.Contains(err.Error(), "unsupported") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Export library error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	contentType, ext := "application/xml", "xml"
+	if format == user.LibraryFormatAniList {
+		contentType, ext = "application/json", "json"
+	}
+	filename := fmt.Sprintf("mangahub_%s_%s.%s", format, time.Now().Format("20060102"), ext)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// Get import review list endpoint
+func (s *APIServer) getImportReviewList(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	items, err := s.UserService.GetImportReviewList(userID)
+	if err != nil {
+		log.Printf("Get import review list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"total": len(items),
+	})
+}
+
+// Resolve import review item endpoint
+func (s *APIServer) resolveImportReviewItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review item ID"})
+		return
+	}
+
+	var req models.ResolveImportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.UserService.ResolveImportReviewItem(userID, itemID, req.MangaID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Resolve import review item error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entry imported successfully"})
+}
+
+// Dismiss import review item endpoint
+func (s *APIServer) dismissImportReviewItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review item ID"})
+		return
+	}
+
+	if err := s.UserService.DismissImportReviewItem(userID, itemID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Dismiss import review item error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review item dismissed"})
+}
+
+// Import Tachiyomi/Mihon backup endpoint - accepts a multipart "file" upload or the raw backup as body
+func (s *APIServer) importTachiyomiBackup(c *gin.Context) {
+	userID := c.GetString("user_id")
+	overwrite := c.Query("overwrite") == "true"
+
+	data, ok := readUploadedFile(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.UserService.ImportTachiyomiBackup(userID, data, overwrite)
+	if err != nil {
+		if strings.Contains(err.Error(), "too large") {
+			respondUploadTooLarge(c)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Import Tachiyomi backup error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Get read chapters endpoint
+func (s *APIServer) getReadChapters(c *gin.Context) {
+	userID := c.GetString("user_id")
+	mangaID := c.Param("manga_id")
+
+	chapters, err := s.UserService.GetReadChapters(userID, mangaID)
+	if err != nil {
+		log.Printf("Get read chapters error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"manga_id": mangaID,
+		"chapters": chapters,
+		"total":    len(chapters),
+	})
+}
+
+// multipartOverhead allows for multipart boundaries and part headers on top of
+// the file itself
+const multipartOverhead = 64 << 10
+
+// readUploadedFile reads an uploaded file from the multipart "file" field or the raw
+// request body, up to user.MaxImportFileSize. It writes the error response itself
+// and returns false on failure.
+func readUploadedFile(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, user.MaxImportFileSize+multipartOverhead)
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, formErr := c.Request.FormFile("file")
+		if formErr != nil {
+			if isBodyTooLarge(formErr) {
+				respondUploadTooLarge(c)
+				return nil, false
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+			return nil, false
+		}
+		defer file.Close()
+		data, err = io.ReadAll(io.LimitReader(file, user.MaxImportFileSize+1))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return nil, false
+		}
+	} else {
+		data, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				respondUploadTooLarge(c)
+				return nil, false
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return nil, false
+		}
+	}
+
+	if len(data) > user.MaxImportFileSize {
+		respondUploadTooLarge(c)
+		return nil, false
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return nil, false
+	}
+	return data, true
+}
+
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func respondUploadTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":    "File too large",
+		"max_size": user.MaxImportFileSize,
+	})
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"mangahub/internal/user"
+	"mangahub/pkg/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get login attempts endpoint (the caller's own recent logins)
+func (s *APIServer) getLoginAttempts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	limit, offset := reviewPagination(c)
+
+	attempts, total, err := s.UserService.GetLoginAttempts(userID, limit, offset)
+	if err != nil {
+		respondLoginAttemptError(c, "Get login attempts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attempts": attempts,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// Get all login attempts endpoint (admin only; filter by user_id, ip_address and failed)
+func (s *APIServer) getAllLoginAttempts(c *gin.Context) {
+	limit, offset := reviewPagination(c)
+	failed, _ := strconv.ParseBool(c.Query("failed"))
+
+	attempts, total, err := s.UserService.ListLoginAttempts(models.LoginAttemptFilter{
+		UserID:    c.Query("user_id"),
+		IPAddress: c.Query("ip_address"),
+		Failed:    failed,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		respondLoginAttemptError(c, "Get all login attempts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attempts": attempts,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// Get account lock status endpoint (admin only)
+func (s *APIServer) getAccountLockStatus(c *gin.Context) {
+	status, err := s.UserService.GetAccountLockStatus(c.Param("id"))
+	if err != nil {
+		respondLoginAttemptError(c, "Get account lock status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Unlock account endpoint (admin only; also forgets the failed logins)
+func (s *APIServer) unlockAccount(c *gin.Context) {
+	status, err := s.UserService.UnlockAccount(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		respondLoginAttemptError(c, "Unlock account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// respondLoginThrottled answers a login refused for too many failed attempts.
+// It reports whether err was such a refusal.
+func respondLoginThrottled(c *gin.Context, err error) bool {
+	var throttled *user.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       err.Error(),
+		"locked":      throttled.Locked,
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+func respondLoginAttemptError(c *gin.Context, action string, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("%s error: %v", action, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MAL account linking handlers
+
+// Start MAL account linking endpoint
+func (s *APIServer) linkMALAccount(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	authURL, err := s.UserService.StartMALLink(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not configured") {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "MAL API not configured"})
+			return
+		}
+		log.Printf("Start MAL link error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"authorization_url": authURL,
+	})
+}
+
+// MAL OAuth2 callback endpoint (the user is identified by the state parameter)
+func (s *APIServer) malOAuthCallback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "MAL authorization failed: " + errParam})
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	status, err := s.UserService.CompleteMALLink(state, code)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid or expired") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Complete MAL link error: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to link MAL account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "MAL account linked successfully",
+		"mal":     status,
+	})
+}
+
+// Get MAL link status endpoint
+func (s *APIServer) getMALLinkStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	status, err := s.UserService.GetMALLinkStatus(userID)
+	if err != nil {
+		log.Printf("Get MAL link status error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Unlink MAL account endpoint
+func (s *APIServer) unlinkMALAccount(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.UnlinkMAL(userID); err != nil {
+		if strings.Contains(err.Error(), "not linked") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MAL account not linked"})
+			return
+		}
+		log.Printf("Unlink MAL error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "MAL account unlinked successfully"})
+}
+
+// Import MAL manga list endpoint
+func (s *APIServer) importMALList(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	result, err := s.UserService.ImportMALList(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not linked") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MAL account not linked"})
+			return
+		}
+		if strings.Contains(err.Error(), "relink") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Import MAL list error: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to import MAL list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -88,6 +88,35 @@ func (s *APIServer) getManga(c *gin.Context) {
 	c.JSON(http.StatusOK, manga)
 }
 
+// Get similar manga endpoint - nearest neighbours by description, genres and tags
+func (s *APIServer) getSimilarManga(c *gin.Context) {
+	mangaID := c.Param("id")
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 20 {
+			limit = l
+		}
+	}
+
+	similar, err := s.MangaService.GetSimilarManga(mangaID, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manga not found"})
+		} else {
+			log.Printf("Get similar manga error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"manga_id": mangaID,
+		"similar":  similar,
+		"count":    len(similar),
+	})
+}
+
 // Get genres endpoint
 func (s *APIServer) getGenres(c *gin.Context) {
 	genres, err := s.MangaService.GetAllGenres()
@@ -125,6 +154,35 @@ func (s *APIServer) getPopularManga(c *gin.Context) {
 	})
 }
 
+// Get trending manga endpoint - highest trending score in a 24h, 7d or 30d window
+func (s *APIServer) getTrendingManga(c *gin.Context) {
+	window := c.DefaultQuery("window", "7d")
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+
+	trending, err := s.MangaService.GetTrendingManga(window, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("Get trending manga error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window": window,
+		"manga":  trending,
+		"count":  len(trending),
+	})
+}
+
 // Get manga stats endpoint
 func (s *APIServer) getMangaStats(c *gin.Context) {
 	stats, err := s.MangaService.GetMangaStats()
@@ -376,15 +434,16 @@ func (s *APIServer) rateManga(c *gin.Context) {
 	userID := c.GetString("user_id")
 
 	var req struct {
-		Rating int `json:"rating" binding:"required,min=1,max=5"`
+		Rating int `json:"rating" binding:"required,min=1,max=10"`
+		models.RatingSubScores
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Rating must be between 1 and 5"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rating and sub-scores (story, art, characters) must be between 1 and 10"})
 		return
 	}
 
-	if err := s.RatingService.RateManga(userID, mangaID, req.Rating); err != nil {
+	if err := s.RatingService.RateManga(userID, mangaID, req.Rating, req.RatingSubScores); err != nil {
 		log.Printf("Error rating manga: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save rating"})
 		return
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"log"
+	"mangahub/pkg/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Review handlers - written reviews attached to ratings, helpfulness votes and moderation
+
+// Get manga reviews endpoint (public, shows the viewer's votes when authenticated)
+func (s *APIServer) getMangaReviews(c *gin.Context) {
+	limit, offset := reviewPagination(c)
+
+	reviews, total, err := s.ReviewService.GetMangaReviews(c.Param("id"), c.GetString("user_id"),
+		c.Query("sort"), limit, offset)
+	if err != nil {
+		respondReviewError(c, "Get manga reviews", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// Get review endpoint
+func (s *APIServer) getReview(c *gin.Context) {
+	review, err := s.ReviewService.GetReview(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		respondReviewError(c, "Get review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// Get review history endpoint
+func (s *APIServer) getReviewHistory(c *gin.Context) {
+	revisions, err := s.ReviewService.GetReviewHistory(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		respondReviewError(c, "Get review history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+// Get own review endpoint
+func (s *APIServer) getUserReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	review, err := s.ReviewService.GetUserReview(userID, c.Param("manga_id"))
+	if err != nil {
+		respondReviewError(c, "Get user review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// Write review endpoint (creates the review or edits it)
+func (s *APIServer) writeReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.WriteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := s.ReviewService.WriteReview(userID, c.Param("manga_id"), req)
+	if err != nil {
+		respondReviewError(c, "Write review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// Delete review endpoint
+func (s *APIServer) deleteReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.ReviewService.DeleteReview(userID, c.Param("manga_id")); err != nil {
+		respondReviewError(c, "Delete review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// Vote review endpoint
+func (s *APIServer) voteReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ReviewVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := s.ReviewService.VoteReview(userID, c.Param("id"), *req.Helpful)
+	if err != nil {
+		respondReviewError(c, "Vote review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// Remove review vote endpoint
+func (s *APIServer) removeReviewVote(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.ReviewService.RemoveVote(userID, c.Param("id")); err != nil {
+		respondReviewError(c, "Remove review vote", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vote removed successfully"})
+}
+
+// Report review endpoint
+func (s *APIServer) reportReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.ReviewService.ReportReview(userID, c.Param("id"), req.Reason); err != nil {
+		respondReviewError(c, "Report review", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Review reported for moderation"})
+}
+
+// Get review moderation queue endpoint (admin)
+func (s *APIServer) getReviewModerationQueue(c *gin.Context) {
+	limit, offset := reviewPagination(c)
+
+	reviews, total, err := s.ReviewService.GetModerationQueue(limit, offset)
+	if err != nil {
+		respondReviewError(c, "Get review moderation queue", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// Moderate review endpoint (admin)
+func (s *APIServer) moderateReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := s.ReviewService.ModerateReview(userID, c.Param("id"), req.Action)
+	if err != nil {
+		respondReviewError(c, "Moderate review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func reviewPagination(c *gin.Context) (int, int) {
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+	return limit, offset
+}
+
+func respondReviewError(c *gin.Context, action string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "already exists"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	case strings.Contains(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
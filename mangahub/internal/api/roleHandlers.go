@@ -0,0 +1,79 @@
+package api
+
+import (
+	"log"
+	"mangahub/pkg/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get roles endpoint (admin only)
+func (s *APIServer) getRoles(c *gin.Context) {
+	roles, err := s.UserService.GetRoles()
+	if err != nil {
+		respondRoleError(c, "Get roles", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// Get user roles endpoint (admin only)
+func (s *APIServer) getUserRoles(c *gin.Context) {
+	userID := c.Param("id")
+
+	roles, err := s.UserService.GetUserRoles(userID)
+	if err != nil {
+		respondRoleError(c, "Get user roles", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": userID,
+		"roles":   roles,
+	})
+}
+
+// Grant role endpoint (admin only)
+func (s *APIServer) grantRole(c *gin.Context) {
+	var req models.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.Param("id")
+	if err := s.UserService.GrantRole(userID, req.Role, c.GetString("user_id")); err != nil {
+		respondRoleError(c, "Grant role", err)
+		return
+	}
+
+	s.getUserRoles(c)
+}
+
+// Revoke role endpoint (admin only)
+func (s *APIServer) revokeRole(c *gin.Context) {
+	if err := s.UserService.RevokeRole(c.Param("id"), c.Param("role")); err != nil {
+		respondRoleError(c, "Revoke role", err)
+		return
+	}
+
+	s.getUserRoles(c)
+}
+
+func respondRoleError(c *gin.Context, action string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	case strings.Contains(msg, "already has"), strings.Contains(msg, "last admin"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
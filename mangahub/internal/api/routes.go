@@ -17,14 +17,24 @@ func (s *APIServer) setupRoutes() {
 	s.Router.GET("/health", s.healthCheck)
 	s.Router.HEAD("/health", s.healthCheck)
 
+	// Public keys of the JWT signing keys, for services that verify tokens
+	s.Router.GET("/.well-known/jwks.json", s.getJWKS)
+
 	// API version 1
 	v1 := s.Router.Group("/api/v1")
 	{
 		// Auth routes (no middleware)
-		auth := v1.Group("/auth")
+		authRoutes := v1.Group("/auth")
 		{
-			auth.POST("/register", s.register)
-			auth.POST("/login", s.login)
+			authRoutes.POST("/register", s.register)
+			authRoutes.POST("/login", s.login)
+			authRoutes.POST("/login/2fa", s.loginTwoFactor)
+			authRoutes.POST("/refresh", s.refreshSession)
+			authRoutes.POST("/verify-email", s.verifyEmail)
+			authRoutes.POST("/password/forgot", s.forgotPassword)
+			authRoutes.POST("/password/reset", s.resetPassword)
+			// MAL OAuth2 redirect target, the user is identified by the state
+			authRoutes.GET("/mal/callback", s.malOAuthCallback)
 		}
 
 		// Public manga browsing routes (no auth required)
@@ -33,6 +43,7 @@ func (s *APIServer) setupRoutes() {
 			publicManga.GET("/", s.searchManga)
 			publicManga.GET("/genres", s.getGenres)
 			publicManga.GET("/popular", s.getPopularManga)
+			publicManga.GET("/trending", s.getTrendingManga)
 			publicManga.GET("/stats", s.getMangaStats)
 
 			// Sync endpoint - fetch from MAL and store manga with chapters
@@ -56,38 +67,110 @@ func (s *APIServer) setupRoutes() {
 			// This must be last to avoid conflicts with specific routes above
 			publicManga.GET("/:id", s.getManga)
 			publicManga.GET("/:id/chapters", s.getChapterList)
+			publicManga.GET("/:id/similar", s.getSimilarManga)
 			// Use optional auth for ratings to return user-specific rating if authenticated
 			publicManga.GET("/:id/ratings", optionalAuthMiddleware(), s.getMangaRatings)
+			publicManga.GET("/:id/reviews", optionalAuthMiddleware(), s.getMangaReviews)
+		}
+
+		// Public reviews (optional auth shows the viewer's votes and own hidden reviews)
+		publicReviews := v1.Group("/reviews")
+		publicReviews.Use(optionalAuthMiddleware())
+		{
+			publicReviews.GET("/:id", s.getReview)
+			publicReviews.GET("/:id/history", s.getReviewHistory)
+		}
+
+		// Shared collections (read-only, no auth required)
+		collections := v1.Group("/collections")
+		{
+			collections.GET("/public", s.getPublicCollections)
+			collections.GET("/shared/:token", s.getSharedCollection)
 		}
 
 		// Protected routes
 		protected := v1.Group("/")
 		protected.Use(authMiddleware())
 		{
+			protected.POST("/auth/logout", s.logout)
+
 			// User routes
 			users := protected.Group("/users")
 			{
 				users.GET("/profile", s.getProfile)
 				users.PUT("/profile", s.updateProfile)
 				users.PUT("/password", s.changePassword)
+				users.POST("/email/verification", s.resendVerificationEmail)
+				users.GET("/sessions", s.getSessions)
+				users.DELETE("/sessions", s.revokeAllSessions)
+				users.DELETE("/sessions/:id", s.revokeSession)
+				users.GET("/login-attempts", s.getLoginAttempts)
+				users.GET("/tokens", s.getAPITokens)
+				users.POST("/tokens", s.createAPIToken)
+				users.DELETE("/tokens/:id", s.revokeAPIToken)
+				users.GET("/2fa", s.getTwoFactorStatus)
+				users.POST("/2fa/totp", s.setupTOTP)
+				users.POST("/2fa/totp/enable", s.enableTOTP)
+				users.POST("/2fa/totp/disable", s.disableTOTP)
+				users.POST("/2fa/recovery-codes", s.regenerateRecoveryCodes)
 				users.GET("/library", s.getLibrary)
 				users.GET("/library/filtered", s.getFilteredLibrary)
 				users.GET("/library/stats", s.getLibraryStats)
+				users.GET("/library/changes", s.getLibraryChanges)
 				users.GET("/recommendations", s.getRecommendations)
 				users.POST("/library", s.addToLibrary)
 				users.PUT("/progress", s.updateProgress)
 				users.PUT("/progress/batch", s.batchUpdateProgress)
+				users.POST("/progress/ops", s.applyProgressOps)
+				users.GET("/progress/conflicts", s.getProgressConflicts)
+				users.DELETE("/progress/conflicts", s.clearProgressConflicts)
+				users.GET("/library/tags", s.getLibraryTags)
+				users.PUT("/library/:manga_id", s.updateLibraryEntry)
 				users.DELETE("/library/:manga_id", s.removeFromLibrary)
+				// Library import/export (MAL XML, AniList JSON)
+				users.POST("/library/import", s.importLibrary)
+				users.GET("/library/export", s.exportLibrary)
+				users.GET("/library/import/review", s.getImportReviewList)
+				users.POST("/library/import/review/:id", s.resolveImportReviewItem)
+				users.DELETE("/library/import/review/:id", s.dismissImportReviewItem)
+				users.POST("/import/tachiyomi", s.importTachiyomiBackup)
 				// Rating routes (protected)
 				users.POST("/manga/:manga_id/rating", s.rateManga)
 				users.DELETE("/manga/:manga_id/rating", s.deleteRating)
+				// Review attached to the user's rating
+				users.GET("/manga/:manga_id/review", s.getUserReview)
+				users.PUT("/manga/:manga_id/review", s.writeReview)
+				users.DELETE("/manga/:manga_id/review", s.deleteReview)
+				users.GET("/manga/:manga_id/chapters/read", s.getReadChapters)
+				// MAL account linking and list sync
+				users.GET("/mal", s.getMALLinkStatus)
+				users.GET("/mal/link", s.linkMALAccount)
+				users.DELETE("/mal", s.unlinkMALAccount)
+				users.POST("/mal/import", s.importMALList)
+				// Custom collections
+				users.GET("/collections", s.getCollections)
+				users.POST("/collections", s.createCollection)
+				users.PUT("/collections/order", s.reorderCollections)
+				users.GET("/collections/:id", s.getCollection)
+				users.PUT("/collections/:id", s.updateCollection)
+				users.DELETE("/collections/:id", s.deleteCollection)
+				users.POST("/collections/:id/share", s.regenerateShareLink)
+				users.POST("/collections/:id/items", s.addToCollection)
+				users.PUT("/collections/:id/items/order", s.reorderCollection)
+				users.DELETE("/collections/:id/items/:manga_id", s.removeFromCollection)
+				// Reading activity log and stats
+				users.GET("/activity", s.getReadingActivity)
+				users.GET("/stats/reading", s.getReadingStats)
+				users.GET("/goals", s.getReadingGoals)
+				users.POST("/goals", s.createReadingGoal)
+				users.DELETE("/goals/:id", s.deleteReadingGoal)
 			}
 
 			// Admin routes for manga management
 			manga := protected.Group("/manga")
 			{
 				adminManga := manga.Group("/")
-				adminManga.Use(adminMiddleware())
+				adminManga.Use(requirePermission(auth.PermManageManga))
 				{
 					adminManga.POST("/", s.createManga)
 					adminManga.PUT("/:id", s.updateManga)
@@ -101,6 +184,42 @@ func (s *APIServer) setupRoutes() {
 				}
 			}
 
+			// Review votes and reports
+			reviews := protected.Group("/reviews")
+			{
+				reviews.POST("/:id/vote", s.voteReview)
+				reviews.DELETE("/:id/vote", s.removeReviewVote)
+				reviews.POST("/:id/report", s.reportReview)
+			}
+
+			// Admin maintenance routes
+			admin := protected.Group("/admin")
+			{
+				// External API response cache
+				admin.GET("/cache", requirePermission(auth.PermManageCache), s.getResponseCacheStats)
+				admin.DELETE("/cache", requirePermission(auth.PermManageCache), s.purgeResponseCache)
+
+				// Review moderation queue
+				admin.GET("/reviews/queue", requirePermission(auth.PermModerateReviews), s.getReviewModerationQueue)
+				admin.POST("/reviews/:id/moderate", requirePermission(auth.PermModerateReviews), s.moderateReview)
+
+				// Ratings quarantined by the brigading detector
+				admin.GET("/ratings/flagged", requirePermission(auth.PermModerateRatings), s.getRatingFlags)
+				admin.POST("/ratings/flagged/:id/review", requirePermission(auth.PermModerateRatings), s.reviewRatingFlag)
+				admin.POST("/ratings/scan", requirePermission(auth.PermModerateRatings), s.scanRatingAnomalies)
+
+				// Roles and their holders
+				admin.GET("/roles", requirePermission(auth.PermManageRoles), s.getRoles)
+				admin.GET("/users/:id/roles", requirePermission(auth.PermManageRoles), s.getUserRoles)
+				admin.POST("/users/:id/roles", requirePermission(auth.PermManageRoles), s.grantRole)
+				admin.DELETE("/users/:id/roles/:role", requirePermission(auth.PermManageRoles), s.revokeRole)
+
+				// Login audit trail and account lockouts
+				admin.GET("/login-attempts", requirePermission(auth.PermManageUsers), s.getAllLoginAttempts)
+				admin.GET("/users/:id/lock", requirePermission(auth.PermManageUsers), s.getAccountLockStatus)
+				admin.POST("/users/:id/unlock", requirePermission(auth.PermManageUsers), s.unlockAccount)
+			}
+
 			// WebSocket chat endpoint (protected - requires authentication)
 			protected.GET("/ws/chat", internalWebsocket.HandleWebSocketChat(s.ChatHub, s.upgrader))
 
@@ -108,16 +227,31 @@ func (s *APIServer) setupRoutes() {
 			grpcProtected := protected.Group("/grpc")
 			{
 				grpcProtected.PUT("/progress/update", s.updateProgressViaGRPC)
+				grpcProtected.POST("/progress/ops", s.applyProgressOpsViaGRPC)
+				grpcProtected.GET("/progress/conflicts", s.getProgressConflictsViaGRPC)
 
 				// Library management via gRPC
 				grpcProtected.GET("/library", s.getLibraryViaGRPC)
 				grpcProtected.POST("/library", s.addToLibraryViaGRPC)
 				grpcProtected.DELETE("/library/:manga_id", s.removeFromLibraryViaGRPC)
 				grpcProtected.GET("/library/stats", s.getLibraryStatsViaGRPC)
+				grpcProtected.GET("/library/changes", s.getLibraryChangesViaGRPC)
 
 				// Rating system via gRPC
 				grpcProtected.POST("/rating", s.rateMangaViaGRPC)
 				grpcProtected.DELETE("/rating/:manga_id", s.deleteRatingViaGRPC)
+
+				// Reviews via gRPC
+				grpcProtected.POST("/reviews", s.writeReviewViaGRPC)
+				grpcProtected.POST("/reviews/:id/vote", s.voteReviewViaGRPC)
+				grpcProtected.DELETE("/reviews/manga/:manga_id", s.deleteReviewViaGRPC)
+
+				// Recommendations via gRPC
+				grpcProtected.GET("/recommendations", s.getRecommendationsViaGRPC)
+
+				// Role management via gRPC (the gRPC server checks the permission too)
+				grpcProtected.POST("/admin/users/:id/roles", requirePermission(auth.PermManageRoles), s.grantRoleViaGRPC)
+				grpcProtected.DELETE("/admin/users/:id/roles/:role", requirePermission(auth.PermManageRoles), s.revokeRoleViaGRPC)
 			}
 		}
 
@@ -127,6 +261,7 @@ func (s *APIServer) setupRoutes() {
 			grpcPublic.GET("/manga/:id", s.getMangaViaGRPC)
 			grpcPublic.GET("/manga/search", s.searchMangaViaGRPC)
 			grpcPublic.GET("/rating/:manga_id", optionalAuthMiddleware(), s.getMangaRatingsViaGRPC)
+			grpcPublic.GET("/reviews/manga/:manga_id", optionalAuthMiddleware(), s.getMangaReviewsViaGRPC)
 		}
 
 		// WebSocket stats endpoint (public for monitoring)
@@ -223,18 +358,27 @@ func authMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Validate token
-		claims, err := auth.ValidateToken(token)
+		// Validate token, including the WebSocket token query parameter
+		claims, err := auth.ValidateRequestToken(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		// Personal access tokens only reach the routes their scopes allow
+		if !checkAPITokenScope(c, claims) {
+			c.Abort()
+			return
+		}
+
 		// Store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("email", claims.Email)
+		c.Set("session_id", claims.SessionID)
+		c.Set("roles", claims.Roles)
+		c.Set("token", token)
 
 		c.Next()
 	}
@@ -256,12 +400,15 @@ func optionalAuthMiddleware() gin.HandlerFunc {
 
 		// If token exists, validate and set user info
 		if token != "" {
-			claims, err := auth.ValidateToken(token)
+			claims, err := auth.ValidateRequestToken(token)
 			if err == nil {
 				// Store user info in context
 				c.Set("user_id", claims.UserID)
 				c.Set("username", claims.Username)
 				c.Set("email", claims.Email)
+				c.Set("session_id", claims.SessionID)
+				c.Set("roles", claims.Roles)
+				c.Set("token", token)
 			}
 		}
 
@@ -270,20 +417,15 @@ func optionalAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-// adminMiddleware checks if user has admin privileges
-func adminMiddleware() gin.HandlerFunc {
+// requirePermission allows the request only if one of the user's roles, taken
+// from the token, grants the permission
+func requirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// For now, we'll use a simple check for admin email or username
-		// In production, you'd have proper role-based access control
-		email := c.GetString("email")
-		username := c.GetString("username")
-
-		// Simple admin check - you can customize this logic
-		isAdmin := email == "admin@mangahub.com" || username == "admin" ||
-			strings.HasPrefix(email, "admin") || strings.HasSuffix(username, "admin")
-
-		if !isAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
+		if !auth.HasPermission(c.GetStringSlice("roles"), permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":      "Insufficient permissions",
+				"permission": permission,
+			})
 			c.Abort()
 			return
 		}
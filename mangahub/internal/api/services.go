@@ -121,7 +121,6 @@ func (s *APIServer) initializeUDP() {
 		udpServerHost = "http://localhost:9020" // Default UDP server HTTP trigger API
 	}
 	s.udpServerURL = udpServerHost
-	log.Printf("UDP PORT", s.udpServerURL)
 	log.Printf("UDP Server HTTP API configured at %s", s.udpServerURL)
 }
 
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"log"
+	"mangahub/pkg/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get two-factor status endpoint
+func (s *APIServer) getTwoFactorStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	status, err := s.UserService.GetTwoFactorStatus(userID)
+	if err != nil {
+		respondTwoFactorError(c, "Get two-factor status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Set up TOTP endpoint (returns the secret and provisioning URI for the authenticator app)
+func (s *APIServer) setupTOTP(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	setup, err := s.UserService.SetupTOTP(userID)
+	if err != nil {
+		respondTwoFactorError(c, "Set up TOTP", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// Enable TOTP endpoint (confirms the first code from the app)
+func (s *APIServer) enableTOTP(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := s.UserService.EnableTOTP(userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, "Enable TOTP", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable TOTP endpoint
+func (s *APIServer) disableTOTP(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.UserService.DisableTOTP(userID, req.Code, sessionClient(c)); err != nil {
+		respondTwoFactorError(c, "Disable TOTP", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// Regenerate recovery codes endpoint
+func (s *APIServer) regenerateRecoveryCodes(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := s.UserService.RegenerateRecoveryCodes(userID, req.Code, sessionClient(c))
+	if err != nil {
+		respondTwoFactorError(c, "Regenerate recovery codes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func respondTwoFactorError(c *gin.Context, action string, err error) {
+	if respondLoginThrottled(c, err) {
+		return
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "second factor required"):
+		c.JSON(http.StatusForbidden, gin.H{"error": msg, "two_factor_required": true})
+	case strings.Contains(msg, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	case strings.Contains(msg, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "already enabled"), strings.Contains(msg, "not enabled"), strings.Contains(msg, "not started"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	default:
+		log.Printf("%s error: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -31,6 +31,7 @@ func (s *APIServer) updateProfile(c *gin.Context) {
 	var req struct {
 		Username string `json:"username"`
 		Email    string `json:"email"`
+		OTPCode  string `json:"otp_code"` // Required with two-factor authentication
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -44,6 +45,11 @@ func (s *APIServer) updateProfile(c *gin.Context) {
 		return
 	}
 
+	if err := s.UserService.RequireSecondFactor(userID, req.OTPCode, sessionClient(c)); err != nil {
+		respondTwoFactorError(c, "Update profile", err)
+		return
+	}
+
 	profile, err := s.UserService.UpdateProfile(userID, req.Username, req.Email)
 	if err != nil {
 		if strings.Contains(err.Error(), "already taken") {
@@ -70,6 +76,7 @@ func (s *APIServer) changePassword(c *gin.Context) {
 	var req struct {
 		OldPassword string `json:"old_password" binding:"required"`
 		NewPassword string `json:"new_password" binding:"required,min=6"`
+		OTPCode     string `json:"otp_code"` // Required with two-factor authentication
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -77,7 +84,12 @@ func (s *APIServer) changePassword(c *gin.Context) {
 		return
 	}
 
-	err := s.UserService.ChangePassword(userID, req.OldPassword, req.NewPassword)
+	if err := s.UserService.RequireSecondFactor(userID, req.OTPCode, sessionClient(c)); err != nil {
+		respondTwoFactorError(c, "Change password", err)
+		return
+	}
+
+	err := s.UserService.ChangePassword(userID, req.OldPassword, req.NewPassword, c.GetString("session_id"))
 	if err != nil {
 		if strings.Contains(err.Error(), "incorrect") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
@@ -90,7 +102,7 @@ func (s *APIServer) changePassword(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully, other sessions were signed out"})
 }
 
 // Get library endpoint
@@ -128,6 +140,8 @@ func (s *APIServer) addToLibrary(c *gin.Context) {
 		return
 	}
 
+	go s.notifyAchievedGoals(userID, c.GetString("username"))
+
 	c.JSON(http.StatusOK, gin.H{"message": "Manga added to library successfully"})
 }
 
@@ -159,6 +173,9 @@ func (s *APIServer) updateProgress(c *gin.Context) {
 	// Broadcast progress update to WebSocket clients in the manga's chat room
 	go s.ChatHub.BroadcastProgressUpdate(userID, userName, req.MangaID, req.CurrentChapter)
 
+	// Announce reading goals reached by this update
+	go s.notifyAchievedGoals(userID, userName)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Progress updated successfully"})
 }
 
@@ -168,10 +185,18 @@ func (s *APIServer) getFilteredLibrary(c *gin.Context) {
 
 	// Parse query parameters
 	req := models.LibraryFilterRequest{
-		Status: c.Query("status"),
-		SortBy: c.Query("sort_by"),
-		Limit:  20,
-		Offset: 0,
+		Status:         c.Query("status"),
+		SortBy:         c.Query("sort_by"),
+		Order:          c.Query("order"),
+		Limit:          20,
+		Offset:         0,
+		Tag:            c.Query("tag"),
+		Search:         c.Query("q"),
+		HasNotes:       c.Query("has_notes") == "true",
+		StartedAfter:   c.Query("started_after"),
+		StartedBefore:  c.Query("started_before"),
+		FinishedAfter:  c.Query("finished_after"),
+		FinishedBefore: c.Query("finished_before"),
 	}
 
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -184,9 +209,18 @@ func (s *APIServer) getFilteredLibrary(c *gin.Context) {
 			req.Offset = offset
 		}
 	}
+	if rereadsStr := c.Query("min_rereads"); rereadsStr != "" {
+		if rereads, err := strconv.Atoi(rereadsStr); err == nil && rereads > 0 {
+			req.MinRereads = rereads
+		}
+	}
 
-	progressList, err := s.UserService.GetFilteredLibrary(userID, req.Status, req.SortBy, req.Limit, req.Offset)
+	progressList, err := s.UserService.GetFilteredLibrary(userID, req)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("Get filtered library error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
@@ -212,6 +246,34 @@ func (s *APIServer) getLibraryStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// Get library changes endpoint (delta sync; no since token returns the full state)
+func (s *APIServer) getLibraryChanges(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = l
+	}
+
+	changes, err := s.UserService.GetLibraryChanges(userID, c.Query("since"), limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Get library changes error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
 // Get recommendations endpoint
 func (s *APIServer) getRecommendations(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -257,12 +319,69 @@ func (s *APIServer) batchUpdateProgress(c *gin.Context) {
 		return
 	}
 
+	go s.notifyAchievedGoals(userID, c.GetString("username"))
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Progress updated successfully",
 		"updated": len(req.Updates),
 	})
 }
 
+// Apply progress ops endpoint (queued, client-stamped progress changes from offline clients)
+func (s *APIServer) applyProgressOps(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ProgressOpsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := s.UserService.ApplyProgressOps(userID, req.Ops)
+	if err != nil {
+		log.Printf("Apply progress ops error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	go s.notifyAchievedGoals(userID, c.GetString("username"))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Get progress conflicts endpoint
+func (s *APIServer) getProgressConflicts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	limit, offset := reviewPagination(c)
+
+	conflicts, total, err := s.UserService.GetProgressConflicts(userID, c.Query("manga_id"), limit, offset)
+	if err != nil {
+		log.Printf("Get progress conflicts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conflicts": conflicts,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// Clear progress conflicts endpoint
+func (s *APIServer) clearProgressConflicts(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.UserService.ClearProgressConflicts(userID); err != nil {
+		log.Printf("Clear progress conflicts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Progress conflicts cleared"})
+}
+
 // Remove from library endpoint
 func (s *APIServer) removeFromLibrary(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -281,3 +400,47 @@ func (s *APIServer) removeFromLibrary(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Manga removed from library successfully"})
 }
+
+// Update library entry endpoint - notes, tags, start/finish dates and re-read count
+func (s *APIServer) updateLibraryEntry(c *gin.Context) {
+	userID := c.GetString("user_id")
+	mangaID := c.Param("manga_id")
+
+	var req models.UpdateLibraryEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := s.UserService.UpdateLibraryEntry(userID, mangaID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			log.Printf("Update library entry error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// Get library tags endpoint
+func (s *APIServer) getLibraryTags(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	tags, err := s.UserService.GetLibraryTags(userID)
+	if err != nil {
+		log.Printf("Get library tags error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"total": len(tags),
+	})
+}
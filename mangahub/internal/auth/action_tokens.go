@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Purposes of action tokens, the signed links sent by email
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+)
+
+// Lifetimes of action tokens
+const (
+	VerifyEmailTokenTTL   = 48 * time.Hour
+	ResetPasswordTokenTTL = time.Hour
+)
+
+// actionClaims are the claims of an action token. Binding ties the token to
+// account state it must not outlive: the email address being verified, or a
+// fingerprint of the password being reset, so a reset link works only once.
+type actionClaims struct {
+	Purpose string `json:"purpose"`
+	Binding string `json:"bnd"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken signs a token for an emailed action on the user's account
+func GenerateActionToken(purpose, userID, binding string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &actionClaims{
+		Purpose: purpose,
+		Binding: binding,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "mangahub",
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{purpose},
+		},
+	}
+
+	tokenString, err := signToken(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// ParseActionToken checks an action token for the purpose and returns the user
+// ID and binding it was issued for
+func ParseActionToken(tokenString, purpose string) (string, string, error) {
+	claims := &actionClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey)
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return "", "", errors.New("invalid token: link expired")
+		}
+		return "", "", errors.New("invalid token")
+	}
+	if !token.Valid || claims.Purpose != purpose || !claims.VerifyAudience(purpose, true) || claims.Subject == "" {
+		return "", "", errors.New("invalid token")
+	}
+
+	return claims.Subject, claims.Binding, nil
+}
+
+// EmailBinding is the binding of an email verification token
+func EmailBinding(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// PasswordBinding is the binding of a password reset token: a fingerprint of the
+// current password hash, which changes once the password is reset
+func PasswordBinding(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
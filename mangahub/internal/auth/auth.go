@@ -5,72 +5,69 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// getJWTSecret returns the JWT secret from environment or default
-func getJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// Fallback to default (should be changed in production)
-		secret = "your-secret-key-change-this-in-production"
-	}
-	return []byte(secret)
-}
+// AccessTokenAudience is the audience of access tokens. Action tokens, signed
+// with the same keys, carry their purpose instead, so neither passes for the other.
+const AccessTokenAudience = "access"
 
 // Claims represents the JWT claims
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID    string   `json:"user_id"`
+	Username  string   `json:"username"`
+	Email     string   `json:"email"`
+	SessionID string   `json:"sid"`   // Login session, checked for revocation
+	Roles     []string `json:"roles"` // Sorted; checked against the stored roles
 	jwt.RegisteredClaims
+
+	// Set when the request was authenticated with a personal access token
+	APITokenID string   `json:"-"`
+	Scopes     []string `json:"-"`
 }
 
-// GenerateToken generates a JWT token for the user
-func GenerateToken(userID, username, email string) (string, error) {
-	// Set token expiration time (24 hours)
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken generates a short-lived access token for a login session,
+// carrying the user's roles (see GetUserRoles)
+func GenerateToken(userID, username, email, sessionID string, roles []string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL())
 
 	// Create claims
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:    userID,
+		Username:  username,
+		Email:     email,
+		SessionID: sessionID,
+		Roles:     roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "mangahub",
 			Subject:   userID,
+			Audience:  jwt.ClaimStrings{AccessTokenAudience},
 		},
 	}
 
-	// Create token with claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token with secret key
-	tokenString, err := token.SignedString(getJWTSecret())
+	// Sign token with the current key (see signToken)
+	tokenString, err := signToken(claims)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign token: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, nil
+	return tokenString, expirationTime, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*Claims, error) {
+// ParseAccessToken checks the signature, expiry and audience of an access token
+// and returns the claims. It needs only the public keys, so services without the
+// database (JWKS_URL) can use it. It does not check the session: services with
+// the database follow it with CheckSession, others accept the token until it
+// expires.
+func ParseAccessToken(tokenString string) (*Claims, error) {
 	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return getJWTSecret(), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -87,6 +84,11 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("invalid token claims")
 	}
 
+	// Email links and login challenges are signed with the same keys
+	if !claims.VerifyAudience(AccessTokenAudience, true) || claims.UserID == "" {
+		return nil, errors.New("invalid token: not an access token")
+	}
+
 	return claims, nil
 }
 
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// jwksClient fetches the key set of JWKS_URL
+var jwksClient = &http.Client{Timeout: 5 * time.Second}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKeySet returns the public keys that verify tokens: the current
+// signing key and retired keys within their overlap. It is empty with HS256.
+func PublicKeySet() (*JWKS, error) {
+	set := &JWKS{Keys: []JWK{}}
+	if SigningAlgorithm() == AlgHS256 {
+		return set, nil
+	}
+
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+	if err := keys.loadFromDB(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys.keys {
+		jwk, err := publicJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+func publicJWK(key *signingKey) (JWK, error) {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Algorithm}
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", key.Public)
+	}
+	return jwk, nil
+}
+
+// fetchJWKS loads the key set another service publishes at url
+func fetchJWKS(url string) (map[string]*signingKey, error) {
+	resp, err := jwksClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	loaded := map[string]*signingKey{}
+	for _, jwk := range set.Keys {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			log.Printf("Skipping JWKS key %s: %v", jwk.Kid, err)
+			continue
+		}
+		loaded[key.ID] = key
+	}
+	return loaded, nil
+}
+
+func parseJWK(jwk JWK) (*signingKey, error) {
+	if jwk.Kid == "" {
+		return nil, fmt.Errorf("no key ID")
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return nil, fmt.Errorf("not a signing key")
+	}
+
+	key := &signingKey{ID: jwk.Kid, Algorithm: jwk.Alg}
+	switch {
+	case jwk.Kty == "RSA" && jwk.Alg == AlgRS256:
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		key.Public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519" && jwk.Alg == AlgEdDSA:
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		key.Public = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("unsupported key type %s/%s", jwk.Kty, jwk.Alg)
+	}
+	return key, nil
+}
@@ -0,0 +1,413 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"mangahub/pkg/database"
+	"mangahub/pkg/utils"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Signing algorithms of JWTs (JWT_SIGNING_ALG)
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+	// Shared secret (JWT_SECRET); every service that checks tokens needs the secret
+	AlgHS256 = "HS256"
+)
+
+const (
+	defaultKeyRotation = 30 * 24 * time.Hour
+	defaultKeyOverlap  = 72 * time.Hour
+	rsaKeyBits         = 2048
+
+	// How often the key set is reloaded, so processes sharing it see rotations
+	keyReloadInterval = time.Minute
+	// Minimum time between reloads for a token signed with an unknown key
+	unknownKeyReloadInterval = 10 * time.Second
+)
+
+// SigningAlgorithm returns the algorithm new tokens are signed with (JWT_SIGNING_ALG)
+func SigningAlgorithm() string {
+	switch alg := os.Getenv("JWT_SIGNING_ALG"); strings.ToUpper(alg) {
+	case "", "RS256":
+		return AlgRS256
+	case "EDDSA":
+		return AlgEdDSA
+	case "HS256":
+		return AlgHS256
+	default:
+		log.Printf("Invalid JWT_SIGNING_ALG %q, using %s", alg, AlgRS256)
+		return AlgRS256
+	}
+}
+
+// keyRotationInterval is how long a key signs before the next one takes over (JWT_KEY_ROTATION)
+func keyRotationInterval() time.Duration {
+	return utils.DurationFromEnv("JWT_KEY_ROTATION", defaultKeyRotation)
+}
+
+// keyOverlap is how long a key still verifies after it stopped signing
+// (JWT_KEY_OVERLAP). It covers the longest lived token a key signs, the
+// email verification link.
+func keyOverlap() time.Duration {
+	overlap := utils.DurationFromEnv("JWT_KEY_OVERLAP", defaultKeyOverlap)
+	if overlap < VerifyEmailTokenTTL {
+		overlap = VerifyEmailTokenTTL
+	}
+	if overlap < AccessTokenTTL() {
+		overlap = AccessTokenTTL()
+	}
+	return overlap
+}
+
+// jwtSecret returns the HS256 secret (JWT_SECRET)
+func jwtSecret() ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, errors.New("JWT_SECRET is required with JWT_SIGNING_ALG=HS256")
+	}
+	return []byte(secret), nil
+}
+
+// signingKey is a key of the key set. The private half is only loaded by
+// processes that sign; verifying needs the public half only.
+type signingKey struct {
+	ID        string
+	Algorithm string
+	Public    crypto.PublicKey
+	Private   crypto.Signer
+	CreatedAt time.Time
+	Retired   bool
+}
+
+// keyStore caches the key set, loaded from the jwt_keys table or, when
+// JWKS_URL is set, from another service's JWKS endpoint
+type keyStore struct {
+	mu       sync.Mutex
+	keys     map[string]*signingKey
+	current  *signingKey
+	loadedAt time.Time
+}
+
+var keys = &keyStore{}
+
+// InitSigningKeys checks the signing configuration and makes sure there is a
+// key to sign with, so the JWKS endpoint publishes it from the start
+func InitSigningKeys() error {
+	alg := SigningAlgorithm()
+	if alg == AlgHS256 {
+		_, err := jwtSecret()
+		return err
+	}
+
+	key, err := keys.signingKey(alg)
+	if err != nil {
+		return err
+	}
+	log.Printf("Signing JWTs with %s key %s", key.Algorithm, key.ID)
+	return nil
+}
+
+// signToken signs claims with the configured algorithm. Asymmetric tokens
+// carry the ID of their key in the kid header.
+func signToken(claims jwt.Claims) (string, error) {
+	alg := SigningAlgorithm()
+	if alg == AlgHS256 {
+		secret, err := jwtSecret()
+		if err != nil {
+			return "", err
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	}
+
+	key, err := keys.signingKey(alg)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// verificationKey is the jwt.Keyfunc of the tokens this package signs. HMAC
+// tokens are only accepted while JWT_SIGNING_ALG is HS256.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if SigningAlgorithm() != AlgHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtSecret()
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no key ID")
+	}
+	key, err := keys.verificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// signingKey returns the key to sign with, rotating it once it is due
+func (s *keyStore) signingKey(alg string) (*signingKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || time.Since(s.loadedAt) > keyReloadInterval {
+		if err := s.loadFromDB(); err != nil {
+			return nil, err
+		}
+	}
+
+	due := func(key *signingKey) bool {
+		return key == nil || key.Algorithm != alg || time.Since(key.CreatedAt) >= keyRotationInterval()
+	}
+	if due(s.current) {
+		// Another process sharing the key set may have rotated it already
+		if err := s.loadFromDB(); err != nil {
+			return nil, err
+		}
+		if due(s.current) {
+			if err := s.rotate(alg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	current := s.current
+
+	if current.Private == nil {
+		var privatePEM string
+		if err := database.GetDB().QueryRow("SELECT private_key FROM jwt_keys WHERE kid = ?", current.ID).Scan(&privatePEM); err != nil {
+			return nil, fmt.Errorf("failed to load signing key: %w", err)
+		}
+		private, err := parsePrivateKey(privatePEM)
+		if err != nil {
+			return nil, err
+		}
+		current.Private = private
+	}
+
+	return current, nil
+}
+
+// verificationKey returns a key of the set by ID, reloading the set for
+// keys it does not know yet
+func (s *keyStore) verificationKey(kid string) (*signingKey, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	reloadAfter := keyReloadInterval
+	if !ok {
+		reloadAfter = unknownKeyReloadInterval
+	}
+	reload := s.keys == nil || time.Since(s.loadedAt) > reloadAfter
+	if reload {
+		// Claim the reload, so concurrent requests keep using the cached set
+		s.loadedAt = time.Now()
+	}
+	s.mu.Unlock()
+
+	if reload {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		key, ok = s.keys[kid]
+		s.mu.Unlock()
+	}
+
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	return key, nil
+}
+
+// load reloads the key set from its source. The JWKS is fetched without
+// holding the lock.
+func (s *keyStore) load() error {
+	if url := os.Getenv("JWKS_URL"); url != "" {
+		loaded, err := fetchJWKS(url)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.keys, s.loadedAt = loaded, time.Now()
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadFromDB()
+}
+
+// loadFromDB loads the public keys that still verify from the database
+func (s *keyStore) loadFromDB() error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("no signing keys: the database is not open, set JWKS_URL")
+	}
+
+	rows, err := db.Query(`
+		SELECT kid, algorithm, public_key, created_at, retired_at IS NOT NULL
+		FROM jwt_keys WHERE expires_at IS NULL OR expires_at > ?
+		ORDER BY created_at`, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	loaded := map[string]*signingKey{}
+	var current *signingKey
+	for rows.Next() {
+		var publicPEM string
+		key := &signingKey{}
+		if err := rows.Scan(&key.ID, &key.Algorithm, &publicPEM, &key.CreatedAt, &key.Retired); err != nil {
+			return fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		if key.Public, err = parsePublicKey(publicPEM); err != nil {
+			return err
+		}
+		// Keep the private key already loaded for the current key
+		if old, ok := s.keys[key.ID]; ok {
+			key.Private = old.Private
+		}
+		loaded[key.ID] = key
+		if !key.Retired {
+			current = key
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	s.keys, s.current, s.loadedAt = loaded, current, time.Now()
+	return nil
+}
+
+// rotate creates a new signing key and retires the current one. A retired key
+// is still published and verifies tokens for keyOverlap, then it is deleted.
+func (s *keyStore) rotate(alg string) error {
+	private, err := generatePrivateKey(alg)
+	if err != nil {
+		return err
+	}
+	privatePEM, publicPEM, kid, err := encodeKeyPair(private)
+	if err != nil {
+		return err
+	}
+
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE jwt_keys SET retired_at = ?, expires_at = ? WHERE retired_at IS NULL",
+		now, now.Add(keyOverlap())); err != nil {
+		return fmt.Errorf("failed to retire signing key: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM jwt_keys WHERE expires_at < ?", now); err != nil {
+		return fmt.Errorf("failed to delete expired signing keys: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO jwt_keys (kid, algorithm, private_key, public_key, created_at)
+		VALUES (?, ?, ?, ?, ?)`, kid, alg, privatePEM, publicPEM, now); err != nil {
+		return fmt.Errorf("failed to save signing key: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Rotated JWT signing key, now signing with %s key %s", alg, kid)
+	if err := s.loadFromDB(); err != nil {
+		return err
+	}
+	s.current.Private = private
+	return nil
+}
+
+func generatePrivateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		return key, nil
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+}
+
+// encodeKeyPair returns the PEM encoded private (PKCS #8) and public (PKIX)
+// key, and the key ID: a fingerprint of the public key
+func encodeKeyPair(private crypto.Signer) (string, string, string, error) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode private key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	sum := sha256.Sum256(publicDER)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return string(privatePEM), string(publicPEM), hex.EncodeToString(sum[:8]), nil
+}
+
+func parsePrivateKey(privatePEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, errors.New("invalid signing key PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported signing key type")
+	}
+	return signer, nil
+}
+
+func parsePublicKey(publicPEM string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return nil, errors.New("invalid public key PEM")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mangahub/pkg/database"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestKeyStore signs with a fresh key set in a temporary database
+func useTestKeyStore(t *testing.T) {
+	t.Helper()
+	t.Setenv("JWT_SIGNING_ALG", AlgEdDSA)
+	t.Setenv("JWKS_URL", "")
+	require.NoError(t, database.OpenDatabase(filepath.Join(t.TempDir(), "mangahub.db")))
+	t.Cleanup(func() { database.GetDB().Close() })
+	keys = &keyStore{}
+	t.Cleanup(func() { keys = &keyStore{} })
+}
+
+// forceKeyReload makes the next verification read the key set again
+func forceKeyReload() {
+	keys.mu.Lock()
+	keys.loadedAt = time.Time{}
+	keys.mu.Unlock()
+}
+
+func TestTokensVerifyAcrossKeyRotationUntilTheKeyExpires(t *testing.T) {
+	useTestKeyStore(t)
+
+	before, _, err := GenerateToken("user-1", "reader", "reader@example.com", "session-1", nil)
+	require.NoError(t, err)
+	first := keys.current.ID
+
+	keys.mu.Lock()
+	require.NoError(t, keys.rotate(AlgEdDSA))
+	keys.mu.Unlock()
+	after, _, err := GenerateToken("user-1", "reader", "reader@example.com", "session-1", nil)
+	require.NoError(t, err)
+	require.NotEqual(t, first, keys.current.ID)
+
+	// The retired key still verifies during the overlap
+	for _, token := range []string{before, after} {
+		claims, err := ParseAccessToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.UserID)
+	}
+
+	// Once past the overlap it is gone, with the tokens it signed
+	_, err = database.GetDB().Exec("UPDATE jwt_keys SET expires_at = ? WHERE kid = ?", time.Now().Add(-time.Minute), first)
+	require.NoError(t, err)
+	forceKeyReload()
+	_, err = ParseAccessToken(before)
+	assert.ErrorContains(t, err, "unknown signing key")
+	_, err = ParseAccessToken(after)
+	assert.NoError(t, err)
+}
+
+func TestAccessAndActionTokensAreNotInterchangeable(t *testing.T) {
+	useTestKeyStore(t)
+
+	access, _, err := GenerateToken("user-1", "reader", "reader@example.com", "session-1", nil)
+	require.NoError(t, err)
+	for _, purpose := range []string{PurposeVerifyEmail, PurposeResetPassword, PurposeLoginChallenge} {
+		action, err := GenerateActionToken(purpose, "user-1", "binding", time.Hour)
+		require.NoError(t, err)
+		_, err = ParseAccessToken(action)
+		assert.Error(t, err, purpose)
+
+		_, _, err = ParseActionToken(access, purpose)
+		assert.Error(t, err, purpose)
+	}
+
+	// Tokens without the access audience are refused too
+	unscoped, err := signToken(&Claims{
+		UserID:           "user-1",
+		SessionID:        "session-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	require.NoError(t, err)
+	_, err = ParseAccessToken(unscoped)
+	assert.Error(t, err)
+}
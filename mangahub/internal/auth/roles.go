@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"fmt"
+	"mangahub/pkg/database"
+	"sort"
+)
+
+// Roles. Every user has RoleUser; the others are granted by an admin.
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleCurator   = "curator"
+	RoleUser      = "user"
+)
+
+// Permissions checked by the REST middleware and the gRPC interceptor
+const (
+	PermManageManga     = "manga:manage"     // Create, edit, delete and bulk import catalog entries
+	PermModerateReviews = "reviews:moderate" // Review moderation queue
+	PermModerateRatings = "ratings:moderate" // Ratings flagged by the brigading detector
+	PermManageCache     = "cache:manage"     // External API response cache
+	PermManageRoles     = "roles:manage"     // Grant and revoke roles
+	PermManageUsers     = "users:manage"     // Login audit trail and unlocking accounts
+)
+
+// rolePermissions lists what each role may do beyond a regular user
+var rolePermissions = map[string][]string{
+	RoleAdmin:     {PermManageManga, PermModerateReviews, PermModerateRatings, PermManageCache, PermManageRoles, PermManageUsers},
+	RoleModerator: {PermModerateReviews, PermModerateRatings},
+	RoleCurator:   {PermManageManga},
+	RoleUser:      {},
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RolePermissions returns the permissions of a role
+func RolePermissions(role string) []string {
+	return append([]string{}, rolePermissions[role]...)
+}
+
+// HasPermission reports whether any of the roles grants the permission
+func HasPermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetUserRoles returns the user's roles, sorted and always including RoleUser
+func GetUserRoles(userID string) ([]string, error) {
+	rows, err := database.GetDB().Query("SELECT role FROM user_roles WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []string{RoleUser}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan user role: %w", err)
+		}
+		if IsValidRole(role) && role != RoleUser {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+
+	return roles, nil
+}
+
+// sameRoles reports whether two sorted role lists are equal
+func sameRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mangahub/pkg/database"
+	"mangahub/pkg/utils"
+	"strings"
+	"time"
+)
+
+// Default token lifetimes: access tokens are short-lived and renewed with the
+// refresh token, which stays valid as long as it is used within its lifetime
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// AccessTokenTTL returns the lifetime of access tokens (ACCESS_TOKEN_TTL)
+func AccessTokenTTL() time.Duration {
+	return utils.DurationFromEnv("ACCESS_TOKEN_TTL", defaultAccessTokenTTL)
+}
+
+// RefreshTokenTTL returns how long a session lasts without being refreshed (REFRESH_TOKEN_TTL)
+func RefreshTokenTTL() time.Duration {
+	return utils.DurationFromEnv("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL)
+}
+
+// GenerateRefreshToken returns a new random refresh token. Only its hash (see
+// HashToken) is stored.
+func GenerateRefreshToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken returns the hash under which a refresh token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateRequestToken checks the bearer token of a request on a service with
+// the database. Access tokens are checked against the signing key set, then
+// their session, so revoked sessions stop working at once. Personal access
+// tokens only exist in the database.
+func ValidateRequestToken(token string) (*Claims, error) {
+	if strings.HasPrefix(token, APITokenPrefix) {
+		return ValidateAPIToken(token)
+	}
+
+	claims, err := ParseAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckSession(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// CheckSession fails when the session of a parsed access token was revoked or
+// has expired, or the user's roles changed since the token was issued; the
+// client gets a token with the new roles by refreshing
+func CheckSession(claims *Claims) error {
+	if claims.SessionID == "" {
+		return errors.New("invalid token: no session")
+	}
+
+	var revokedAt sql.NullTime
+	var expiresAt time.Time
+	err := database.GetDB().QueryRow("SELECT revoked_at, expires_at FROM sessions WHERE id = ? AND user_id = ?",
+		claims.SessionID, claims.UserID).Scan(&revokedAt, &expiresAt)
+	if err == sql.ErrNoRows {
+		return errors.New("invalid token: session not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check session: %w", err)
+	}
+	if revokedAt.Valid {
+		return errors.New("invalid token: session revoked")
+	}
+	if time.Now().After(expiresAt) {
+		return errors.New("invalid token: session expired")
+	}
+
+	roles, err := GetUserRoles(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if !sameRoles(roles, claims.Roles) {
+		return errors.New("invalid token: roles changed")
+	}
+	return nil
+}
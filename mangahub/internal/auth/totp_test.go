@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The SHA-1 test vectors of RFC 6238 (appendix B), cut to the six digits used here
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for _, vector := range rfc6238Vectors {
+		assert.Equal(t, vector.code, totpCode(key, vector.unix/totpPeriod), "t=%d", vector.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	at := time.Unix(1111111111, 0)
+
+	step, ok := ValidateTOTP(secret, "050 471", at)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/totpPeriod), step)
+
+	// Codes of the neighbouring periods are accepted for clock drift, with their own step
+	step, ok = ValidateTOTP(secret, "050471", at.Add(totpPeriod*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/totpPeriod), step)
+
+	_, ok = ValidateTOTP(secret, "050471", at.Add(time.Duration(totpSkew+1)*totpPeriod*time.Second))
+	assert.False(t, ok)
+	_, ok = ValidateTOTP(secret, "050472", at)
+	assert.False(t, ok)
+	_, ok = ValidateTOTP(secret, "50471", at)
+	assert.False(t, ok)
+	_, ok = ValidateTOTP("not base32!", "050471", at)
+	assert.False(t, ok)
+}
@@ -0,0 +1,468 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mangahub/pkg/database"
+	"mangahub/pkg/utils"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache status values reported in the X-Cache response header
+const (
+	CacheHit         = "HIT"
+	CacheMiss        = "MISS"
+	CacheStale       = "STALE"
+	CacheRevalidated = "REVALIDATED"
+)
+
+// maxCachedBodySize limits how large a cached response body may be
+const maxCachedBodySize = 5 << 20
+
+// cacheSweepInterval is how often each transport deletes unusable entries
+const cacheSweepInterval = time.Hour
+
+// CacheRule assigns a TTL to requests whose URL path matches Pattern
+type CacheRule struct {
+	Pattern *regexp.Regexp
+	TTL     time.Duration
+}
+
+// CachePolicy is the ordered list of cache rules for one upstream API.
+// The first matching rule wins; requests matching no rule are not cached.
+type CachePolicy []CacheRule
+
+// ttlFor returns the TTL for a request path
+func (p CachePolicy) ttlFor(path string) (time.Duration, bool) {
+	for _, rule := range p {
+		if rule.Pattern.MatchString(path) {
+			return rule.TTL, rule.TTL > 0
+		}
+	}
+	return 0, false
+}
+
+// maxTTL returns the longest TTL of any rule
+func (p CachePolicy) maxTTL() time.Duration {
+	var longest time.Duration
+	for _, rule := range p {
+		if rule.TTL > longest {
+			longest = rule.TTL
+		}
+	}
+	return longest
+}
+
+// Cache policies for the external APIs
+var (
+	jikanCachePolicy = CachePolicy{
+		{regexp.MustCompile(`/manga/\d+/recommendations$`), 24 * time.Hour},
+		{regexp.MustCompile(`/manga/\d+$`), 24 * time.Hour},
+		{regexp.MustCompile(`/top/manga$`), 6 * time.Hour},
+		{regexp.MustCompile(`/manga$`), time.Hour},
+	}
+
+	malCachePolicy = CachePolicy{
+		{regexp.MustCompile(`/manga/ranking$`), 6 * time.Hour},
+		{regexp.MustCompile(`/manga/\d+$`), 24 * time.Hour},
+		{regexp.MustCompile(`/manga$`), time.Hour},
+	}
+
+	mangaDexCachePolicy = CachePolicy{
+		// at-home server URLs are short-lived tokens and must never be cached
+		{regexp.MustCompile(`/at-home/`), 0},
+		{regexp.MustCompile(`/manga/[^/]+/feed$`), 30 * time.Minute},
+		{regexp.MustCompile(`/manga/[^/]+$`), 12 * time.Hour},
+		{regexp.MustCompile(`/manga$`), time.Hour},
+	}
+)
+
+// cacheEntry is a stored upstream response
+type cacheEntry struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified string
+	FetchedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// CachingTransport serves GET requests from the SQLite response cache.
+// Fresh entries are returned directly; entries within the stale window are
+// returned immediately while being refreshed in the background; older entries
+// are revalidated with If-None-Match/If-Modified-Since. If the upstream fails,
+// a stale entry is served for up to StaleIfError. Entries past every window
+// are deleted on lookup and by an hourly sweep of the namespace.
+type CachingTransport struct {
+	Next         http.RoundTripper
+	Namespace    string
+	Policy       CachePolicy
+	StaleIfError time.Duration
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+	lastSweep  time.Time
+}
+
+// NewCachedHTTPClient creates an HTTP client backed by the shared resilient
+// transport with a persistent response cache in front of it. The namespace
+// also names the service whose rate limits and breaker requests go through.
+func NewCachedHTTPClient(timeout time.Duration, namespace string, policy CachePolicy) *http.Client {
+	if os.Getenv("HTTP_CACHE_ENABLED") == "false" {
+		return NewHTTPClient(timeout, namespace)
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &CachingTransport{
+			Next:         SharedTransport().ForService(namespace),
+			Namespace:    namespace,
+			Policy:       policy,
+			StaleIfError: utils.DurationFromEnv("HTTP_CACHE_STALE_IF_ERROR", 24*time.Hour),
+			refreshing:   make(map[string]bool),
+		},
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	db := database.GetDB()
+	ttl, cacheable := t.Policy.ttlFor(req.URL.Path)
+	if db == nil || !cacheable || req.Method != http.MethodGet ||
+		req.Header.Get("Authorization") != "" || req.Header.Get("Cache-Control") == "no-cache" {
+		return t.Next.RoundTrip(req)
+	}
+
+	key := cacheKey(t.Namespace, req)
+	entry, err := loadCacheEntry(db, key)
+	if err != nil {
+		log.Printf("Response cache lookup failed for %s: %v", req.URL, err)
+		return t.Next.RoundTrip(req)
+	}
+
+	now := time.Now()
+	t.sweepInBackground(db, now)
+	if entry != nil && !now.Before(entry.ExpiresAt.Add(t.retention())) {
+		// Too old to serve in any case
+		if err := deleteCacheEntry(db, key); err != nil {
+			log.Printf("Failed to delete expired cache entry for %s: %v", req.URL, err)
+		}
+		entry = nil
+	}
+	if entry != nil {
+		// Fresh
+		if now.Before(entry.ExpiresAt) {
+			return entry.response(req, CacheHit), nil
+		}
+		// Stale but within the stale-while-revalidate window (one extra TTL)
+		if now.Before(entry.ExpiresAt.Add(ttl)) {
+			t.refreshInBackground(db, key, ttl, req, entry)
+			return entry.response(req, CacheStale), nil
+		}
+	}
+
+	resp, status, err := t.fetch(db, key, ttl, req, entry)
+	if err != nil || resp.StatusCode >= 500 {
+		// Serve stale content during upstream outages
+		if entry != nil && now.Before(entry.ExpiresAt.Add(t.StaleIfError)) {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			log.Printf("Serving stale cached response for %s (upstream unavailable)", req.URL)
+			return entry.response(req, CacheStale), nil
+		}
+		return resp, err
+	}
+
+	resp.Header.Set("X-Cache", status)
+	return resp, nil
+}
+
+// fetch performs a conditional request upstream and updates the cache
+func (t *CachingTransport) fetch(db *sql.DB, key string, ttl time.Duration, req *http.Request, entry *cacheEntry) (*http.Response, string, error) {
+	upstreamReq := req
+	if entry != nil && (entry.ETag != "" || entry.LastModified != "") {
+		upstreamReq = req.Clone(req.Context())
+		if entry.ETag != "" {
+			upstreamReq.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			upstreamReq.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(upstreamReq)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Not modified: extend the existing entry
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.FetchedAt = time.Now()
+		entry.ExpiresAt = entry.FetchedAt.Add(ttl)
+		if err := touchCacheEntry(db, key, entry); err != nil {
+			log.Printf("Failed to refresh cache entry for %s: %v", req.URL, err)
+		}
+		return entry.response(req, CacheRevalidated), CacheRevalidated, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, CacheMiss, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodySize+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if len(body) <= maxCachedBodySize {
+		fetchedAt := time.Now()
+		newEntry := &cacheEntry{
+			StatusCode:   resp.StatusCode,
+			Header:       resp.Header.Clone(),
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    fetchedAt,
+			ExpiresAt:    fetchedAt.Add(ttl),
+		}
+		if err := storeCacheEntry(db, key, t.Namespace, req.URL.String(), newEntry); err != nil {
+			log.Printf("Failed to store cache entry for %s: %v", req.URL, err)
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, CacheMiss, nil
+}
+
+// refreshInBackground revalidates a stale entry without blocking the caller
+func (t *CachingTransport) refreshInBackground(db *sql.DB, key string, ttl time.Duration, req *http.Request, entry *cacheEntry) {
+	t.mu.Lock()
+	if t.refreshing[key] {
+		t.mu.Unlock()
+		return
+	}
+	t.refreshing[key] = true
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	bgReq := req.Clone(ctx)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			t.mu.Lock()
+			delete(t.refreshing, key)
+			t.mu.Unlock()
+		}()
+
+		resp, _, err := t.fetch(db, key, ttl, bgReq, entry)
+		if err != nil {
+			log.Printf("Background cache refresh failed for %s: %v", bgReq.URL, err)
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+// retention is how long past its expiry an entry can still be served: one
+// extra TTL for stale-while-revalidate, or StaleIfError during outages
+func (t *CachingTransport) retention() time.Duration {
+	if ttl := t.Policy.maxTTL(); ttl > t.StaleIfError {
+		return ttl
+	}
+	return t.StaleIfError
+}
+
+// sweepInBackground deletes the namespace's unusable entries at most once per
+// cacheSweepInterval
+func (t *CachingTransport) sweepInBackground(db *sql.DB, now time.Time) {
+	t.mu.Lock()
+	if now.Sub(t.lastSweep) < cacheSweepInterval {
+		t.mu.Unlock()
+		return
+	}
+	t.lastSweep = now
+	t.mu.Unlock()
+
+	cutoff := now.Add(-t.retention()).UTC()
+	go func() {
+		result, err := db.Exec(`DELETE FROM http_cache WHERE namespace = ? AND expires_at < ?`, t.Namespace, cutoff)
+		if err != nil {
+			log.Printf("Failed to sweep %s response cache: %v", t.Namespace, err)
+			return
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			log.Printf("Swept %d expired %s response cache entries", n, t.Namespace)
+		}
+	}()
+}
+
+// response builds an *http.Response from a cache entry
+func (e *cacheEntry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("X-Cache", status)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a request within a namespace
+func cacheKey(namespace string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(namespace + " " + req.Method + " " + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func loadCacheEntry(db *sql.DB, key string) (*cacheEntry, error) {
+	var entry cacheEntry
+	var headerJSON string
+	err := db.QueryRow(`
+		SELECT status_code, headers, body, etag, last_modified, fetched_at, expires_at
+		FROM http_cache WHERE cache_key = ?
+	`, key).Scan(&entry.StatusCode, &headerJSON, &entry.Body, &entry.ETag, &entry.LastModified,
+		&entry.FetchedAt, &entry.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if headerJSON != "" {
+		if err := json.Unmarshal([]byte(headerJSON), &entry.Header); err != nil {
+			return nil, fmt.Errorf("failed to decode cached headers: %w", err)
+		}
+	}
+	return &entry, nil
+}
+
+func storeCacheEntry(db *sql.DB, key, namespace, url string, entry *cacheEntry) error {
+	headerJSON, err := json.Marshal(entry.Header)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		INSERT INTO http_cache (cache_key, namespace, url, status_code, headers, body, etag, last_modified, fetched_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cache_key) DO UPDATE SET
+			status_code = excluded.status_code,
+			headers = excluded.headers,
+			body = excluded.body,
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			fetched_at = excluded.fetched_at,
+			expires_at = excluded.expires_at
+	`, key, namespace, url, entry.StatusCode, string(headerJSON), entry.Body, entry.ETag, entry.LastModified,
+		entry.FetchedAt.UTC(), entry.ExpiresAt.UTC())
+	return err
+}
+
+func touchCacheEntry(db *sql.DB, key string, entry *cacheEntry) error {
+	_, err := db.Exec(`UPDATE http_cache SET fetched_at = ?, expires_at = ? WHERE cache_key = ?`,
+		entry.FetchedAt.UTC(), entry.ExpiresAt.UTC(), key)
+	return err
+}
+
+func deleteCacheEntry(db *sql.DB, key string) error {
+	_, err := db.Exec(`DELETE FROM http_cache WHERE cache_key = ?`, key)
+	return err
+}
+
+// ResponseCacheStats summarizes the cache contents per namespace
+type ResponseCacheStats struct {
+	Namespace string `json:"namespace"`
+	Entries   int    `json:"entries"`
+	Expired   int    `json:"expired"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// GetResponseCacheStats returns per-namespace cache statistics
+func GetResponseCacheStats() ([]ResponseCacheStats, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	rows, err := db.Query(`
+		SELECT namespace, COUNT(*),
+			COALESCE(SUM(CASE WHEN expires_at < ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(LENGTH(body)), 0)
+		FROM http_cache
+		GROUP BY namespace
+		ORDER BY namespace
+	`, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cache stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []ResponseCacheStats{}
+	for rows.Next() {
+		var s ResponseCacheStats
+		if err := rows.Scan(&s.Namespace, &s.Entries, &s.Expired, &s.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to scan cache stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// PurgeResponseCache deletes cached responses. An empty namespace matches all
+// namespaces, urlPrefix restricts the purge to matching URLs, and expiredOnly
+// keeps entries that are still fresh.
+func PurgeResponseCache(namespace, urlPrefix string, expiredOnly bool) (int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+	if namespace != "" {
+		conditions = append(conditions, "namespace = ?")
+		args = append(args, namespace)
+	}
+	if urlPrefix != "" {
+		conditions = append(conditions, "url LIKE ? ESCAPE '\\'")
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(urlPrefix)
+		args = append(args, escaped+"%")
+	}
+	if expiredOnly {
+		conditions = append(conditions, "expires_at < ?")
+		args = append(args, time.Now().UTC())
+	}
+
+	result, err := db.Exec("DELETE FROM http_cache WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge response cache: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -31,8 +31,6 @@ func getJikanRateLimit() time.Duration {
 	return time.Second // Default: 1 request per second
 }
 
-var lastRequestTime time.Time
-
 // JikanClient handles requests to the Jikan API (MyAnimeList unofficial API)
 type JikanClient struct {
 	BaseURL    string
@@ -41,11 +39,14 @@ type JikanClient struct {
 
 // NewJikanClient creates a new Jikan API client
 func NewJikanClient() *JikanClient {
+	baseURL := getJikanBaseURL()
+
+	// Pacing is handled per service and host by the shared transport
+	SharedTransport().SetServiceRateLimit("jikan", baseURL, 1/getJikanRateLimit().Seconds(), 1)
+
 	return &JikanClient{
-		BaseURL: getJikanBaseURL(),
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		BaseURL:    baseURL,
+		HTTPClient: NewCachedHTTPClient(10*time.Second, "jikan", jikanCachePolicy),
 	}
 }
 
@@ -182,16 +183,6 @@ type JikanPaginationItems struct {
 	PerPage int `json:"per_page"`
 }
 
-// respectRateLimit ensures we don't exceed API rate limits
-func (c *JikanClient) respectRateLimit() {
-	rateLimit := getJikanRateLimit()
-	elapsed := time.Since(lastRequestTime)
-	if elapsed < rateLimit {
-		time.Sleep(rateLimit - elapsed)
-	}
-	lastRequestTime = time.Now()
-}
-
 // SearchManga searches for manga by query
 func (c *JikanClient) SearchManga(query string, page int, limit int) (*JikanMangaResponse, error) {
 	return c.SearchMangaWithSort(query, page, limit, "popularity", "asc")
@@ -199,8 +190,6 @@ func (c *JikanClient) SearchManga(query string, page int, limit int) (*JikanMang
 
 // SearchMangaWithSort searches for manga by query with sorting options
 func (c *JikanClient) SearchMangaWithSort(query string, page int, limit int, orderBy string, sort string) (*JikanMangaResponse, error) {
-	c.respectRateLimit()
-
 	if limit <= 0 {
 		limit = 10
 	}
@@ -246,8 +235,6 @@ func (c *JikanClient) SearchMangaWithSort(query string, page int, limit int, ord
 
 // GetMangaByID retrieves a specific manga by MAL ID
 func (c *JikanClient) GetMangaByID(malID int) (*JikanManga, error) {
-	c.respectRateLimit()
-
 	url := fmt.Sprintf("%s/manga/%d", c.BaseURL, malID)
 
 	resp, err := c.HTTPClient.Get(url)
@@ -280,8 +267,6 @@ func (c *JikanClient) GetTopManga(page int, limit int) (*JikanMangaResponse, err
 // orderBy: "mal_id", "title", "start_date", "end_date", "chapters", "volumes", "score", "scored_by", "rank", "popularity", "members", "favorites"
 // sort: "asc" or "desc"
 func (c *JikanClient) GetMangaWithSort(page int, limit int, orderBy string, sort string) (*JikanMangaResponse, error) {
-	c.respectRateLimit()
-
 	if limit <= 0 {
 		limit = 10
 	}
@@ -334,8 +319,6 @@ func (c *JikanClient) GetMangaWithSort(page int, limit int, orderBy string, sort
 
 // GetMangaRecommendations gets manga recommendations by MAL ID
 func (c *JikanClient) GetMangaRecommendations(malID int) ([]JikanManga, error) {
-	c.respectRateLimit()
-
 	url := fmt.Sprintf("%s/manga/%d/recommendations", c.BaseURL, malID)
 
 	resp, err := c.HTTPClient.Get(url)
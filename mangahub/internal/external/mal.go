@@ -15,9 +15,19 @@ type MALClient struct {
 	ClientID     string
 	ClientSecret string
 	BaseURL      string
+	OAuthBaseURL string
 	HTTPClient   *http.Client
 }
 
+// getMALBaseURL returns the MAL API base URL from environment or default
+func getMALBaseURL() string {
+	baseURL := os.Getenv("MAL_API_BASE_URL")
+	if baseURL == "" {
+		return "https://api.myanimelist.net/v2" // Default
+	}
+	return baseURL
+}
+
 // NewMALClient creates a new official MAL API client
 func NewMALClient() *MALClient {
 	clientID := os.Getenv("MAL_CLIENT_ID")
@@ -26,10 +36,9 @@ func NewMALClient() *MALClient {
 	return &MALClient{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
-		BaseURL:      "https://api.myanimelist.net/v2",
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		BaseURL:      getMALBaseURL(),
+		OAuthBaseURL: getMALOAuthBaseURL(),
+		HTTPClient:   NewCachedHTTPClient(10*time.Second, "mal", malCachePolicy),
 	}
 }
 
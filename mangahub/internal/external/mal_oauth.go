@@ -0,0 +1,273 @@
+package external
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getMALOAuthBaseURL returns the MAL OAuth2 base URL from environment or default
+func getMALOAuthBaseURL() string {
+	baseURL := os.Getenv("MAL_OAUTH_BASE_URL")
+	if baseURL == "" {
+		return "https://myanimelist.net/v1/oauth2" // Default
+	}
+	return baseURL
+}
+
+// getMALChallengeMethod returns the PKCE code challenge method.
+// MyAnimeList currently only accepts "plain".
+func getMALChallengeMethod() string {
+	if method := os.Getenv("MAL_OAUTH_CHALLENGE_METHOD"); method == "S256" {
+		return method
+	}
+	return "plain"
+}
+
+// MALToken represents an OAuth2 token pair issued by MAL
+type MALToken struct {
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"-"`
+}
+
+// MALUser represents the authenticated MAL user
+type MALUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// MALListStatus represents an entry's status on the user's MAL list
+type MALListStatus struct {
+	Status          string `json:"status"` // reading, completed, on_hold, dropped, plan_to_read
+	IsRereading     bool   `json:"is_rereading"`
+	NumVolumesRead  int    `json:"num_volumes_read"`
+	NumChaptersRead int    `json:"num_chapters_read"`
+	Score           int    `json:"score"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// UpdatedTime parses UpdatedAt, returning the zero time if it is missing
+func (s *MALListStatus) UpdatedTime() time.Time {
+	t, err := time.Parse(time.RFC3339, s.UpdatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// MALUserMangaListResponse represents the user's manga list
+type MALUserMangaListResponse struct {
+	Data []struct {
+		Node       MALMangaNode  `json:"node"`
+		ListStatus MALListStatus `json:"list_status"`
+	} `json:"data"`
+	Paging MALPaging `json:"paging"`
+}
+
+// MALPKCE holds a PKCE verifier and the matching challenge
+type MALPKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewMALPKCE generates a PKCE verifier (43-128 chars) and its challenge
+func NewMALPKCE() (*MALPKCE, error) {
+	buf := make([]byte, 64)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+
+	method := getMALChallengeMethod()
+	challenge := verifier
+	if method == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return &MALPKCE{Verifier: verifier, Challenge: challenge, Method: method}, nil
+}
+
+// AuthorizationURL builds the URL the user is sent to in order to approve account linking
+func (c *MALClient) AuthorizationURL(state string, pkce *MALPKCE, redirectURI string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.ClientID)
+	params.Set("state", state)
+	params.Set("code_challenge", pkce.Challenge)
+	params.Set("code_challenge_method", pkce.Method)
+	if redirectURI != "" {
+		params.Set("redirect_uri", redirectURI)
+	}
+	return fmt.Sprintf("%s/authorize?%s", c.OAuthBaseURL, params.Encode())
+}
+
+// ExchangeCode exchanges an authorization code for a token pair
+func (c *MALClient) ExchangeCode(code, verifier, redirectURI string) (*MALToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", verifier)
+	if redirectURI != "" {
+		form.Set("redirect_uri", redirectURI)
+	}
+	return c.requestToken(form)
+}
+
+// RefreshToken obtains a new token pair using a refresh token
+func (c *MALClient) RefreshToken(refreshToken string) (*MALToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	return c.requestToken(form)
+}
+
+// requestToken calls the OAuth2 token endpoint
+func (c *MALClient) requestToken(form url.Values) (*MALToken, error) {
+	if !c.IsConfigured() {
+		return nil, fmt.Errorf("MAL API not configured: please set MAL_CLIENT_ID in .env")
+	}
+
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequest("POST", c.OAuthBaseURL+"/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token MALToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint returned no access token")
+	}
+	token.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	return &token, nil
+}
+
+// GetMyUserInfo returns the MAL user the access token belongs to
+func (c *MALClient) GetMyUserInfo(accessToken string) (*MALUser, error) {
+	var user MALUser
+	if err := c.doUserRequest("GET", c.BaseURL+"/users/@me", accessToken, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetMyMangaList retrieves one page of the authenticated user's manga list
+func (c *MALClient) GetMyMangaList(accessToken string, limit, offset int) (*MALUserMangaListResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+
+	params := url.Values{}
+	params.Add("fields", "list_status,num_chapters")
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("nsfw", "true")
+	if offset > 0 {
+		params.Add("offset", strconv.Itoa(offset))
+	}
+
+	var result MALUserMangaListResponse
+	fullURL := fmt.Sprintf("%s/users/@me/mangalist?%s", c.BaseURL, params.Encode())
+	if err := c.doUserRequest("GET", fullURL, accessToken, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetMyListStatus returns the user's list status for one manga, or nil if it is not on the list
+func (c *MALClient) GetMyListStatus(accessToken string, malID int) (*MALListStatus, error) {
+	var result struct {
+		MyListStatus *MALListStatus `json:"my_list_status"`
+	}
+	fullURL := fmt.Sprintf("%s/manga/%d?fields=my_list_status", c.BaseURL, malID)
+	if err := c.doUserRequest("GET", fullURL, accessToken, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.MyListStatus, nil
+}
+
+// UpdateMyListStatus creates or updates a manga on the user's MAL list
+func (c *MALClient) UpdateMyListStatus(accessToken string, malID int, status MALListStatus) (*MALListStatus, error) {
+	form := url.Values{}
+	form.Set("status", status.Status)
+	form.Set("is_rereading", strconv.FormatBool(status.IsRereading))
+	form.Set("num_chapters_read", strconv.Itoa(status.NumChaptersRead))
+	if status.Score > 0 {
+		form.Set("score", strconv.Itoa(status.Score))
+	}
+
+	var result MALListStatus
+	fullURL := fmt.Sprintf("%s/manga/%d/my_list_status", c.BaseURL, malID)
+	if err := c.doUserRequest("PATCH", fullURL, accessToken, form, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// doUserRequest performs an authenticated request on behalf of a user
+func (c *MALClient) doUserRequest(method, fullURL, accessToken string, form url.Values, out interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequest(method, fullURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("MAL access token unauthorized")
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
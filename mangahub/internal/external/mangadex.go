@@ -38,12 +38,10 @@ func NewMangaDexClient() *MangaDexClient {
 	apiKey := os.Getenv("MANGADEX_API_KEY")
 
 	return &MangaDexClient{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		HTTPClient: &http.Client{
-			Timeout: timeout,
-		},
-		Debug: debug,
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		HTTPClient: NewCachedHTTPClient(timeout, "mangadex", mangaDexCachePolicy),
+		Debug:      debug,
 	}
 }
 
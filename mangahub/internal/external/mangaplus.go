@@ -35,11 +35,9 @@ func NewMangaPlusClient() *MangaPlusClient {
 	debug := os.Getenv("MANGAPLUS_DEBUG") == "true"
 
 	return &MangaPlusClient{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: timeout,
-		},
-		Debug: debug,
+		BaseURL:    baseURL,
+		HTTPClient: NewHTTPClient(timeout, "mangaplus"),
+		Debug:      debug,
 	}
 }
 
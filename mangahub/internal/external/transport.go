@@ -0,0 +1,529 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mangahub/pkg/utils"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a host has failed too often and requests are
+// short-circuited until the cooldown expires
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Default per-host request rates (requests per second, burst).
+// Hosts not listed here use defaultHostLimit.
+var knownHostLimits = map[string]hostLimit{
+	"api.jikan.moe":              {rate: 1, burst: 1},
+	"api.mangadex.org":           {rate: 5, burst: 5},
+	"api.myanimelist.net":        {rate: 2, burst: 2},
+	"jumpg-webapi.tokyo-cdn.com": {rate: 2, burst: 2},
+}
+
+var defaultHostLimit = hostLimit{rate: 5, burst: 5}
+
+// maxUpstreamWait is the longest pause a Retry-After or rate limit header can impose
+const maxUpstreamWait = 5 * time.Minute
+
+type hostLimit struct {
+	rate  float64
+	burst int
+}
+
+// TransportConfig controls retry and circuit breaker behaviour of the shared transport
+type TransportConfig struct {
+	MaxRetries       int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// loadTransportConfig reads transport settings from environment or defaults
+func loadTransportConfig() TransportConfig {
+	cfg := TransportConfig{
+		MaxRetries:       3,
+		BaseBackoff:      500 * time.Millisecond,
+		MaxBackoff:       30 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("HTTP_MAX_RETRIES")); err == nil && v >= 0 {
+		cfg.MaxRetries = v
+	}
+	cfg.BaseBackoff = utils.DurationFromEnv("HTTP_RETRY_BASE_DELAY", cfg.BaseBackoff)
+	if v, err := strconv.Atoi(os.Getenv("HTTP_BREAKER_THRESHOLD")); err == nil && v > 0 {
+		cfg.BreakerThreshold = v
+	}
+	cfg.BreakerCooldown = utils.DurationFromEnv("HTTP_BREAKER_COOLDOWN", cfg.BreakerCooldown)
+
+	return cfg
+}
+
+// tokenBucket is a simple token bucket rate limiter that can additionally be
+// paused until a point in time announced by the upstream (Retry-After etc.)
+type tokenBucket struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newTokenBucket(limit hostLimit) *tokenBucket {
+	return &tokenBucket{
+		rate:   limit.rate,
+		burst:  float64(limit.burst),
+		tokens: float64(limit.burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// blockUntil pauses the bucket until the given time
+func (b *tokenBucket) blockUntil(t time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t.After(b.blockedUntil) {
+		b.blockedUntil = t
+	}
+}
+
+// setLimit replaces the rate and burst of the bucket
+func (b *tokenBucket) setLimit(limit hostLimit) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rate = limit.rate
+	b.burst = float64(limit.burst)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// circuitBreaker opens after a number of consecutive failures and lets a single
+// probe request through once the cooldown has passed
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether a request may be sent
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(cb.openUntil) || cb.probing {
+		return false
+	}
+	// Half-open: allow one probe through
+	cb.probing = true
+	return true
+}
+
+func (cb *circuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+	cb.openUntil = time.Time{}
+	cb.probing = false
+}
+
+// release gives up a half-open probe slot without recording an outcome
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+}
+
+// recordFailure returns true if this failure opened the breaker
+func (cb *circuitBreaker) recordFailure(threshold int, cooldown time.Duration) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures++
+	if cb.probing || cb.failures >= threshold {
+		cb.openUntil = time.Now().Add(cooldown)
+		cb.probing = false
+		return true
+	}
+	return false
+}
+
+// hostState groups the limiter and breaker of a single upstream host
+type hostState struct {
+	bucket  *tokenBucket
+	breaker *circuitBreaker
+}
+
+// upstreamKey identifies the limiter and breaker a request goes through. The
+// service keeps APIs apart that share a host, as all of them do behind the
+// fake upstream server in OFFLINE mode.
+type upstreamKey struct {
+	service string
+	host    string
+}
+
+func (k upstreamKey) String() string {
+	if k.service == "" {
+		return k.host
+	}
+	return k.service + " (" + k.host + ")"
+}
+
+// ResilientTransport is an http.RoundTripper shared by all external API clients.
+// It applies per-host rate limits, retries 429/5xx responses with jittered
+// exponential backoff and fails fast through a per-host circuit breaker.
+// Clients of a service send through ForService, which keeps the limits and
+// breaker of each service separate even on the same host.
+type ResilientTransport struct {
+	Base   http.RoundTripper
+	Config TransportConfig
+
+	mu    sync.Mutex
+	hosts map[upstreamKey]*hostState
+}
+
+// serviceTransport sends requests through the shared transport on behalf of one service
+type serviceTransport struct {
+	transport *ResilientTransport
+	service   string
+}
+
+func (t *serviceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport.roundTrip(t.service, req)
+}
+
+var (
+	sharedTransport     *ResilientTransport
+	sharedTransportOnce sync.Once
+)
+
+// SharedTransport returns the process-wide resilient transport
+func SharedTransport() *ResilientTransport {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = &ResilientTransport{
+			Base:   http.DefaultTransport,
+			Config: loadTransportConfig(),
+			hosts:  make(map[upstreamKey]*hostState),
+		}
+	})
+	return sharedTransport
+}
+
+// NewHTTPClient creates an HTTP client for the named service backed by the
+// shared resilient transport
+func NewHTTPClient(timeout time.Duration, service string) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: SharedTransport().ForService(service),
+	}
+}
+
+// ForService returns a round tripper that sends through the transport with
+// rate limits and a circuit breaker of the service's own
+func (t *ResilientTransport) ForService(service string) http.RoundTripper {
+	return &serviceTransport{transport: t, service: service}
+}
+
+// SetServiceRateLimit overrides the request rate of the service at the host of the given base URL
+func (t *ResilientTransport) SetServiceRateLimit(service, baseURL string, requestsPerSecond float64, burst int) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" || requestsPerSecond <= 0 {
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	t.host(upstreamKey{service: service, host: u.Host}).bucket.setLimit(hostLimit{rate: requestsPerSecond, burst: burst})
+}
+
+// host returns (creating if needed) the state for a service at a host
+func (t *ResilientTransport) host(key upstreamKey) *hostState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, ok := t.hosts[key]; ok {
+		return state
+	}
+
+	limit, ok := knownHostLimits[strings.ToLower(hostname(key.host))]
+	if !ok {
+		limit = defaultHostLimit
+	}
+	state := &hostState{
+		bucket:  newTokenBucket(limit),
+		breaker: &circuitBreaker{},
+	}
+	t.hosts[key] = state
+	return state
+}
+
+// RoundTrip implements http.RoundTripper for requests that belong to no service
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.roundTrip("", req)
+}
+
+func (t *ResilientTransport) roundTrip(service string, req *http.Request) (*http.Response, error) {
+	key := upstreamKey{service: service, host: req.URL.Host}
+	state := t.host(key)
+	ctx := req.Context()
+
+	if !state.breaker.allow() {
+		return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, key)
+	}
+
+	// Requests with a body can only be replayed when the body can be recreated
+	maxRetries := t.Config.MaxRetries
+	if req.Body != nil && req.GetBody == nil {
+		maxRetries = 0
+	}
+
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		if err := sleepContext(ctx, state.bucket.reserve()); err != nil {
+			state.breaker.release()
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				state.breaker.release()
+				return nil, bodyErr
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err = t.Base.RoundTrip(attemptReq)
+		if resp != nil {
+			t.applyRateLimitHeaders(state, resp)
+		}
+
+		if !shouldRetry(req, resp, err) || ctx.Err() != nil {
+			break
+		}
+		if attempt >= maxRetries {
+			break
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header); ok && retryAfter > wait {
+				wait = retryAfter
+			}
+			// Discard the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		log.Printf("External API %s %s failed (%s), retrying in %v (attempt %d/%d)",
+			req.Method, key, describeFailure(resp, err), wait, attempt+1, maxRetries)
+
+		if err := sleepContext(ctx, wait); err != nil {
+			state.breaker.release()
+			return nil, err
+		}
+	}
+
+	if isFailure(resp, err) {
+		if state.breaker.recordFailure(t.Config.BreakerThreshold, t.Config.BreakerCooldown) {
+			log.Printf("Circuit breaker opened for %s for %v", key, t.Config.BreakerCooldown)
+		}
+	} else {
+		state.breaker.recordSuccess()
+	}
+
+	return resp, err
+}
+
+// applyRateLimitHeaders pauses the host bucket when the upstream reports that
+// the quota is exhausted
+func (t *ResilientTransport) applyRateLimitHeaders(state *hostState, resp *http.Response) {
+	now := time.Now()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if wait, ok := parseRetryAfter(resp.Header); ok {
+			state.bucket.blockUntil(now.Add(wait))
+		}
+	}
+
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		if n, err := strconv.Atoi(remaining); err == nil && n <= 0 {
+			if reset, ok := parseRateLimitReset(resp.Header); ok {
+				state.bucket.blockUntil(now.Add(reset))
+			}
+		}
+	}
+}
+
+// backoff returns a jittered exponential backoff for the given attempt
+func (t *ResilientTransport) backoff(attempt int) time.Duration {
+	d := t.Config.BaseBackoff * time.Duration(1<<uint(attempt))
+	if d > t.Config.MaxBackoff || d <= 0 {
+		d = t.Config.MaxBackoff
+	}
+	// Full jitter between 50% and 100% of the computed delay
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// shouldRetry reports whether the request is worth retrying. Non-idempotent
+// requests (MAL list updates) may already have been applied after a 5xx or a
+// broken connection, so they are only retried when the upstream cannot have
+// processed them: the connection was never made, or it answered 429.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return isIdempotent(req.Method) || neverSent(err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500 && isIdempotent(req.Method)
+}
+
+// isIdempotent reports whether repeating a request with the method has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// neverSent reports whether the request failed before reaching the upstream
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isFailure reports whether the final outcome counts against the circuit breaker
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= 500
+}
+
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("status %d", resp.StatusCode)
+}
+
+// parseRetryAfter reads Retry-After (seconds or HTTP date) and the MangaDex
+// specific X-RateLimit-Retry-After (unix timestamp)
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs >= 0 {
+			return clampWait(time.Duration(secs) * time.Second), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return clampWait(time.Until(t)), true
+		}
+	}
+	if v := h.Get("X-RateLimit-Retry-After"); v != "" {
+		if ts, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return clampWait(time.Until(time.Unix(ts, 0))), true
+		}
+	}
+	return 0, false
+}
+
+// parseRateLimitReset reads X-RateLimit-Reset, which is either a unix timestamp
+// or a number of seconds depending on the upstream
+func parseRateLimitReset(h http.Header) (time.Duration, bool) {
+	if wait, ok := parseRetryAfter(h); ok {
+		return wait, true
+	}
+	v := h.Get("X-RateLimit-Reset")
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, false
+	}
+	if n > 1e9 {
+		return clampWait(time.Until(time.Unix(int64(n), 0))), true
+	}
+	return clampWait(time.Duration(n * float64(time.Second))), true
+}
+
+// clampWait bounds a wait announced by the upstream, so a single bogus value
+// cannot stall a host for longer than maxUpstreamWait
+func clampWait(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	if d > maxUpstreamWait {
+		return maxUpstreamWait
+	}
+	return d
+}
+
+// sleepContext sleeps for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// hostname strips the port from a host[:port] string
+func hostname(host string) string {
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
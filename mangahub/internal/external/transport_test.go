@@ -0,0 +1,66 @@
+package external
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfterClampsEveryForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   time.Duration
+	}{
+		{"seconds", "Retry-After", "7", 7 * time.Second},
+		{"huge seconds", "Retry-After", "999999", maxUpstreamWait},
+		{"http date", "Retry-After", time.Now().Add(48 * time.Hour).UTC().Format(http.TimeFormat), maxUpstreamWait},
+		{"unix timestamp", "X-RateLimit-Retry-After", "99999999999", maxUpstreamWait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set(tt.header, tt.value)
+			wait, ok := parseRetryAfter(h)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, wait)
+		})
+	}
+}
+
+func TestServicesOnOneHostHaveSeparateLimitsAndBreakers(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	transport := &ResilientTransport{
+		Base:   http.DefaultTransport,
+		Config: TransportConfig{BreakerThreshold: 1, BreakerCooldown: time.Minute, MaxBackoff: time.Millisecond},
+		hosts:  make(map[upstreamKey]*hostState),
+	}
+	transport.SetServiceRateLimit("jikan", upstream.URL, 0.001, 1)
+
+	jikan := &http.Client{Transport: transport.ForService("jikan")}
+	mangadex := &http.Client{Transport: transport.ForService("mangadex")}
+
+	resp, err := jikan.Get(upstream.URL + "/jikan/manga")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The jikan breaker is open now, and its bucket would make a second request wait
+	_, err = jikan.Get(upstream.URL + "/jikan/manga")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	start := time.Now()
+	resp, err = mangadex.Get(upstream.URL + "/mangadex/manga")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,248 @@
+package fakeupstream
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// seedFixtures are the recorded responses shipped with the repository
+//
+//go:embed fixtures
+var seedFixtures embed.FS
+
+// baseURLPlaceholder is replaced by the fake server's own base URL for the
+// service when a fixture is served (used e.g. for MangaDex at-home URLs)
+const baseURLPlaceholder = "{{BASE_URL}}"
+
+// Fixture is a single recorded upstream response.
+//
+// Path is relative to the service base URL and may contain "*" segments that
+// match any single path segment. Query lists the parameters that must be
+// present on the request; a value of "*" matches any value.
+type Fixture struct {
+	Service string            `json:"service"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   map[string]string `json:"query,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Text    string            `json:"text,omitempty"` // Used instead of Body for non-JSON responses
+}
+
+// matches reports whether the fixture applies to a request and how specific
+// the match is (higher wins)
+func (f *Fixture) matches(method, path string, query url.Values) (int, bool) {
+	if !strings.EqualFold(f.Method, method) {
+		return 0, false
+	}
+
+	patternSegs := strings.Split(strings.Trim(f.Path, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegs) != len(pathSegs) {
+		return 0, false
+	}
+
+	score := 0
+	for i, seg := range patternSegs {
+		if seg == "*" {
+			continue
+		}
+		if seg != pathSegs[i] {
+			return 0, false
+		}
+		score += 100
+	}
+
+	for key, want := range f.Query {
+		values, ok := query[key]
+		if !ok {
+			return 0, false
+		}
+		if want == "*" {
+			score++
+			continue
+		}
+		found := false
+		for _, v := range values {
+			if v == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		score += 10
+	}
+
+	return score, true
+}
+
+// Store holds the fixtures available to the fake servers. Fixtures recorded
+// into Dir take precedence over the embedded seed fixtures.
+type Store struct {
+	Dir string
+
+	mu       sync.RWMutex
+	fixtures map[string][]*Fixture // keyed by service
+}
+
+// NewStore loads the seed fixtures and any fixtures found in dir
+func NewStore(dir string) (*Store, error) {
+	s := &Store{
+		Dir:      dir,
+		fixtures: make(map[string][]*Fixture),
+	}
+
+	if err := s.loadFS(seedFixtures, "fixtures"); err != nil {
+		return nil, fmt.Errorf("failed to load seed fixtures: %w", err)
+	}
+
+	if dir != "" {
+		if _, err := os.Stat(dir); err == nil {
+			if err := s.loadFS(os.DirFS(dir), "."); err != nil {
+				return nil, fmt.Errorf("failed to load fixtures from %s: %w", dir, err)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// loadFS reads every *.json fixture under root
+func (s *Store) loadFS(fsys fs.FS, root string) error {
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+		if fixture.Service == "" || fixture.Path == "" {
+			return fmt.Errorf("invalid fixture %s: service and path are required", path)
+		}
+		if fixture.Method == "" {
+			fixture.Method = "GET"
+		}
+		if fixture.Status == 0 {
+			fixture.Status = 200
+		}
+
+		s.add(&fixture)
+		return nil
+	})
+}
+
+// add registers a fixture, replacing one with the same request signature
+func (s *Store) add(fixture *Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.fixtures[fixture.Service]
+	for i, existing := range list {
+		if existing.signature() == fixture.signature() {
+			list[i] = fixture
+			return
+		}
+	}
+	s.fixtures[fixture.Service] = append(list, fixture)
+}
+
+// Find returns the most specific fixture for a request, or nil
+func (s *Store) Find(service, method, path string, query url.Values) *Fixture {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best *Fixture
+	bestScore := -1
+	for _, fixture := range s.fixtures[service] {
+		if score, ok := fixture.matches(method, path, query); ok && score > bestScore {
+			best = fixture
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// Count returns the number of fixtures per service
+func (s *Store) Count() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for service, list := range s.fixtures {
+		counts[service] = len(list)
+	}
+	return counts
+}
+
+// Save writes a recorded fixture to Dir and makes it available for replay
+func (s *Store) Save(fixture *Fixture) (string, error) {
+	if s.Dir == "" {
+		return "", fmt.Errorf("no fixture directory configured")
+	}
+
+	dir := filepath.Join(s.Dir, fixture.Service)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	path := filepath.Join(dir, fixture.fileName())
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	s.add(fixture)
+	return path, nil
+}
+
+// signature identifies the request a fixture answers
+func (f *Fixture) signature() string {
+	keys := make([]string, 0, len(f.Query))
+	for k := range f.Query {
+		keys = append(keys, k+"="+f.Query[k])
+	}
+	sort.Strings(keys)
+	return strings.ToUpper(f.Method) + " " + f.Path + "?" + strings.Join(keys, "&")
+}
+
+// fileName builds a readable, stable file name for a recorded fixture
+func (f *Fixture) fileName() string {
+	name := strings.Trim(f.Path, "/")
+	name = strings.NewReplacer("/", "_", "*", "any", ".", "_").Replace(name)
+	if name == "" {
+		name = "root"
+	}
+	if len(name) > 80 {
+		name = name[:80]
+	}
+
+	sum := sha256.Sum256([]byte(f.signature()))
+	return fmt.Sprintf("%s_%s_%s.json", strings.ToLower(f.Method), name, hex.EncodeToString(sum[:])[:8])
+}
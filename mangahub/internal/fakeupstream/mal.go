@@ -0,0 +1,304 @@
+package fakeupstream
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeMALUser is the account every fake authorization is granted for
+var fakeMALUser = map[string]interface{}{"id": 1000001, "name": "offline_reader"}
+
+// fakeMALTokenTTL is the lifetime of fake access tokens
+const fakeMALTokenTTL = time.Hour
+
+var malListStatusPath = regexp.MustCompile(`^/manga/(\d+)/my_list_status$`)
+var malMangaPath = regexp.MustCompile(`^/manga/(\d+)$`)
+
+// malAuthCode is an issued, not yet exchanged authorization code
+type malAuthCode struct {
+	challenge   string
+	method      string
+	redirectURI string
+}
+
+// malEntry is one entry on the fake user's manga list
+type malEntry struct {
+	Title           string
+	Status          string
+	IsRereading     bool
+	NumChaptersRead int
+	Score           int
+	UpdatedAt       time.Time
+}
+
+// MALServer is a stateful fake of the MyAnimeList OAuth2 authorization server
+// and the user list endpoints of the MAL API. Authorization is approved
+// immediately and PKCE verifiers are checked like the real server does.
+type MALServer struct {
+	mu       sync.Mutex
+	codes    map[string]malAuthCode
+	access   map[string]time.Time // access token -> expiry
+	refresh  map[string]bool
+	list     map[int]*malEntry
+	clientID string // empty accepts any client
+}
+
+// NewMALServer creates a fake MAL server with a small seeded list
+func NewMALServer() *MALServer {
+	seeded := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	return &MALServer{
+		codes:   make(map[string]malAuthCode),
+		access:  make(map[string]time.Time),
+		refresh: make(map[string]bool),
+		list: map[int]*malEntry{
+			13:     {Title: "One Piece", Status: "reading", NumChaptersRead: 1000, Score: 9, UpdatedAt: seeded},
+			2:      {Title: "Berserk", Status: "completed", NumChaptersRead: 364, Score: 10, UpdatedAt: seeded},
+			121496: {Title: "Na Honjaman Level Up", Status: "plan_to_read", UpdatedAt: seeded},
+		},
+	}
+}
+
+// Handles reports whether a request on the "mal" service is served by the fake
+// user API rather than by catalog fixtures
+func (m *MALServer) Handles(r *http.Request, rest string) bool {
+	if strings.HasPrefix(rest, "/users/@me") || malListStatusPath.MatchString(rest) {
+		return true
+	}
+	return malMangaPath.MatchString(rest) && strings.Contains(r.URL.Query().Get("fields"), "my_list_status")
+}
+
+// ServeAuth handles the OAuth2 endpoints (service "malauth")
+func (m *MALServer) ServeAuth(w http.ResponseWriter, r *http.Request, rest string) {
+	switch rest {
+	case "/authorize":
+		m.authorize(w, r)
+	case "/token":
+		m.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// ServeAPI handles the authenticated user API endpoints (service "mal")
+func (m *MALServer) ServeAPI(w http.ResponseWriter, r *http.Request, rest string) {
+	if !m.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+
+	switch {
+	case rest == "/users/@me":
+		writeJSON(w, http.StatusOK, fakeMALUser)
+	case rest == "/users/@me/mangalist":
+		m.mangaList(w, r)
+	case malListStatusPath.MatchString(rest):
+		id, _ := strconv.Atoi(malListStatusPath.FindStringSubmatch(rest)[1])
+		m.updateListStatus(w, r, id)
+	default:
+		id, _ := strconv.Atoi(malMangaPath.FindStringSubmatch(rest)[1])
+		m.mangaWithStatus(w, id)
+	}
+}
+
+// authorize approves the request and redirects back with a code
+func (m *MALServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("code_challenge") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if m.clientID != "" && q.Get("client_id") != m.clientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	method := q.Get("code_challenge_method")
+	if method == "" {
+		method = "plain"
+	}
+
+	code := randomToken()
+	m.mu.Lock()
+	m.codes[code] = malAuthCode{challenge: q.Get("code_challenge"), method: method, redirectURI: q.Get("redirect_uri")}
+	m.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("redirect_uri") == "" {
+		// Without a redirect URI just show the code, like an out-of-band flow
+		writeJSON(w, http.StatusOK, map[string]string{"code": code, "state": q.Get("state")})
+		return
+	}
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token exchanges authorization codes and refresh tokens
+func (m *MALServer) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		code, ok := m.codes[r.PostForm.Get("code")]
+		delete(m.codes, r.PostForm.Get("code"))
+		if !ok || !verifyPKCE(code, r.PostForm.Get("code_verifier")) ||
+			(code.redirectURI != "" && r.PostForm.Get("redirect_uri") != code.redirectURI) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	case "refresh_token":
+		token := r.PostForm.Get("refresh_token")
+		if !m.refresh[token] {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		// Refresh tokens rotate
+		delete(m.refresh, token)
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	access, refresh := randomToken(), randomToken()
+	m.access[access] = time.Now().Add(fakeMALTokenTTL)
+	m.refresh[refresh] = true
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token_type":    "Bearer",
+		"expires_in":    int(fakeMALTokenTTL.Seconds()),
+		"access_token":  access,
+		"refresh_token": refresh,
+	})
+}
+
+// mangaList returns the fake user's list in MAL's paged format
+func (m *MALServer) mangaList(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := make([]map[string]interface{}, 0, len(m.list))
+	for id, entry := range m.list {
+		data = append(data, map[string]interface{}{
+			"node":        map[string]interface{}{"id": id, "title": entry.Title},
+			"list_status": entry.status(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "paging": map[string]string{}})
+}
+
+// mangaWithStatus returns a manga with the user's list status
+func (m *MALServer) mangaWithStatus(w http.ResponseWriter, id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := map[string]interface{}{"id": id}
+	if entry, ok := m.list[id]; ok {
+		result["title"] = entry.Title
+		result["my_list_status"] = entry.status()
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// updateListStatus handles PATCH and DELETE on my_list_status
+func (m *MALServer) updateListStatus(w http.ResponseWriter, r *http.Request, id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Method == http.MethodDelete {
+		delete(m.list, id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+		return
+	}
+	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	entry, ok := m.list[id]
+	if !ok {
+		entry = &malEntry{Title: fmt.Sprintf("Manga %d", id), Status: "plan_to_read"}
+		m.list[id] = entry
+	}
+	if v := r.PostForm.Get("status"); v != "" {
+		entry.Status = v
+	}
+	if v := r.PostForm.Get("is_rereading"); v != "" {
+		entry.IsRereading = v == "true"
+	}
+	if v, err := strconv.Atoi(r.PostForm.Get("num_chapters_read")); err == nil {
+		entry.NumChaptersRead = v
+	}
+	if v, err := strconv.Atoi(r.PostForm.Get("score")); err == nil {
+		entry.Score = v
+	}
+	entry.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	writeJSON(w, http.StatusOK, entry.status())
+}
+
+// authorized checks the bearer token of an API request
+func (m *MALServer) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expiry, ok := m.access[token]
+	return ok && time.Now().Before(expiry)
+}
+
+func (e *malEntry) status() map[string]interface{} {
+	return map[string]interface{}{
+		"status":            e.Status,
+		"is_rereading":      e.IsRereading,
+		"num_volumes_read":  0,
+		"num_chapters_read": e.NumChaptersRead,
+		"score":             e.Score,
+		"updated_at":        e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// verifyPKCE checks a code verifier against the stored challenge
+func verifyPKCE(code malAuthCode, verifier string) bool {
+	if verifier == "" {
+		return false
+	}
+	if code.method == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(sum[:]) == code.challenge
+	}
+	return verifier == code.challenge
+}
+
+func randomToken() string {
+	buf := make([]byte, 24)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
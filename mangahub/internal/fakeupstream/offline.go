@@ -0,0 +1,76 @@
+package fakeupstream
+
+import (
+	"fmt"
+	"log"
+	"mangahub/pkg/database"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FixtureDir returns the directory recorded fixtures are read from and written to
+func FixtureDir() string {
+	if dir := os.Getenv("FAKE_UPSTREAM_FIXTURES"); dir != "" {
+		return dir
+	}
+
+	// Default: next to the SQLite database
+	dataDir, err := database.DataDir()
+	if err != nil {
+		return filepath.Join("data", "fixtures")
+	}
+	return filepath.Join(dataDir, "fixtures")
+}
+
+// ModeFromEnv returns the mode requested by the OFFLINE environment variable.
+// OFFLINE=true replays fixtures, OFFLINE=record captures real responses.
+// ok is false when offline mode is disabled.
+func ModeFromEnv() (mode Mode, ok bool) {
+	switch strings.ToLower(os.Getenv("OFFLINE")) {
+	case "true", "1", "replay":
+		return ModeReplay, true
+	case "record":
+		return ModeRecord, true
+	}
+	return "", false
+}
+
+// ConfigureFromEnv points every external API client at the fake upstream
+// server when OFFLINE is set. If FAKE_UPSTREAM_URL is set the standalone
+// fake-upstream server at that address is used, otherwise an in-process
+// httptest server is started. It must be called before any client is created.
+// The returned function stops the in-process server.
+func ConfigureFromEnv() (func(), error) {
+	mode, ok := ModeFromEnv()
+	if !ok {
+		return func() {}, nil
+	}
+
+	baseURL := strings.TrimRight(os.Getenv("FAKE_UPSTREAM_URL"), "/")
+	shutdown := func() {}
+
+	if baseURL == "" {
+		store, err := NewStore(FixtureDir())
+		if err != nil {
+			return nil, err
+		}
+
+		server := httptest.NewServer(NewServer(store, mode))
+		baseURL = server.URL
+		shutdown = server.Close
+		log.Printf("Fake upstream server (%s mode) started at %s with fixtures %v", mode, baseURL, store.Count())
+	}
+
+	for _, svc := range Services {
+		url := fmt.Sprintf("%s/%s", baseURL, svc.Name)
+		if err := os.Setenv(svc.EnvVar, url); err != nil {
+			shutdown()
+			return nil, fmt.Errorf("failed to set %s: %w", svc.EnvVar, err)
+		}
+		log.Printf("  - OFFLINE: %s=%s", svc.EnvVar, url)
+	}
+
+	return shutdown, nil
+}
@@ -0,0 +1,263 @@
+package fakeupstream
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Mode selects how the fake server answers requests
+type Mode string
+
+const (
+	// ModeReplay serves responses from fixtures only
+	ModeReplay Mode = "replay"
+	// ModeRecord proxies to the real upstream and saves every response as a fixture
+	ModeRecord Mode = "record"
+)
+
+// Service describes an upstream API that can be faked
+type Service struct {
+	Name        string // Path prefix on the fake server and fixture directory
+	EnvVar      string // Environment variable the client reads its base URL from
+	UpstreamURL string // Real API base URL used in record mode
+}
+
+// Services lists the upstream APIs served by the fake server
+var Services = []Service{
+	{Name: "mangadex", EnvVar: "MANGADEX_API_BASE_URL", UpstreamURL: "https://api.mangadex.org"},
+	{Name: "jikan", EnvVar: "JIKAN_API_BASE_URL", UpstreamURL: "https://api.jikan.moe/v4"},
+	{Name: "mangaplus", EnvVar: "MANGAPLUS_API_BASE_URL", UpstreamURL: "https://jumpg-webapi.tokyo-cdn.com/api"},
+	{Name: "mal", EnvVar: "MAL_API_BASE_URL", UpstreamURL: "https://api.myanimelist.net/v2"},
+	{Name: "malauth", EnvVar: "MAL_OAUTH_BASE_URL", UpstreamURL: "https://myanimelist.net/v1/oauth2"},
+}
+
+// recordedHeaders are the response headers kept in recorded fixtures
+var recordedHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+// placeholderPNG is a 1x1 grey PNG returned for image requests without a fixture
+var placeholderPNG, _ = base64.StdEncoding.DecodeString(
+	"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mN8+x8AAuMB8DtXNJsAAAAASUVORK5CYII=")
+
+// Server is an http.Handler that fakes the external manga APIs.
+// Requests are routed by their first path segment, e.g. /mangadex/manga.
+type Server struct {
+	Store     *Store
+	Mode      Mode
+	Upstreams map[string]string // service name -> real base URL (record mode)
+	MAL       *MALServer        // Stateful MAL OAuth2 and user list endpoints
+
+	client *http.Client
+}
+
+// NewServer creates a fake upstream handler
+func NewServer(store *Store, mode Mode) *Server {
+	upstreams := make(map[string]string)
+	for _, svc := range Services {
+		upstreams[svc.Name] = svc.UpstreamURL
+	}
+
+	return &Server{
+		Store:     store,
+		Mode:      mode,
+		Upstreams: upstreams,
+		MAL:       NewMALServer(),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/health" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "healthy",
+			"mode":     s.Mode,
+			"fixtures": s.Store.Count(),
+		})
+		return
+	}
+
+	service, rest := splitService(r.URL.Path)
+	if _, ok := s.Upstreams[service]; !ok {
+		http.Error(w, fmt.Sprintf("unknown service %q", service), http.StatusNotFound)
+		return
+	}
+
+	// Account linking and user lists are always faked, in both modes,
+	// so user tokens and personal lists never end up in fixtures
+	if service == "malauth" {
+		s.MAL.ServeAuth(w, r, rest)
+		return
+	}
+	if service == "mal" && s.MAL.Handles(r, rest) {
+		s.MAL.ServeAPI(w, r, rest)
+		return
+	}
+
+	if s.Mode == ModeRecord {
+		s.record(w, r, service, rest)
+		return
+	}
+	s.replay(w, r, service, rest)
+}
+
+// replay answers a request from the fixture store
+func (s *Server) replay(w http.ResponseWriter, r *http.Request, service, rest string) {
+	fixture := s.Store.Find(service, r.Method, rest, r.URL.Query())
+	if fixture == nil {
+		if isImagePath(rest) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(placeholderPNG)
+			return
+		}
+
+		log.Printf("Fake upstream: no fixture for %s %s %s?%s", service, r.Method, rest, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"result": "error",
+			"error":  fmt.Sprintf("no fixture for %s %s", r.Method, rest),
+		})
+		return
+	}
+
+	for key, value := range fixture.Headers {
+		w.Header().Set(key, value)
+	}
+
+	// Honour conditional requests so clients can exercise revalidation
+	if etag := fixture.Headers["ETag"]; etag != "" && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body := []byte(fixture.Text)
+	if len(fixture.Body) > 0 {
+		body = fixture.Body
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+	}
+	body = bytes.ReplaceAll(body, []byte(baseURLPlaceholder), []byte(requestBaseURL(r, service)))
+
+	w.WriteHeader(fixture.Status)
+	w.Write(body)
+}
+
+// record forwards a request to the real upstream and stores the response
+func (s *Server) record(w http.ResponseWriter, r *http.Request, service, rest string) {
+	target := strings.TrimRight(s.Upstreams[service], "/") + rest
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	for key, values := range r.Header {
+		// Ask for an uncompressed body so fixtures stay readable
+		if key == "Accept-Encoding" {
+			continue
+		}
+		req.Header[key] = values
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("Fake upstream: record request to %s failed: %v", target, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	// Transient failures are passed through but never recorded
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		fixture := &Fixture{
+			Service: service,
+			Method:  r.Method,
+			Path:    rest,
+			Query:   flattenQuery(r.URL.Query()),
+			Status:  resp.StatusCode,
+			Headers: make(map[string]string),
+		}
+		for _, key := range recordedHeaders {
+			if v := resp.Header.Get(key); v != "" {
+				fixture.Headers[key] = v
+			}
+		}
+		if json.Valid(body) {
+			fixture.Body = json.RawMessage(body)
+		} else {
+			fixture.Text = string(body)
+		}
+
+		if file, err := s.Store.Save(fixture); err != nil {
+			log.Printf("Fake upstream: failed to save fixture: %v", err)
+		} else {
+			log.Printf("Fake upstream: recorded %s %s -> %s", r.Method, target, file)
+		}
+	}
+
+	for _, key := range recordedHeaders {
+		if v := resp.Header.Get(key); v != "" {
+			w.Header().Set(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+// splitService separates the service prefix from the upstream path
+func splitService(p string) (string, string) {
+	p = strings.TrimPrefix(p, "/")
+	service, rest, _ := strings.Cut(p, "/")
+	return service, "/" + rest
+}
+
+// flattenQuery keeps the first value of every query parameter
+func flattenQuery(values url.Values) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(values))
+	for key, v := range values {
+		if len(v) > 0 {
+			flat[key] = v[0]
+		}
+	}
+	return flat
+}
+
+// requestBaseURL returns the base URL of a service on this fake server
+func requestBaseURL(r *http.Request, service string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, r.Host, service)
+}
+
+func isImagePath(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".webp", ".gif":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"mangahub/internal/auth"
+	pb "mangahub/proto"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationKey is the metadata key carrying "Bearer <access token>"
+const authorizationKey = "authorization"
+
+// userScoped is implemented by requests made on behalf of a user
+type userScoped interface {
+	GetUserId() string
+}
+
+// methodPermissions lists the RPCs that need a permission (see auth.HasPermission)
+// rather than only a token of the user they name
+var methodPermissions = map[string]string{
+	pb.MangaService_GrantRole_FullMethodName:  auth.PermManageRoles,
+	pb.MangaService_RevokeRole_FullMethodName: auth.PermManageRoles,
+}
+
+// methodScopes lists the RPCs personal access tokens may call and the scope each
+// needs. Profile and password RPCs are only available with a login session.
+var methodScopes = map[string]string{
+	pb.MangaService_UpdateProgress_FullMethodName:       auth.ScopeLibraryWrite,
+	pb.MangaService_GetLibrary_FullMethodName:           auth.ScopeLibraryRead,
+	pb.MangaService_AddToLibrary_FullMethodName:         auth.ScopeLibraryWrite,
+	pb.MangaService_RemoveFromLibrary_FullMethodName:    auth.ScopeLibraryWrite,
+	pb.MangaService_GetLibraryStats_FullMethodName:      auth.ScopeLibraryRead,
+	pb.MangaService_GetLibraryChanges_FullMethodName:    auth.ScopeLibraryRead,
+	pb.MangaService_ApplyProgressOps_FullMethodName:     auth.ScopeLibraryWrite,
+	pb.MangaService_GetProgressConflicts_FullMethodName: auth.ScopeLibraryRead,
+	pb.MangaService_ListCollections_FullMethodName:      auth.ScopeLibraryRead,
+	pb.MangaService_GetCollection_FullMethodName:        auth.ScopeLibraryRead,
+	pb.MangaService_CreateCollection_FullMethodName:     auth.ScopeLibraryWrite,
+	pb.MangaService_UpdateCollection_FullMethodName:     auth.ScopeLibraryWrite,
+	pb.MangaService_DeleteCollection_FullMethodName:     auth.ScopeLibraryWrite,
+	pb.MangaService_AddToCollection_FullMethodName:      auth.ScopeLibraryWrite,
+	pb.MangaService_RemoveFromCollection_FullMethodName: auth.ScopeLibraryWrite,
+	pb.MangaService_ReorderCollection_FullMethodName:    auth.ScopeLibraryWrite,
+	pb.MangaService_GetRecommendations_FullMethodName:   auth.ScopeLibraryRead,
+	pb.MangaService_RateManga_FullMethodName:            auth.ScopeRatingsWrite,
+	pb.MangaService_GetMangaRatings_FullMethodName:      auth.ScopeLibraryRead,
+	pb.MangaService_DeleteRating_FullMethodName:         auth.ScopeRatingsWrite,
+	pb.MangaService_GetMangaReviews_FullMethodName:      auth.ScopeLibraryRead,
+	pb.MangaService_WriteReview_FullMethodName:          auth.ScopeRatingsWrite,
+	pb.MangaService_VoteReview_FullMethodName:           auth.ScopeRatingsWrite,
+	pb.MangaService_DeleteReview_FullMethodName:         auth.ScopeRatingsWrite,
+	pb.MangaService_GrantRole_FullMethodName:            auth.ScopeAdmin,
+	pb.MangaService_RevokeRole_FullMethodName:           auth.ScopeAdmin,
+}
+
+// claimsKey is the context key of the caller's token claims
+type claimsKey struct{}
+
+// authInterceptor requires a valid access token of the same user for every
+// request that names a user, so revoked sessions cannot act over gRPC, and a
+// token whose roles grant the permission for the RPCs in methodPermissions.
+// Personal access tokens also need the scope in methodScopes. Requests without
+// a user (browsing) need no token.
+func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	permission, restricted := methodPermissions[info.FullMethod]
+	scoped, ok := req.(userScoped)
+	if !restricted && (!ok || scoped.GetUserId() == "") {
+		return handler(ctx, req)
+	}
+
+	claims, err := tokenClaims(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if claims.APITokenID != "" {
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "API tokens cannot be used for this method")
+		}
+		if !auth.HasScope(claims, scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "API token lacks scope %s", scope)
+		}
+	}
+	if restricted && !auth.HasPermission(claims.Roles, permission) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission %s required", permission)
+	}
+	if ok && scoped.GetUserId() != "" && claims.UserID != scoped.GetUserId() {
+		return nil, status.Error(codes.PermissionDenied, "token does not belong to user")
+	}
+
+	return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+}
+
+// tokenClaims validates the token in the call's metadata. Access tokens are
+// checked against the signing key set like on every other service, then their
+// session in the database, so revoked sessions stop working at once. Personal
+// access tokens only exist in the database.
+func tokenClaims(ctx context.Context, method string) (*auth.Claims, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationKey); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization token required")
+	}
+
+	claims, err := auth.ValidateRequestToken(token)
+	if err != nil {
+		log.Printf("gRPC %s rejected: %v", method, err)
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return claims, nil
+}
+
+// callerID returns the user ID of the authenticated caller, if any
+func callerID(ctx context.Context) string {
+	if claims, ok := ctx.Value(claimsKey{}).(*auth.Claims); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+// callerSessionID returns the login session of the caller, if any
+func callerSessionID(ctx context.Context) string {
+	if claims, ok := ctx.Value(claimsKey{}).(*auth.Claims); ok {
+		return claims.SessionID
+	}
+	return ""
+}
+
+// WithToken attaches a user's access token to the outgoing context of a call
+func WithToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+}
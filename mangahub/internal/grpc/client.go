@@ -121,6 +121,60 @@ func (c *Client) GetLibrary(ctx context.Context, userID string) (*pb.LibraryResp
 	return resp, nil
 }
 
+// GetLibraryChanges retrieves user's library changes since a sync token via gRPC
+func (c *Client) GetLibraryChanges(ctx context.Context, userID, syncToken string, limit int32) (*pb.LibraryChangesResponse, error) {
+	req := &pb.LibraryChangesRequest{
+		UserId:    userID,
+		SyncToken: syncToken,
+		Limit:     limit,
+	}
+
+	log.Printf("gRPC Client: Getting library changes for user %s", userID)
+
+	resp, err := c.client.GetLibraryChanges(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetLibraryChanges RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// ApplyProgressOps sends queued, client-stamped progress ops via gRPC
+func (c *Client) ApplyProgressOps(ctx context.Context, userID string, ops []*pb.ProgressOp) (*pb.ProgressOpsResponse, error) {
+	req := &pb.ProgressOpsRequest{
+		UserId: userID,
+		Ops:    ops,
+	}
+
+	log.Printf("gRPC Client: Applying %d progress ops for user %s", len(ops), userID)
+
+	resp, err := c.client.ApplyProgressOps(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ApplyProgressOps RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GetProgressConflicts retrieves user's progress conflict log via gRPC
+func (c *Client) GetProgressConflicts(ctx context.Context, userID, mangaID string, limit, offset int32) (*pb.ProgressConflictsResponse, error) {
+	req := &pb.ProgressConflictsRequest{
+		UserId:  userID,
+		MangaId: mangaID,
+		Limit:   limit,
+		Offset:  offset,
+	}
+
+	log.Printf("gRPC Client: Getting progress conflicts for user %s", userID)
+
+	resp, err := c.client.GetProgressConflicts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetProgressConflicts RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
 // AddToLibrary adds a manga to user's library via gRPC
 func (c *Client) AddToLibrary(ctx context.Context, userID, mangaID, status string) (*pb.AddToLibraryResponse, error) {
 	req := &pb.AddToLibraryRequest{
@@ -172,12 +226,15 @@ func (c *Client) GetLibraryStats(ctx context.Context, userID string) (*pb.Librar
 	return resp, nil
 }
 
-// RateManga submits a rating for a manga via gRPC
-func (c *Client) RateManga(ctx context.Context, userID, mangaID string, rating int32) (*pb.RatingResponse, error) {
+// RateManga submits a rating for a manga via gRPC. Sub-scores of 0 are left unchanged.
+func (c *Client) RateManga(ctx context.Context, userID, mangaID string, rating, story, art, characters int32) (*pb.RatingResponse, error) {
 	req := &pb.RatingRequest{
-		UserId:  userID,
-		MangaId: mangaID,
-		Rating:  rating,
+		UserId:     userID,
+		MangaId:    mangaID,
+		Rating:     rating,
+		Story:      story,
+		Art:        art,
+		Characters: characters,
 	}
 
 	log.Printf("gRPC Client: Rating manga %s with %d for user %s", mangaID, rating, userID)
@@ -240,12 +297,14 @@ func (c *Client) GetUserProfile(ctx context.Context, userID string) (*pb.UserPro
 	return resp, nil
 }
 
-// UpdateUserProfile updates a user's profile via gRPC
-func (c *Client) UpdateUserProfile(ctx context.Context, userID, username, email string) (*pb.UpdateUserProfileResponse, error) {
+// UpdateUserProfile updates a user's profile via gRPC. otpCode is needed when
+// the user has two-factor authentication.
+func (c *Client) UpdateUserProfile(ctx context.Context, userID, username, email, otpCode string) (*pb.UpdateUserProfileResponse, error) {
 	req := &pb.UpdateUserProfileRequest{
 		UserId:   userID,
 		Username: username,
 		Email:    email,
+		OtpCode:  otpCode,
 	}
 
 	log.Printf("gRPC Client: Updating profile for user %s", userID)
@@ -258,12 +317,14 @@ func (c *Client) UpdateUserProfile(ctx context.Context, userID, username, email
 	return resp, nil
 }
 
-// ChangePassword changes a user's password via gRPC
-func (c *Client) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) (*pb.ChangePasswordResponse, error) {
+// ChangePassword changes a user's password via gRPC. otpCode is needed when
+// the user has two-factor authentication.
+func (c *Client) ChangePassword(ctx context.Context, userID, oldPassword, newPassword, otpCode string) (*pb.ChangePasswordResponse, error) {
 	req := &pb.ChangePasswordRequest{
 		UserId:      userID,
 		OldPassword: oldPassword,
 		NewPassword: newPassword,
+		OtpCode:     otpCode,
 	}
 
 	log.Printf("gRPC Client: Changing password for user %s", userID)
@@ -276,6 +337,291 @@ func (c *Client) ChangePassword(ctx context.Context, userID, oldPassword, newPas
 	return resp, nil
 }
 
+// ListCollections lists a user's collections via gRPC
+func (c *Client) ListCollections(ctx context.Context, userID string) (*pb.ListCollectionsResponse, error) {
+	req := &pb.ListCollectionsRequest{
+		UserId: userID,
+	}
+
+	log.Printf("gRPC Client: Listing collections for user %s", userID)
+
+	resp, err := c.client.ListCollections(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ListCollections RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GetCollection gets one of a user's collections via gRPC
+func (c *Client) GetCollection(ctx context.Context, userID, collectionID string) (*pb.CollectionResponse, error) {
+	req := &pb.GetCollectionRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+	}
+
+	log.Printf("gRPC Client: Getting collection %s for user %s", collectionID, userID)
+
+	resp, err := c.client.GetCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// CreateCollection creates a collection via gRPC
+func (c *Client) CreateCollection(ctx context.Context, userID, name, description, visibility string) (*pb.CollectionResponse, error) {
+	req := &pb.CreateCollectionRequest{
+		UserId:      userID,
+		Name:        name,
+		Description: description,
+		Visibility:  visibility,
+	}
+
+	log.Printf("gRPC Client: Creating collection %q for user %s", name, userID)
+
+	resp, err := c.client.CreateCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CreateCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// UpdateCollection updates a collection via gRPC. A nil description leaves it unchanged.
+func (c *Client) UpdateCollection(ctx context.Context, userID, collectionID, name string, description *string, visibility string) (*pb.CollectionResponse, error) {
+	req := &pb.UpdateCollectionRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+		Name:         name,
+		Description:  description,
+		Visibility:   visibility,
+	}
+
+	log.Printf("gRPC Client: Updating collection %s for user %s", collectionID, userID)
+
+	resp, err := c.client.UpdateCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteCollection deletes a collection via gRPC
+func (c *Client) DeleteCollection(ctx context.Context, userID, collectionID string) (*pb.DeleteCollectionResponse, error) {
+	req := &pb.DeleteCollectionRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+	}
+
+	log.Printf("gRPC Client: Deleting collection %s for user %s", collectionID, userID)
+
+	resp, err := c.client.DeleteCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("DeleteCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// AddToCollection adds a manga to a collection via gRPC
+func (c *Client) AddToCollection(ctx context.Context, userID, collectionID, mangaID string) (*pb.CollectionResponse, error) {
+	req := &pb.CollectionItemRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+		MangaId:      mangaID,
+	}
+
+	log.Printf("gRPC Client: Adding manga %s to collection %s for user %s", mangaID, collectionID, userID)
+
+	resp, err := c.client.AddToCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("AddToCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// RemoveFromCollection removes a manga from a collection via gRPC
+func (c *Client) RemoveFromCollection(ctx context.Context, userID, collectionID, mangaID string) (*pb.CollectionResponse, error) {
+	req := &pb.CollectionItemRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+		MangaId:      mangaID,
+	}
+
+	log.Printf("gRPC Client: Removing manga %s from collection %s for user %s", mangaID, collectionID, userID)
+
+	resp, err := c.client.RemoveFromCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("RemoveFromCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// ReorderCollection sets the order of a collection via gRPC
+func (c *Client) ReorderCollection(ctx context.Context, userID, collectionID string, mangaIDs []string) (*pb.CollectionResponse, error) {
+	req := &pb.ReorderCollectionRequest{
+		UserId:       userID,
+		CollectionId: collectionID,
+		MangaIds:     mangaIDs,
+	}
+
+	log.Printf("gRPC Client: Reordering collection %s for user %s", collectionID, userID)
+
+	resp, err := c.client.ReorderCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ReorderCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GetSharedCollection gets a shared collection by its token via gRPC
+func (c *Client) GetSharedCollection(ctx context.Context, shareToken string) (*pb.CollectionResponse, error) {
+	req := &pb.GetSharedCollectionRequest{
+		ShareToken: shareToken,
+	}
+
+	log.Printf("gRPC Client: Getting shared collection")
+
+	resp, err := c.client.GetSharedCollection(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetSharedCollection RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GetRecommendations gets manga recommendations for a user via gRPC
+func (c *Client) GetRecommendations(ctx context.Context, userID string, limit int32) (*pb.RecommendationsResponse, error) {
+	req := &pb.RecommendationsRequest{
+		UserId: userID,
+		Limit:  limit,
+	}
+
+	log.Printf("gRPC Client: Getting recommendations for user %s", userID)
+
+	resp, err := c.client.GetRecommendations(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetRecommendations RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GetMangaReviews gets the reviews of a manga via gRPC
+func (c *Client) GetMangaReviews(ctx context.Context, mangaID, userID, sort string, limit, offset int32) (*pb.MangaReviewsResponse, error) {
+	req := &pb.MangaReviewsRequest{
+		MangaId: mangaID,
+		UserId:  userID,
+		Sort:    sort,
+		Limit:   limit,
+		Offset:  offset,
+	}
+
+	log.Printf("gRPC Client: Getting reviews for manga %s", mangaID)
+
+	resp, err := c.client.GetMangaReviews(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetMangaReviews RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// WriteReview writes a user's review of a manga via gRPC
+func (c *Client) WriteReview(ctx context.Context, userID, mangaID, title, body string, spoiler bool) (*pb.ReviewResponse, error) {
+	req := &pb.WriteReviewRequest{
+		UserId:  userID,
+		MangaId: mangaID,
+		Title:   title,
+		Body:    body,
+		Spoiler: spoiler,
+	}
+
+	log.Printf("gRPC Client: Writing review of manga %s for user %s", mangaID, userID)
+
+	resp, err := c.client.WriteReview(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("WriteReview RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// VoteReview votes on a review via gRPC
+func (c *Client) VoteReview(ctx context.Context, userID, reviewID string, helpful bool) (*pb.ReviewResponse, error) {
+	req := &pb.VoteReviewRequest{
+		UserId:   userID,
+		ReviewId: reviewID,
+		Helpful:  helpful,
+	}
+
+	log.Printf("gRPC Client: Voting on review %s for user %s", reviewID, userID)
+
+	resp, err := c.client.VoteReview(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("VoteReview RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteReview deletes a user's review of a manga via gRPC
+func (c *Client) DeleteReview(ctx context.Context, userID, mangaID string) (*pb.DeleteReviewResponse, error) {
+	req := &pb.DeleteReviewRequest{
+		UserId:  userID,
+		MangaId: mangaID,
+	}
+
+	log.Printf("gRPC Client: Deleting review of manga %s for user %s", mangaID, userID)
+
+	resp, err := c.client.DeleteReview(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("DeleteReview RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// GrantRole grants a role to a user via gRPC; the context must carry an admin's token
+func (c *Client) GrantRole(ctx context.Context, userID, role string) (*pb.RoleResponse, error) {
+	req := &pb.RoleRequest{
+		TargetUserId: userID,
+		Role:         role,
+	}
+
+	log.Printf("gRPC Client: Granting role %s to user %s", role, userID)
+
+	resp, err := c.client.GrantRole(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GrantRole RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
+// RevokeRole revokes a role of a user via gRPC; the context must carry an admin's token
+func (c *Client) RevokeRole(ctx context.Context, userID, role string) (*pb.RoleResponse, error) {
+	req := &pb.RoleRequest{
+		TargetUserId: userID,
+		Role:         role,
+	}
+
+	log.Printf("gRPC Client: Revoking role %s of user %s", role, userID)
+
+	resp, err := c.client.RevokeRole(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("RevokeRole RPC failed: %v", err)
+	}
+
+	return resp, nil
+}
+
 // Close closes the gRPC client connection
 func (c *Client) Close() error {
 	if c.conn != nil {
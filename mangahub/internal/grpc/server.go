@@ -23,17 +23,19 @@ type Server struct {
 	MangaService  *manga.Service
 	UserService   *user.Service
 	RatingService *manga.RatingService
+	ReviewService *manga.ReviewService
 	grpcServer    *grpc.Server
 	tcpConn       net.Conn
 	tcpMu         sync.Mutex
 }
 
 // NewServer creates a new gRPC server
-func NewServer(mangaService *manga.Service, userService *user.Service, ratingService *manga.RatingService) *Server {
+func NewServer(mangaService *manga.Service, userService *user.Service, ratingService *manga.RatingService, reviewService *manga.ReviewService) *Server {
 	return &Server{
 		MangaService:  mangaService,
 		UserService:   userService,
 		RatingService: ratingService,
+		ReviewService: reviewService,
 	}
 }
 
@@ -113,6 +115,7 @@ func modelMangaToPB(m *models.Manga) *pb.Manga {
 		CoverUrl:        m.CoverURL,
 		PublicationYear: int32(m.PublicationYear),
 		Rating:          m.Rating,
+		WeightedRating:  m.WeightedRating,
 		CreatedAt:       m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
@@ -217,16 +220,8 @@ func (s *Server) GetLibrary(ctx context.Context, req *pb.LibraryRequest) (*pb.Li
 	// Convert models.UserProgress to pb.UserProgress
 	convertProgress := func(progressList []models.UserProgress) []*pb.UserProgress {
 		result := make([]*pb.UserProgress, len(progressList))
-		for i, p := range progressList {
-			result[i] = &pb.UserProgress{
-				MangaId:        p.MangaID,
-				CurrentChapter: int32(p.CurrentChapter),
-				Status:         p.Status,
-				LastUpdated:    p.LastUpdated.Format("2006-01-02T15:04:05Z07:00"),
-				Title:          p.Title,
-				Author:         p.Author,
-				CoverUrl:       p.CoverURL,
-			}
+		for i := range progressList {
+			result[i] = modelProgressToPB(&progressList[i])
 		}
 		return result
 	}
@@ -241,6 +236,168 @@ func (s *Server) GetLibrary(ctx context.Context, req *pb.LibraryRequest) (*pb.Li
 	}, nil
 }
 
+// Helper function to convert models.UserProgress to pb.UserProgress
+func modelProgressToPB(p *models.UserProgress) *pb.UserProgress {
+	progress := &pb.UserProgress{
+		MangaId:        p.MangaID,
+		CurrentChapter: int32(p.CurrentChapter),
+		Status:         p.Status,
+		LastUpdated:    p.LastUpdated.Format("2006-01-02T15:04:05Z07:00"),
+		Title:          p.Title,
+		Author:         p.Author,
+		CoverUrl:       p.CoverURL,
+		Notes:          p.Notes,
+		Tags:           p.Tags,
+		RereadCount:    int32(p.RereadCount),
+		Clock:          p.Clock,
+	}
+	if p.StartedAt != nil {
+		progress.StartedAt = p.StartedAt.Format(time.RFC3339)
+	}
+	if p.FinishedAt != nil {
+		progress.FinishedAt = p.FinishedAt.Format(time.RFC3339)
+	}
+	return progress
+}
+
+// GetLibraryChanges returns the user's library, rating and collection changes since a sync token
+func (s *Server) GetLibraryChanges(ctx context.Context, req *pb.LibraryChangesRequest) (*pb.LibraryChangesResponse, error) {
+	log.Printf("gRPC GetLibraryChanges called for user: %s", req.UserId)
+
+	changes, err := s.UserService.GetLibraryChanges(req.UserId, req.SyncToken, int(req.Limit))
+	if err != nil {
+		return &pb.LibraryChangesResponse{
+			Error: fmt.Sprintf("Failed to get library changes: %v", err),
+		}, nil
+	}
+
+	response := &pb.LibraryChangesResponse{
+		SyncToken: changes.SyncToken,
+		Full:      changes.Full,
+		HasMore:   changes.HasMore,
+	}
+	for i := range changes.Library {
+		response.Library = append(response.Library, modelProgressToPB(&changes.Library[i]))
+	}
+	subScore := func(score *int) int32 {
+		if score == nil {
+			return 0
+		}
+		return int32(*score)
+	}
+	for _, rating := range changes.Ratings {
+		response.Ratings = append(response.Ratings, &pb.UserRating{
+			MangaId:    rating.MangaID,
+			Rating:     int32(rating.Rating),
+			Story:      subScore(rating.Story),
+			Art:        subScore(rating.Art),
+			Characters: subScore(rating.Characters),
+			UpdatedAt:  rating.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	for i := range changes.Collections {
+		response.Collections = append(response.Collections, modelCollectionToPB(&changes.Collections[i]))
+	}
+	for _, tombstone := range changes.Deleted {
+		response.Deleted = append(response.Deleted, &pb.SyncTombstone{
+			Entity:    tombstone.Entity,
+			Id:        tombstone.ID,
+			DeletedAt: tombstone.DeletedAt.Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
+
+// ApplyProgressOps merges client-stamped progress ops, made possibly offline, into the library
+func (s *Server) ApplyProgressOps(ctx context.Context, req *pb.ProgressOpsRequest) (*pb.ProgressOpsResponse, error) {
+	log.Printf("gRPC ApplyProgressOps called for user: %s, ops: %d", req.UserId, len(req.Ops))
+
+	ops := make([]models.ProgressOp, len(req.Ops))
+	for i, op := range req.Ops {
+		clientTime, err := time.Parse(time.RFC3339, op.ClientTime)
+		if err != nil {
+			return &pb.ProgressOpsResponse{
+				Error: fmt.Sprintf("invalid client_time of op %d: %v", i, err),
+			}, nil
+		}
+		ops[i] = models.ProgressOp{
+			DeviceID:   op.DeviceId,
+			Clock:      op.Clock,
+			ClientTime: clientTime,
+			MangaID:    op.MangaId,
+			Status:     op.Status,
+		}
+		if op.CurrentChapter != nil {
+			chapter := int(*op.CurrentChapter)
+			ops[i].CurrentChapter = &chapter
+		}
+	}
+
+	result, err := s.UserService.ApplyProgressOps(req.UserId, ops)
+	if err != nil {
+		return &pb.ProgressOpsResponse{
+			Error: fmt.Sprintf("Failed to apply progress ops: %v", err),
+		}, nil
+	}
+
+	response := &pb.ProgressOpsResponse{
+		Conflicts: int32(result.Conflicts),
+		Clock:     result.Clock,
+	}
+	for _, r := range result.Results {
+		opResult := &pb.ProgressOpResult{
+			DeviceId: r.DeviceID,
+			Clock:    r.Clock,
+			MangaId:  r.MangaID,
+			Outcome:  r.Outcome,
+			Error:    r.Error,
+		}
+		if r.Entry != nil {
+			opResult.Entry = modelProgressToPB(r.Entry)
+		}
+		response.Results = append(response.Results, opResult)
+	}
+
+	return response, nil
+}
+
+// GetProgressConflicts lists the conflicts found while merging the user's progress ops
+func (s *Server) GetProgressConflicts(ctx context.Context, req *pb.ProgressConflictsRequest) (*pb.ProgressConflictsResponse, error) {
+	log.Printf("gRPC GetProgressConflicts called for user: %s", req.UserId)
+
+	conflicts, total, err := s.UserService.GetProgressConflicts(req.UserId, req.MangaId, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return &pb.ProgressConflictsResponse{
+			Error: fmt.Sprintf("Failed to get progress conflicts: %v", err),
+		}, nil
+	}
+
+	response := &pb.ProgressConflictsResponse{Total: int32(total)}
+	for _, c := range conflicts {
+		conflict := &pb.ProgressConflict{
+			Id:              int32(c.ID),
+			MangaId:         c.MangaID,
+			Title:           c.Title,
+			DeviceId:        c.DeviceID,
+			Clock:           c.Clock,
+			ClientTime:      c.ClientTime.Format(time.RFC3339),
+			RequestedStatus: c.RequestedStatus,
+			ResolvedChapter: int32(c.ResolvedChapter),
+			ResolvedStatus:  c.ResolvedStatus,
+			Reason:          c.Reason,
+			CreatedAt:       c.CreatedAt.Format(time.RFC3339),
+		}
+		if c.RequestedChapter != nil {
+			chapter := int32(*c.RequestedChapter)
+			conflict.RequestedChapter = &chapter
+		}
+		response.Conflicts = append(response.Conflicts, conflict)
+	}
+
+	return response, nil
+}
+
 // AddToLibrary adds a manga to user's library
 func (s *Server) AddToLibrary(ctx context.Context, req *pb.AddToLibraryRequest) (*pb.AddToLibraryResponse, error) {
 	log.Printf("gRPC AddToLibrary called for user: %s, manga: %s", req.UserId, req.MangaId)
@@ -309,7 +466,11 @@ func (s *Server) GetLibraryStats(ctx context.Context, req *pb.LibraryStatsReques
 func (s *Server) RateManga(ctx context.Context, req *pb.RatingRequest) (*pb.RatingResponse, error) {
 	log.Printf("gRPC RateManga called for user: %s, manga: %s, rating: %d", req.UserId, req.MangaId, req.Rating)
 
-	err := s.RatingService.RateManga(req.UserId, req.MangaId, int(req.Rating))
+	err := s.RatingService.RateManga(req.UserId, req.MangaId, int(req.Rating), models.RatingSubScores{
+		Story:      subScoreFromPB(req.Story),
+		Art:        subScoreFromPB(req.Art),
+		Characters: subScoreFromPB(req.Characters),
+	})
 	if err != nil {
 		return &pb.RatingResponse{
 			Success: false,
@@ -328,13 +489,23 @@ func (s *Server) RateManga(ctx context.Context, req *pb.RatingRequest) (*pb.Rati
 	}
 
 	return &pb.RatingResponse{
-		Success:       true,
-		Message:       "Rating submitted successfully",
-		AverageRating: stats.AverageRating,
-		TotalRatings:  int32(stats.TotalRatings),
+		Success:        true,
+		Message:        "Rating submitted successfully",
+		AverageRating:  stats.AverageRating,
+		TotalRatings:   int32(stats.TotalRatings),
+		WeightedRating: stats.WeightedRating,
 	}, nil
 }
 
+// subScoreFromPB converts an optional sub-score, where 0 means not given
+func subScoreFromPB(score int32) *int {
+	if score == 0 {
+		return nil
+	}
+	value := int(score)
+	return &value
+}
+
 // GetMangaRatings retrieves rating statistics for a manga
 func (s *Server) GetMangaRatings(ctx context.Context, req *pb.MangaRatingRequest) (*pb.MangaRatingResponse, error) {
 	log.Printf("gRPC GetMangaRatings called for manga: %s", req.MangaId)
@@ -362,6 +533,8 @@ func (s *Server) GetMangaRatings(ctx context.Context, req *pb.MangaRatingRequest
 		TotalRatings:       int32(stats.TotalRatings),
 		UserRating:         userRating,
 		RatingDistribution: ratingDistribution,
+		WeightedRating:     stats.WeightedRating,
+		SubScores:          stats.SubScores,
 	}, nil
 }
 
@@ -408,6 +581,15 @@ func (s *Server) GetUserProfile(ctx context.Context, req *pb.GetUserProfileReque
 func (s *Server) UpdateUserProfile(ctx context.Context, req *pb.UpdateUserProfileRequest) (*pb.UpdateUserProfileResponse, error) {
 	log.Printf("gRPC UpdateUserProfile called for user: %s", req.UserId)
 
+	// Account changes need a fresh second factor. The caller's address is not
+	// known here (calls come through the API server), so only the account is throttled.
+	if err := s.UserService.RequireSecondFactor(req.UserId, req.OtpCode, models.SessionClient{}); err != nil {
+		return &pb.UpdateUserProfileResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to update profile: %v", err),
+		}, nil
+	}
+
 	profile, err := s.UserService.UpdateProfile(req.UserId, req.Username, req.Email)
 	if err != nil {
 		return &pb.UpdateUserProfileResponse{
@@ -432,7 +614,16 @@ func (s *Server) UpdateUserProfile(ctx context.Context, req *pb.UpdateUserProfil
 func (s *Server) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
 	log.Printf("gRPC ChangePassword called for user: %s", req.UserId)
 
-	err := s.UserService.ChangePassword(req.UserId, req.OldPassword, req.NewPassword)
+	// Account changes need a fresh second factor. The caller's address is not
+	// known here (calls come through the API server), so only the account is throttled.
+	if err := s.UserService.RequireSecondFactor(req.UserId, req.OtpCode, models.SessionClient{}); err != nil {
+		return &pb.ChangePasswordResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to change password: %v", err),
+		}, nil
+	}
+
+	err := s.UserService.ChangePassword(req.UserId, req.OldPassword, req.NewPassword, callerSessionID(ctx))
 	if err != nil {
 		return &pb.ChangePasswordResponse{
 			Success: false,
@@ -446,6 +637,324 @@ func (s *Server) ChangePassword(ctx context.Context, req *pb.ChangePasswordReque
 	}, nil
 }
 
+// Helper function to convert models.Collection to pb.Collection
+func modelCollectionToPB(c *models.Collection) *pb.Collection {
+	if c == nil {
+		return nil
+	}
+	collection := &pb.Collection{
+		Id:          c.ID,
+		UserId:      c.UserID,
+		Owner:       c.Owner,
+		Name:        c.Name,
+		Description: c.Description,
+		Visibility:  c.Visibility,
+		ShareToken:  c.ShareToken,
+		SortOrder:   int32(c.SortOrder),
+		ItemCount:   int32(c.ItemCount),
+		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   c.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, item := range c.Items {
+		collection.Items = append(collection.Items, &pb.CollectionItem{
+			MangaId:  item.MangaID,
+			Position: int32(item.Position),
+			AddedAt:  item.AddedAt.Format(time.RFC3339),
+			Title:    item.Title,
+			Author:   item.Author,
+			CoverUrl: item.CoverURL,
+		})
+	}
+	return collection
+}
+
+// ListCollections lists a user's collections
+func (s *Server) ListCollections(ctx context.Context, req *pb.ListCollectionsRequest) (*pb.ListCollectionsResponse, error) {
+	log.Printf("gRPC ListCollections called for user: %s", req.UserId)
+
+	collections, err := s.UserService.GetCollections(req.UserId)
+	if err != nil {
+		return &pb.ListCollectionsResponse{
+			Error: fmt.Sprintf("Failed to get collections: %v", err),
+		}, nil
+	}
+
+	response := &pb.ListCollectionsResponse{}
+	for i := range collections {
+		response.Collections = append(response.Collections, modelCollectionToPB(&collections[i]))
+	}
+	return response, nil
+}
+
+// GetCollection retrieves one of a user's collections with its items
+func (s *Server) GetCollection(ctx context.Context, req *pb.GetCollectionRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC GetCollection called: User=%s, Collection=%s", req.UserId, req.CollectionId)
+
+	collection, err := s.UserService.GetCollection(req.UserId, req.CollectionId)
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to get collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// CreateCollection creates a new collection
+func (s *Server) CreateCollection(ctx context.Context, req *pb.CreateCollectionRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC CreateCollection called: User=%s, Name=%s", req.UserId, req.Name)
+
+	collection, err := s.UserService.CreateCollection(req.UserId, models.CreateCollectionRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to create collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// UpdateCollection updates the name, description or visibility of a collection
+func (s *Server) UpdateCollection(ctx context.Context, req *pb.UpdateCollectionRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC UpdateCollection called: User=%s, Collection=%s", req.UserId, req.CollectionId)
+
+	collection, err := s.UserService.UpdateCollection(req.UserId, req.CollectionId, models.UpdateCollectionRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to update collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// DeleteCollection deletes a collection
+func (s *Server) DeleteCollection(ctx context.Context, req *pb.DeleteCollectionRequest) (*pb.DeleteCollectionResponse, error) {
+	log.Printf("gRPC DeleteCollection called: User=%s, Collection=%s", req.UserId, req.CollectionId)
+
+	if err := s.UserService.DeleteCollection(req.UserId, req.CollectionId); err != nil {
+		return &pb.DeleteCollectionResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to delete collection: %v", err),
+		}, nil
+	}
+
+	return &pb.DeleteCollectionResponse{
+		Success: true,
+		Message: "Collection deleted successfully",
+	}, nil
+}
+
+// AddToCollection appends a manga to a collection
+func (s *Server) AddToCollection(ctx context.Context, req *pb.CollectionItemRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC AddToCollection called: User=%s, Collection=%s, Manga=%s", req.UserId, req.CollectionId, req.MangaId)
+
+	collection, err := s.UserService.AddToCollection(req.UserId, req.CollectionId, req.MangaId)
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to add to collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// RemoveFromCollection removes a manga from a collection
+func (s *Server) RemoveFromCollection(ctx context.Context, req *pb.CollectionItemRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC RemoveFromCollection called: User=%s, Collection=%s, Manga=%s", req.UserId, req.CollectionId, req.MangaId)
+
+	collection, err := s.UserService.RemoveFromCollection(req.UserId, req.CollectionId, req.MangaId)
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to remove from collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// ReorderCollection sets the manual order of a collection
+func (s *Server) ReorderCollection(ctx context.Context, req *pb.ReorderCollectionRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC ReorderCollection called: User=%s, Collection=%s", req.UserId, req.CollectionId)
+
+	collection, err := s.UserService.ReorderCollection(req.UserId, req.CollectionId, req.MangaIds)
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to reorder collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// GetSharedCollection retrieves an unlisted or public collection by its share token
+func (s *Server) GetSharedCollection(ctx context.Context, req *pb.GetSharedCollectionRequest) (*pb.CollectionResponse, error) {
+	log.Printf("gRPC GetSharedCollection called")
+
+	collection, err := s.UserService.GetSharedCollection(req.ShareToken)
+	if err != nil {
+		return &pb.CollectionResponse{
+			Error: fmt.Sprintf("Failed to get shared collection: %v", err),
+		}, nil
+	}
+
+	return &pb.CollectionResponse{Collection: modelCollectionToPB(collection)}, nil
+}
+
+// GetRecommendations returns collaborative-filtering recommendations with explanations
+func (s *Server) GetRecommendations(ctx context.Context, req *pb.RecommendationsRequest) (*pb.RecommendationsResponse, error) {
+	log.Printf("gRPC GetRecommendations called for user: %s, limit: %d", req.UserId, req.Limit)
+
+	recommendations, err := s.UserService.GetReadingRecommendations(req.UserId, int(req.Limit))
+	if err != nil {
+		return &pb.RecommendationsResponse{
+			Error: fmt.Sprintf("Failed to get recommendations: %v", err),
+		}, nil
+	}
+
+	pbRecommendations := make([]*pb.Recommendation, 0, len(recommendations))
+	for i := range recommendations {
+		pbRecommendations = append(pbRecommendations, &pb.Recommendation{
+			Manga:   modelMangaToPB(&recommendations[i].Manga),
+			Score:   recommendations[i].Score,
+			Reasons: recommendations[i].Reasons,
+		})
+	}
+
+	return &pb.RecommendationsResponse{Recommendations: pbRecommendations}, nil
+}
+
+func modelReviewToPB(r *models.Review) *pb.Review {
+	if r == nil {
+		return nil
+	}
+	return &pb.Review{
+		Id:             r.ID,
+		UserId:         r.UserID,
+		Username:       r.Username,
+		MangaId:        r.MangaID,
+		Rating:         int32(r.Rating),
+		Title:          r.Title,
+		Body:           r.Body,
+		Spoiler:        r.Spoiler,
+		ChapterRead:    int32(r.ChapterRead),
+		Status:         r.Status,
+		HelpfulCount:   int32(r.HelpfulCount),
+		UnhelpfulCount: int32(r.UnhelpfulCount),
+		EditCount:      int32(r.EditCount),
+		UserVote:       r.UserVote,
+		CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// GetMangaReviews lists the published reviews of a manga
+func (s *Server) GetMangaReviews(ctx context.Context, req *pb.MangaReviewsRequest) (*pb.MangaReviewsResponse, error) {
+	log.Printf("gRPC GetMangaReviews called for manga: %s, sort: %s", req.MangaId, req.Sort)
+
+	reviews, total, err := s.ReviewService.GetMangaReviews(req.MangaId, req.UserId, req.Sort, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return &pb.MangaReviewsResponse{
+			Error: fmt.Sprintf("Failed to get reviews: %v", err),
+		}, nil
+	}
+
+	pbReviews := make([]*pb.Review, 0, len(reviews))
+	for i := range reviews {
+		pbReviews = append(pbReviews, modelReviewToPB(&reviews[i]))
+	}
+
+	return &pb.MangaReviewsResponse{Reviews: pbReviews, Total: int32(total)}, nil
+}
+
+// WriteReview creates or edits the user's review of a manga
+func (s *Server) WriteReview(ctx context.Context, req *pb.WriteReviewRequest) (*pb.ReviewResponse, error) {
+	log.Printf("gRPC WriteReview called: User=%s, Manga=%s", req.UserId, req.MangaId)
+
+	review, err := s.ReviewService.WriteReview(req.UserId, req.MangaId, models.WriteReviewRequest{
+		Title:   req.Title,
+		Body:    req.Body,
+		Spoiler: req.Spoiler,
+	})
+	if err != nil {
+		return &pb.ReviewResponse{
+			Error: fmt.Sprintf("Failed to write review: %v", err),
+		}, nil
+	}
+
+	return &pb.ReviewResponse{Review: modelReviewToPB(review)}, nil
+}
+
+// VoteReview records whether the user found a review helpful
+func (s *Server) VoteReview(ctx context.Context, req *pb.VoteReviewRequest) (*pb.ReviewResponse, error) {
+	log.Printf("gRPC VoteReview called: User=%s, Review=%s, Helpful=%v", req.UserId, req.ReviewId, req.Helpful)
+
+	review, err := s.ReviewService.VoteReview(req.UserId, req.ReviewId, req.Helpful)
+	if err != nil {
+		return &pb.ReviewResponse{
+			Error: fmt.Sprintf("Failed to vote on review: %v", err),
+		}, nil
+	}
+
+	return &pb.ReviewResponse{Review: modelReviewToPB(review)}, nil
+}
+
+// DeleteReview deletes the user's review of a manga
+func (s *Server) DeleteReview(ctx context.Context, req *pb.DeleteReviewRequest) (*pb.DeleteReviewResponse, error) {
+	log.Printf("gRPC DeleteReview called: User=%s, Manga=%s", req.UserId, req.MangaId)
+
+	if err := s.ReviewService.DeleteReview(req.UserId, req.MangaId); err != nil {
+		return &pb.DeleteReviewResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to delete review: %v", err),
+		}, nil
+	}
+
+	return &pb.DeleteReviewResponse{
+		Success: true,
+		Message: "Review deleted successfully",
+	}, nil
+}
+
+// GrantRole grants a role to a user (admin only, see methodPermissions)
+func (s *Server) GrantRole(ctx context.Context, req *pb.RoleRequest) (*pb.RoleResponse, error) {
+	grantedBy := callerID(ctx)
+	log.Printf("gRPC GrantRole called: User=%s, Role=%s, By=%s", req.TargetUserId, req.Role, grantedBy)
+
+	if err := s.UserService.GrantRole(req.TargetUserId, req.Role, grantedBy); err != nil {
+		return &pb.RoleResponse{UserId: req.TargetUserId, Error: fmt.Sprintf("Failed to grant role: %v", err)}, nil
+	}
+
+	return s.roleResponse(req.TargetUserId)
+}
+
+// RevokeRole revokes a role of a user (admin only, see methodPermissions)
+func (s *Server) RevokeRole(ctx context.Context, req *pb.RoleRequest) (*pb.RoleResponse, error) {
+	log.Printf("gRPC RevokeRole called: User=%s, Role=%s, By=%s", req.TargetUserId, req.Role, callerID(ctx))
+
+	if err := s.UserService.RevokeRole(req.TargetUserId, req.Role); err != nil {
+		return &pb.RoleResponse{UserId: req.TargetUserId, Error: fmt.Sprintf("Failed to revoke role: %v", err)}, nil
+	}
+
+	return s.roleResponse(req.TargetUserId)
+}
+
+func (s *Server) roleResponse(userID string) (*pb.RoleResponse, error) {
+	roles, err := s.UserService.GetUserRoles(userID)
+	if err != nil {
+		return &pb.RoleResponse{UserId: userID, Error: fmt.Sprintf("Failed to get roles: %v", err)}, nil
+	}
+	return &pb.RoleResponse{UserId: userID, Roles: roles}, nil
+}
+
 // Start starts the gRPC server
 func (s *Server) Start(port string) error {
 	lis, err := net.Listen("tcp", "0.0.0.0:"+port)
@@ -453,7 +962,8 @@ func (s *Server) Start(port string) error {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
-	s.grpcServer = grpc.NewServer()
+	// Calls on behalf of a user need that user's access token
+	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(authInterceptor))
 
 	// Register the MangaService with the gRPC server
 	pb.RegisterMangaServiceServer(s.grpcServer, s)
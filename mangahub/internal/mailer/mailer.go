@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"log"
+	"mangahub/pkg/database"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewFromEnv returns the mailer selected by MAIL_DRIVER: "smtp" sends through
+// SMTP_HOST, anything else (the default, "file") writes to the outbox directory
+// MAIL_OUTBOX_DIR, which defaults to data/outbox.
+func NewFromEnv() Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "MangaHub <no-reply@mangahub.local>"
+	}
+
+	if strings.EqualFold(os.Getenv("MAIL_DRIVER"), "smtp") {
+		if os.Getenv("SMTP_HOST") != "" {
+			return NewSMTPMailer(os.Getenv("SMTP_HOST"), os.Getenv("SMTP_PORT"),
+				os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+		}
+		log.Println("Warning: MAIL_DRIVER=smtp but SMTP_HOST is not set, writing emails to the outbox")
+	}
+
+	dir := os.Getenv("MAIL_OUTBOX_DIR")
+	if dir == "" {
+		dataDir, err := database.DataDir()
+		if err != nil {
+			dataDir = "data"
+		}
+		dir = filepath.Join(dataDir, "outbox")
+	}
+	return NewOutboxMailer(dir, from)
+}
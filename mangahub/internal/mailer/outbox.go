@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+// unsafeFileChars are replaced in the recipient part of outbox file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._@-]`)
+
+// outboxSeq keeps file names of messages written in the same instant apart
+var outboxSeq atomic.Uint64
+
+// OutboxMailer writes each email to a .eml file in a directory instead of
+// sending it, for development and tests without a mail server
+type OutboxMailer struct {
+	Dir  string
+	From string
+}
+
+// NewOutboxMailer creates a mailer writing to dir
+func NewOutboxMailer(dir, from string) *OutboxMailer {
+	return &OutboxMailer{Dir: dir, From: from}
+}
+
+// Send writes the message to the outbox
+func (m *OutboxMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%04d-%s.eml", time.Now().Format("20060102-150405.000"),
+		outboxSeq.Add(1)%10000, unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, formatMessage(m.From, msg), 0600); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	log.Printf("Email to %s written to %s", msg.To, path)
+	return nil
+}
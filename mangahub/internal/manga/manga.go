@@ -102,9 +102,12 @@ func (s *Service) SearchManga(req models.MangaSearchRequest) ([]models.Manga, er
 		}
 	case "newest":
 		orderBy = "publication_year DESC, created_at DESC"
-	case "popular":
-		// Could be based on ratings, followers, etc. For now use total_chapters as proxy
-		orderBy = "total_chapters DESC"
+	case "rating":
+		// Bayesian-weighted rating, unrated manga last
+		orderBy = weightedRatingSQL + " DESC, title ASC"
+	case "popular", "trending", "trending_24h", "trending_7d", "trending_30d":
+		// Materialized popularity and trending scores, see RefreshPopularity
+		orderBy, _ = popularityOrder(req.Sort)
 	default:
 		orderBy = "title ASC"
 	}
@@ -246,18 +249,17 @@ func (s *Service) GetAllManga(limit, offset int) ([]models.Manga, error) {
 	return mangaList, nil
 }
 
-// GetPopularManga retrieves popular manga (for now, just returns all manga)
+// GetPopularManga retrieves the most popular manga by their materialized popularity score
 func (s *Service) GetPopularManga(limit int) ([]models.Manga, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 10
 	}
 
-	// For now, just return the first N manga ordered by title
-	// In a real application, you'd have a popularity metric
+	orderBy, _ := popularityOrder("popular")
 	rows, err := s.db.Query(`
 		SELECT id, title, author, genres, status, total_chapters, description, cover_url, publication_year, created_at 
 		FROM manga 
-		ORDER BY title 
+		ORDER BY `+orderBy+` 
 		LIMIT ?`, limit)
 
 	if err != nil {
@@ -394,6 +396,7 @@ func (s *Service) CreateManga(manga models.Manga) (*models.Manga, error) {
 		return nil, fmt.Errorf("failed to create manga: %w", err)
 	}
 
+	rebuildSimilarIndexAsync(s.db)
 	return &manga, nil
 }
 
@@ -467,6 +470,7 @@ func (s *Service) UpdateManga(id string, manga models.Manga) (*models.Manga, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to update manga: %w", err)
 	}
+	rebuildSimilarIndexAsync(s.db)
 
 	// Return updated manga
 	return s.GetManga(id)
@@ -492,6 +496,33 @@ func (s *Service) DeleteManga(id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete user progress: %w", err)
 	}
+	_, err = tx.Exec("DELETE FROM progress_conflicts WHERE manga_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete progress conflicts: %w", err)
+	}
+
+	// Delete similarity entries (content index and recommendations)
+	_, err = tx.Exec("DELETE FROM similar_manga WHERE manga_id = ? OR similar_id = ?", id, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete similar manga: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM manga_similarity WHERE manga_id = ? OR similar_id = ?", id, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete manga similarity: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM manga_popularity WHERE manga_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete manga popularity: %w", err)
+	}
+
+	// Delete reviews with their votes, history and reports
+	if err := deleteReviews(tx, "manga_id = ?", id); err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM rating_flags WHERE manga_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rating flags: %w", err)
+	}
 
 	// Delete manga
 	_, err = tx.Exec("DELETE FROM manga WHERE id = ?", id)
@@ -0,0 +1,339 @@
+package manga
+
+import (
+	"fmt"
+	"log"
+	"mangahub/internal/activity"
+	"mangahub/pkg/models"
+	"math"
+	"sync"
+	"time"
+)
+
+// Trending windows. WindowAll ranks by all-time popularity.
+const (
+	Window24h = "24h"
+	Window7d  = "7d"
+	Window30d = "30d"
+	WindowAll = "all"
+)
+
+// windowColumns maps each window to its manga_popularity column
+var windowColumns = map[string]string{
+	Window24h: "trending_24h",
+	Window7d:  "trending_7d",
+	Window30d: "trending_30d",
+	WindowAll: "popularity",
+}
+
+var windowDurations = map[string]time.Duration{
+	Window24h: 24 * time.Hour,
+	Window7d:  7 * 24 * time.Hour,
+	Window30d: 30 * 24 * time.Hour,
+}
+
+// Popularity settings
+const (
+	activeReaderWindow = 30 * 24 * time.Hour // Readers with activity this recent are active
+	maxEventChapters   = 10                  // Chapters counted per progress event, so bulk jumps don't dominate
+)
+
+// Trending weights of reading events
+const (
+	trendAddWeight      = 3.0
+	trendChapterWeight  = 1.0
+	trendCompleteWeight = 2.0
+	trendRatingWeight   = 2.0
+)
+
+// popularityMu serializes refreshes from the scheduler and lazy first use
+var popularityMu sync.Mutex
+
+// TrendingColumn returns the manga_popularity column for a window ("" is 7d)
+func TrendingColumn(window string) (string, error) {
+	if window == "" {
+		window = Window7d
+	}
+	column, ok := windowColumns[window]
+	if !ok {
+		return "", fmt.Errorf("invalid window %q: use 24h, 7d, 30d or all", window)
+	}
+	return column, nil
+}
+
+// RefreshPopularity materializes the popularity and trending scores of every manga
+// with reading activity into manga_popularity.
+//
+// Popularity combines library adds, active readers (activity in the last 30 days),
+// ratings weighted by their average, and chapters read, each dampened
+// logarithmically so that no single signal dominates. Trending scores sum the
+// reading events of the last 24 hours, 7 days or 30 days, each decaying
+// exponentially with a half-life of a quarter of the window. Only changes made by
+// users count; imports and MAL pulls replay reading that happened elsewhere, and
+// quarantined ratings are left out.
+func (s *Service) RefreshPopularity() error {
+	popularityMu.Lock()
+	defer popularityMu.Unlock()
+	started := time.Now()
+	now := time.Now().UTC()
+
+	stats := make(map[string]*models.TrendingManga)
+	trending := make(map[string]map[string]float64)
+	entry := func(mangaID string) *models.TrendingManga {
+		if stats[mangaID] == nil {
+			stats[mangaID] = &models.TrendingManga{}
+			trending[mangaID] = make(map[string]float64)
+		}
+		return stats[mangaID]
+	}
+
+	rows, err := s.db.Query("SELECT manga_id, COUNT(*) FROM user_progress GROUP BY manga_id")
+	if err != nil {
+		return fmt.Errorf("failed to count library entries: %w", err)
+	}
+	for rows.Next() {
+		var mangaID string
+		var count int
+		if err := rows.Scan(&mangaID, &count); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan library count: %w", err)
+		}
+		entry(mangaID).LibraryCount = count
+	}
+	rows.Close()
+
+	rows, err = s.db.Query("SELECT manga_id, COUNT(*), AVG(rating) FROM manga_ratings WHERE quarantined = 0 GROUP BY manga_id")
+	if err != nil {
+		return fmt.Errorf("failed to get rating stats: %w", err)
+	}
+	for rows.Next() {
+		var mangaID string
+		var count int
+		var average float64
+		if err := rows.Scan(&mangaID, &count, &average); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan rating stats: %w", err)
+		}
+		e := entry(mangaID)
+		e.RatingCount = count
+		e.AverageRating = average
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`
+		SELECT manga_id, COALESCE(SUM(chapter_to - COALESCE(chapter_from, 0)), 0)
+		FROM reading_events
+		WHERE source = ? AND chapter_to > COALESCE(chapter_from, 0)
+		GROUP BY manga_id`, activity.SourceUser)
+	if err != nil {
+		return fmt.Errorf("failed to count chapters read: %w", err)
+	}
+	for rows.Next() {
+		var mangaID string
+		var chapters int
+		if err := rows.Scan(&mangaID, &chapters); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan chapters read: %w", err)
+		}
+		entry(mangaID).ChaptersRead = chapters
+	}
+	rows.Close()
+
+	// Recent events give the active readers and the trending scores
+	since := now.Add(-activeReaderWindow)
+	rows, err = s.db.Query(`
+		SELECT user_id, manga_id, event_type, COALESCE(chapter_from, 0), COALESCE(chapter_to, 0),
+			COALESCE(status_to, ''), created_at
+		FROM reading_events
+		WHERE source = ? AND event_type IN (?, ?, ?, ?) AND created_at >= ?
+			AND NOT (event_type = ? AND EXISTS (
+				SELECT 1 FROM manga_ratings q
+				WHERE q.user_id = reading_events.user_id AND q.manga_id = reading_events.manga_id AND q.quarantined = 1))`,
+		activity.SourceUser, activity.EventAdded, activity.EventProgress, activity.EventStatus, activity.EventRating, since,
+		activity.EventRating)
+	if err != nil {
+		return fmt.Errorf("failed to get recent reading events: %w", err)
+	}
+	active := make(map[string]map[string]bool)
+	for rows.Next() {
+		var userID, mangaID, eventType, statusTo string
+		var chapterFrom, chapterTo int
+		var createdAt time.Time
+		if err := rows.Scan(&userID, &mangaID, &eventType, &chapterFrom, &chapterTo, &statusTo, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan reading event: %w", err)
+		}
+		entry(mangaID)
+
+		if eventType != activity.EventRating {
+			if active[mangaID] == nil {
+				active[mangaID] = make(map[string]bool)
+			}
+			active[mangaID][userID] = true
+		}
+
+		weight := 0.0
+		switch eventType {
+		case activity.EventAdded:
+			weight = trendAddWeight
+		case activity.EventRating:
+			weight = trendRatingWeight
+		}
+		if chapters := chapterTo - chapterFrom; chapters > 0 {
+			weight += trendChapterWeight * float64(min(chapters, maxEventChapters))
+		}
+		if statusTo == "completed" && eventType != activity.EventAdded {
+			weight += trendCompleteWeight
+		}
+
+		age := now.Sub(createdAt.UTC())
+		for window, duration := range windowDurations {
+			if age > duration {
+				continue
+			}
+			halfLife := duration / 4
+			trending[mangaID][window] += weight * math.Exp2(-float64(age)/float64(halfLife))
+		}
+	}
+	rows.Close()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM manga_popularity"); err != nil {
+		return fmt.Errorf("failed to clear manga popularity: %w", err)
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO manga_popularity (manga_id, library_count, active_readers, rating_count, average_rating,
+			chapters_read, popularity, trending_24h, trending_7d, trending_30d, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare manga popularity insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for mangaID, e := range stats {
+		e.ActiveReaders = len(active[mangaID])
+		popularity := math.Log1p(float64(e.LibraryCount)) +
+			1.5*math.Log1p(float64(e.ActiveReaders)) +
+			math.Log1p(float64(e.RatingCount))*e.AverageRating/MaxRating +
+			0.5*math.Log1p(float64(e.ChaptersRead))
+
+		_, err := stmt.Exec(mangaID, e.LibraryCount, e.ActiveReaders, e.RatingCount, e.AverageRating,
+			e.ChaptersRead, popularity, trending[mangaID][Window24h], trending[mangaID][Window7d],
+			trending[mangaID][Window30d], now)
+		if err != nil {
+			return fmt.Errorf("failed to store manga popularity: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit manga popularity: %w", err)
+	}
+
+	log.Printf("Manga popularity refreshed: %d manga in %v", len(stats), time.Since(started))
+	return nil
+}
+
+// StartPopularityRefresh recomputes popularity and trending scores now and then at every interval
+func (s *Service) StartPopularityRefresh(interval time.Duration) {
+	if err := s.RefreshPopularity(); err != nil {
+		log.Printf("Manga popularity refresh failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.RefreshPopularity(); err != nil {
+			log.Printf("Manga popularity refresh failed: %v", err)
+		}
+	}
+}
+
+// GetTrendingManga returns the manga with the highest trending score in the
+// window (24h, 7d or 30d; all ranks by popularity). Manga without activity in
+// the window are left out.
+func (s *Service) GetTrendingManga(window string, limit int) ([]models.TrendingManga, error) {
+	if window == "" {
+		window = Window7d
+	}
+	column, err := TrendingColumn(window)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// Materialize the scores on first use, e.g. before the scheduler has run
+	var materialized bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM manga_popularity)").Scan(&materialized); err != nil {
+		return nil, fmt.Errorf("failed to check manga popularity: %w", err)
+	}
+	if !materialized {
+		if err := s.RefreshPopularity(); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.db.Query(`
+		SELECT m.id, m.title, COALESCE(m.author, ''), COALESCE(m.genres, '[]'), COALESCE(m.status, ''),
+			COALESCE(m.total_chapters, 0), COALESCE(m.description, ''), COALESCE(m.cover_url, ''),
+			COALESCE(m.publication_year, 0), m.created_at, p.`+column+`,
+			p.library_count, p.active_readers, p.rating_count, p.average_rating, p.chapters_read
+		FROM manga_popularity p
+		JOIN manga m ON m.id = p.manga_id
+		WHERE p.`+column+` > 0
+		ORDER BY p.`+column+` DESC, m.title ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending manga: %w", err)
+	}
+	defer rows.Close()
+
+	trending := []models.TrendingManga{}
+	for rows.Next() {
+		item := models.TrendingManga{Window: window}
+		manga := &item.Manga
+		if err := rows.Scan(&manga.ID, &manga.Title, &manga.Author, &manga.GenresJSON, &manga.Status,
+			&manga.TotalChapters, &manga.Description, &manga.CoverURL, &manga.PublicationYear,
+			&manga.CreatedAt, &item.Score, &item.LibraryCount, &item.ActiveReaders, &item.RatingCount,
+			&item.AverageRating, &item.ChaptersRead); err != nil {
+			return nil, fmt.Errorf("failed to scan trending manga: %w", err)
+		}
+		if err := manga.GetGenres(); err != nil {
+			manga.Genres = []string{}
+		}
+		item.Score = math.Round(item.Score*1000) / 1000
+		item.AverageRating = math.Round(item.AverageRating*100) / 100
+		trending = append(trending, item)
+	}
+
+	return trending, nil
+}
+
+// popularityOrder returns the ORDER BY clause of the popular and trending search
+// sorts: popular, trending (7d), trending_24h, trending_7d and trending_30d
+func popularityOrder(sort string) (string, bool) {
+	window := ""
+	switch sort {
+	case "popular":
+		window = WindowAll
+	case "trending":
+		window = Window7d
+	case "trending_24h":
+		window = Window24h
+	case "trending_7d":
+		window = Window7d
+	case "trending_30d":
+		window = Window30d
+	default:
+		return "", false
+	}
+
+	column := windowColumns[window]
+	return "COALESCE((SELECT p." + column + " FROM manga_popularity p WHERE p.manga_id = manga.id), 0) DESC, title ASC", true
+}
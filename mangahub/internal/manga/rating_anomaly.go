@@ -0,0 +1,469 @@
+package manga
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mangahub/pkg/database"
+	"mangahub/pkg/models"
+	"sort"
+	"time"
+)
+
+// Rating flag statuses
+const (
+	FlagQuarantined = "quarantined" // Left out of aggregates, awaiting review
+	FlagCleared     = "cleared"     // Reviewed and put back into aggregates
+	FlagConfirmed   = "confirmed"   // Reviewed and kept out of aggregates
+)
+
+// Signals of the brigading detector
+const (
+	SignalNewAccount = "new_account" // Account younger than newAccountAge when rating
+	SignalBurst      = "burst"       // Part of a spike of ratings on the manga
+	SignalRatingOnly = "rating_only" // Account has no library entries, only ratings
+	SignalCorrelated = "correlated"  // Rates the same manga alike as an account created around the same time
+)
+
+// Detector settings
+const (
+	anomalyWindow       = 30 * 24 * time.Hour // Ratings set this recently are examined
+	newAccountAge       = 7 * 24 * time.Hour
+	burstWindow         = 24 * time.Hour // Centered on each rating
+	burstBaseline       = 30             // Days before the burst window giving a manga's usual rate
+	burstMinRatings     = 5
+	burstFactor         = 4.0 // Times the usual daily rate
+	correlatedMinShared = 3   // Manga two accounts must both have rated
+	correlatedMaxDiff   = 1   // Largest difference between their ratings of any of them
+	correlatedSignupGap = 72 * time.Hour
+)
+
+// ratingCandidate is a recently set rating with what the detector knows about it
+type ratingCandidate struct {
+	userID, mangaID  string
+	rating           int
+	ratedAt          time.Time
+	accountCreatedAt sql.NullTime
+	signals          []string
+}
+
+// DetectRatingAnomalies flags ratings of the last 30 days that look like
+// manipulation, of one manga or of all when mangaID is empty, and quarantines
+// them from aggregates. It returns the number of newly quarantined ratings.
+//
+// Each rating is checked for four signals: a new account, a burst of ratings on
+// the manga far above its usual rate, an account with nothing in its library,
+// and correlated voting with accounts created around the same time. A rating is
+// quarantined when it is part of both a burst and correlated voting, or part of
+// either and shows two other signals, so a new reader who rates during a burst is
+// not caught. Flags settled by an admin are left as they are.
+func (s *RatingService) DetectRatingAnomalies(mangaID string) (int, error) {
+	db := database.GetDB()
+	since := time.Now().UTC().Add(-anomalyWindow)
+
+	scope := ""
+	args := []interface{}{since}
+	if mangaID != "" {
+		scope = " AND r.manga_id = ?"
+		args = append(args, mangaID)
+	}
+
+	rows, err := db.Query(`
+		SELECT r.user_id, r.manga_id, r.rating, r.updated_at, u.created_at
+		FROM manga_ratings r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.updated_at >= ?`+scope, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recent ratings: %w", err)
+	}
+	var candidates []*ratingCandidate
+	for rows.Next() {
+		c := &ratingCandidate{}
+		if err := rows.Scan(&c.userID, &c.mangaID, &c.rating, &c.ratedAt, &c.accountCreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	bursts, err := findBurstRatings(db, candidates, since)
+	if err != nil {
+		return 0, err
+	}
+	ratingOnly, err := findRatingOnlyAccounts(db, since, scope, args)
+	if err != nil {
+		return 0, err
+	}
+	correlated, err := findCorrelatedAccounts(db, candidates, scope, args)
+	if err != nil {
+		return 0, err
+	}
+
+	var flagged []*ratingCandidate
+	for _, c := range candidates {
+		if c.accountCreatedAt.Valid && c.ratedAt.Sub(c.accountCreatedAt.Time) < newAccountAge {
+			c.signals = append(c.signals, SignalNewAccount)
+		}
+		if bursts[c] {
+			c.signals = append(c.signals, SignalBurst)
+		}
+		if ratingOnly[c.userID] {
+			c.signals = append(c.signals, SignalRatingOnly)
+		}
+		if correlated[c.userID] {
+			c.signals = append(c.signals, SignalCorrelated)
+		}
+		if (bursts[c] && correlated[c.userID]) || ((bursts[c] || correlated[c.userID]) && len(c.signals) >= 3) {
+			flagged = append(flagged, c)
+		}
+	}
+
+	return quarantineRatings(db, flagged)
+}
+
+// findBurstRatings returns the candidates rated within a spike on their manga: at
+// least burstMinRatings ratings in the 24 hours around them, and burstFactor
+// times the manga's daily rate over the month before
+func findBurstRatings(db *sql.DB, candidates []*ratingCandidate, since time.Time) (map[*ratingCandidate]bool, error) {
+	history := time.Duration(burstBaseline)*24*time.Hour + burstWindow
+	rows, err := db.Query(`
+		SELECT manga_id, updated_at FROM manga_ratings
+		WHERE updated_at >= ? AND manga_id IN (SELECT manga_id FROM manga_ratings WHERE updated_at >= ?)`,
+		since.Add(-history), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating times: %w", err)
+	}
+	defer rows.Close()
+
+	times := make(map[string][]time.Time)
+	for rows.Next() {
+		var mangaID string
+		var ratedAt time.Time
+		if err := rows.Scan(&mangaID, &ratedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rating time: %w", err)
+		}
+		times[mangaID] = append(times[mangaID], ratedAt)
+	}
+	for _, t := range times {
+		sort.Slice(t, func(i, j int) bool { return t[i].Before(t[j]) })
+	}
+
+	// countBetween counts the ratings of a manga in [from, to)
+	countBetween := func(t []time.Time, from, to time.Time) int {
+		lo := sort.Search(len(t), func(i int) bool { return !t[i].Before(from) })
+		hi := sort.Search(len(t), func(i int) bool { return !t[i].Before(to) })
+		return hi - lo
+	}
+
+	bursts := make(map[*ratingCandidate]bool)
+	for _, c := range candidates {
+		t := times[c.mangaID]
+		windowStart := c.ratedAt.Add(-burstWindow / 2)
+		inWindow := countBetween(t, windowStart, c.ratedAt.Add(burstWindow/2+time.Nanosecond))
+		usual := float64(countBetween(t, windowStart.Add(-time.Duration(burstBaseline)*24*time.Hour), windowStart)) / burstBaseline
+		if inWindow >= burstMinRatings && float64(inWindow) >= burstFactor*usual {
+			bursts[c] = true
+		}
+	}
+	return bursts, nil
+}
+
+// findRatingOnlyAccounts returns the recent raters without any library entry
+func findRatingOnlyAccounts(db *sql.DB, since time.Time, scope string, args []interface{}) (map[string]bool, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT r.user_id FROM manga_ratings r
+		WHERE r.updated_at >= ?`+scope+`
+			AND NOT EXISTS (SELECT 1 FROM user_progress p WHERE p.user_id = r.user_id)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rating-only accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan rating-only account: %w", err)
+		}
+		accounts[userID] = true
+	}
+	return accounts, nil
+}
+
+// findCorrelatedAccounts returns the recent raters that rated at least
+// correlatedMinShared manga within correlatedMaxDiff of another recent rater
+// whose account was created within correlatedSignupGap of theirs
+func findCorrelatedAccounts(db *sql.DB, candidates []*ratingCandidate, scope string, args []interface{}) (map[string]bool, error) {
+	created := make(map[string]sql.NullTime)
+	for _, c := range candidates {
+		created[c.userID] = c.accountCreatedAt
+	}
+
+	rows, err := db.Query(`
+		SELECT user_id, manga_id, rating FROM manga_ratings
+		WHERE user_id IN (SELECT r.user_id FROM manga_ratings r WHERE r.updated_at >= ?`+scope+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings of recent raters: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := make(map[string]map[string]int)
+	raters := make(map[string][]string)
+	for rows.Next() {
+		var userID, mangaID string
+		var rating int
+		if err := rows.Scan(&userID, &mangaID, &rating); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		if ratings[userID] == nil {
+			ratings[userID] = make(map[string]int)
+		}
+		ratings[userID][mangaID] = rating
+		raters[mangaID] = append(raters[mangaID], userID)
+	}
+
+	correlated := make(map[string]bool)
+	for userID, own := range ratings {
+		if correlated[userID] || len(own) < correlatedMinShared || !created[userID].Valid {
+			continue
+		}
+
+		// Count the manga each other rater shares with this one, dropping raters
+		// that disagree on any of them
+		shared := make(map[string]int)
+		disagree := make(map[string]bool)
+		for mangaID, rating := range own {
+			for _, other := range raters[mangaID] {
+				if other == userID || disagree[other] {
+					continue
+				}
+				diff := ratings[other][mangaID] - rating
+				if diff < -correlatedMaxDiff || diff > correlatedMaxDiff {
+					disagree[other] = true
+					continue
+				}
+				shared[other]++
+			}
+		}
+
+		for other, count := range shared {
+			if disagree[other] || count < correlatedMinShared || !created[other].Valid {
+				continue
+			}
+			gap := created[userID].Time.Sub(created[other].Time)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap <= correlatedSignupGap {
+				correlated[userID] = true
+				correlated[other] = true
+			}
+		}
+	}
+	return correlated, nil
+}
+
+// quarantineRatings records the flagged ratings and takes them out of aggregates.
+// Ratings already quarantined get their signals updated; settled flags are kept.
+func quarantineRatings(db *sql.DB, flagged []*ratingCandidate) (int, error) {
+	if len(flagged) == 0 {
+		return 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	quarantined := 0
+	now := time.Now()
+	for _, c := range flagged {
+		signals, err := json.Marshal(c.signals)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode signals: %w", err)
+		}
+
+		var status string
+		err = tx.QueryRow("SELECT status FROM rating_flags WHERE user_id = ? AND manga_id = ?",
+			c.userID, c.mangaID).Scan(&status)
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = tx.Exec(`
+				INSERT INTO rating_flags (user_id, manga_id, rating, signals, status, flagged_at)
+				VALUES (?, ?, ?, ?, ?, ?)`, c.userID, c.mangaID, c.rating, string(signals), FlagQuarantined, now)
+			if err != nil {
+				return 0, fmt.Errorf("failed to flag rating: %w", err)
+			}
+			_, err = tx.Exec("UPDATE manga_ratings SET quarantined = 1 WHERE user_id = ? AND manga_id = ?",
+				c.userID, c.mangaID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to quarantine rating: %w", err)
+			}
+			quarantined++
+		case err != nil:
+			return 0, fmt.Errorf("failed to check rating flag: %w", err)
+		case status == FlagQuarantined:
+			_, err = tx.Exec("UPDATE rating_flags SET rating = ?, signals = ? WHERE user_id = ? AND manga_id = ?",
+				c.rating, string(signals), c.userID, c.mangaID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to update rating flag: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rating flags: %w", err)
+	}
+	if quarantined > 0 {
+		log.Printf("Rating anomaly detection quarantined %d ratings", quarantined)
+	}
+	return quarantined, nil
+}
+
+// StartRatingAnomalyScan runs the brigading detector over all recent ratings now
+// and then at every interval
+func (s *RatingService) StartRatingAnomalyScan(interval time.Duration) {
+	if _, err := s.DetectRatingAnomalies(""); err != nil {
+		log.Printf("Rating anomaly scan failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := s.DetectRatingAnomalies(""); err != nil {
+			log.Printf("Rating anomaly scan failed: %v", err)
+		}
+	}
+}
+
+// GetRatingFlags lists flagged ratings with a status (all when empty), newest
+// first, and the number of them
+func (s *RatingService) GetRatingFlags(status string, limit, offset int) ([]models.RatingFlag, int, error) {
+	switch status {
+	case "", FlagQuarantined, FlagCleared, FlagConfirmed:
+	default:
+		return nil, 0, fmt.Errorf("invalid status %q: use quarantined, cleared or confirmed", status)
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	db := database.GetDB()
+
+	condition := "1 = 1"
+	args := []interface{}{}
+	if status != "" {
+		condition = "f.status = ?"
+		args = append(args, status)
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM rating_flags f WHERE "+condition, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count rating flags: %w", err)
+	}
+
+	rows, err := db.Query(ratingFlagColumns+" WHERE "+condition+`
+		ORDER BY f.flagged_at DESC, f.id DESC
+		LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get rating flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := []models.RatingFlag{}
+	for rows.Next() {
+		flag, err := scanRatingFlag(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		flags = append(flags, *flag)
+	}
+
+	return flags, total, nil
+}
+
+// ReviewRatingFlag settles a flagged rating: clear puts it back into aggregates,
+// confirm keeps it out. Later scans leave settled flags alone.
+func (s *RatingService) ReviewRatingFlag(flagID int, adminID, action string) (*models.RatingFlag, error) {
+	var status string
+	var quarantined bool
+	switch action {
+	case "clear":
+		status = FlagCleared
+	case "confirm":
+		status, quarantined = FlagConfirmed, true
+	default:
+		return nil, fmt.Errorf("invalid action %q: use clear or confirm", action)
+	}
+
+	db := database.GetDB()
+
+	var userID, mangaID string
+	err := db.QueryRow("SELECT user_id, manga_id FROM rating_flags WHERE id = ?", flagID).Scan(&userID, &mangaID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("rating flag not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating flag: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE rating_flags SET status = ?, reviewed_at = ?, reviewed_by = ? WHERE id = ?",
+		status, time.Now(), adminID, flagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rating flag: %w", err)
+	}
+	_, err = tx.Exec("UPDATE manga_ratings SET quarantined = ? WHERE user_id = ? AND manga_id = ?",
+		quarantined, userID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rating: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rating flag review: %w", err)
+	}
+
+	return scanRatingFlag(db.QueryRow(ratingFlagColumns+" WHERE f.id = ?", flagID))
+}
+
+// ratingFlagColumns selects a rating flag with its rater and manga, in the order
+// expected by scanRatingFlag
+const ratingFlagColumns = `
+	SELECT f.id, f.user_id, COALESCE(u.username, ''), u.created_at, f.manga_id, COALESCE(m.title, ''),
+		f.rating, f.signals, f.status, f.flagged_at, f.reviewed_at, COALESCE(f.reviewed_by, '')
+	FROM rating_flags f
+	LEFT JOIN users u ON u.id = f.user_id
+	LEFT JOIN manga m ON m.id = f.manga_id`
+
+func scanRatingFlag(row rowScanner) (*models.RatingFlag, error) {
+	var flag models.RatingFlag
+	var accountCreatedAt, reviewedAt sql.NullTime
+	var signals string
+	err := row.Scan(&flag.ID, &flag.UserID, &flag.Username, &accountCreatedAt, &flag.MangaID, &flag.MangaTitle,
+		&flag.Rating, &signals, &flag.Status, &flag.FlaggedAt, &reviewedAt, &flag.ReviewedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan rating flag: %w", err)
+	}
+	flag.AccountCreatedAt = accountCreatedAt.Time
+	if reviewedAt.Valid {
+		flag.ReviewedAt = &reviewedAt.Time
+	}
+	if err := json.Unmarshal([]byte(signals), &flag.Signals); err != nil {
+		flag.Signals = []string{}
+	}
+	return &flag, nil
+}
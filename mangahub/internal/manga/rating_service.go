@@ -3,10 +3,33 @@ package manga
 import (
 	"database/sql"
 	"fmt"
+	"log"
+	"mangahub/internal/activity"
 	"mangahub/pkg/database"
 	"mangahub/pkg/models"
+	"math"
 )
 
+// Rating scale of manga_ratings, also used by the optional sub-scores
+const (
+	MinRating = 1
+	MaxRating = 10
+)
+
+// Bayesian weighting: every manga's average is pulled toward the mean of all
+// ratings as if it had ratingPriorVotes extra votes at that mean, so a single
+// high vote does not outrank a well-reviewed title
+const (
+	ratingPriorVotes = 10
+	ratingPriorMean  = 5.5 // Used before anything has been rated
+)
+
+// weightedRatingSQL computes the Bayesian-weighted rating of the manga row
+// (aliased manga in SearchManga), NULL when it has no ratings. Quarantined
+// ratings are left out here and in every other aggregate.
+var weightedRatingSQL = fmt.Sprintf(`(SELECT (SUM(r.rating) + %[1]d * (SELECT COALESCE(AVG(rating), %[2]g) FROM manga_ratings WHERE quarantined = 0)) / (COUNT(*) + %[1]d.0)
+	FROM manga_ratings r WHERE r.manga_id = manga.id AND r.quarantined = 0 HAVING COUNT(*) > 0)`, ratingPriorVotes, ratingPriorMean)
+
 // RatingService handles manga rating operations
 type RatingService struct{}
 
@@ -15,10 +38,16 @@ func NewRatingService() *RatingService {
 	return &RatingService{}
 }
 
-// RateManga adds or updates a user's rating for a manga
-func (s *RatingService) RateManga(userID, mangaID string, rating int) error {
-	if rating < 0 || rating > 10 {
-		return fmt.Errorf("rating must be between 0 and 10")
+// RateManga adds or updates a user's rating for a manga. Sub-scores left out
+// keep their previous value.
+func (s *RatingService) RateManga(userID, mangaID string, rating int, scores models.RatingSubScores) error {
+	if rating < MinRating || rating > MaxRating {
+		return fmt.Errorf("invalid rating: must be between %d and %d", MinRating, MaxRating)
+	}
+	for name, score := range map[string]*int{"story": scores.Story, "art": scores.Art, "characters": scores.Characters} {
+		if score != nil && (*score < MinRating || *score > MaxRating) {
+			return fmt.Errorf("invalid %s score: must be between %d and %d", name, MinRating, MaxRating)
+		}
 	}
 
 	db := database.GetDB()
@@ -33,9 +62,9 @@ func (s *RatingService) RateManga(userID, mangaID string, rating int) error {
 	if err == sql.ErrNoRows {
 		// Insert new rating
 		_, err = db.Exec(`
-			INSERT INTO manga_ratings (user_id, manga_id, rating, created_at, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		`, userID, mangaID, rating)
+			INSERT INTO manga_ratings (user_id, manga_id, rating, story, art, characters, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`, userID, mangaID, rating, scores.Story, scores.Art, scores.Characters)
 		if err != nil {
 			return fmt.Errorf("failed to insert rating: %w", err)
 		}
@@ -45,14 +74,26 @@ func (s *RatingService) RateManga(userID, mangaID string, rating int) error {
 		// Update existing rating
 		_, err = db.Exec(`
 			UPDATE manga_ratings 
-			SET rating = ?, updated_at = CURRENT_TIMESTAMP
+			SET rating = ?,
+				story = COALESCE(?, story),
+				art = COALESCE(?, art),
+				characters = COALESCE(?, characters),
+				updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?
-		`, rating, existingID)
+		`, rating, scores.Story, scores.Art, scores.Characters, existingID)
 		if err != nil {
 			return fmt.Errorf("failed to update rating: %w", err)
 		}
 	}
 
+	if err := activity.RecordRating(db, activity.SourceUser, userID, mangaID, rating); err != nil {
+		return err
+	}
+
+	// Check the manga for brigading right away; the rating itself is saved either way
+	if _, err := s.DetectRatingAnomalies(mangaID); err != nil {
+		log.Printf("Rating anomaly detection failed for manga %s: %v", mangaID, err)
+	}
 	return nil
 }
 
@@ -82,27 +123,40 @@ func (s *RatingService) GetMangaRatingStats(mangaID string, userID string) (*mod
 
 	stats := &models.MangaRatingStats{
 		MangaID:            mangaID,
+		SubScores:          make(map[string]float64),
 		RatingDistribution: make(map[int]int),
 	}
 
-	// Get average rating and count
+	// Get average rating, count and sub-score averages
+	var story, art, characters sql.NullFloat64
+	var globalMean float64
 	err := db.QueryRow(`
 		SELECT 
 			COALESCE(AVG(rating), 0) as avg_rating,
-			COUNT(*) as total_ratings
+			COUNT(*) as total_ratings,
+			AVG(story), AVG(art), AVG(characters),
+			(SELECT COALESCE(AVG(rating), ?) FROM manga_ratings WHERE quarantined = 0)
 		FROM manga_ratings
-		WHERE manga_id = ?
-	`, mangaID).Scan(&stats.AverageRating, &stats.TotalRatings)
+		WHERE manga_id = ? AND quarantined = 0
+	`, ratingPriorMean, mangaID).Scan(&stats.AverageRating, &stats.TotalRatings, &story, &art, &characters, &globalMean)
 
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get rating stats: %w", err)
 	}
 
-	// Get rating distribution (count for each rating 1-5)
+	stats.WeightedRating = roundRating(WeightedRating(stats.AverageRating, stats.TotalRatings, globalMean))
+	stats.AverageRating = roundRating(stats.AverageRating)
+	for name, average := range map[string]sql.NullFloat64{"story": story, "art": art, "characters": characters} {
+		if average.Valid {
+			stats.SubScores[name] = roundRating(average.Float64)
+		}
+	}
+
+	// Get rating distribution (count for each rating 1-10)
 	rows, err := db.Query(`
 		SELECT rating, COUNT(*) as count
 		FROM manga_ratings
-		WHERE manga_id = ?
+		WHERE manga_id = ? AND quarantined = 0
 		GROUP BY rating
 		ORDER BY rating DESC
 	`, mangaID)
@@ -122,31 +176,45 @@ func (s *RatingService) GetMangaRatingStats(mangaID string, userID string) (*mod
 		}
 	}
 
-	// Initialize all ratings 1-5 with 0 if not present
-	for i := 1; i <= 5; i++ {
+	// Initialize all ratings 1-10 with 0 if not present
+	for i := MinRating; i <= MaxRating; i++ {
 		if _, exists := stats.RatingDistribution[i]; !exists {
 			stats.RatingDistribution[i] = 0
 		}
 	}
 
-	// Get user's rating if authenticated
+	// Get user's rating and sub-scores if authenticated
 	if userID != "" {
-		userRating, err := s.GetUserRating(userID, mangaID)
-		if err != nil {
-			return nil, err
+		var rating int
+		var scores models.RatingSubScores
+		err := db.QueryRow(`
+			SELECT rating, story, art, characters FROM manga_ratings 
+			WHERE user_id = ? AND manga_id = ?
+		`, userID, mangaID).Scan(&rating, &scores.Story, &scores.Art, &scores.Characters)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get user rating: %w", err)
+		}
+		if err == nil {
+			stats.UserRating = &rating
+			stats.UserSubScores = &scores
 		}
-		stats.UserRating = userRating
 	}
 
 	return stats, nil
 }
 
-// DeleteRating deletes a user's rating for a manga
+// DeleteRating deletes a user's rating for a manga, and the review attached to it
 func (s *RatingService) DeleteRating(userID, mangaID string) error {
 	db := database.GetDB()
 
-	result, err := db.Exec(`
-		DELETE FROM manga_ratings 
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		DELETE FROM manga_ratings
 		WHERE user_id = ? AND manga_id = ?
 	`, userID, mangaID)
 
@@ -163,10 +231,23 @@ func (s *RatingService) DeleteRating(userID, mangaID string) error {
 		return fmt.Errorf("rating not found")
 	}
 
-	return nil
+	if err := deleteReviews(tx, "user_id = ? AND manga_id = ?", userID, mangaID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM rating_flags WHERE user_id = ? AND manga_id = ?", userID, mangaID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rating flag: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return activity.RecordRatingRemoved(db, userID, mangaID)
 }
 
-// GetAllRatingsForManga gets all ratings for a specific manga
+// GetAllRatingsForManga gets all ratings for a specific manga, except quarantined ones
 func (s *RatingService) GetAllRatingsForManga(mangaID string, limit, offset int) ([]models.MangaRating, error) {
 	db := database.GetDB()
 
@@ -175,9 +256,9 @@ func (s *RatingService) GetAllRatingsForManga(mangaID string, limit, offset int)
 	}
 
 	rows, err := db.Query(`
-		SELECT id, user_id, manga_id, rating, created_at, updated_at
+		SELECT id, user_id, manga_id, rating, story, art, characters, created_at, updated_at
 		FROM manga_ratings
-		WHERE manga_id = ?
+		WHERE manga_id = ? AND quarantined = 0
 		ORDER BY updated_at DESC
 		LIMIT ? OFFSET ?
 	`, mangaID, limit, offset)
@@ -195,6 +276,9 @@ func (s *RatingService) GetAllRatingsForManga(mangaID string, limit, offset int)
 			&rating.UserID,
 			&rating.MangaID,
 			&rating.Rating,
+			&rating.Story,
+			&rating.Art,
+			&rating.Characters,
 			&rating.CreatedAt,
 			&rating.UpdatedAt,
 		)
@@ -206,3 +290,16 @@ func (s *RatingService) GetAllRatingsForManga(mangaID string, limit, offset int)
 
 	return ratings, nil
 }
+
+// WeightedRating returns the Bayesian-weighted rating of a manga with the given
+// average and number of ratings, 0 when it has none
+func WeightedRating(average float64, count int, globalMean float64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return (average*float64(count) + ratingPriorVotes*globalMean) / float64(count+ratingPriorVotes)
+}
+
+func roundRating(value float64) float64 {
+	return math.Round(value*100) / 100
+}
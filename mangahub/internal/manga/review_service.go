@@ -0,0 +1,504 @@
+package manga
+
+import (
+	"database/sql"
+	"fmt"
+	"mangahub/pkg/database"
+	"mangahub/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review statuses
+const (
+	ReviewPublished = "published"
+	ReviewHidden    = "hidden" // Removed by a moderator, visible to its author only
+)
+
+// Moderation actions, which resolve all open reports of a review
+const (
+	ModerationDismiss = "dismiss" // Keep the review as it is
+	ModerationHide    = "hide"
+	ModerationRestore = "restore" // Publish a hidden review again
+)
+
+// Review sort orders
+const (
+	ReviewSortHelpful = "helpful"
+	ReviewSortRecent  = "recent"
+)
+
+// reviewColumns selects a review with its author, manga and rating (aliases r, u,
+// m, mr) in the order expected by scanReview. The first placeholder is the
+// viewing user, for their vote.
+const reviewColumns = `
+	SELECT r.id, r.user_id, COALESCE(u.username, ''), r.manga_id, COALESCE(m.title, ''),
+		COALESCE(mr.rating, 0), mr.story, mr.art, mr.characters,
+		r.title, r.body, r.spoiler, r.chapter_read, r.status, r.helpful_count, r.unhelpful_count,
+		r.edit_count, r.created_at, r.updated_at,
+		COALESCE((SELECT CASE v.helpful WHEN 1 THEN 'helpful' ELSE 'unhelpful' END
+			FROM review_votes v WHERE v.review_id = r.id AND v.user_id = ?), '')
+	FROM manga_reviews r
+	LEFT JOIN users u ON u.id = r.user_id
+	LEFT JOIN manga m ON m.id = r.manga_id
+	LEFT JOIN manga_ratings mr ON mr.user_id = r.user_id AND mr.manga_id = r.manga_id`
+
+// helpfulnessOrder ranks reviews by their share of helpful votes, smoothed so
+// that one helpful vote does not outrank a review most of many readers found helpful
+const helpfulnessOrder = `(r.helpful_count + 1.0) / (r.helpful_count + r.unhelpful_count + 2.0) DESC,
+	r.helpful_count DESC, r.created_at DESC`
+
+// ReviewService handles written reviews, their votes and moderation
+type ReviewService struct {
+	db *sql.DB
+}
+
+// NewReviewService creates a new review service
+func NewReviewService() *ReviewService {
+	return &ReviewService{
+		db: database.GetDB(),
+	}
+}
+
+// WriteReview creates the user's review of a manga or edits it, keeping the
+// previous version in the review history. A review is attached to the user's
+// rating, so the manga must be rated first. The chapter the user is on is
+// recorded with every version.
+func (s *ReviewService) WriteReview(userID, mangaID string, req models.WriteReviewRequest) (*models.Review, error) {
+	title := strings.TrimSpace(req.Title)
+	body := strings.TrimSpace(req.Body)
+	if title == "" || body == "" {
+		return nil, fmt.Errorf("invalid review: title and body are required")
+	}
+
+	var rated bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM manga_ratings WHERE user_id = ? AND manga_id = ?)",
+		userID, mangaID).Scan(&rated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rating: %w", err)
+	}
+	if !rated {
+		return nil, fmt.Errorf("invalid review: rate the manga before reviewing it")
+	}
+
+	var chapter int
+	err = s.db.QueryRow("SELECT current_chapter FROM user_progress WHERE user_id = ? AND manga_id = ?",
+		userID, mangaID).Scan(&chapter)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get reading progress: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var reviewID string
+	err = tx.QueryRow("SELECT id FROM manga_reviews WHERE user_id = ? AND manga_id = ?", userID, mangaID).Scan(&reviewID)
+	switch {
+	case err == sql.ErrNoRows:
+		reviewID = uuid.New().String()
+		_, err = tx.Exec(`
+			INSERT INTO manga_reviews (id, user_id, manga_id, title, body, spoiler, chapter_read, status, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			reviewID, userID, mangaID, title, body, req.Spoiler, chapter, ReviewPublished, now, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create review: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to check existing review: %w", err)
+	default:
+		_, err = tx.Exec(`
+			INSERT INTO review_revisions (review_id, title, body, spoiler, chapter_read, written_at)
+			SELECT id, title, body, spoiler, chapter_read, updated_at FROM manga_reviews WHERE id = ?`, reviewID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save review history: %w", err)
+		}
+		_, err = tx.Exec(`
+			UPDATE manga_reviews
+			SET title = ?, body = ?, spoiler = ?, chapter_read = ?, edit_count = edit_count + 1, updated_at = ?
+			WHERE id = ?`,
+			title, body, req.Spoiler, chapter, now, reviewID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update review: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit review: %w", err)
+	}
+	return s.getReviewBy(userID, "r.id = ?", reviewID)
+}
+
+// GetUserReview returns the user's own review of a manga, whatever its status
+func (s *ReviewService) GetUserReview(userID, mangaID string) (*models.Review, error) {
+	return s.getReviewBy(userID, "r.user_id = ? AND r.manga_id = ?", userID, mangaID)
+}
+
+// GetReview returns a published review; its author also sees it when hidden
+func (s *ReviewService) GetReview(reviewID, viewerID string) (*models.Review, error) {
+	return s.getReviewBy(viewerID, "r.id = ? AND (r.status = ? OR r.user_id = ?)", reviewID, ReviewPublished, viewerID)
+}
+
+// GetMangaReviews returns the published reviews of a manga, sorted by
+// helpfulness (default) or recency, and the total number of them
+func (s *ReviewService) GetMangaReviews(mangaID, viewerID, sort string, limit, offset int) ([]models.Review, int, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	orderBy := helpfulnessOrder
+	switch sort {
+	case "", ReviewSortHelpful:
+	case ReviewSortRecent:
+		orderBy = "r.created_at DESC"
+	default:
+		return nil, 0, fmt.Errorf("invalid sort %q: use helpful or recent", sort)
+	}
+
+	var total int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM manga_reviews WHERE manga_id = ? AND status = ?",
+		mangaID, ReviewPublished).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	rows, err := s.db.Query(reviewColumns+`
+		WHERE r.manga_id = ? AND r.status = ?
+		ORDER BY `+orderBy+`
+		LIMIT ? OFFSET ?`, viewerID, mangaID, ReviewPublished, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	return reviews, total, nil
+}
+
+// GetReviewHistory returns the previous versions of a published review, newest first
+func (s *ReviewService) GetReviewHistory(reviewID, viewerID string) ([]models.ReviewRevision, error) {
+	if _, err := s.GetReview(reviewID, viewerID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, title, body, spoiler, chapter_read, written_at
+		FROM review_revisions
+		WHERE review_id = ?
+		ORDER BY id DESC`, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review history: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ReviewRevision{}
+	for rows.Next() {
+		var revision models.ReviewRevision
+		if err := rows.Scan(&revision.ID, &revision.Title, &revision.Body, &revision.Spoiler,
+			&revision.ChapterRead, &revision.WrittenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// DeleteReview deletes the user's review of a manga with its votes, history and reports
+func (s *ReviewService) DeleteReview(userID, mangaID string) error {
+	var reviewID string
+	err := s.db.QueryRow("SELECT id FROM manga_reviews WHERE user_id = ? AND manga_id = ?", userID, mangaID).Scan(&reviewID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("review not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get review: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteReviews(tx, "id = ?", reviewID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// VoteReview records whether the user found a published review helpful,
+// replacing an earlier vote. Authors cannot vote on their own reviews.
+func (s *ReviewService) VoteReview(userID, reviewID string, helpful bool) (*models.Review, error) {
+	review, err := s.getReviewBy(userID, "r.id = ? AND r.status = ?", reviewID, ReviewPublished)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID == userID {
+		return nil, fmt.Errorf("invalid vote: you cannot vote on your own review")
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO review_votes (review_id, user_id, helpful, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(review_id, user_id) DO UPDATE SET helpful = excluded.helpful, created_at = excluded.created_at`,
+		reviewID, userID, helpful, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save vote: %w", err)
+	}
+
+	if err := s.updateVoteCounts(reviewID); err != nil {
+		return nil, err
+	}
+	return s.getReviewBy(userID, "r.id = ?", reviewID)
+}
+
+// RemoveVote withdraws the user's vote on a review
+func (s *ReviewService) RemoveVote(userID, reviewID string) error {
+	result, err := s.db.Exec("DELETE FROM review_votes WHERE review_id = ? AND user_id = ?", reviewID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete vote: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vote not found")
+	}
+
+	return s.updateVoteCounts(reviewID)
+}
+
+// ReportReview puts a published review in the moderation queue. Each user can
+// report a review once.
+func (s *ReviewService) ReportReview(userID, reviewID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("invalid report: a reason is required")
+	}
+
+	review, err := s.getReviewBy(userID, "r.id = ? AND r.status = ?", reviewID, ReviewPublished)
+	if err != nil {
+		return err
+	}
+	if review.UserID == userID {
+		return fmt.Errorf("invalid report: you cannot report your own review")
+	}
+
+	var exists bool
+	err = s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM review_reports WHERE review_id = ? AND reporter_id = ?)",
+		reviewID, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check existing report: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("report already exists")
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO review_reports (review_id, reporter_id, reason, created_at)
+		VALUES (?, ?, ?, ?)`, reviewID, userID, reason, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to report review: %w", err)
+	}
+	return nil
+}
+
+// GetModerationQueue returns the reviews with open reports, most reported first,
+// with those reports, and the number of reviews in the queue
+func (s *ReviewService) GetModerationQueue(limit, offset int) ([]models.Review, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var total int
+	err := s.db.QueryRow("SELECT COUNT(DISTINCT review_id) FROM review_reports WHERE resolved_at IS NULL").Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation queue: %w", err)
+	}
+
+	rows, err := s.db.Query(reviewColumns+`
+		JOIN (
+			SELECT review_id, COUNT(*) AS open_reports, MIN(created_at) AS first_report
+			FROM review_reports
+			WHERE resolved_at IS NULL
+			GROUP BY review_id
+		) q ON q.review_id = r.id
+		ORDER BY q.open_reports DESC, q.first_report
+		LIMIT ? OFFSET ?`, "", limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		reviews = append(reviews, *review)
+	}
+	rows.Close()
+
+	for i := range reviews {
+		if reviews[i].Reports, err = s.getOpenReports(reviews[i].ID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return reviews, total, nil
+}
+
+// ModerateReview resolves all open reports of a review: dismiss keeps it, hide
+// takes it down and restore publishes a hidden review again
+func (s *ReviewService) ModerateReview(moderatorID, reviewID, action string) (*models.Review, error) {
+	var status string
+	switch action {
+	case ModerationDismiss:
+	case ModerationHide:
+		status = ReviewHidden
+	case ModerationRestore:
+		status = ReviewPublished
+	default:
+		return nil, fmt.Errorf("invalid action %q: use dismiss, hide or restore", action)
+	}
+
+	if _, err := s.getReviewBy(moderatorID, "r.id = ?", reviewID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if status != "" {
+		if _, err := tx.Exec("UPDATE manga_reviews SET status = ? WHERE id = ?", status, reviewID); err != nil {
+			return nil, fmt.Errorf("failed to update review status: %w", err)
+		}
+	}
+	_, err = tx.Exec(`
+		UPDATE review_reports SET resolved_at = ?, resolved_by = ?, resolution = ?
+		WHERE review_id = ? AND resolved_at IS NULL`, time.Now(), moderatorID, action, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit moderation: %w", err)
+	}
+	return s.getReviewBy(moderatorID, "r.id = ?", reviewID)
+}
+
+// getReviewBy loads a single review matching a condition, as seen by viewerID
+func (s *ReviewService) getReviewBy(viewerID, condition string, args ...interface{}) (*models.Review, error) {
+	row := s.db.QueryRow(reviewColumns+" WHERE "+condition, append([]interface{}{viewerID}, args...)...)
+	review, err := scanReview(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review not found")
+	}
+	return review, err
+}
+
+// getOpenReports returns the unresolved reports of a review, oldest first
+func (s *ReviewService) getOpenReports(reviewID string) ([]models.ReviewReport, error) {
+	rows, err := s.db.Query(`
+		SELECT rr.id, rr.reporter_id, COALESCE(u.username, ''), rr.reason, rr.created_at
+		FROM review_reports rr
+		LEFT JOIN users u ON u.id = rr.reporter_id
+		WHERE rr.review_id = ? AND rr.resolved_at IS NULL
+		ORDER BY rr.created_at`, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ReviewReport{}
+	for rows.Next() {
+		var report models.ReviewReport
+		if err := rows.Scan(&report.ID, &report.ReporterID, &report.Reporter, &report.Reason,
+			&report.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// updateVoteCounts recounts the helpful and unhelpful votes of a review
+func (s *ReviewService) updateVoteCounts(reviewID string) error {
+	_, err := s.db.Exec(`
+		UPDATE manga_reviews
+		SET helpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = ? AND helpful = 1),
+			unhelpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = ? AND helpful = 0)
+		WHERE id = ?`, reviewID, reviewID, reviewID)
+	if err != nil {
+		return fmt.Errorf("failed to update vote counts: %w", err)
+	}
+	return nil
+}
+
+// deleteReviews deletes the reviews matching a condition on manga_reviews with
+// their votes, history and reports. Foreign keys are not enforced, so the
+// dependent rows are removed here.
+func deleteReviews(tx *sql.Tx, condition string, args ...interface{}) error {
+	selected := "SELECT id FROM manga_reviews WHERE " + condition
+	for _, table := range []string{"review_votes", "review_revisions", "review_reports"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE review_id IN ("+selected+")", args...); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", strings.ReplaceAll(table, "_", " "), err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM manga_reviews WHERE "+condition, args...); err != nil {
+		return fmt.Errorf("failed to delete reviews: %w", err)
+	}
+	return nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReview(row rowScanner) (*models.Review, error) {
+	var review models.Review
+	err := row.Scan(&review.ID, &review.UserID, &review.Username, &review.MangaID, &review.MangaTitle,
+		&review.Rating, &review.Story, &review.Art, &review.Characters,
+		&review.Title, &review.Body, &review.Spoiler, &review.ChapterRead, &review.Status,
+		&review.HelpfulCount, &review.UnhelpfulCount, &review.EditCount, &review.CreatedAt,
+		&review.UpdatedAt, &review.UserVote)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review: %w", err)
+	}
+	return &review, nil
+}
@@ -0,0 +1,347 @@
+package manga
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mangahub/pkg/models"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Content similarity settings
+const (
+	similarNeighbors = 20   // Nearest neighbours stored per manga
+	similarMinScore  = 0.05 // Weaker neighbours are not stored
+	genreTermWeight  = 2.0  // Genres say more about a manga than a word of its synopsis
+	tagTermWeight    = 1.5
+	maxTermDocShare  = 0.5 // Terms in more than half of the catalog carry no signal
+	maxPostingDocs   = 200 // Terms of more manga only add to the score of neighbours found through rarer terms
+)
+
+// sourceNotes matches credits that sources append to synopses, e.g. "[Written by MAL Rewrite]"
+var sourceNotes = regexp.MustCompile(`[\[(](?i:written by|source:)[^\])]*[\])]`)
+
+// stopWords are common English words left out of description vectors
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "her": true, "was": true, "one": true, "our": true,
+	"out": true, "his": true, "has": true, "had": true, "him": true, "she": true, "its": true,
+	"who": true, "how": true, "now": true, "with": true, "that": true, "this": true, "from": true,
+	"they": true, "them": true, "their": true, "there": true, "will": true, "what": true,
+	"when": true, "where": true, "which": true, "while": true, "into": true, "than": true,
+	"then": true, "been": true, "have": true, "were": true, "after": true, "about": true,
+	"only": true, "also": true, "more": true, "most": true, "some": true, "such": true,
+	"just": true, "over": true, "even": true, "each": true, "other": true, "these": true,
+	"those": true, "being": true, "does": true, "very": true, "your": true, "would": true,
+	"could": true, "should": true, "because": true, "before": true, "through": true, "himself": true,
+	"herself": true, "themselves": true, "between": true, "against": true, "during": true,
+	"under": true, "again": true, "once": true, "both": true, "same": true, "own": true,
+	"manga": true, "story": true, "series": true, "chapter": true, "chapters": true,
+}
+
+// similarIndexMu serializes index rebuilds from syncs and admin edits
+var similarIndexMu sync.Mutex
+
+// similarIndexRetry is how long a lookup that finds the index empty waits after
+// the last rebuild before starting another one
+const similarIndexRetry = 10 * time.Minute
+
+// similarIndexState tracks rebuilds so lookups do not start one per request
+var similarIndexState struct {
+	sync.Mutex
+	builtAt   time.Time // last successful rebuild
+	requested time.Time // last rebuild started by a lookup
+}
+
+// RebuildSimilarIndex recomputes the content-based nearest neighbours of every
+// local manga into similar_manga. Each manga is a TF-IDF vector over the words of
+// its title and description, its genres and its tags; neighbours are ranked by
+// cosine similarity. The whole catalog is rebuilt because document frequencies
+// change with every manga added; see nearestNeighbors for how its cost is bounded.
+func RebuildSimilarIndex(db *sql.DB) error {
+	similarIndexMu.Lock()
+	defer similarIndexMu.Unlock()
+	started := time.Now()
+
+	rows, err := db.Query(`
+		SELECT id, title, COALESCE(description, ''), COALESCE(genres, '[]'), COALESCE(tags, '[]')
+		FROM manga`)
+	if err != nil {
+		return fmt.Errorf("failed to load manga for similarity: %w", err)
+	}
+
+	var ids []string
+	var docs []map[string]float64
+	for rows.Next() {
+		var id, title, description, genresJSON, tagsJSON string
+		if err := rows.Scan(&id, &title, &description, &genresJSON, &tagsJSON); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan manga for similarity: %w", err)
+		}
+		ids = append(ids, id)
+		docs = append(docs, contentTerms(title, description, genresJSON, tagsJSON))
+	}
+	rows.Close()
+
+	neighbors := nearestNeighbors(tfidfVectors(docs))
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM similar_manga"); err != nil {
+		return fmt.Errorf("failed to clear similar manga: %w", err)
+	}
+	stmt, err := tx.Prepare("INSERT INTO similar_manga (manga_id, similar_id, score) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare similar manga insert: %w", err)
+	}
+	defer stmt.Close()
+
+	pairs := 0
+	for i, list := range neighbors {
+		for _, n := range list {
+			if _, err := stmt.Exec(ids[i], ids[n.index], n.score); err != nil {
+				return fmt.Errorf("failed to store similar manga: %w", err)
+			}
+			pairs++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit similar manga: %w", err)
+	}
+
+	similarIndexState.Lock()
+	similarIndexState.builtAt = time.Now()
+	similarIndexState.Unlock()
+
+	log.Printf("Similar manga index rebuilt: %d manga, %d pairs in %v", len(ids), pairs, time.Since(started))
+	return nil
+}
+
+// GetSimilarManga returns the local manga most similar in content to the given one
+func (s *Service) GetSimilarManga(id string, limit int) ([]models.SimilarManga, error) {
+	if limit <= 0 || limit > similarNeighbors {
+		limit = 10
+	}
+
+	source, err := s.GetManga(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the index on first use, e.g. for a catalog synced before it existed.
+	// The build runs in the background; until it finishes no neighbours are found.
+	var indexed bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM similar_manga)").Scan(&indexed); err != nil {
+		return nil, fmt.Errorf("failed to check similar manga index: %w", err)
+	}
+	if !indexed {
+		requestSimilarIndex(s.db)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT m.id, m.title, COALESCE(m.author, ''), COALESCE(m.genres, '[]'), COALESCE(m.status, ''),
+			COALESCE(m.total_chapters, 0), COALESCE(m.description, ''), COALESCE(m.cover_url, ''),
+			COALESCE(m.publication_year, 0), m.created_at, sm.score
+		FROM similar_manga sm
+		JOIN manga m ON m.id = sm.similar_id
+		WHERE sm.manga_id = ?
+		ORDER BY sm.score DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar manga: %w", err)
+	}
+	defer rows.Close()
+
+	sourceGenres := make(map[string]bool, len(source.Genres))
+	for _, genre := range source.Genres {
+		sourceGenres[strings.ToLower(genre)] = true
+	}
+
+	similar := []models.SimilarManga{}
+	for rows.Next() {
+		var item models.SimilarManga
+		manga := &item.Manga
+		if err := rows.Scan(&manga.ID, &manga.Title, &manga.Author, &manga.GenresJSON, &manga.Status,
+			&manga.TotalChapters, &manga.Description, &manga.CoverURL, &manga.PublicationYear,
+			&manga.CreatedAt, &item.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan similar manga: %w", err)
+		}
+		if err := manga.GetGenres(); err != nil {
+			manga.Genres = []string{}
+		}
+		item.Score = math.Round(item.Score*1000) / 1000
+
+		item.SharedGenres = []string{}
+		for _, genre := range manga.Genres {
+			if sourceGenres[strings.ToLower(genre)] {
+				item.SharedGenres = append(item.SharedGenres, genre)
+			}
+		}
+		similar = append(similar, item)
+	}
+
+	return similar, nil
+}
+
+type neighbor struct {
+	index int
+	score float64
+}
+
+// nearestNeighbors returns the most similar vectors of each vector, best first.
+// Candidates are found through an inverted index over the terms held by at
+// most maxPostingDocs manga. Common terms such as a popular genre would make
+// every manga a candidate of every other; they still count in the cosine
+// similarity of the candidates found.
+func nearestNeighbors(vectors []map[string]float64) [][]neighbor {
+	postings := make(map[string][]int)
+	for i, vector := range vectors {
+		for term := range vector {
+			postings[term] = append(postings[term], i)
+		}
+	}
+
+	result := make([][]neighbor, len(vectors))
+	for i, vector := range vectors {
+		candidates := make(map[int]bool)
+		for term := range vector {
+			if len(postings[term]) > maxPostingDocs {
+				continue
+			}
+			for _, j := range postings[term] {
+				if j != i {
+					candidates[j] = true
+				}
+			}
+		}
+
+		neighbors := make([]neighbor, 0, len(candidates))
+		for j := range candidates {
+			score := 0.0
+			for term, weight := range vector {
+				score += weight * vectors[j][term]
+			}
+			if score >= similarMinScore {
+				neighbors = append(neighbors, neighbor{j, score})
+			}
+		}
+		sort.Slice(neighbors, func(a, b int) bool {
+			if neighbors[a].score != neighbors[b].score {
+				return neighbors[a].score > neighbors[b].score
+			}
+			return neighbors[a].index < neighbors[b].index
+		})
+		if len(neighbors) > similarNeighbors {
+			neighbors = neighbors[:similarNeighbors]
+		}
+		result[i] = neighbors
+	}
+
+	return result
+}
+
+// rebuildSimilarIndexAsync refreshes the index in the background after a catalog change
+func rebuildSimilarIndexAsync(db *sql.DB) {
+	go func() {
+		if err := RebuildSimilarIndex(db); err != nil {
+			log.Printf("Similar manga index rebuild failed: %v", err)
+		}
+	}()
+}
+
+// requestSimilarIndex starts a background rebuild of an empty index unless one
+// was built or started within similarIndexRetry
+func requestSimilarIndex(db *sql.DB) {
+	similarIndexState.Lock()
+	defer similarIndexState.Unlock()
+	if time.Since(similarIndexState.builtAt) < similarIndexRetry ||
+		time.Since(similarIndexState.requested) < similarIndexRetry {
+		return
+	}
+	similarIndexState.requested = time.Now()
+	rebuildSimilarIndexAsync(db)
+}
+
+// contentTerms returns the raw term counts of a manga. Words, genres and tags are
+// kept apart by prefix so that e.g. the genre "Romance" and the word "romance"
+// are separate features.
+func contentTerms(title, description, genresJSON, tagsJSON string) map[string]float64 {
+	terms := make(map[string]float64)
+
+	text := title + " " + sourceNotes.ReplaceAllString(description, " ")
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms["w:"+word]++
+	}
+
+	var genres, tags []string
+	json.Unmarshal([]byte(genresJSON), &genres)
+	json.Unmarshal([]byte(tagsJSON), &tags)
+	for _, genre := range genres {
+		terms["g:"+strings.ToLower(genre)] = 1
+	}
+	for _, tag := range tags {
+		terms["t:"+strings.ToLower(tag)] = 1
+	}
+
+	return terms
+}
+
+// tfidfVectors weighs term counts by inverse document frequency and normalizes
+// each vector to unit length. Word counts are dampened logarithmically. Words,
+// genres and tags found in more than maxTermDocShare of a larger catalog are
+// left out.
+func tfidfVectors(docs []map[string]float64) []map[string]float64 {
+	df := make(map[string]int)
+	for _, doc := range docs {
+		for term := range doc {
+			df[term]++
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i, doc := range docs {
+		vector := make(map[string]float64, len(doc))
+		norm := 0.0
+		for term, count := range doc {
+			if float64(df[term]) > n*maxTermDocShare && n > 10 {
+				continue
+			}
+
+			weight := (1 + math.Log(count)) * (math.Log((1+n)/(1+float64(df[term]))) + 1)
+			switch term[:2] {
+			case "g:":
+				weight *= genreTermWeight
+			case "t:":
+				weight *= tagTermWeight
+			}
+			vector[term] = weight
+			norm += weight * weight
+		}
+
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[i] = vector
+	}
+
+	return vectors
+}
@@ -0,0 +1,47 @@
+package manga
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTfidfVectorsDropTermsInMostOfTheCatalog(t *testing.T) {
+	docs := make([]map[string]float64, 20)
+	for i := range docs {
+		docs[i] = map[string]float64{"g:action": 1, "w:sword": 1, fmt.Sprintf("t:tag%d", i%5): 1}
+	}
+	docs[0]["g:romance"] = 1
+
+	vectors := tfidfVectors(docs)
+
+	for _, vector := range vectors {
+		assert.NotContains(t, vector, "g:action")
+		assert.NotContains(t, vector, "w:sword")
+	}
+	assert.Contains(t, vectors[0], "g:romance")
+	assert.Contains(t, vectors[0], "t:tag0")
+}
+
+func TestNearestNeighborsFindsCandidatesOnlyThroughRareTerms(t *testing.T) {
+	// Every manga shares a genre too common to find candidates through; pairs
+	// share a rare tag, and the last manga has the common genre only
+	count := 2*maxPostingDocs + 1
+	vectors := make([]map[string]float64, count)
+	for i := 0; i < count-1; i++ {
+		vectors[i] = map[string]float64{"g:action": 0.6, fmt.Sprintf("t:pair%d", i/2): 0.8}
+	}
+	vectors[count-1] = map[string]float64{"g:action": 1}
+
+	neighbors := nearestNeighbors(vectors)
+
+	for i := 0; i < count-1; i++ {
+		require.Len(t, neighbors[i], 1, "manga %d", i)
+		assert.Equal(t, i^1, neighbors[i][0].index)
+		// The common genre still counts in the score
+		assert.InDelta(t, 1.0, neighbors[i][0].score, 1e-9)
+	}
+	assert.Empty(t, neighbors[count-1])
+}
@@ -2,6 +2,7 @@ package manga
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"mangahub/internal/external"
@@ -115,6 +116,7 @@ func (s *SyncService) SyncFromMAL(query string, limit int) (*SyncResult, error)
 			continue
 		}
 		log.Printf("  Manga stored successfully")
+		s.storeMangaTags(manga.ID, malTags(malData))
 
 		// Store chapters
 		log.Printf("  Storing %d chapters...", len(chapters))
@@ -144,6 +146,7 @@ func (s *SyncService) SyncFromMAL(query string, limit int) (*SyncResult, error)
 	}
 
 	log.Printf("Sync completed: %d synced, %d skipped, %d failed", result.Synced, result.Skipped, result.Failed)
+	s.rebuildSimilarIndex(result)
 	return result, nil
 }
 
@@ -213,6 +216,7 @@ func (s *SyncService) SyncTopManga(limit int) (*SyncResult, error) {
 			log.Printf("  ERROR: Failed to store manga: %v", err)
 			continue
 		}
+		s.storeMangaTags(manga.ID, malTags(malData))
 
 		// Store chapters
 		stored := 0
@@ -241,6 +245,7 @@ func (s *SyncService) SyncTopManga(limit int) (*SyncResult, error) {
 
 	log.Printf("Auto-sync complete: fetched=%d, synced=%d, skipped=%d, failed=%d",
 		result.TotalFetched, result.Synced, result.Skipped, result.Failed)
+	s.rebuildSimilarIndex(result)
 
 	return result, nil
 }
@@ -263,11 +268,7 @@ func (s *SyncService) SyncFromMangaDex(maxManga int) (*SyncResult, error) {
 	unlimited := maxManga == 0
 
 	for unlimited || totalSynced < maxManga {
-		// Rate limiting - wait between requests
-		if offset > 0 {
-			time.Sleep(300 * time.Millisecond) // Slightly longer delay to avoid rate limits
-		}
-
+		// Rate limiting and retries are handled by the shared external transport
 		log.Printf("Fetching manga batch: offset=%d, limit=%d", offset, limit)
 
 		// Fetch manga list from MangaDex
@@ -420,6 +421,7 @@ func (s *SyncService) SyncFromMangaDex(maxManga int) (*SyncResult, error) {
 
 	log.Printf("MangaDex sync complete: fetched=%d, synced=%d, skipped=%d, failed=%d",
 		result.TotalFetched, result.Synced, result.Skipped, result.Failed)
+	s.rebuildSimilarIndex(result)
 
 	return result, nil
 }
@@ -639,6 +641,43 @@ func (s *SyncService) convertMALToManga(malData external.JikanManga, mangaDexID,
 	}
 }
 
+// malTags returns the MAL themes and demographics of a manga, which MAL keeps
+// apart from its genres
+func malTags(malData external.JikanManga) []string {
+	tags := make([]string, 0, len(malData.Themes)+len(malData.Demographics))
+	for _, theme := range malData.Themes {
+		tags = append(tags, theme.Name)
+	}
+	for _, demographic := range malData.Demographics {
+		tags = append(tags, demographic.Name)
+	}
+	return tags
+}
+
+// storeMangaTags saves the tags of a synced manga, used by the similar manga index
+func (s *SyncService) storeMangaTags(mangaID string, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return
+	}
+	if _, err := s.db.Exec("UPDATE manga SET tags = ? WHERE id = ?", string(data), mangaID); err != nil {
+		log.Printf("  WARNING: Failed to store tags: %v", err)
+	}
+}
+
+// rebuildSimilarIndex refreshes the similar manga index when a sync added manga
+func (s *SyncService) rebuildSimilarIndex(result *SyncResult) {
+	if result.Synced == 0 {
+		return
+	}
+	if err := RebuildSimilarIndex(s.db); err != nil {
+		log.Printf("WARNING: Failed to rebuild similar manga index: %v", err)
+	}
+}
+
 // extractAuthor gets the first author from the list
 func (s *SyncService) extractAuthor(authors []external.JikanAuthor) string {
 	if len(authors) > 0 {
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"mangahub/internal/auth"
 	"net"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,6 +15,10 @@ import (
 type ClientInfo struct {
 	Conn     net.Conn
 	LastSeen time.Time
+	// Set once the client sent "AUTH <access token>"; its updates are then
+	// attributed to this user
+	UserID   string
+	Username string
 }
 
 type ProgressSyncServer struct {
@@ -100,6 +106,30 @@ func (s *ProgressSyncServer) handleTCPClient(conn net.Conn) {
 			continue
 		}
 
+		// Authenticate the connection with an access token, checked against
+		// the published public keys (JWKS_URL)
+		if token, ok := strings.CutPrefix(message, "AUTH "); ok {
+			reply := "AUTH OK\n"
+			claims, err := auth.ParseAccessToken(strings.TrimSpace(token))
+			if err != nil {
+				log.Printf("Authentication of %s failed: %v", addr, err)
+				reply = "AUTH FAILED\n"
+			}
+			s.mu.Lock()
+			if client, exists := s.Connections[addr]; exists {
+				// A failed AUTH also drops the identity of an earlier one
+				client.UserID, client.Username = "", ""
+				if claims != nil {
+					client.UserID, client.Username = claims.UserID, claims.Username
+				}
+				client.LastSeen = time.Now()
+				client.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+				client.Conn.Write([]byte(reply))
+			}
+			s.mu.Unlock()
+			continue
+		}
+
 		var update ProgressUpdate
 		if err := json.Unmarshal([]byte(message), &update); err != nil {
 			log.Printf("Error parsing message from %s: %v", addr, err)
@@ -121,10 +151,13 @@ func (s *ProgressSyncServer) handleTCPClient(conn net.Conn) {
 			update.Timestamp = time.Now().Unix()
 		}
 
-		// Update last seen
+		// Update last seen; authenticated clients cannot post as another user
 		s.mu.Lock()
 		if client, exists := s.Connections[addr]; exists {
 			client.LastSeen = time.Now()
+			if client.UserID != "" {
+				update.UserID, update.Username = client.UserID, client.Username
+			}
 		}
 		s.mu.Unlock()
 
@@ -0,0 +1,201 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"mangahub/internal/auth"
+	"mangahub/internal/mailer"
+	"os"
+	"strings"
+	"time"
+)
+
+// appURL returns the web app address used in emailed links (APP_URL)
+func appURL() string {
+	url := os.Getenv("APP_URL")
+	if url == "" {
+		url = "http://localhost:3000"
+	}
+	return strings.TrimRight(url, "/")
+}
+
+// SendVerificationEmail emails the user a link confirming their address
+func (s *Service) SendVerificationEmail(userID string) error {
+	var username, email string
+	var verifiedAt sql.NullTime
+	err := s.db.QueryRow("SELECT username, email, email_verified_at FROM users WHERE id = ?", userID).Scan(
+		&username, &email, &verifiedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if verifiedAt.Valid {
+		return fmt.Errorf("email already verified")
+	}
+
+	token, err := auth.GenerateActionToken(auth.PurposeVerifyEmail, userID, auth.EmailBinding(email), auth.VerifyEmailTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Verify your MangaHub email",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Confirm your email address by opening this link:\n\n"+
+			"%s/verify-email?token=%s\n\n"+
+			"The link expires in 48 hours. If you did not create a MangaHub account, ignore this email.\n",
+			username, appURL(), token),
+	})
+}
+
+// VerifyEmail marks the user's email verified with the token from the
+// verification email. The token only works for the address it was sent to.
+func (s *Service) VerifyEmail(token string) error {
+	userID, binding, err := auth.ParseActionToken(token, auth.PurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	var email string
+	err = s.db.QueryRow("SELECT email FROM users WHERE id = ?", userID).Scan(&email)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("invalid token: user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if auth.EmailBinding(email) != binding {
+		return fmt.Errorf("invalid token: email changed since the link was sent")
+	}
+
+	if _, err := s.db.Exec("UPDATE users SET email_verified_at = ? WHERE id = ? AND email_verified_at IS NULL",
+		time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset emails a password reset link if an account uses the
+// address. Unknown addresses are not reported, so it cannot be used to find accounts.
+func (s *Service) RequestPasswordReset(email string) error {
+	var userID, username, passwordHash string
+	err := s.db.QueryRow("SELECT id, username, password_hash FROM users WHERE email = ? COLLATE NOCASE", email).Scan(
+		&userID, &username, &passwordHash)
+	if err == sql.ErrNoRows {
+		log.Printf("Password reset requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Sent in the background, so the response takes as long as for unknown addresses
+	go s.sendPasswordResetEmail(userID, username, email, passwordHash)
+	return nil
+}
+
+// sendPasswordResetEmail emails the reset link. Failures are only logged, as
+// the response must not depend on the account existing.
+func (s *Service) sendPasswordResetEmail(userID, username, email, passwordHash string) {
+	token, err := auth.GenerateActionToken(auth.PurposeResetPassword, userID, auth.PasswordBinding(passwordHash), auth.ResetPasswordTokenTTL)
+	if err != nil {
+		log.Printf("Failed to create password reset token for user %s: %v", userID, err)
+		return
+	}
+
+	err = s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Reset your MangaHub password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Someone asked to reset the password of your MangaHub account. To choose a new password, open this link within an hour:\n\n"+
+			"%s/reset-password?token=%s\n\n"+
+			"The link works once. If you did not ask for this, ignore this email; your password stays the same.\n",
+			username, appURL(), token),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", userID, err)
+	}
+}
+
+// ResetPassword sets a new password with the token from the reset email, signs
+// out every session and revokes every API token. Using the link also confirms
+// the email address.
+func (s *Service) ResetPassword(token, newPassword string) error {
+	userID, binding, err := auth.ParseActionToken(token, auth.PurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	var passwordHash string
+	err = s.db.QueryRow("SELECT password_hash FROM users WHERE id = ?", userID).Scan(&passwordHash)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("invalid token: user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if auth.PasswordBinding(passwordHash) != binding {
+		return fmt.Errorf("invalid token: link already used")
+	}
+
+	newHash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Only replace the hash the token was issued for, so the link works once.
+	// Proving the email also lifts a lockout.
+	result, err := tx.Exec(`
+		UPDATE users SET password_hash = ?, email_verified_at = COALESCE(email_verified_at, ?),
+			failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = ? AND password_hash = ?`, newHash, time.Now(), userID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("invalid token: link already used")
+	}
+
+	if err := revokeCredentials(tx, userID, ""); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.sendPasswordChangedEmail(userID)
+
+	return nil
+}
+
+// sendPasswordChangedEmail tells the user their password was changed, in case it was not them
+func (s *Service) sendPasswordChangedEmail(userID string) {
+	var username, email string
+	if err := s.db.QueryRow("SELECT username, email FROM users WHERE id = ?", userID).Scan(&username, &email); err != nil {
+		log.Printf("Failed to get user %s for password change email: %v", userID, err)
+		return
+	}
+
+	err := s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Your MangaHub password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"The password of your MangaHub account was just changed, your other sessions were signed out "+
+			"and your API tokens were revoked.\n\n"+
+			"If this was not you, reset your password at %s/forgot-password.\n",
+			username, appURL()),
+	})
+	if err != nil {
+		log.Printf("Failed to send password change email to user %s: %v", userID, err)
+	}
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// credentialTestUser creates a user with a password, two sessions and an API token
+func credentialTestUser(t *testing.T, s *Service, password string) (userID, apiToken string) {
+	t.Helper()
+	t.Setenv("JWT_SIGNING_ALG", auth.AlgHS256)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	userID = createTestUser(t, s.db, "reader")
+	hash, err := auth.HashPassword(password)
+	require.NoError(t, err)
+	_, err = s.db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", hash, userID)
+	require.NoError(t, err)
+	for _, id := range []string{"current", "other"} {
+		_, err := s.db.Exec("INSERT INTO sessions (id, user_id, refresh_token_hash, expires_at) VALUES (?, ?, ?, ?)",
+			id, userID, "hash-"+id, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+	}
+
+	created, err := s.CreateAPIToken(userID, models.CreateAPITokenRequest{Name: "bot", Scopes: []string{auth.ScopeLibraryRead}})
+	require.NoError(t, err)
+	_, err = auth.ValidateAPIToken(created.Token)
+	require.NoError(t, err)
+	return userID, created.Token
+}
+
+func activeSessions(t *testing.T, s *Service, userID string) []string {
+	t.Helper()
+	rows, err := s.db.Query("SELECT id FROM sessions WHERE user_id = ? AND revoked_at IS NULL ORDER BY id", userID)
+	require.NoError(t, err)
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestChangePasswordRevokesOtherSessionsAndAPITokens(t *testing.T) {
+	s := newTestService(t)
+	userID, apiToken := credentialTestUser(t, s, "old password")
+
+	require.NoError(t, s.ChangePassword(userID, "old password", "new password", "current"))
+
+	assert.Equal(t, []string{"current"}, activeSessions(t, s, userID))
+	_, err := auth.ValidateAPIToken(apiToken)
+	assert.ErrorContains(t, err, "revoked")
+}
+
+func TestResetPasswordRevokesEverySessionAndAPIToken(t *testing.T) {
+	s := newTestService(t)
+	userID, apiToken := credentialTestUser(t, s, "old password")
+
+	var hash string
+	require.NoError(t, s.db.QueryRow("SELECT password_hash FROM users WHERE id = ?", userID).Scan(&hash))
+	token, err := auth.GenerateActionToken(auth.PurposeResetPassword, userID, auth.PasswordBinding(hash), time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, s.ResetPassword(token, "new password"))
+	assert.Empty(t, activeSessions(t, s, userID))
+	_, err = auth.ValidateAPIToken(apiToken)
+	assert.ErrorContains(t, err, "revoked")
+
+	assert.EqualError(t, s.ResetPassword(token, "another password"), "invalid token: link already used")
+}
+
+func TestRequestPasswordResetAnswersTheSameForUnknownAddresses(t *testing.T) {
+	s := newTestService(t)
+	credentialTestUser(t, s, "password")
+	outbox := os.Getenv("MAIL_OUTBOX_DIR")
+	sent := func() int {
+		files, _ := filepath.Glob(filepath.Join(outbox, "*.eml"))
+		return len(files)
+	}
+
+	require.NoError(t, s.RequestPasswordReset("nobody@example.com"))
+	require.NoError(t, s.RequestPasswordReset("READER@example.com"))
+
+	// The link is mailed in the background
+	assert.Eventually(t, func() bool { return sent() == 1 }, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,148 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultAPITokenTTL applies when a token is created without an expiry
+	defaultAPITokenTTL = 90 * 24 * time.Hour
+	// maxAPITokens caps the active personal access tokens per user
+	maxAPITokens = 50
+	// apiTokenPrefixLength is how much of the token is kept to recognize it
+	apiTokenPrefixLength = 12
+)
+
+// CreateAPIToken creates a personal access token with the requested scopes. The
+// admin:* scope needs a role beyond user, and never grants more than the user's roles.
+func (s *Service) CreateAPIToken(userID string, req models.CreateAPITokenRequest) (*models.CreateAPITokenResponse, error) {
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !auth.IsValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope: %q (valid: %s)", scope, strings.Join(auth.Scopes, ", "))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if seen[auth.ScopeAdmin] {
+		roles, err := auth.GetUserRoles(userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(roles) == 1 {
+			return nil, fmt.Errorf("invalid scope: %s needs an admin, moderator or curator role", auth.ScopeAdmin)
+		}
+	}
+
+	var active int
+	if err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM api_tokens
+		WHERE user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)`,
+		userID, time.Now()).Scan(&active); err != nil {
+		return nil, fmt.Errorf("failed to count API tokens: %w", err)
+	}
+	if active >= maxAPITokens {
+		return nil, fmt.Errorf("invalid request: at most %d active API tokens", maxAPITokens)
+	}
+
+	token, err := auth.GenerateAPIToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var expiresAt *time.Time
+	if !req.NoExpiry {
+		expiry := now.Add(defaultAPITokenTTL)
+		if req.ExpiresInDays > 0 {
+			expiry = now.AddDate(0, 0, req.ExpiresInDays)
+		}
+		expiresAt = &expiry
+	}
+
+	apiToken := models.APIToken{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    token[:apiTokenPrefixLength],
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO api_tokens (id, user_id, name, token_hash, token_prefix, scopes, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		apiToken.ID, userID, apiToken.Name, auth.HashToken(token), apiToken.Prefix,
+		strings.Join(scopes, " "), now, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return &models.CreateAPITokenResponse{APIToken: apiToken, Token: token}, nil
+}
+
+// GetAPITokens lists the user's personal access tokens that were not revoked, newest first
+func (s *Service) GetAPITokens(userID string) ([]models.APIToken, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, token_prefix, scopes, created_at, expires_at, last_used_at
+		FROM api_tokens
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API tokens: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		var token models.APIToken
+		var scopes string
+		var expiresAt, lastUsedAt sql.NullTime
+		if err := rows.Scan(&token.ID, &token.Name, &token.Prefix, &scopes, &token.CreatedAt,
+			&expiresAt, &lastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan API token: %w", err)
+		}
+		token.Scopes = strings.Fields(scopes)
+		if expiresAt.Valid {
+			token.ExpiresAt = &expiresAt.Time
+			token.Expired = now.After(expiresAt.Time)
+		}
+		if lastUsedAt.Valid {
+			token.LastUsedAt = &lastUsedAt.Time
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes one of the user's personal access tokens; it stops working right away
+func (s *Service) RevokeAPIToken(userID, tokenID string) error {
+	result, err := s.db.Exec("UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		time.Now(), tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("API token not found")
+	}
+
+	return nil
+}
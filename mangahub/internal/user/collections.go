@@ -0,0 +1,489 @@
+package user
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"mangahub/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection visibility levels
+const (
+	VisibilityPrivate  = "private"  // Owner only
+	VisibilityUnlisted = "unlisted" // Anyone with the share link
+	VisibilityPublic   = "public"   // Share link and the public collection listing
+)
+
+// GetCollections returns all collections of a user without their items
+func (s *Service) GetCollections(userID string) ([]models.Collection, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.user_id, c.name, c.description, c.visibility, c.share_token, c.sort_order,
+			c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id)
+		FROM collections c
+		WHERE c.user_id = ?
+		ORDER BY c.sort_order, c.created_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCollections(rows)
+}
+
+// GetPublicCollections returns public collections, optionally only those of one user
+func (s *Service) GetPublicCollections(username string, limit, offset int) ([]models.Collection, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT c.id, c.user_id, c.name, c.description, c.visibility, c.share_token, c.sort_order,
+			c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id),
+			u.username
+		FROM collections c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.visibility = 'public'`
+	args := []interface{}{}
+	if username != "" {
+		query += " AND u.username = ?"
+		args = append(args, username)
+	}
+	query += " ORDER BY c.updated_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.Collection{}
+	for rows.Next() {
+		var c models.Collection
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Description, &c.Visibility, &c.ShareToken,
+			&c.SortOrder, &c.CreatedAt, &c.UpdatedAt, &c.ItemCount, &c.Owner); err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, c)
+	}
+
+	return collections, nil
+}
+
+// GetCollection returns one of the user's collections with its items
+func (s *Service) GetCollection(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.getCollectionBy("c.id = ? AND c.user_id = ?", collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Items, err = s.getCollectionItems(collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetSharedCollection returns a collection by its share token. Private
+// collections are reported as not found, and the token is not echoed back.
+func (s *Service) GetSharedCollection(shareToken string) (*models.Collection, error) {
+	collection, err := s.getCollectionBy("c.share_token = ? AND c.visibility != 'private'", shareToken)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.ShareToken = ""
+	collection.Items, err = s.getCollectionItems(collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// CreateCollection creates a new collection at the end of the user's collections
+func (s *Service) CreateCollection(userID string, req models.CreateCollectionRequest) (*models.Collection, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = VisibilityPrivate
+	}
+	if !isValidVisibility(visibility) {
+		return nil, fmt.Errorf("invalid visibility %q", visibility)
+	}
+
+	exists, err := s.collectionNameExists(userID, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("collection with this name already exists")
+	}
+
+	shareToken, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	collectionID := uuid.New().String()
+	now := time.Now()
+	_, err = s.db.Exec(`
+		INSERT INTO collections (id, user_id, name, description, visibility, share_token, sort_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?,
+			(SELECT COALESCE(MAX(sort_order), -1) + 1 FROM collections WHERE user_id = ?), ?, ?)`,
+		collectionID, userID, name, strings.TrimSpace(req.Description), visibility, shareToken, userID, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// UpdateCollection changes the name, description or visibility of a collection
+func (s *Service) UpdateCollection(userID, collectionID string, req models.UpdateCollectionRequest) (*models.Collection, error) {
+	collection, err := s.getCollectionBy("c.id = ? AND c.user_id = ?", collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" && name != collection.Name {
+		exists, err := s.collectionNameExists(userID, name, collectionID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("collection with this name already exists")
+		}
+		collection.Name = name
+	}
+	if req.Description != nil {
+		collection.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Visibility != "" {
+		if !isValidVisibility(req.Visibility) {
+			return nil, fmt.Errorf("invalid visibility %q", req.Visibility)
+		}
+		collection.Visibility = req.Visibility
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE collections
+		SET name = ?, description = ?, visibility = ?, updated_at = ?
+		WHERE id = ?`,
+		collection.Name, collection.Description, collection.Visibility, time.Now(), collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// DeleteCollection deletes a collection and its items (the manga stay in the library)
+func (s *Service) DeleteCollection(userID, collectionID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM collections WHERE id = ? AND user_id = ?", collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("collection not found")
+	}
+
+	if _, err := tx.Exec("DELETE FROM collection_items WHERE collection_id = ?", collectionID); err != nil {
+		return fmt.Errorf("failed to delete collection items: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RegenerateShareToken replaces the share link of a collection, invalidating the old one
+func (s *Service) RegenerateShareToken(userID, collectionID string) (*models.Collection, error) {
+	shareToken, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		UPDATE collections SET share_token = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?`, shareToken, time.Now(), collectionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update share link: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("collection not found")
+	}
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// AddToCollection appends a manga to the end of a collection
+func (s *Service) AddToCollection(userID, collectionID, mangaID string) (*models.Collection, error) {
+	if _, err := s.getCollectionBy("c.id = ? AND c.user_id = ?", collectionID, userID); err != nil {
+		return nil, err
+	}
+
+	// Same rule as the library: external manga IDs are not checked locally
+	if !strings.HasPrefix(mangaID, "mal-") && !strings.HasPrefix(mangaID, "mangadex-") {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", mangaID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check manga existence: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("manga not found")
+		}
+	}
+
+	if err := s.addCollectionItem(collectionID, mangaID); err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// RemoveFromCollection removes a manga from a collection
+func (s *Service) RemoveFromCollection(userID, collectionID, mangaID string) (*models.Collection, error) {
+	if _, err := s.getCollectionBy("c.id = ? AND c.user_id = ?", collectionID, userID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec("DELETE FROM collection_items WHERE collection_id = ? AND manga_id = ?", collectionID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove from collection: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("manga not found in collection")
+	}
+	s.touchCollection(collectionID)
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// ReorderCollection sets the manual order of a collection. mangaIDs must list
+// every manga in the collection exactly once.
+func (s *Service) ReorderCollection(userID, collectionID string, mangaIDs []string) (*models.Collection, error) {
+	collection, err := s.GetCollection(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(collection.Items))
+	for _, item := range collection.Items {
+		current[item.MangaID] = true
+	}
+	if !sameIDSet(current, mangaIDs) {
+		return nil, fmt.Errorf("invalid order: manga_ids must list every manga in the collection exactly once")
+	}
+
+	if err := s.setPositions("UPDATE collection_items SET position = ? WHERE collection_id = ? AND manga_id = ?",
+		collectionID, mangaIDs); err != nil {
+		return nil, err
+	}
+	s.touchCollection(collectionID)
+
+	return s.GetCollection(userID, collectionID)
+}
+
+// ReorderCollections sets the order of the user's collections. collectionIDs
+// must list every collection of the user exactly once.
+func (s *Service) ReorderCollections(userID string, collectionIDs []string) ([]models.Collection, error) {
+	collections, err := s.GetCollections(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(collections))
+	for _, c := range collections {
+		current[c.ID] = true
+	}
+	if !sameIDSet(current, collectionIDs) {
+		return nil, fmt.Errorf("invalid order: collection_ids must list every collection exactly once")
+	}
+
+	if err := s.setPositions("UPDATE collections SET sort_order = ? WHERE user_id = ? AND id = ?",
+		userID, collectionIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetCollections(userID)
+}
+
+// ensureCollection returns the ID of the user's collection with the given name,
+// creating a private collection if there is none
+func (s *Service) ensureCollection(userID, name string) (string, error) {
+	var collectionID string
+	err := s.db.QueryRow("SELECT id FROM collections WHERE user_id = ? AND name = ?", userID, name).Scan(&collectionID)
+	if err == nil {
+		return collectionID, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	collection, err := s.CreateCollection(userID, models.CreateCollectionRequest{Name: name})
+	if err != nil {
+		return "", err
+	}
+	return collection.ID, nil
+}
+
+// addCollectionItem appends a manga to a collection, keeping its position if it is already there
+func (s *Service) addCollectionItem(collectionID, mangaID string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO collection_items (collection_id, manga_id, position, added_at)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM collection_items WHERE collection_id = ?), ?)
+		ON CONFLICT(collection_id, manga_id) DO NOTHING`,
+		collectionID, mangaID, collectionID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add to collection: %w", err)
+	}
+	s.touchCollection(collectionID)
+	return nil
+}
+
+// getCollectionBy loads a single collection (without items) matching a condition
+func (s *Service) getCollectionBy(condition string, args ...interface{}) (*models.Collection, error) {
+	var c models.Collection
+	err := s.db.QueryRow(`
+		SELECT c.id, c.user_id, c.name, c.description, c.visibility, c.share_token, c.sort_order,
+			c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id),
+			COALESCE(u.username, '')
+		FROM collections c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE `+condition, args...).
+		Scan(&c.ID, &c.UserID, &c.Name, &c.Description, &c.Visibility, &c.ShareToken, &c.SortOrder,
+			&c.CreatedAt, &c.UpdatedAt, &c.ItemCount, &c.Owner)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("collection not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return &c, nil
+}
+
+// getCollectionItems returns the manga of a collection in their manual order
+func (s *Service) getCollectionItems(collectionID string) ([]models.CollectionItem, error) {
+	rows, err := s.db.Query(`
+		SELECT i.manga_id, i.position, i.added_at,
+			COALESCE(m.title, ''), COALESCE(m.author, ''), COALESCE(m.cover_url, '')
+		FROM collection_items i
+		LEFT JOIN manga m ON m.id = i.manga_id
+		WHERE i.collection_id = ?
+		ORDER BY i.position, i.added_at`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.CollectionItem{}
+	for rows.Next() {
+		var item models.CollectionItem
+		if err := rows.Scan(&item.MangaID, &item.Position, &item.AddedAt,
+			&item.Title, &item.Author, &item.CoverURL); err != nil {
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// collectionNameExists checks whether the user has another collection with the name
+func (s *Service) collectionNameExists(userID, name, excludeID string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM collections WHERE user_id = ? AND name = ? AND id != ?)`,
+		userID, name, excludeID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check collection name: %w", err)
+	}
+	return exists, nil
+}
+
+// setPositions runs an UPDATE for every ID with its index as position in one transaction
+func (s *Service) setPositions(query, ownerID string, ids []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for position, id := range ids {
+		if _, err := tx.Exec(query, position, ownerID, id); err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// touchCollection bumps the updated_at of a collection
+func (s *Service) touchCollection(collectionID string) {
+	s.db.Exec("UPDATE collections SET updated_at = ? WHERE id = ?", time.Now(), collectionID)
+}
+
+func scanCollections(rows *sql.Rows) ([]models.Collection, error) {
+	collections := []models.Collection{}
+	for rows.Next() {
+		var c models.Collection
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Description, &c.Visibility, &c.ShareToken,
+			&c.SortOrder, &c.CreatedAt, &c.UpdatedAt, &c.ItemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, c)
+	}
+	return collections, nil
+}
+
+// sameIDSet reports whether ids contains exactly the IDs in current, each once
+func sameIDSet(current map[string]bool, ids []string) bool {
+	if len(ids) != len(current) {
+		return false
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !current[id] || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+func isValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// generateShareToken creates an unguessable token for share links
+func generateShareToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
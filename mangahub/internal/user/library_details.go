@@ -0,0 +1,262 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mangahub/internal/activity"
+	"mangahub/pkg/models"
+	"strings"
+	"time"
+)
+
+// libraryDateFormat is the format of start/finish dates in requests and filters
+const libraryDateFormat = "2006-01-02"
+
+// progressDetailColumns selects the personal details of a user_progress row (alias up)
+// in the order expected by progressDetails.dest
+const progressDetailColumns = `COALESCE(up.notes, ''), COALESCE(up.tags, '[]'), up.started_at, up.finished_at,
+	COALESCE(up.reread_count, 0), COALESCE(up.clock, 0)`
+
+// progressDetails holds the scanned personal details of a library entry
+type progressDetails struct {
+	notes       string
+	tags        string
+	startedAt   sql.NullTime
+	finishedAt  sql.NullTime
+	rereadCount int
+	clock       int64
+}
+
+func (d *progressDetails) dest() []interface{} {
+	return []interface{}{&d.notes, &d.tags, &d.startedAt, &d.finishedAt, &d.rereadCount, &d.clock}
+}
+
+// apply copies the scanned details into a library entry
+func (d *progressDetails) apply(progress *models.UserProgress) {
+	progress.Notes = d.notes
+	progress.Tags = decodeTags(d.tags)
+	progress.RereadCount = d.rereadCount
+	progress.Clock = d.clock
+	progress.StartedAt, progress.FinishedAt = nil, nil
+	if d.startedAt.Valid {
+		startedAt := d.startedAt.Time
+		progress.StartedAt = &startedAt
+	}
+	if d.finishedAt.Valid {
+		finishedAt := d.finishedAt.Time
+		progress.FinishedAt = &finishedAt
+	}
+}
+
+// progressStore is implemented by *sql.DB and *sql.Tx
+type progressStore interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// saveProgress adds a manga to the library or updates its chapter and status. The
+// start and finish dates and the re-read count follow the status transition. A
+// negative chapter keeps the current chapter. The change is logged to
+// reading_events through the same store. It reports whether the entry was new.
+func saveProgress(store progressStore, userID, mangaID string, chapter int, status string) (bool, error) {
+	now := time.Now().UTC()
+
+	var oldStatus string
+	var oldChapter int
+	err := store.QueryRow("SELECT status, current_chapter FROM user_progress WHERE user_id = ? AND manga_id = ?",
+		userID, mangaID).Scan(&oldStatus, &oldChapter)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to check progress existence: %w", err)
+	}
+	start, finish, reread := statusTransition(oldStatus, status)
+
+	if err == sql.ErrNoRows {
+		var startedAt, finishedAt *time.Time
+		if start {
+			startedAt = &now
+		}
+		if finish {
+			finishedAt = &now
+		}
+		if chapter < 0 {
+			chapter = 0
+		}
+
+		_, err = store.Exec(`
+			INSERT INTO user_progress (user_id, manga_id, current_chapter, status, last_updated, started_at, finished_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			userID, mangaID, chapter, status, now, startedAt, finishedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to add manga to library: %w", err)
+		}
+		return true, activity.RecordProgress(store, activity.SourceUser, userID, mangaID, "", 0, status, chapter)
+	}
+
+	rereads := 0
+	if reread {
+		rereads = 1
+	}
+	_, err = store.Exec(`
+		UPDATE user_progress
+		SET current_chapter = CASE WHEN ? < 0 THEN current_chapter ELSE ? END,
+			status = ?,
+			last_updated = ?,
+			started_at = CASE WHEN ? THEN COALESCE(started_at, ?) ELSE started_at END,
+			finished_at = CASE WHEN ? THEN ? ELSE finished_at END,
+			reread_count = COALESCE(reread_count, 0) + ?
+		WHERE user_id = ? AND manga_id = ?`,
+		chapter, chapter, status, now, start, now, finish, now, rereads, userID, mangaID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update progress: %w", err)
+	}
+
+	if chapter < 0 {
+		chapter = oldChapter
+	}
+	return false, activity.RecordProgress(store, activity.SourceUser, userID, mangaID, oldStatus, oldChapter, status, chapter)
+}
+
+// statusTransition reports which personal details change when a library entry
+// moves from oldStatus ("" for a new entry) to newStatus: the start date is set
+// once reading begins, the finish date on completion, and finishing a re-read
+// counts as one more read
+func statusTransition(oldStatus, newStatus string) (start, finish, reread bool) {
+	if oldStatus == newStatus {
+		return false, false, false
+	}
+
+	switch newStatus {
+	case "reading", "re_reading":
+		start = true
+	case "completed":
+		finish = true
+		reread = oldStatus == "re_reading"
+	}
+	return start, finish, reread
+}
+
+// UpdateLibraryEntry updates the notes, tags, dates and re-read count of a library entry
+func (s *Service) UpdateLibraryEntry(userID, mangaID string, req models.UpdateLibraryEntryRequest) (*models.UserProgress, error) {
+	entry, err := s.GetUserProgress(userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("manga not found in user's library")
+	}
+
+	if req.Notes != nil {
+		entry.Notes = strings.TrimSpace(*req.Notes)
+	}
+	if req.Tags != nil {
+		entry.Tags = normalizeTags(req.Tags)
+	}
+	if req.StartedAt != nil {
+		if entry.StartedAt, err = parseLibraryDate(*req.StartedAt); err != nil {
+			return nil, fmt.Errorf("invalid started_at: %w", err)
+		}
+	}
+	if req.FinishedAt != nil {
+		if entry.FinishedAt, err = parseLibraryDate(*req.FinishedAt); err != nil {
+			return nil, fmt.Errorf("invalid finished_at: %w", err)
+		}
+	}
+	if req.RereadCount != nil {
+		entry.RereadCount = *req.RereadCount
+	}
+
+	if entry.StartedAt != nil && entry.FinishedAt != nil && entry.FinishedAt.Before(*entry.StartedAt) {
+		return nil, fmt.Errorf("invalid dates: finished_at is before started_at")
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE user_progress
+		SET notes = ?, tags = ?, started_at = ?, finished_at = ?, reread_count = ?
+		WHERE user_id = ? AND manga_id = ?`,
+		entry.Notes, encodeTags(entry.Tags), entry.StartedAt, entry.FinishedAt, entry.RereadCount,
+		userID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update library entry: %w", err)
+	}
+
+	return s.GetUserProgress(userID, mangaID)
+}
+
+// GetLibraryTags returns the user's personal tags with the number of entries using each
+func (s *Service) GetLibraryTags(userID string) ([]models.LibraryTag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.value, COUNT(*)
+		FROM user_progress up, json_each(COALESCE(up.tags, '[]')) t
+		WHERE up.user_id = ?
+		GROUP BY t.value
+		ORDER BY COUNT(*) DESC, t.value`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.LibraryTag{}
+	for rows.Next() {
+		var tag models.LibraryTag
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan library tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// parseLibraryDate parses a YYYY-MM-DD date; an empty string clears the date
+func parseLibraryDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(libraryDateFormat, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected YYYY-MM-DD, got %q", value)
+	}
+	return &date, nil
+}
+
+// normalizeTags trims tags and drops empty and duplicate (case-insensitive) ones
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// encodeTags stores tags as a JSON array, like manga genres
+func encodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// decodeTags reads a JSON array of tags, returning an empty list for invalid data
+func decodeTags(data string) []string {
+	tags := []string{}
+	if data != "" {
+		json.Unmarshal([]byte(data), &tags)
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags
+}
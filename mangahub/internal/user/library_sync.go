@@ -0,0 +1,323 @@
+package user
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entities of the sync_changes feed
+const (
+	SyncEntityLibrary    = "library"
+	SyncEntityRating     = "rating"
+	SyncEntityCollection = "collection"
+)
+
+// Change feed settings
+const (
+	defaultSyncChanges = 500
+	maxSyncChanges     = 1000
+	syncTokenPrefix    = "v2:"
+
+	// Tombstones are kept this long by default (SYNC_TOMBSTONE_RETENTION)
+	defaultTombstoneRetention = 30 * 24 * time.Hour
+)
+
+// tombstoneRetention returns how long removals stay in the feed. Older sync
+// tokens get the full state, since removals they have not seen may be gone.
+func tombstoneRetention() time.Duration {
+	return utils.DurationFromEnv("SYNC_TOMBSTONE_RETENTION", defaultTombstoneRetention)
+}
+
+// syncChange is the latest change of an entity in the feed
+type syncChange struct {
+	seq       int64
+	entity    string
+	entityID  string
+	deleted   bool
+	changedAt time.Time
+}
+
+// GetLibraryChanges returns what changed in the user's library, ratings and
+// collections since a sync token, with tombstones for removals, and the token to
+// send next time. Each entity appears once, in its current state. Without a token
+// everything is returned, as it is for tokens older than the tombstone retention.
+// At most limit changed entities are returned; HasMore
+// tells the client to ask again with the new token.
+func (s *Service) GetLibraryChanges(userID, since string, limit int) (*models.LibraryChanges, error) {
+	if limit <= 0 || limit > maxSyncChanges {
+		limit = defaultSyncChanges
+	}
+
+	// Capture the head first: writes made while loading are sent again next time,
+	// which clients apply idempotently. It comes from the AUTOINCREMENT counter,
+	// which unlike MAX(seq) does not go back when the latest change is pruned.
+	var head int64
+	if err := s.db.QueryRow("SELECT COALESCE((SELECT seq FROM sqlite_sequence WHERE name = 'sync_changes'), 0)").Scan(&head); err != nil {
+		return nil, fmt.Errorf("failed to get sync head: %w", err)
+	}
+
+	now := time.Now()
+	retention := tombstoneRetention()
+
+	changes := &models.LibraryChanges{
+		Library:     []models.UserProgress{},
+		Ratings:     []models.MangaRating{},
+		Collections: []models.Collection{},
+		Deleted:     []models.SyncTombstone{},
+	}
+
+	var from int64
+	full := since == ""
+	if !full {
+		var issuedAt time.Time
+		var err error
+		from, issuedAt, err = decodeSyncToken(since)
+		if err != nil {
+			return nil, err
+		}
+		if from > head {
+			return nil, fmt.Errorf("invalid sync token: ahead of the server")
+		}
+		full = now.Sub(issuedAt) > retention
+	}
+
+	if full {
+		if err := s.loadSyncState(userID, nil, nil, nil, changes); err != nil {
+			return nil, err
+		}
+		changes.Full = true
+		changes.SyncToken = encodeSyncToken(head, now)
+		return changes, nil
+	}
+
+	// SQLite takes the bare columns from the row with the largest seq
+	rows, err := s.db.Query(`
+		SELECT MAX(seq), entity, entity_id, deleted, changed_at
+		FROM sync_changes
+		WHERE user_id = ? AND seq > ? AND seq <= ?
+		GROUP BY entity, entity_id
+		ORDER BY MAX(seq)
+		LIMIT ?`, userID, from, head, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library changes: %w", err)
+	}
+	var feed []syncChange
+	for rows.Next() {
+		var change syncChange
+		if err := rows.Scan(&change.seq, &change.entity, &change.entityID, &change.deleted, &change.changedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan library change: %w", err)
+		}
+		feed = append(feed, change)
+	}
+	rows.Close()
+
+	changes.SyncToken = encodeSyncToken(head, now)
+	if len(feed) > limit {
+		// The rest of the feed is newer than the last change sent, so its
+		// tombstones are kept at least as long as that change's
+		feed = feed[:limit]
+		changes.HasMore = true
+		last := feed[len(feed)-1]
+		changes.SyncToken = encodeSyncToken(last.seq, last.changedAt)
+	}
+
+	var libraryIDs, ratingIDs, collectionIDs []string
+	for _, change := range feed {
+		if change.deleted {
+			changes.Deleted = append(changes.Deleted, models.SyncTombstone{
+				Entity:    change.entity,
+				ID:        change.entityID,
+				DeletedAt: change.changedAt,
+			})
+			continue
+		}
+		switch change.entity {
+		case SyncEntityLibrary:
+			libraryIDs = append(libraryIDs, change.entityID)
+		case SyncEntityRating:
+			ratingIDs = append(ratingIDs, change.entityID)
+		case SyncEntityCollection:
+			collectionIDs = append(collectionIDs, change.entityID)
+		}
+	}
+
+	// Entities removed after the head was captured are skipped here; their
+	// tombstones come with the next request
+	if len(libraryIDs)+len(ratingIDs)+len(collectionIDs) > 0 {
+		if err := s.loadSyncState(userID, libraryIDs, ratingIDs, collectionIDs, changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// loadSyncState fills in the current library entries, ratings and collections
+// with the given IDs, or all of them when every list is nil
+func (s *Service) loadSyncState(userID string, libraryIDs, ratingIDs, collectionIDs []string, changes *models.LibraryChanges) error {
+	all := libraryIDs == nil && ratingIDs == nil && collectionIDs == nil
+
+	if all || len(libraryIDs) > 0 {
+		condition, args := syncCondition("up.manga_id", userID, libraryIDs)
+		rows, err := s.db.Query(`
+			SELECT up.manga_id, up.current_chapter, up.status, up.last_updated, `+progressDetailColumns+`,
+				COALESCE(m.title, ''), COALESCE(m.author, ''), COALESCE(m.cover_url, '')
+			FROM user_progress up
+			LEFT JOIN manga m ON m.id = up.manga_id
+			WHERE up.user_id = ?`+condition+`
+			ORDER BY up.last_updated DESC`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get library entries: %w", err)
+		}
+		for rows.Next() {
+			progress := models.UserProgress{UserID: userID}
+			var details progressDetails
+			dest := []interface{}{&progress.MangaID, &progress.CurrentChapter, &progress.Status, &progress.LastUpdated}
+			dest = append(dest, details.dest()...)
+			if err := rows.Scan(append(dest, &progress.Title, &progress.Author, &progress.CoverURL)...); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan library entry: %w", err)
+			}
+			details.apply(&progress)
+			changes.Library = append(changes.Library, progress)
+		}
+		rows.Close()
+	}
+
+	if all || len(ratingIDs) > 0 {
+		condition, args := syncCondition("manga_id", userID, ratingIDs)
+		rows, err := s.db.Query(`
+			SELECT id, user_id, manga_id, rating, story, art, characters, created_at, updated_at
+			FROM manga_ratings
+			WHERE user_id = ?`+condition+`
+			ORDER BY updated_at DESC`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get ratings: %w", err)
+		}
+		for rows.Next() {
+			var rating models.MangaRating
+			if err := rows.Scan(&rating.ID, &rating.UserID, &rating.MangaID, &rating.Rating, &rating.Story,
+				&rating.Art, &rating.Characters, &rating.CreatedAt, &rating.UpdatedAt); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan rating: %w", err)
+			}
+			changes.Ratings = append(changes.Ratings, rating)
+		}
+		rows.Close()
+	}
+
+	if all {
+		collections, err := s.GetCollections(userID)
+		if err != nil {
+			return err
+		}
+		for _, collection := range collections {
+			collectionIDs = append(collectionIDs, collection.ID)
+		}
+	}
+	for _, collectionID := range collectionIDs {
+		collection, err := s.GetCollection(userID, collectionID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				continue
+			}
+			return err
+		}
+		changes.Collections = append(changes.Collections, *collection)
+	}
+
+	return nil
+}
+
+// PruneSyncChanges drops changes superseded by a later change of the same
+// entity, and tombstones older than the retention, of all users. Only the
+// latest change of each entity is ever sent, so tokens stay valid; tokens
+// older than the retention get the full state instead.
+func (s *Service) PruneSyncChanges() error {
+	cutoff := time.Now().Add(-tombstoneRetention())
+	result, err := s.db.Exec(`
+		DELETE FROM sync_changes
+		WHERE seq < (
+			SELECT MAX(later.seq) FROM sync_changes later
+			WHERE later.user_id = sync_changes.user_id AND later.entity = sync_changes.entity
+				AND later.entity_id = sync_changes.entity_id)
+			OR (deleted = 1 AND changed_at < ?)`, cutoff.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to prune sync changes: %w", err)
+	}
+	if pruned, _ := result.RowsAffected(); pruned > 0 {
+		log.Printf("Pruned %d sync changes", pruned)
+	}
+	return nil
+}
+
+// StartSyncChangePrune prunes the change feed now and then at every interval
+func (s *Service) StartSyncChangePrune(interval time.Duration) {
+	if err := s.PruneSyncChanges(); err != nil {
+		log.Printf("Sync change prune failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.PruneSyncChanges(); err != nil {
+			log.Printf("Sync change prune failed: %v", err)
+		}
+	}
+}
+
+// syncCondition restricts a query to the given IDs of column; nil IDs match all
+func syncCondition(column, userID string, ids []string) (string, []interface{}) {
+	args := []interface{}{userID}
+	if ids == nil {
+		return "", args
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return " AND " + column + " IN (?" + strings.Repeat(", ?", len(ids)-1) + ")", args
+}
+
+// Sync tokens carry the feed position and the time up to which the client has
+// seen every change, which decides whether the tombstones it needs still exist
+func encodeSyncToken(seq int64, seenAt time.Time) string {
+	token := syncTokenPrefix + strconv.FormatInt(seq, 10) + ":" + strconv.FormatInt(seenAt.Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
+
+// decodeSyncToken parses a token. Tokens from before tombstones expired (v1)
+// carry no time and decode as infinitely old.
+func decodeSyncToken(token string) (int64, time.Time, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid sync token")
+	}
+	if v1, ok := strings.CutPrefix(string(data), "v1:"); ok {
+		seq, err := strconv.ParseInt(v1, 10, 64)
+		if err != nil || seq < 0 {
+			return 0, time.Time{}, fmt.Errorf("invalid sync token")
+		}
+		return seq, time.Time{}, nil
+	}
+
+	seqPart, seenPart, ok := strings.Cut(strings.TrimPrefix(string(data), syncTokenPrefix), ":")
+	if !ok || !strings.HasPrefix(string(data), syncTokenPrefix) {
+		return 0, time.Time{}, fmt.Errorf("invalid sync token")
+	}
+	seq, err := strconv.ParseInt(seqPart, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, time.Time{}, fmt.Errorf("invalid sync token")
+	}
+	seenAt, err := strconv.ParseInt(seenPart, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid sync token")
+	}
+	return seq, time.Unix(seenAt, 0), nil
+}
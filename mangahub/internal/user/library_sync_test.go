@@ -0,0 +1,111 @@
+package user
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"mangahub/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSyncToken(t *testing.T) {
+	seenAt := time.Unix(1_700_000_000, 0)
+
+	seq, decodedAt, err := decodeSyncToken(encodeSyncToken(42, seenAt))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+	assert.True(t, decodedAt.Equal(seenAt))
+
+	// v1 tokens carry no time and decode as infinitely old
+	seq, decodedAt, err = decodeSyncToken(base64.RawURLEncoding.EncodeToString([]byte("v1:17")))
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), seq)
+	assert.True(t, decodedAt.IsZero())
+
+	for _, token := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("v1:-1")),
+		base64.RawURLEncoding.EncodeToString([]byte("v2:12")),
+		base64.RawURLEncoding.EncodeToString([]byte("v2:x:1700000000")),
+		base64.RawURLEncoding.EncodeToString([]byte("v3:1:1700000000")),
+	} {
+		_, _, err := decodeSyncToken(token)
+		assert.Error(t, err, token)
+	}
+}
+
+func TestGetLibraryChangesSendsFullStatePastTombstoneRetention(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	createTestManga(t, s.db, "m1")
+	createTestManga(t, s.db, "m2")
+
+	require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m1", Status: "reading"}))
+	require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m2", Status: "reading"}))
+	initial, err := s.GetLibraryChanges(userID, "", 0)
+	require.NoError(t, err)
+	require.True(t, initial.Full)
+
+	require.NoError(t, s.RemoveFromLibrary(userID, "m2"))
+
+	// A recent token gets the tombstone
+	changes, err := s.GetLibraryChanges(userID, initial.SyncToken, 0)
+	require.NoError(t, err)
+	assert.False(t, changes.Full)
+	require.Len(t, changes.Deleted, 1)
+	assert.Equal(t, SyncEntityLibrary, changes.Deleted[0].Entity)
+	assert.Equal(t, "m2", changes.Deleted[0].ID)
+
+	// A token older than the retention may have missed pruned tombstones
+	seq, _, err := decodeSyncToken(initial.SyncToken)
+	require.NoError(t, err)
+	stale := encodeSyncToken(seq, time.Now().Add(-defaultTombstoneRetention-time.Hour))
+	changes, err = s.GetLibraryChanges(userID, stale, 0)
+	require.NoError(t, err)
+	assert.True(t, changes.Full)
+	assert.Empty(t, changes.Deleted)
+	require.Len(t, changes.Library, 1)
+	assert.Equal(t, "m1", changes.Library[0].MangaID)
+}
+
+func TestPruneSyncChangesKeepsLatestChangesAndRecentTombstones(t *testing.T) {
+	s := newTestService(t)
+	reader := createTestUser(t, s.db, "reader")
+	webOnly := createTestUser(t, s.db, "webonly")
+	for _, id := range []string{"m1", "m2", "m3"} {
+		createTestManga(t, s.db, id)
+	}
+
+	for _, userID := range []string{reader, webOnly} {
+		require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m1", Status: "reading"}))
+		require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m1", Status: "completed"}))
+		require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m2", Status: "reading"}))
+		require.NoError(t, s.RemoveFromLibrary(userID, "m2"))
+		require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m3", Status: "reading"}))
+		require.NoError(t, s.RemoveFromLibrary(userID, "m3"))
+	}
+	_, err := s.db.Exec("UPDATE sync_changes SET changed_at = ? WHERE entity_id = 'm3' AND deleted = 1",
+		time.Now().UTC().Add(-defaultTombstoneRetention-time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, s.PruneSyncChanges())
+
+	// Both users, whether they sync or not, keep one change per entity and no expired tombstone
+	for _, userID := range []string{reader, webOnly} {
+		rows, err := s.db.Query("SELECT entity_id, deleted FROM sync_changes WHERE user_id = ? ORDER BY seq", userID)
+		require.NoError(t, err)
+		remaining := map[string]bool{}
+		for rows.Next() {
+			var entityID string
+			var deleted bool
+			require.NoError(t, rows.Scan(&entityID, &deleted))
+			assert.NotContains(t, remaining, entityID, "superseded change of %s kept", entityID)
+			remaining[entityID] = deleted
+		}
+		require.NoError(t, rows.Close())
+		assert.Equal(t, map[string]bool{"m1": false, "m2": true}, remaining)
+	}
+}
@@ -0,0 +1,885 @@
+package user
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"mangahub/internal/activity"
+	"mangahub/pkg/models"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported library file formats
+const (
+	LibraryFormatMAL     = "mal"     // MyAnimeList XML export (optionally gzipped)
+	LibraryFormatAniList = "anilist" // AniList MediaListCollection JSON
+)
+
+// MaxImportFileSize limits library exports and Tachiyomi backups, both as
+// uploaded and after decompression
+const MaxImportFileSize = 20 << 20
+
+// libraryEntry is an imported list entry in a format independent form
+type libraryEntry struct {
+	Source    string // mal, anilist
+	SourceID  string // ID in the source the file came from
+	MALID     string // MAL ID, also known for AniList entries
+	Title     string
+	Status    string // local status
+	Chapter   int
+	Score     int       // 0-10, 0 when unscored
+	UpdatedAt time.Time // zero when the format has no timestamps
+	// Personal details, empty when the format has none
+	Notes       string
+	Tags        []string
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	RereadCount int
+}
+
+// MAL XML export format
+
+type malXMLText struct {
+	Text string `xml:",cdata"`
+}
+
+type malXMLExport struct {
+	XMLName xml.Name      `xml:"myanimelist"`
+	MyInfo  malXMLInfo    `xml:"myinfo"`
+	Manga   []malXMLEntry `xml:"manga"`
+}
+
+type malXMLInfo struct {
+	UserID          int    `xml:"user_id"`
+	UserName        string `xml:"user_name"`
+	UserExportType  int    `xml:"user_export_type"` // 2 = manga
+	TotalManga      int    `xml:"user_total_manga"`
+	TotalReading    int    `xml:"user_total_reading"`
+	TotalCompleted  int    `xml:"user_total_completed"`
+	TotalOnHold     int    `xml:"user_total_onhold"`
+	TotalDropped    int    `xml:"user_total_dropped"`
+	TotalPlanToRead int    `xml:"user_total_plantoread"`
+}
+
+type malXMLEntry struct {
+	MangaDBID      int        `xml:"manga_mangadb_id"`
+	Title          malXMLText `xml:"manga_title"`
+	Volumes        int        `xml:"manga_volumes"`
+	Chapters       int        `xml:"manga_chapters"`
+	MyID           int        `xml:"my_id"`
+	ReadVolumes    int        `xml:"my_read_volumes"`
+	ReadChapters   int        `xml:"my_read_chapters"`
+	StartDate      string     `xml:"my_start_date"`
+	FinishDate     string     `xml:"my_finish_date"`
+	Score          int        `xml:"my_score"`
+	Status         string     `xml:"my_status"`
+	TimesRead      int        `xml:"my_times_read"`
+	Comments       malXMLText `xml:"my_comments"`
+	Tags           malXMLText `xml:"my_tags"` // Comma separated
+	Rereading      string     `xml:"my_rereading"`
+	UpdateOnImport int        `xml:"update_on_import"`
+}
+
+// AniList MediaListCollection JSON format
+
+type aniListCollection struct {
+	Lists []aniListList `json:"lists"`
+	User  *aniListUser  `json:"user,omitempty"`
+}
+
+type aniListUser struct {
+	Name             string `json:"name"`
+	MediaListOptions struct {
+		ScoreFormat string `json:"scoreFormat"`
+	} `json:"mediaListOptions"`
+}
+
+type aniListList struct {
+	Name    string         `json:"name"`
+	Entries []aniListEntry `json:"entries"`
+}
+
+type aniListEntry struct {
+	Status      string       `json:"status"` // CURRENT, COMPLETED, PAUSED, DROPPED, PLANNING, REPEATING
+	Score       float64      `json:"score"`
+	Progress    int          `json:"progress"`
+	Repeat      int          `json:"repeat"`
+	Notes       string       `json:"notes,omitempty"`
+	StartedAt   aniListDate  `json:"startedAt"`
+	CompletedAt aniListDate  `json:"completedAt"`
+	UpdatedAt   int64        `json:"updatedAt"`
+	Media       aniListMedia `json:"media"`
+}
+
+// aniListDate is an AniList FuzzyDate, unknown parts are null
+type aniListDate struct {
+	Year  *int `json:"year"`
+	Month *int `json:"month"`
+	Day   *int `json:"day"`
+}
+
+type aniListMedia struct {
+	ID       int    `json:"id"`
+	IDMal    int    `json:"idMal,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Chapters int    `json:"chapters,omitempty"`
+	Title    struct {
+		Romaji  string `json:"romaji,omitempty"`
+		English string `json:"english,omitempty"`
+		Native  string `json:"native,omitempty"`
+	} `json:"title"`
+}
+
+// ImportLibrary imports a MAL XML or AniList JSON export into user_progress and
+// manga_ratings. Entries are matched to local manga through manga_sources (and an
+// exact title match as fallback); unmatched entries are added to the review list.
+// Existing library entries are only overwritten when overwrite is set.
+// An empty format is detected from the file contents.
+func (s *Service) ImportLibrary(userID, format string, data []byte, overwrite bool) (*models.LibraryImportResult, error) {
+	data, err := maybeGunzip(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = detectLibraryFormat(data)
+	}
+
+	var entries []libraryEntry
+	switch format {
+	case LibraryFormatMAL:
+		entries, err = parseMALExport(data)
+	case LibraryFormatAniList:
+		entries, err = parseAniListExport(data)
+	default:
+		return nil, fmt.Errorf("unsupported library format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LibraryImportResult{Format: format, Total: len(entries)}
+	var applied []string
+
+	for _, entry := range entries {
+		mangaID, _, ok := s.matchLibraryEntry(entry)
+		if !ok {
+			if err := s.addImportReviewItem(userID, entry); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.Title, err))
+				continue
+			}
+			result.Unmatched++
+			continue
+		}
+
+		if err := s.applyLibraryEntry(userID, mangaID, entry, overwrite, result); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.Title, err))
+			continue
+		}
+		applied = append(applied, mangaID)
+	}
+
+	log.Printf("User %s imported %s library: %d imported, %d updated, %d unmatched",
+		userID, format, result.Imported, result.Updated, result.Unmatched)
+
+	// Push the imported entries to the linked MAL account, if any
+	if len(applied) > 0 {
+		go s.PushProgressToMAL(userID, applied...)
+	}
+
+	return result, nil
+}
+
+// ExportLibrary writes the user's library and ratings in the given format
+func (s *Service) ExportLibrary(userID, format string) ([]byte, error) {
+	var username string
+	err := s.db.QueryRow("SELECT username FROM users WHERE id = ?", userID).Scan(&username)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT up.manga_id, up.current_chapter, up.status, up.last_updated, `+progressDetailColumns+`,
+			COALESCE(m.title, ''), COALESCE(m.total_chapters, 0), COALESCE(r.rating, 0)
+		FROM user_progress up
+		LEFT JOIN manga m ON m.id = up.manga_id
+		LEFT JOIN manga_ratings r ON r.user_id = up.user_id AND r.manga_id = up.manga_id
+		WHERE up.user_id = ?
+		ORDER BY up.last_updated DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library: %w", err)
+	}
+
+	type exportRow struct {
+		progress      models.UserProgress
+		totalChapters int
+		rating        int
+	}
+	var library []exportRow
+	for rows.Next() {
+		var row exportRow
+		var details progressDetails
+		dest := []interface{}{&row.progress.MangaID, &row.progress.CurrentChapter, &row.progress.Status,
+			&row.progress.LastUpdated}
+		dest = append(dest, details.dest()...)
+		if err := rows.Scan(append(dest, &row.progress.Title, &row.totalChapters, &row.rating)...); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan library entry: %w", err)
+		}
+		details.apply(&row.progress)
+		library = append(library, row)
+	}
+	rows.Close()
+
+	switch format {
+	case LibraryFormatMAL:
+		export := malXMLExport{MyInfo: malXMLInfo{UserName: username, UserExportType: 2}}
+		for _, row := range library {
+			malID, ok := s.malIDForManga(row.progress.MangaID)
+			if !ok {
+				// MAL can only import entries it knows the ID of
+				continue
+			}
+			status, rereading := localStatusToMALXML(row.progress.Status)
+			export.Manga = append(export.Manga, malXMLEntry{
+				MangaDBID:      malID,
+				Title:          malXMLText{Text: row.progress.Title},
+				Chapters:       row.totalChapters,
+				ReadChapters:   row.progress.CurrentChapter,
+				StartDate:      formatMALDate(row.progress.StartedAt),
+				FinishDate:     formatMALDate(row.progress.FinishedAt),
+				Score:          row.rating,
+				Status:         status,
+				TimesRead:      row.progress.RereadCount,
+				Comments:       malXMLText{Text: row.progress.Notes},
+				Tags:           malXMLText{Text: strings.Join(row.progress.Tags, ", ")},
+				Rereading:      rereading,
+				UpdateOnImport: 1,
+			})
+			export.MyInfo.countStatus(status)
+		}
+		export.MyInfo.TotalManga = len(export.Manga)
+
+		out, err := xml.MarshalIndent(export, "", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode MAL export: %w", err)
+		}
+		return append([]byte(xml.Header), out...), nil
+
+	case LibraryFormatAniList:
+		collection := aniListCollection{User: &aniListUser{Name: username}}
+		collection.User.MediaListOptions.ScoreFormat = "POINT_10"
+		lists := make(map[string]*aniListList)
+		var order []string
+
+		for _, row := range library {
+			status := localStatusToAniList(row.progress.Status)
+			entry := aniListEntry{
+				Status:      status,
+				Score:       float64(row.rating),
+				Progress:    row.progress.CurrentChapter,
+				Repeat:      row.progress.RereadCount,
+				Notes:       row.progress.Notes,
+				StartedAt:   toAniListDate(row.progress.StartedAt),
+				CompletedAt: toAniListDate(row.progress.FinishedAt),
+				UpdatedAt:   row.progress.LastUpdated.Unix(),
+			}
+			entry.Media.Type = "MANGA"
+			entry.Media.Chapters = row.totalChapters
+			entry.Media.Title.Romaji = row.progress.Title
+			if malID, ok := s.malIDForManga(row.progress.MangaID); ok {
+				entry.Media.IDMal = malID
+			}
+			if id, ok := s.sourceIDForManga(row.progress.MangaID, LibraryFormatAniList); ok {
+				entry.Media.ID, _ = strconv.Atoi(id)
+			}
+
+			list, ok := lists[status]
+			if !ok {
+				list = &aniListList{Name: aniListListName(status)}
+				lists[status] = list
+				order = append(order, status)
+			}
+			list.Entries = append(list.Entries, entry)
+		}
+		for _, status := range order {
+			collection.Lists = append(collection.Lists, *lists[status])
+		}
+
+		out, err := json.MarshalIndent(map[string]interface{}{
+			"data": map[string]interface{}{"MediaListCollection": collection},
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode AniList export: %w", err)
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported library format %q", format)
+}
+
+// GetImportReviewList returns imported entries waiting to be matched by the user
+func (s *Service) GetImportReviewList(userID string) ([]models.LibraryImportReviewItem, error) {
+	rows, err := s.db.Query(`
+		SELECT id, source, source_id, mal_id, title, status, current_chapter, score, created_at
+		FROM library_import_review
+		WHERE user_id = ?
+		ORDER BY created_at DESC, title`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import review list: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.LibraryImportReviewItem{}
+	for rows.Next() {
+		var item models.LibraryImportReviewItem
+		if err := rows.Scan(&item.ID, &item.Source, &item.SourceID, &item.MALID, &item.Title,
+			&item.Status, &item.CurrentChapter, &item.Score, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ResolveImportReviewItem imports a review list entry as the given manga and removes it from the list
+func (s *Service) ResolveImportReviewItem(userID string, itemID int64, mangaID string) error {
+	var entry libraryEntry
+	err := s.db.QueryRow(`
+		SELECT source, source_id, mal_id, title, status, current_chapter, score
+		FROM library_import_review WHERE id = ? AND user_id = ?`, itemID, userID).
+		Scan(&entry.Source, &entry.SourceID, &entry.MALID, &entry.Title, &entry.Status, &entry.Chapter, &entry.Score)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("review item not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get review item: %w", err)
+	}
+
+	if !strings.HasPrefix(mangaID, "mal-") && !strings.HasPrefix(mangaID, "mangadex-") {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", mangaID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check manga existence: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("manga not found")
+		}
+	}
+
+	result := &models.LibraryImportResult{}
+	if err := s.applyLibraryEntry(userID, mangaID, entry, true, result); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec("DELETE FROM library_import_review WHERE id = ?", itemID); err != nil {
+		return fmt.Errorf("failed to remove review item: %w", err)
+	}
+
+	go s.PushProgressToMAL(userID, mangaID)
+	return nil
+}
+
+// DismissImportReviewItem removes an entry from the review list without importing it
+func (s *Service) DismissImportReviewItem(userID string, itemID int64) error {
+	result, err := s.db.Exec("DELETE FROM library_import_review WHERE id = ? AND user_id = ?", itemID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove review item: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("review item not found")
+	}
+	return nil
+}
+
+// matchLibraryEntry finds the local manga for an imported entry and reports
+// how it was matched: by the entry's own source ID, by MAL ID ("mal") or by title
+func (s *Service) matchLibraryEntry(entry libraryEntry) (string, string, bool) {
+	if entry.Source != "mal" && entry.SourceID != "" {
+		if mangaID, ok := s.mangaIDForSource(entry.Source, entry.SourceID); ok {
+			return mangaID, entry.Source, true
+		}
+	}
+	if entry.MALID != "" {
+		if mangaID, ok := s.mangaIDForSource("mal", entry.MALID); ok {
+			return mangaID, "mal", true
+		}
+	}
+
+	// Fall back to an exact, unambiguous title match
+	if entry.Title == "" {
+		return "", "", false
+	}
+	rows, err := s.db.Query("SELECT id FROM manga WHERE LOWER(title) = LOWER(?) LIMIT 2", entry.Title)
+	if err != nil {
+		return "", "", false
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if rows.Scan(&id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) != 1 {
+		return "", "", false
+	}
+	return ids[0], "title", true
+}
+
+// mangaIDForSource maps an external ID to a local manga ID through manga_sources
+func (s *Service) mangaIDForSource(source, sourceID string) (string, bool) {
+	var mangaID string
+	err := s.db.QueryRow(
+		"SELECT manga_id FROM manga_sources WHERE source = ? AND source_id = ? LIMIT 1",
+		source, sourceID).Scan(&mangaID)
+	return mangaID, err == nil && mangaID != ""
+}
+
+// sourceIDForManga maps a local manga ID to its ID in an external source
+func (s *Service) sourceIDForManga(mangaID, source string) (string, bool) {
+	var sourceID string
+	err := s.db.QueryRow(
+		"SELECT source_id FROM manga_sources WHERE manga_id = ? AND source = ?",
+		mangaID, source).Scan(&sourceID)
+	return sourceID, err == nil && sourceID != ""
+}
+
+// applyLibraryEntry writes an imported entry into user_progress and manga_ratings
+func (s *Service) applyLibraryEntry(userID, mangaID string, entry libraryEntry, overwrite bool, result *models.LibraryImportResult) error {
+	existing, err := s.GetUserProgress(userID, mangaID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && !overwrite {
+		result.Skipped++
+		return nil
+	}
+
+	updated := entry.UpdatedAt
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	// Personal details the file does not have are kept
+	_, err = s.db.Exec(`
+		INSERT INTO user_progress (user_id, manga_id, current_chapter, status, last_updated,
+			notes, tags, started_at, finished_at, reread_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, manga_id) DO UPDATE SET
+			current_chapter = excluded.current_chapter,
+			status = excluded.status,
+			last_updated = excluded.last_updated,
+			notes = CASE WHEN excluded.notes != '' THEN excluded.notes ELSE user_progress.notes END,
+			tags = CASE WHEN excluded.tags != '[]' THEN excluded.tags ELSE user_progress.tags END,
+			started_at = COALESCE(excluded.started_at, user_progress.started_at),
+			finished_at = COALESCE(excluded.finished_at, user_progress.finished_at),
+			reread_count = MAX(excluded.reread_count, COALESCE(user_progress.reread_count, 0))`,
+		userID, mangaID, entry.Chapter, entry.Status, updated,
+		entry.Notes, encodeTags(normalizeTags(entry.Tags)), entry.StartedAt, entry.FinishedAt, entry.RereadCount)
+	if err != nil {
+		return fmt.Errorf("failed to import progress: %w", err)
+	}
+	if existing != nil {
+		result.Updated++
+	} else {
+		result.Imported++
+	}
+
+	var fromStatus string
+	var fromChapter int
+	if existing != nil {
+		fromStatus, fromChapter = existing.Status, existing.CurrentChapter
+	}
+	if err := activity.RecordProgress(s.db, activity.SourceImport, userID, mangaID,
+		fromStatus, fromChapter, entry.Status, entry.Chapter); err != nil {
+		return err
+	}
+
+	if entry.Score > 0 {
+		_, err = s.db.Exec(`
+			INSERT INTO manga_ratings (user_id, manga_id, rating, created_at, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			ON CONFLICT(user_id, manga_id) DO UPDATE SET
+				rating = excluded.rating,
+				updated_at = CURRENT_TIMESTAMP`,
+			userID, mangaID, clampScore(entry.Score))
+		if err != nil {
+			return fmt.Errorf("failed to import rating: %w", err)
+		}
+		if err := activity.RecordRating(s.db, activity.SourceImport, userID, mangaID, clampScore(entry.Score)); err != nil {
+			return err
+		}
+		result.Ratings++
+	}
+
+	return nil
+}
+
+// addImportReviewItem stores an unmatched entry for manual review
+func (s *Service) addImportReviewItem(userID string, entry libraryEntry) error {
+	_, err := s.db.Exec(`
+		INSERT INTO library_import_review (user_id, source, source_id, mal_id, title, status, current_chapter, score)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, source, source_id, title) DO UPDATE SET
+			mal_id = excluded.mal_id,
+			status = excluded.status,
+			current_chapter = excluded.current_chapter,
+			score = excluded.score,
+			created_at = CURRENT_TIMESTAMP`,
+		userID, entry.Source, entry.SourceID, entry.MALID, entry.Title, entry.Status, entry.Chapter, entry.Score)
+	if err != nil {
+		return fmt.Errorf("failed to add review item: %w", err)
+	}
+	return nil
+}
+
+// parseMALExport parses a MyAnimeList manga list XML export
+func parseMALExport(data []byte) ([]libraryEntry, error) {
+	var export malXMLExport
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid MAL XML export: %w", err)
+	}
+
+	entries := make([]libraryEntry, 0, len(export.Manga))
+	for _, m := range export.Manga {
+		status := malXMLStatusToLocal(m.Status)
+		if strings.EqualFold(m.Rereading, "yes") || m.Rereading == "1" {
+			status = "re_reading"
+		}
+		entry := libraryEntry{
+			Source:      LibraryFormatMAL,
+			Title:       strings.TrimSpace(m.Title.Text),
+			Status:      status,
+			Chapter:     m.ReadChapters,
+			Score:       clampScore(m.Score),
+			Notes:       strings.TrimSpace(m.Comments.Text),
+			Tags:        strings.Split(m.Tags.Text, ","),
+			StartedAt:   parseMALDate(m.StartDate),
+			FinishedAt:  parseMALDate(m.FinishDate),
+			RereadCount: m.TimesRead,
+		}
+		if m.MangaDBID > 0 {
+			entry.SourceID = strconv.Itoa(m.MangaDBID)
+			entry.MALID = entry.SourceID
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseAniListExport parses an AniList MediaListCollection, either as the raw
+// GraphQL response ({"data": {"MediaListCollection": ...}}) or the collection itself
+func parseAniListExport(data []byte) ([]libraryEntry, error) {
+	var wrapped struct {
+		Data *struct {
+			MediaListCollection *aniListCollection `json:"MediaListCollection"`
+		} `json:"data"`
+		MediaListCollection *aniListCollection `json:"MediaListCollection"`
+		aniListCollection
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("invalid AniList JSON export: %w", err)
+	}
+
+	collection := &wrapped.aniListCollection
+	switch {
+	case wrapped.Data != nil && wrapped.Data.MediaListCollection != nil:
+		collection = wrapped.Data.MediaListCollection
+	case wrapped.MediaListCollection != nil:
+		collection = wrapped.MediaListCollection
+	}
+
+	scoreFormat := ""
+	if collection.User != nil {
+		scoreFormat = collection.User.MediaListOptions.ScoreFormat
+	}
+
+	var entries []libraryEntry
+	seen := make(map[int]bool) // Custom lists repeat entries of the status lists
+	for _, list := range collection.Lists {
+		for _, e := range list.Entries {
+			if e.Media.Type != "" && e.Media.Type != "MANGA" {
+				continue
+			}
+			if e.Media.ID > 0 {
+				if seen[e.Media.ID] {
+					continue
+				}
+				seen[e.Media.ID] = true
+			}
+
+			entry := libraryEntry{
+				Source:      LibraryFormatAniList,
+				Title:       aniListTitle(e.Media),
+				Status:      aniListStatusToLocal(e.Status),
+				Chapter:     e.Progress,
+				Score:       aniListScoreToTenPoint(e.Score, scoreFormat),
+				Notes:       strings.TrimSpace(e.Notes),
+				StartedAt:   e.StartedAt.time(),
+				FinishedAt:  e.CompletedAt.time(),
+				RereadCount: e.Repeat,
+			}
+			if e.Media.ID > 0 {
+				entry.SourceID = strconv.Itoa(e.Media.ID)
+			}
+			if e.Media.IDMal > 0 {
+				entry.MALID = strconv.Itoa(e.Media.IDMal)
+			}
+			if e.UpdatedAt > 0 {
+				entry.UpdatedAt = time.Unix(e.UpdatedAt, 0)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// parseMALDate parses a MAL list date (YYYY-MM-DD, unknown parts are 00)
+func parseMALDate(value string) *time.Time {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 3 {
+		return nil
+	}
+	year, _ := strconv.Atoi(parts[0])
+	month, _ := strconv.Atoi(parts[1])
+	day, _ := strconv.Atoi(parts[2])
+	return dateFromParts(year, month, day)
+}
+
+// formatMALDate formats a date for a MAL export, 0000-00-00 when unknown
+func formatMALDate(date *time.Time) string {
+	if date == nil {
+		return "0000-00-00"
+	}
+	return date.Format(libraryDateFormat)
+}
+
+// time converts a fuzzy date, nil when the year is unknown
+func (d aniListDate) time() *time.Time {
+	if d.Year == nil {
+		return nil
+	}
+	month, day := 0, 0
+	if d.Month != nil {
+		month = *d.Month
+	}
+	if d.Day != nil {
+		day = *d.Day
+	}
+	return dateFromParts(*d.Year, month, day)
+}
+
+// toAniListDate converts a date to a fuzzy date with null parts when unknown
+func toAniListDate(date *time.Time) aniListDate {
+	if date == nil {
+		return aniListDate{}
+	}
+	year, month, day := date.Year(), int(date.Month()), date.Day()
+	return aniListDate{Year: &year, Month: &month, Day: &day}
+}
+
+// dateFromParts builds a UTC date, unknown (zero) months and days become the first
+func dateFromParts(year, month, day int) *time.Time {
+	if year <= 0 {
+		return nil
+	}
+	if month < 1 || month > 12 {
+		month = 1
+	}
+	if day < 1 || day > 31 {
+		day = 1
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// detectLibraryFormat guesses the format of an export file from its first byte
+func detectLibraryFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return LibraryFormatAniList
+	}
+	return LibraryFormatMAL
+}
+
+// maybeGunzip transparently decompresses gzipped exports (MAL exports are .xml.gz)
+func maybeGunzip(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip file: %w", err)
+	}
+	defer reader.Close()
+
+	out, err := io.ReadAll(io.LimitReader(reader, MaxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress file: %w", err)
+	}
+	if len(out) > MaxImportFileSize {
+		return nil, fmt.Errorf("file too large: more than %d bytes after decompression", MaxImportFileSize)
+	}
+	return out, nil
+}
+
+// malXMLStatusToLocal converts a MAL export status ("Plan to Read", "On-Hold" or numeric codes)
+func malXMLStatusToLocal(status string) string {
+	normalized := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(status))
+	switch normalized {
+	case "reading", "1":
+		return "reading"
+	case "completed", "2":
+		return "completed"
+	case "onhold", "3":
+		return "on_hold"
+	case "dropped", "4":
+		return "dropped"
+	default:
+		return "plan_to_read"
+	}
+}
+
+// localStatusToMALXML converts a local status into a MAL export status and rereading flag
+func localStatusToMALXML(status string) (string, string) {
+	switch status {
+	case "reading":
+		return "Reading", "NO"
+	case "completed":
+		return "Completed", "NO"
+	case "re_reading":
+		return "Completed", "YES"
+	case "on_hold":
+		return "On-Hold", "NO"
+	case "dropped":
+		return "Dropped", "NO"
+	default:
+		return "Plan to Read", "NO"
+	}
+}
+
+// countStatus updates the per-status totals of a MAL export header
+func (info *malXMLInfo) countStatus(status string) {
+	switch status {
+	case "Reading":
+		info.TotalReading++
+	case "Completed":
+		info.TotalCompleted++
+	case "On-Hold":
+		info.TotalOnHold++
+	case "Dropped":
+		info.TotalDropped++
+	default:
+		info.TotalPlanToRead++
+	}
+}
+
+func aniListStatusToLocal(status string) string {
+	switch status {
+	case "CURRENT":
+		return "reading"
+	case "COMPLETED":
+		return "completed"
+	case "PAUSED":
+		return "on_hold"
+	case "DROPPED":
+		return "dropped"
+	case "REPEATING":
+		return "re_reading"
+	default:
+		return "plan_to_read"
+	}
+}
+
+func localStatusToAniList(status string) string {
+	switch status {
+	case "reading":
+		return "CURRENT"
+	case "completed":
+		return "COMPLETED"
+	case "on_hold":
+		return "PAUSED"
+	case "dropped":
+		return "DROPPED"
+	case "re_reading":
+		return "REPEATING"
+	default:
+		return "PLANNING"
+	}
+}
+
+// aniListListName returns the default AniList list name for a status
+func aniListListName(status string) string {
+	switch status {
+	case "CURRENT":
+		return "Reading"
+	case "COMPLETED":
+		return "Completed"
+	case "PAUSED":
+		return "Paused"
+	case "DROPPED":
+		return "Dropped"
+	case "REPEATING":
+		return "Rereading"
+	default:
+		return "Planning"
+	}
+}
+
+func aniListTitle(media aniListMedia) string {
+	switch {
+	case media.Title.Romaji != "":
+		return media.Title.Romaji
+	case media.Title.English != "":
+		return media.Title.English
+	default:
+		return media.Title.Native
+	}
+}
+
+// aniListScoreToTenPoint normalizes a score in the user's AniList score format to 0-10
+func aniListScoreToTenPoint(score float64, format string) int {
+	if score <= 0 {
+		return 0
+	}
+	switch format {
+	case "POINT_100":
+		score /= 10
+	case "POINT_5":
+		score *= 2
+	case "POINT_3":
+		score *= 3
+	case "POINT_10", "POINT_10_DECIMAL":
+	default:
+		// Unknown format: scores above 10 can only be on the 100 point scale
+		if score > 10 {
+			score /= 10
+		}
+	}
+	return clampScore(int(math.Round(score)))
+}
+
+func clampScore(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 10 {
+		return 10
+	}
+	return score
+}
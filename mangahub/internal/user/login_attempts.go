@@ -0,0 +1,490 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"mangahub/internal/mailer"
+	"mangahub/pkg/models"
+	"time"
+)
+
+// Outcomes of login attempts in the audit trail
+const (
+	LoginSucceeded           = "success"
+	LoginChallenged          = "second_factor_required" // Password right, second factor pending
+	LoginUnknownUser         = "unknown_user"
+	LoginInvalidPassword     = "invalid_password"
+	LoginInvalidSecondFactor = "invalid_second_factor"
+	LoginThrottled           = "throttled" // Refused before checking credentials
+	LoginLocked              = "locked"
+)
+
+const (
+	// Failed logins of an account before each further attempt has to wait
+	accountDelayAfter = 3
+	// Failed logins that lock the account for accountLockout
+	accountLockAfter = 10
+	accountLockout   = 15 * time.Minute
+
+	// Failed logins from an address within ipFailureWindow before each further
+	// attempt from it has to wait
+	ipDelayAfter    = 10
+	ipFailureWindow = 15 * time.Minute
+
+	// The wait doubles with every failure, up to maxLoginDelay
+	maxLoginDelay = time.Minute
+
+	// Successful logins from an address within this window make it known, so
+	// logging in from it does not send a new sign-in email
+	knownAddressWindow = 90 * 24 * time.Hour
+	// How long login attempts are kept
+	loginAttemptRetention = 180 * 24 * time.Hour
+)
+
+// LoginThrottledError refuses a login attempt made before the account or the
+// address may try again
+type LoginThrottledError struct {
+	Locked     bool // The account is locked, not just slowed down
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	count, unit := int((e.RetryAfter+time.Second-1)/time.Second), "second"
+	if e.RetryAfter > time.Minute {
+		count, unit = int((e.RetryAfter+time.Minute-1)/time.Minute), "minute"
+	}
+	wait := fmt.Sprintf("%d %s", count, unit)
+	if count != 1 {
+		wait += "s"
+	}
+	if e.Locked {
+		return "account locked after too many failed login attempts: try again in " + wait + " or reset your password"
+	}
+	return "too many failed login attempts: try again in " + wait
+}
+
+// loginDelay is how long to wait after the nth failure past the free ones (n >= 1)
+func loginDelay(n int) time.Duration {
+	if n > 7 {
+		return maxLoginDelay
+	}
+	delay := time.Second << (n - 1)
+	if delay > maxLoginDelay {
+		delay = maxLoginDelay
+	}
+	return delay
+}
+
+// accountLockState is what Login needs to know about an account's failed logins
+type accountLockState struct {
+	failedCount  int
+	lastFailedAt sql.NullTime
+	lockedUntil  sql.NullTime
+}
+
+// throttle returns an error if the account may not try to log in yet
+func (a accountLockState) throttle(now time.Time) error {
+	if a.lockedUntil.Valid && now.Before(a.lockedUntil.Time) {
+		return &LoginThrottledError{Locked: true, RetryAfter: a.lockedUntil.Time.Sub(now)}
+	}
+	if a.failedCount >= accountDelayAfter && a.lastFailedAt.Valid {
+		next := a.lastFailedAt.Time.Add(loginDelay(a.failedCount - accountDelayAfter + 1))
+		if now.Before(next) {
+			return &LoginThrottledError{RetryAfter: next.Sub(now)}
+		}
+	}
+	return nil
+}
+
+func (s *Service) getAccountLockState(userID string) (accountLockState, error) {
+	var state accountLockState
+	err := s.db.QueryRow(`
+		SELECT COALESCE(failed_login_count, 0), last_failed_login_at, locked_until
+		FROM users WHERE id = ?`, userID).Scan(&state.failedCount, &state.lastFailedAt, &state.lockedUntil)
+	if err == sql.ErrNoRows {
+		return state, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to get user: %w", err)
+	}
+	return state, nil
+}
+
+// throttleLogin refuses an attempt, and records it, if the address or the
+// account may not try again yet; otherwise it reserves the attempt (see
+// reserveLoginAttempt). Attempts on unknown accounts (no userID) only wait
+// for the address.
+func (s *Service) throttleLogin(userID, identifier string, client models.SessionClient, now time.Time) (accountLockState, error) {
+	if err := s.throttleAddress(client.IPAddress, now); err != nil {
+		s.recordLoginAttempt(userID, identifier, client, LoginThrottled)
+		return accountLockState{}, err
+	}
+	if userID == "" {
+		return accountLockState{}, nil
+	}
+	return s.reserveLoginAttempt(userID, identifier, client, now)
+}
+
+// reserveLoginAttempt counts an attempt as failed before the credentials are
+// checked, so concurrent attempts cannot all pass the lockout on the same
+// state: the count only goes up if no other attempt changed it since it was
+// read. Attempts the account may not make yet are refused and recorded.
+// A successful login resets the count; releaseLoginAttempt takes back the
+// reservation of an attempt that passed without finishing a login. It returns
+// the state before the reservation.
+func (s *Service) reserveLoginAttempt(userID, identifier string, client models.SessionClient, now time.Time) (accountLockState, error) {
+	for {
+		lock, err := s.getAccountLockState(userID)
+		if err != nil {
+			return lock, err
+		}
+		if err := lock.throttle(now); err != nil {
+			outcome := LoginThrottled
+			if throttled := err.(*LoginThrottledError); throttled.Locked {
+				outcome = LoginLocked
+			}
+			s.recordLoginAttempt(userID, identifier, client, outcome)
+			return lock, err
+		}
+
+		result, err := s.db.Exec(`
+			UPDATE users SET failed_login_count = COALESCE(failed_login_count, 0) + 1, last_failed_login_at = ?
+			WHERE id = ? AND COALESCE(failed_login_count, 0) = ? AND (locked_until IS NULL OR locked_until <= ?)`,
+			now, userID, lock.failedCount, now)
+		if err != nil {
+			return lock, fmt.Errorf("failed to reserve login attempt: %w", err)
+		}
+		if reserved, _ := result.RowsAffected(); reserved == 1 {
+			return lock, nil
+		}
+		// Another attempt got in first; check again with its failure counted
+	}
+}
+
+// releaseLoginAttempt takes back a reservation made at reservedAt, restoring
+// the last failure time unless a later failure replaced it
+func (s *Service) releaseLoginAttempt(userID string, before accountLockState, reservedAt time.Time) {
+	if _, err := s.db.Exec(`
+		UPDATE users SET failed_login_count = MAX(COALESCE(failed_login_count, 0) - 1, 0),
+			last_failed_login_at = CASE WHEN last_failed_login_at = ? THEN ? ELSE last_failed_login_at END
+		WHERE id = ?`, reservedAt, before.lastFailedAt, userID); err != nil {
+		log.Printf("Failed to release login attempt of user %s: %v", userID, err)
+	}
+}
+
+// throttleAddress returns an error if the address failed too often recently.
+// Failures for any account count, so guessing across accounts is slowed down too.
+func (s *Service) throttleAddress(ipAddress string, now time.Time) error {
+	if ipAddress == "" {
+		return nil
+	}
+
+	var failures int
+	var lastFailedAt time.Time
+	err := s.db.QueryRow(`
+		SELECT created_at FROM login_attempts
+		WHERE ip_address = ? AND outcome IN (?, ?, ?) AND created_at > ?
+		ORDER BY created_at DESC LIMIT 1`,
+		ipAddress, LoginUnknownUser, LoginInvalidPassword, LoginInvalidSecondFactor, now.Add(-ipFailureWindow)).Scan(&lastFailedAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM login_attempts
+		WHERE ip_address = ? AND outcome IN (?, ?, ?) AND created_at > ?`,
+		ipAddress, LoginUnknownUser, LoginInvalidPassword, LoginInvalidSecondFactor, now.Add(-ipFailureWindow)).Scan(&failures)
+	if err != nil {
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+
+	if failures >= ipDelayAfter {
+		next := lastFailedAt.Add(loginDelay(failures - ipDelayAfter + 1))
+		if now.Before(next) {
+			return &LoginThrottledError{RetryAfter: next.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// recordLoginAttempt adds an attempt to the audit trail
+func (s *Service) recordLoginAttempt(userID, identifier string, client models.SessionClient, outcome string) {
+	var user interface{}
+	if userID != "" {
+		user = userID
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO login_attempts (user_id, identifier, ip_address, user_agent, success, outcome, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		user, identifier, client.IPAddress, client.UserAgent, outcome == LoginSucceeded, outcome, time.Now())
+	if err != nil {
+		log.Printf("Failed to record login attempt: %v", err)
+	}
+}
+
+// recordLoginFailure records a wrong password or second factor, already
+// counted against the account by reserveLoginAttempt, and locks the account
+// once there were too many
+func (s *Service) recordLoginFailure(userID, identifier string, client models.SessionClient, outcome string) {
+	s.recordLoginAttempt(userID, identifier, client, outcome)
+
+	var failedCount int
+	if err := s.db.QueryRow("SELECT COALESCE(failed_login_count, 0) FROM users WHERE id = ?", userID).Scan(&failedCount); err != nil {
+		log.Printf("Failed to count failed logins of user %s: %v", userID, err)
+		return
+	}
+	if failedCount < accountLockAfter {
+		return
+	}
+
+	// The count starts over, so each lockout takes another accountLockAfter failures.
+	// Of several concurrent failures only one locks the account and sends the email.
+	lockedUntil := time.Now().Add(accountLockout)
+	result, err := s.db.Exec("UPDATE users SET locked_until = ?, failed_login_count = 0 WHERE id = ? AND failed_login_count >= ?",
+		lockedUntil, userID, accountLockAfter)
+	if err != nil {
+		log.Printf("Failed to lock user %s: %v", userID, err)
+		return
+	}
+	if locked, _ := result.RowsAffected(); locked == 0 {
+		return
+	}
+	log.Printf("Locked user %s until %s after %d failed logins (last from %s)",
+		userID, lockedUntil.Format(time.RFC3339), failedCount, client.IPAddress)
+	s.sendAccountLockedEmail(userID, failedCount, client)
+}
+
+// recordLoginSuccess resets the failed logins of the account and tells the
+// user about sign-ins that look suspicious. state is the account's state
+// before the attempt was reserved.
+func (s *Service) recordLoginSuccess(userID, identifier string, client models.SessionClient, state accountLockState) {
+	var reasons []string
+	if newAddress, err := s.isNewLoginAddress(userID, client.IPAddress); err != nil {
+		log.Printf("Failed to check login address of user %s: %v", userID, err)
+	} else if newAddress {
+		reasons = append(reasons, "it came from an address not used for this account before")
+	}
+	if state.lockedUntil.Valid {
+		reasons = append(reasons, "the account was locked after failed login attempts before it")
+	} else if state.failedCount >= accountDelayAfter {
+		reasons = append(reasons, fmt.Sprintf("%d failed login attempts came before it", state.failedCount))
+	}
+
+	s.recordLoginAttempt(userID, identifier, client, LoginSucceeded)
+	if _, err := s.db.Exec(`
+		UPDATE users SET failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = ?`, userID); err != nil {
+		log.Printf("Failed to reset failed logins of user %s: %v", userID, err)
+	}
+
+	// Drop the user's attempts nobody needs anymore; PruneLoginAttempts gets the rest
+	s.db.Exec("DELETE FROM login_attempts WHERE user_id = ? AND created_at < ?", userID, time.Now().Add(-loginAttemptRetention))
+
+	if len(reasons) > 0 {
+		s.sendSuspiciousLoginEmail(userID, client, reasons)
+	}
+}
+
+// PruneLoginAttempts drops the attempts of all accounts, and of unknown ones,
+// older than the retention
+func (s *Service) PruneLoginAttempts() error {
+	result, err := s.db.Exec("DELETE FROM login_attempts WHERE created_at < ?", time.Now().Add(-loginAttemptRetention))
+	if err != nil {
+		return fmt.Errorf("failed to prune login attempts: %w", err)
+	}
+	if pruned, _ := result.RowsAffected(); pruned > 0 {
+		log.Printf("Pruned %d login attempts", pruned)
+	}
+	return nil
+}
+
+// StartLoginAttemptPrune prunes login attempts now and then at every interval
+func (s *Service) StartLoginAttemptPrune(interval time.Duration) {
+	if err := s.PruneLoginAttempts(); err != nil {
+		log.Printf("Login attempt prune failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.PruneLoginAttempts(); err != nil {
+			log.Printf("Login attempt prune failed: %v", err)
+		}
+	}
+}
+
+// isNewLoginAddress reports whether the user has logged in before, but never
+// from the address recently. The first login of an account is not suspicious.
+func (s *Service) isNewLoginAddress(userID, ipAddress string) (bool, error) {
+	if ipAddress == "" {
+		return false, nil
+	}
+
+	var logins, fromAddress int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(ip_address = ? AND created_at > ?), 0)
+		FROM login_attempts WHERE user_id = ? AND success = 1`,
+		ipAddress, time.Now().Add(-knownAddressWindow), userID).Scan(&logins, &fromAddress)
+	if err != nil {
+		return false, err
+	}
+	return logins > 0 && fromAddress == 0, nil
+}
+
+// GetLoginAttempts returns the user's recent login attempts, newest first
+func (s *Service) GetLoginAttempts(userID string, limit, offset int) ([]models.LoginAttempt, int, error) {
+	return s.ListLoginAttempts(models.LoginAttemptFilter{UserID: userID, Limit: limit, Offset: offset})
+}
+
+// ListLoginAttempts returns login attempts of any account for admins, newest first
+func (s *Service) ListLoginAttempts(filter models.LoginAttemptFilter) ([]models.LoginAttempt, int, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	condition := "1 = 1"
+	args := []interface{}{}
+	if filter.UserID != "" {
+		condition += " AND user_id = ?"
+		args = append(args, filter.UserID)
+	}
+	if filter.IPAddress != "" {
+		condition += " AND ip_address = ?"
+		args = append(args, filter.IPAddress)
+	}
+	if filter.Failed {
+		condition += " AND success = 0"
+	}
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM login_attempts WHERE "+condition, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, COALESCE(user_id, ''), identifier, ip_address, user_agent, success, outcome, created_at
+		FROM login_attempts
+		WHERE `+condition+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []models.LoginAttempt{}
+	for rows.Next() {
+		var attempt models.LoginAttempt
+		if err := rows.Scan(&attempt.ID, &attempt.UserID, &attempt.Identifier, &attempt.IPAddress,
+			&attempt.UserAgent, &attempt.Success, &attempt.Outcome, &attempt.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan login attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	return attempts, total, nil
+}
+
+// GetAccountLockStatus returns the failed logins and lockout of an account
+func (s *Service) GetAccountLockStatus(userID string) (*models.AccountLockStatus, error) {
+	state, err := s.getAccountLockState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.AccountLockStatus{UserID: userID, FailedLoginCount: state.failedCount}
+	if state.lastFailedAt.Valid {
+		status.LastFailedLoginAt = &state.lastFailedAt.Time
+	}
+	if state.lockedUntil.Valid && time.Now().Before(state.lockedUntil.Time) {
+		status.Locked = true
+		status.LockedUntil = &state.lockedUntil.Time
+	}
+	return status, nil
+}
+
+// UnlockAccount lifts the lockout of an account and forgets its failed logins
+func (s *Service) UnlockAccount(userID, adminID string) (*models.AccountLockStatus, error) {
+	result, err := s.db.Exec(`
+		UPDATE users SET failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = ?`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock account: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	log.Printf("User %s unlocked by %s", userID, adminID)
+	return s.GetAccountLockStatus(userID)
+}
+
+// sendAccountLockedEmail tells the user their account was locked
+func (s *Service) sendAccountLockedEmail(userID string, failedCount int, client models.SessionClient) {
+	var username, email string
+	if err := s.db.QueryRow("SELECT username, email FROM users WHERE id = ?", userID).Scan(&username, &email); err != nil {
+		log.Printf("Failed to get user %s for account locked email: %v", userID, err)
+		return
+	}
+
+	err := s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Your MangaHub account was locked",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"After %d failed login attempts (the last from %s), logins to your MangaHub account are blocked for %d minutes.\n\n"+
+			"If this was not you, someone may be guessing your password. Reset it at %s/forgot-password, "+
+			"which also unlocks the account.\n",
+			username, failedCount, addressOrUnknown(client.IPAddress), int(accountLockout/time.Minute), appURL()),
+	})
+	if err != nil {
+		log.Printf("Failed to send account locked email to user %s: %v", userID, err)
+	}
+}
+
+// sendSuspiciousLoginEmail tells the user about a sign-in that may not be theirs
+func (s *Service) sendSuspiciousLoginEmail(userID string, client models.SessionClient, reasons []string) {
+	var username, email string
+	if err := s.db.QueryRow("SELECT username, email FROM users WHERE id = ?", userID).Scan(&username, &email); err != nil {
+		log.Printf("Failed to get user %s for sign-in email: %v", userID, err)
+		return
+	}
+
+	because := ""
+	for _, reason := range reasons {
+		because += "- " + reason + "\n"
+	}
+	device := client.DeviceName
+	if device == "" {
+		device = client.UserAgent
+	}
+
+	err := s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "New sign-in to your MangaHub account",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Your MangaHub account was just signed in to from %s (%s). We are letting you know because:\n%s\n"+
+			"If this was you, there is nothing to do. Otherwise reset your password at %s/forgot-password "+
+			"and sign out the sessions you do not recognize.\n",
+			username, addressOrUnknown(client.IPAddress), device, because, appURL()),
+	})
+	if err != nil {
+		log.Printf("Failed to send sign-in email to user %s: %v", userID, err)
+	}
+}
+
+func addressOrUnknown(ipAddress string) string {
+	if ipAddress == "" {
+		return "an unknown address"
+	}
+	return ipAddress
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveLoginAttemptLetsOneOfConcurrentAttemptsThrough(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+
+	// One failure short of the delay: only the first attempt may be made now
+	_, err := s.db.Exec("UPDATE users SET failed_login_count = ? WHERE id = ?", accountDelayAfter-1, userID)
+	require.NoError(t, err)
+
+	const attempts = 20
+	now := time.Now()
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = s.reserveLoginAttempt(userID, "reader", models.SessionClient{}, now)
+		}(i)
+	}
+	wg.Wait()
+
+	reserved := 0
+	for _, err := range errs {
+		if err == nil {
+			reserved++
+		} else {
+			assert.IsType(t, &LoginThrottledError{}, err)
+		}
+	}
+	assert.Equal(t, 1, reserved)
+	assert.Equal(t, accountDelayAfter, failedLogins(t, s, userID))
+}
+
+func TestLoginCountsAndReleasesAttempts(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	_, err = s.db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", hash, userID)
+	require.NoError(t, err)
+	enableTestTwoFactor(t, s, userID, "abcde-fghjk")
+	client := models.SessionClient{IPAddress: "203.0.113.7"}
+
+	_, _, err = s.Login(models.UserLogin{Email: "nobody", Password: "x"}, client)
+	assert.EqualError(t, err, "invalid email/username or password")
+	_, _, err = s.Login(models.UserLogin{Email: "reader", Password: "wrong"}, client)
+	assert.EqualError(t, err, "invalid email/username or password")
+	assert.Equal(t, 1, failedLogins(t, s, userID))
+
+	// The right password releases its reservation while the second factor is pending
+	_, challenge, err := s.Login(models.UserLogin{Email: "reader@example.com", Password: "correct horse"}, client)
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.Equal(t, 1, failedLogins(t, s, userID))
+
+	attempts, _, err := s.ListLoginAttempts(models.LoginAttemptFilter{IPAddress: client.IPAddress})
+	require.NoError(t, err)
+	var outcomes []string
+	for _, attempt := range attempts {
+		outcomes = append(outcomes, attempt.Outcome)
+	}
+	assert.Equal(t, []string{LoginChallenged, LoginInvalidPassword, LoginUnknownUser}, outcomes)
+}
+
+func TestRecordLoginSuccessPrunesOnlyTheUsersAttempts(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	otherID := createTestUser(t, s.db, "other")
+	old := time.Now().Add(-loginAttemptRetention - time.Hour)
+	for _, user := range []interface{}{userID, otherID, nil} {
+		_, err := s.db.Exec(`
+			INSERT INTO login_attempts (user_id, identifier, ip_address, user_agent, success, outcome, created_at)
+			VALUES (?, '', '', '', 0, ?, ?)`, user, LoginInvalidPassword, old)
+		require.NoError(t, err)
+	}
+	countOld := func() int {
+		var count int
+		require.NoError(t, s.db.QueryRow("SELECT COUNT(*) FROM login_attempts WHERE created_at < ?", time.Now().Add(-loginAttemptRetention)).Scan(&count))
+		return count
+	}
+
+	s.recordLoginSuccess(userID, "reader", models.SessionClient{}, accountLockState{})
+	assert.Equal(t, 2, countOld())
+
+	require.NoError(t, s.PruneLoginAttempts())
+	assert.Equal(t, 0, countOld())
+}
@@ -0,0 +1,496 @@
+package user
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mangahub/internal/activity"
+	"mangahub/internal/external"
+	"mangahub/pkg/models"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// malStateTTL is how long a pending MAL authorization request stays valid
+const malStateTTL = 10 * time.Minute
+
+// getMALRedirectURI returns the OAuth2 redirect URI registered for the MAL app
+func getMALRedirectURI() string {
+	redirectURI := os.Getenv("MAL_REDIRECT_URI")
+	if redirectURI == "" {
+		return "http://localhost:8080/api/v1/auth/mal/callback" // Default
+	}
+	return redirectURI
+}
+
+// StartMALLink begins OAuth2 PKCE account linking and returns the MAL
+// authorization URL. The state in it and the PKCE verifier are stored for the
+// user only; a new authorization replaces one still pending.
+func (s *Service) StartMALLink(userID string) (string, error) {
+	if !s.malClient.IsConfigured() {
+		return "", fmt.Errorf("MAL API not configured: please set MAL_CLIENT_ID in .env")
+	}
+
+	pkce, err := external.NewMALPKCE()
+	if err != nil {
+		return "", err
+	}
+
+	stateBytes := make([]byte, 24)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	state := hex.EncodeToString(stateBytes)
+	redirectURI := getMALRedirectURI()
+
+	// Drop expired requests while we are here
+	s.db.Exec("DELETE FROM mal_oauth_states WHERE created_at < ?", time.Now().Add(-malStateTTL))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM mal_oauth_states WHERE user_id = ?", userID); err != nil {
+		return "", fmt.Errorf("failed to replace authorization state: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO mal_oauth_states (state, user_id, code_verifier, redirect_uri, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		state, userID, pkce.Verifier, redirectURI, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to store authorization state: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.malClient.AuthorizationURL(state, pkce, redirectURI), nil
+}
+
+// CompleteMALLink finishes account linking with the code returned by MAL
+func (s *Service) CompleteMALLink(state, code string) (*models.MALLinkStatus, error) {
+	var userID, verifier, redirectURI string
+	var createdAt time.Time
+	err := s.db.QueryRow(`
+		SELECT user_id, code_verifier, COALESCE(redirect_uri, ''), created_at
+		FROM mal_oauth_states WHERE state = ?`, state).
+		Scan(&userID, &verifier, &redirectURI, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invalid or expired authorization state")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load authorization state: %w", err)
+	}
+
+	// States are single use
+	if _, err := s.db.Exec("DELETE FROM mal_oauth_states WHERE state = ?", state); err != nil {
+		return nil, fmt.Errorf("failed to consume authorization state: %w", err)
+	}
+	if time.Since(createdAt) > malStateTTL {
+		return nil, fmt.Errorf("invalid or expired authorization state")
+	}
+
+	token, err := s.malClient.ExchangeCode(code, verifier, redirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	malUser, err := s.malClient.GetMyUserInfo(token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch MAL user: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO mal_accounts (user_id, mal_user_id, mal_username, access_token, refresh_token, token_expires_at, linked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			mal_user_id = excluded.mal_user_id,
+			mal_username = excluded.mal_username,
+			access_token = excluded.access_token,
+			refresh_token = excluded.refresh_token,
+			token_expires_at = excluded.token_expires_at,
+			linked_at = excluded.linked_at`,
+		userID, malUser.ID, malUser.Name, token.AccessToken, token.RefreshToken, token.ExpiresAt, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store MAL account: %w", err)
+	}
+
+	log.Printf("User %s linked MAL account %s", userID, malUser.Name)
+	return s.GetMALLinkStatus(userID)
+}
+
+// GetMALLinkStatus returns the linked MAL account of a user
+func (s *Service) GetMALLinkStatus(userID string) (*models.MALLinkStatus, error) {
+	status := &models.MALLinkStatus{}
+	var linkedAt, expiresAt time.Time
+	var lastImport sql.NullTime
+
+	err := s.db.QueryRow(`
+		SELECT mal_user_id, mal_username, linked_at, token_expires_at, last_import_at
+		FROM mal_accounts WHERE user_id = ?`, userID).
+		Scan(&status.MALUserID, &status.MALUsername, &linkedAt, &expiresAt, &lastImport)
+	if err == sql.ErrNoRows {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MAL account: %w", err)
+	}
+
+	status.Linked = true
+	status.LinkedAt = &linkedAt
+	status.TokenExpiresAt = &expiresAt
+	if lastImport.Valid {
+		status.LastImportAt = &lastImport.Time
+	}
+	return status, nil
+}
+
+// UnlinkMAL removes the linked MAL account and its sync state
+func (s *Service) UnlinkMAL(userID string) error {
+	result, err := s.db.Exec("DELETE FROM mal_accounts WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink MAL account: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("MAL account not linked")
+	}
+
+	if _, err := s.db.Exec("DELETE FROM mal_list_sync WHERE user_id = ?", userID); err != nil {
+		log.Printf("Failed to clear MAL sync state for user %s: %v", userID, err)
+	}
+	return nil
+}
+
+// getMALAccessToken returns a valid access token, refreshing it when it is about to expire
+func (s *Service) getMALAccessToken(userID string) (string, error) {
+	var accessToken, refreshToken string
+	var expiresAt time.Time
+	err := s.db.QueryRow(`
+		SELECT access_token, refresh_token, token_expires_at
+		FROM mal_accounts WHERE user_id = ?`, userID).
+		Scan(&accessToken, &refreshToken, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("MAL account not linked")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get MAL account: %w", err)
+	}
+
+	if time.Until(expiresAt) > time.Minute {
+		return accessToken, nil
+	}
+
+	token, err := s.malClient.RefreshToken(refreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh MAL token, please relink your account: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE mal_accounts SET access_token = ?, refresh_token = ?, token_expires_at = ?
+		WHERE user_id = ?`,
+		token.AccessToken, token.RefreshToken, token.ExpiresAt, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to store refreshed MAL token: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// isMALLinked reports whether the user has a linked MAL account
+func (s *Service) isMALLinked(userID string) bool {
+	var exists bool
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM mal_accounts WHERE user_id = ?)", userID).Scan(&exists)
+	return err == nil && exists
+}
+
+// ImportMALList pulls the user's MAL manga list into user_progress.
+// Entries newer on MAL overwrite local progress; entries newer locally are
+// pushed back to MAL. Local entries that are missing on MAL are pushed too.
+func (s *Service) ImportMALList(userID string) (*models.MALImportResult, error) {
+	accessToken, err := s.getMALAccessToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.MALImportResult{
+		Conflicts: []models.MALSyncConflict{},
+	}
+	seen := make(map[string]bool)
+
+	offset := 0
+	for {
+		page, err := s.malClient.GetMyMangaList(accessToken, 1000, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch MAL list: %w", err)
+		}
+
+		for _, entry := range page.Data {
+			result.TotalRemote++
+			mangaID := s.localMangaIDForMAL(entry.Node.ID)
+			seen[mangaID] = true
+
+			if err := s.mergeMALEntry(userID, accessToken, mangaID, entry.Node, entry.ListStatus, result); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.Node.Title, err))
+			}
+		}
+
+		if page.Paging.Next == "" || len(page.Data) == 0 {
+			break
+		}
+		offset += len(page.Data)
+	}
+
+	// Push local entries that MAL does not know about yet
+	rows, err := s.db.Query("SELECT manga_id FROM user_progress WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library: %w", err)
+	}
+	var localOnly []string
+	for rows.Next() {
+		var mangaID string
+		if err := rows.Scan(&mangaID); err == nil && !seen[mangaID] {
+			localOnly = append(localOnly, mangaID)
+		}
+	}
+	rows.Close()
+
+	for _, mangaID := range localOnly {
+		malID, ok := s.malIDForManga(mangaID)
+		if !ok {
+			continue
+		}
+		if err := s.pushEntryToMAL(userID, accessToken, mangaID, malID); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", mangaID, err))
+			continue
+		}
+		result.Pushed++
+	}
+
+	if _, err := s.db.Exec("UPDATE mal_accounts SET last_import_at = ? WHERE user_id = ?", time.Now(), userID); err != nil {
+		log.Printf("Failed to update MAL import time for user %s: %v", userID, err)
+	}
+
+	return result, nil
+}
+
+// mergeMALEntry reconciles a single MAL list entry with local progress using last_updated
+func (s *Service) mergeMALEntry(userID, accessToken, mangaID string, node external.MALMangaNode, remote external.MALListStatus, result *models.MALImportResult) error {
+	local, err := s.GetUserProgress(userID, mangaID)
+	if err != nil {
+		return err
+	}
+
+	remoteUpdated := remote.UpdatedTime().Truncate(time.Second)
+	if local == nil {
+		if err := s.applyMALStatus(userID, mangaID, node.ID, remote); err != nil {
+			return err
+		}
+		result.Imported++
+		return nil
+	}
+
+	localUpdated := local.LastUpdated.Truncate(time.Second)
+	status, chapter := malStatusToLocal(remote)
+	if status == local.Status && chapter == local.CurrentChapter {
+		s.recordMALSync(userID, mangaID, node.ID, remoteUpdated)
+		result.Unchanged++
+		return nil
+	}
+
+	conflict := models.MALSyncConflict{
+		MangaID:       mangaID,
+		MALID:         node.ID,
+		Title:         node.Title,
+		LocalUpdated:  localUpdated,
+		RemoteUpdated: remoteUpdated,
+	}
+
+	if remoteUpdated.After(localUpdated) {
+		if err := s.applyMALStatus(userID, mangaID, node.ID, remote); err != nil {
+			return err
+		}
+		conflict.Resolution = "remote_newer"
+		result.Updated++
+	} else {
+		if err := s.pushEntryToMAL(userID, accessToken, mangaID, node.ID); err != nil {
+			return err
+		}
+		conflict.Resolution = "local_newer"
+		result.Pushed++
+	}
+
+	result.Conflicts = append(result.Conflicts, conflict)
+	return nil
+}
+
+// applyMALStatus writes a MAL list entry into user_progress
+func (s *Service) applyMALStatus(userID, mangaID string, malID int, remote external.MALListStatus) error {
+	status, chapter := malStatusToLocal(remote)
+	updated := remote.UpdatedTime()
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	existing, err := s.GetUserProgress(userID, mangaID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO user_progress (user_id, manga_id, current_chapter, status, last_updated)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, manga_id) DO UPDATE SET
+			current_chapter = excluded.current_chapter,
+			status = excluded.status,
+			last_updated = excluded.last_updated`,
+		userID, mangaID, chapter, status, updated)
+	if err != nil {
+		return fmt.Errorf("failed to import progress: %w", err)
+	}
+
+	var fromStatus string
+	var fromChapter int
+	if existing != nil {
+		fromStatus, fromChapter = existing.Status, existing.CurrentChapter
+	}
+	if err := activity.RecordProgress(s.db, activity.SourceMALSync, userID, mangaID,
+		fromStatus, fromChapter, status, chapter); err != nil {
+		return err
+	}
+
+	s.recordMALSync(userID, mangaID, malID, updated)
+	return nil
+}
+
+// PushProgressToMAL sends local progress for the given manga to the user's MAL list.
+// It is a no-op when the user has no linked account.
+func (s *Service) PushProgressToMAL(userID string, mangaIDs ...string) {
+	if !s.isMALLinked(userID) {
+		return
+	}
+
+	accessToken, err := s.getMALAccessToken(userID)
+	if err != nil {
+		log.Printf("MAL push for user %s skipped: %v", userID, err)
+		return
+	}
+
+	for _, mangaID := range mangaIDs {
+		malID, ok := s.malIDForManga(mangaID)
+		if !ok {
+			continue
+		}
+		if err := s.pushEntryToMAL(userID, accessToken, mangaID, malID); err != nil {
+			log.Printf("MAL push for user %s manga %s failed: %v", userID, mangaID, err)
+		}
+	}
+}
+
+// pushEntryToMAL updates MAL with local progress unless MAL holds a newer change,
+// in which case the MAL state is applied locally instead
+func (s *Service) pushEntryToMAL(userID, accessToken, mangaID string, malID int) error {
+	local, err := s.GetUserProgress(userID, mangaID)
+	if err != nil {
+		return err
+	}
+	if local == nil {
+		return nil
+	}
+
+	remote, err := s.malClient.GetMyListStatus(accessToken, malID)
+	if err != nil {
+		return err
+	}
+	if remote != nil && remote.UpdatedTime().Truncate(time.Second).After(local.LastUpdated.Truncate(time.Second)) {
+		log.Printf("MAL entry %d for user %s changed after local update, keeping MAL version", malID, userID)
+		return s.applyMALStatus(userID, mangaID, malID, *remote)
+	}
+
+	updated, err := s.malClient.UpdateMyListStatus(accessToken, malID, localStatusToMAL(local))
+	if err != nil {
+		return err
+	}
+
+	s.recordMALSync(userID, mangaID, malID, updated.UpdatedTime())
+	return nil
+}
+
+// recordMALSync remembers the MAL state an entry was last synced with
+func (s *Service) recordMALSync(userID, mangaID string, malID int, malUpdatedAt time.Time) {
+	_, err := s.db.Exec(`
+		INSERT INTO mal_list_sync (user_id, manga_id, mal_id, mal_updated_at, last_synced_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, manga_id) DO UPDATE SET
+			mal_id = excluded.mal_id,
+			mal_updated_at = excluded.mal_updated_at,
+			last_synced_at = excluded.last_synced_at`,
+		userID, mangaID, malID, malUpdatedAt, time.Now())
+	if err != nil {
+		log.Printf("Failed to record MAL sync state for %s/%s: %v", userID, mangaID, err)
+	}
+}
+
+// localMangaIDForMAL maps a MAL ID to a local manga ID through manga_sources.
+// Unknown manga use the "mal-<id>" form that GetLibrary resolves via the MAL API.
+func (s *Service) localMangaIDForMAL(malID int) string {
+	var mangaID string
+	err := s.db.QueryRow(
+		"SELECT manga_id FROM manga_sources WHERE source = 'mal' AND source_id = ? LIMIT 1",
+		strconv.Itoa(malID)).Scan(&mangaID)
+	if err == nil && mangaID != "" {
+		return mangaID
+	}
+	return fmt.Sprintf("mal-%d", malID)
+}
+
+// malIDForManga maps a local manga ID to its MAL ID
+func (s *Service) malIDForManga(mangaID string) (int, bool) {
+	if strings.HasPrefix(mangaID, "mal-") {
+		if id, err := strconv.Atoi(strings.TrimPrefix(mangaID, "mal-")); err == nil {
+			return id, true
+		}
+	}
+
+	var sourceID string
+	err := s.db.QueryRow(
+		"SELECT source_id FROM manga_sources WHERE manga_id = ? AND source = 'mal'", mangaID).Scan(&sourceID)
+	if err != nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(sourceID)
+	return id, err == nil
+}
+
+// malStatusToLocal converts a MAL list status into a local status and chapter
+func malStatusToLocal(remote external.MALListStatus) (string, int) {
+	status := remote.Status
+	switch {
+	case remote.IsRereading:
+		status = "re_reading"
+	case status == "":
+		status = "plan_to_read"
+	}
+	return status, remote.NumChaptersRead
+}
+
+// localStatusToMAL converts local progress into a MAL list status
+func localStatusToMAL(progress *models.UserProgress) external.MALListStatus {
+	status := external.MALListStatus{
+		Status:          progress.Status,
+		NumChaptersRead: progress.CurrentChapter,
+	}
+	if progress.Status == "re_reading" {
+		// MAL models re-reading as a completed entry with the rereading flag
+		status.Status = "completed"
+		status.IsRereading = true
+	}
+	return status
+}
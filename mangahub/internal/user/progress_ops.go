@@ -0,0 +1,353 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"mangahub/pkg/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Outcomes of a progress op
+const (
+	ProgressOpApplied   = "applied"
+	ProgressOpMerged    = "merged"
+	ProgressOpDuplicate = "duplicate"
+	ProgressOpStale     = "stale"
+	ProgressOpRejected  = "rejected"
+)
+
+// progressOpRetention is how long applied ops are remembered to ignore replays.
+// Older ops are forgotten, but the highest clock of each device's forgotten ops
+// is kept, so their replays are still ignored.
+const progressOpRetention = 30 * 24 * time.Hour
+
+// statusPrecedence settles concurrent status changes: the status further along
+// wins, so reading beats pausing or dropping and completing beats everything
+var statusPrecedence = map[string]int{
+	"plan_to_read": 0,
+	"dropped":      1,
+	"on_hold":      2,
+	"reading":      3,
+	"re_reading":   4,
+	"completed":    5,
+}
+
+// ApplyProgressOps merges client-stamped progress ops into the library. An op
+// stamped past the entry's clock has seen the current state and sets the status;
+// an op at or below it was made concurrently, and its status only wins by
+// precedence. Either way the highest chapter wins, except that starting a re-read
+// may go back. Overruled values are recorded in the conflict log. Ops are merged in
+// clock order, so a batch gives the same result however it is ordered.
+func (s *Service) ApplyProgressOps(userID string, ops []models.ProgressOp) (*models.ProgressOpsResponse, error) {
+	order := make([]int, len(ops))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := ops[order[a]], ops[order[b]]
+		if x.Clock != y.Clock {
+			return x.Clock < y.Clock
+		}
+		if !x.ClientTime.Equal(y.ClientTime) {
+			return x.ClientTime.Before(y.ClientTime)
+		}
+		return x.DeviceID < y.DeviceID
+	})
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	response := &models.ProgressOpsResponse{Results: make([]models.ProgressOpResult, len(ops))}
+	for _, i := range order {
+		op := ops[i]
+		outcome, reason, err := applyProgressOp(tx, userID, op)
+		if err != nil {
+			return nil, err
+		}
+		response.Results[i] = models.ProgressOpResult{
+			DeviceID: op.DeviceID,
+			Clock:    op.Clock,
+			MangaID:  op.MangaID,
+			Outcome:  outcome,
+		}
+		switch outcome {
+		case ProgressOpRejected:
+			response.Results[i].Error = reason
+		case ProgressOpMerged:
+			response.Conflicts++
+		}
+	}
+
+	if err := pruneProgressOps(tx, time.Now().UTC().Add(-progressOpRetention)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Send back the merged entries so the client can replace its local copy
+	entries := map[string]*models.UserProgress{}
+	var changed []string
+	for i := range response.Results {
+		result := &response.Results[i]
+		if result.Outcome == ProgressOpRejected {
+			continue
+		}
+		entry, loaded := entries[result.MangaID]
+		if !loaded {
+			entry, err = s.GetUserProgress(userID, result.MangaID)
+			if err != nil {
+				return nil, err
+			}
+			entries[result.MangaID] = entry
+			changed = append(changed, result.MangaID)
+		}
+		result.Entry = entry
+		if entry != nil && entry.Clock > response.Clock {
+			response.Clock = entry.Clock
+		}
+	}
+
+	// Push the changes to the linked MAL account, if any
+	if len(changed) > 0 {
+		go s.PushProgressToMAL(userID, changed...)
+	}
+
+	return response, nil
+}
+
+// applyProgressOp merges one op and returns its outcome, with the reason when it was rejected
+func applyProgressOp(tx *sql.Tx, userID string, op models.ProgressOp) (string, string, error) {
+	switch {
+	case op.DeviceID == "" || op.Clock < 1:
+		return ProgressOpRejected, "invalid op: device_id and a positive clock are required", nil
+	case op.CurrentChapter == nil && op.Status == "":
+		return ProgressOpRejected, "invalid op: current_chapter or status is required", nil
+	case op.CurrentChapter != nil && *op.CurrentChapter < 0:
+		return ProgressOpRejected, "invalid op: current_chapter must not be negative", nil
+	}
+	if _, ok := statusPrecedence[op.Status]; op.Status != "" && !ok {
+		return ProgressOpRejected, "invalid status", nil
+	}
+	if err := checkLibraryManga(tx, op.MangaID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return ProgressOpRejected, err.Error(), nil
+		}
+		return "", "", err
+	}
+
+	var horizon int64
+	err := tx.QueryRow("SELECT clock FROM progress_op_horizons WHERE user_id = ? AND device_id = ?",
+		userID, op.DeviceID).Scan(&horizon)
+	if err != nil && err != sql.ErrNoRows {
+		return "", "", fmt.Errorf("failed to get progress op horizon: %w", err)
+	}
+	if op.Clock <= horizon {
+		// A replay of an op that is no longer remembered
+		return ProgressOpDuplicate, "", nil
+	}
+
+	result, err := tx.Exec(`INSERT OR IGNORE INTO progress_ops (user_id, device_id, clock, manga_id) VALUES (?, ?, ?, ?)`,
+		userID, op.DeviceID, op.Clock, op.MangaID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to record progress op: %w", err)
+	}
+	if recorded, err := result.RowsAffected(); err != nil {
+		return "", "", fmt.Errorf("failed to get rows affected: %w", err)
+	} else if recorded == 0 {
+		return ProgressOpDuplicate, "", nil
+	}
+
+	var oldChapter int
+	var oldStatus, oldDevice string
+	var oldClock int64
+	err = tx.QueryRow(`
+		SELECT current_chapter, status, COALESCE(clock, 0), COALESCE(clock_device, '')
+		FROM user_progress WHERE user_id = ? AND manga_id = ?`, userID, op.MangaID).
+		Scan(&oldChapter, &oldStatus, &oldClock, &oldDevice)
+	if err == sql.ErrNoRows {
+		status, chapter := op.Status, -1
+		if status == "" {
+			status = "reading"
+		}
+		if op.CurrentChapter != nil {
+			chapter = *op.CurrentChapter
+		}
+		if _, err := saveProgress(tx, userID, op.MangaID, chapter, status); err != nil {
+			return "", "", err
+		}
+		return ProgressOpApplied, "", setProgressClock(tx, userID, op.MangaID, op.Clock, op.DeviceID)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	newer := op.Clock > oldClock
+	if !newer && op.DeviceID == oldDevice {
+		// The device already sent a later op for this entry
+		return ProgressOpStale, "", nil
+	}
+
+	status, chapter := oldStatus, oldChapter
+	var overruled []string
+	if op.Status != "" && op.Status != oldStatus {
+		if newer || statusPrecedence[op.Status] > statusPrecedence[oldStatus] {
+			status = op.Status
+		} else {
+			overruled = append(overruled, fmt.Sprintf("kept status %s over %s: concurrent changes keep the status further along", oldStatus, op.Status))
+		}
+	}
+	if op.CurrentChapter != nil && *op.CurrentChapter != oldChapter {
+		restart := newer && status == "re_reading" && oldStatus != "re_reading"
+		if *op.CurrentChapter > oldChapter || restart {
+			chapter = *op.CurrentChapter
+		} else {
+			overruled = append(overruled, fmt.Sprintf("kept chapter %d over %d: the highest chapter wins", oldChapter, *op.CurrentChapter))
+		}
+	}
+
+	if status != oldStatus || chapter != oldChapter {
+		if _, err := saveProgress(tx, userID, op.MangaID, chapter, status); err != nil {
+			return "", "", err
+		}
+	}
+	// A concurrent op leaves the clock alone, so ops that saw the entry still count as newer
+	clock, device := oldClock, oldDevice
+	if newer {
+		clock, device = op.Clock, op.DeviceID
+	}
+	if err := setProgressClock(tx, userID, op.MangaID, clock, device); err != nil {
+		return "", "", err
+	}
+
+	if len(overruled) == 0 {
+		return ProgressOpApplied, "", nil
+	}
+	_, err = tx.Exec(`
+		INSERT INTO progress_conflicts (user_id, manga_id, device_id, clock, client_time, requested_chapter,
+			requested_status, resolved_chapter, resolved_status, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		userID, op.MangaID, op.DeviceID, op.Clock, op.ClientTime.UTC(), op.CurrentChapter,
+		op.Status, chapter, status, strings.Join(overruled, "; "))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to record progress conflict: %w", err)
+	}
+	return ProgressOpMerged, "", nil
+}
+
+// pruneProgressOps forgets ops applied before cutoff, raising the horizon of
+// their devices to the highest clock forgotten
+func pruneProgressOps(tx *sql.Tx, cutoff time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO progress_op_horizons (user_id, device_id, clock)
+		SELECT user_id, device_id, MAX(clock) FROM progress_ops WHERE applied_at < ? GROUP BY user_id, device_id
+		ON CONFLICT (user_id, device_id) DO UPDATE SET clock = MAX(clock, excluded.clock)`, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to update progress op horizons: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM progress_ops WHERE applied_at < ?", cutoff); err != nil {
+		return fmt.Errorf("failed to prune progress ops: %w", err)
+	}
+	return nil
+}
+
+// setProgressClock stamps a library entry with the clock and device of the op
+// that last changed it, replacing the bump made by the clock trigger. An entry
+// already stamped so is left alone.
+func setProgressClock(tx *sql.Tx, userID, mangaID string, clock int64, device string) error {
+	_, err := tx.Exec(`
+		UPDATE user_progress SET clock = ?, clock_device = ?
+		WHERE user_id = ? AND manga_id = ? AND (clock IS NOT ? OR clock_device IS NOT ?)`,
+		clock, device, userID, mangaID, clock, device)
+	if err != nil {
+		return fmt.Errorf("failed to update progress clock: %w", err)
+	}
+	return nil
+}
+
+// GetProgressConflicts lists the user's progress conflicts, newest first
+func (s *Service) GetProgressConflicts(userID, mangaID string, limit, offset int) ([]models.ProgressConflict, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	condition := "pc.user_id = ?"
+	args := []interface{}{userID}
+	if mangaID != "" {
+		condition += " AND pc.manga_id = ?"
+		args = append(args, mangaID)
+	}
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM progress_conflicts pc WHERE "+condition, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count progress conflicts: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT pc.id, pc.manga_id, COALESCE(m.title, ''), pc.device_id, pc.clock, pc.client_time,
+			pc.requested_chapter, pc.requested_status, pc.resolved_chapter, pc.resolved_status, pc.reason, pc.created_at
+		FROM progress_conflicts pc
+		LEFT JOIN manga m ON m.id = pc.manga_id
+		WHERE `+condition+`
+		ORDER BY pc.created_at DESC, pc.id DESC
+		LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get progress conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := []models.ProgressConflict{}
+	for rows.Next() {
+		var conflict models.ProgressConflict
+		var requested sql.NullInt64
+		var clientTime sql.NullTime
+		if err := rows.Scan(&conflict.ID, &conflict.MangaID, &conflict.Title, &conflict.DeviceID, &conflict.Clock,
+			&clientTime, &requested, &conflict.RequestedStatus, &conflict.ResolvedChapter, &conflict.ResolvedStatus,
+			&conflict.Reason, &conflict.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan progress conflict: %w", err)
+		}
+		if requested.Valid {
+			chapter := int(requested.Int64)
+			conflict.RequestedChapter = &chapter
+		}
+		conflict.ClientTime = clientTime.Time
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, total, nil
+}
+
+// ClearProgressConflicts empties the user's conflict log
+func (s *Service) ClearProgressConflicts(userID string) error {
+	if _, err := s.db.Exec("DELETE FROM progress_conflicts WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to clear progress conflicts: %w", err)
+	}
+	return nil
+}
+
+// checkLibraryManga checks that a manga can be added to a library. Only local
+// manga are checked, not external ones like MAL or MangaDex.
+func checkLibraryManga(store progressStore, mangaID string) error {
+	if strings.HasPrefix(mangaID, "mal-") || strings.HasPrefix(mangaID, "mangadex-") {
+		return nil
+	}
+
+	var exists bool
+	err := store.QueryRow("SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", mangaID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check manga existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("manga not found")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"mangahub/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chapter(n int) *int {
+	return &n
+}
+
+func progressOp(device string, clock int64, mangaID string, currentChapter *int, status string) models.ProgressOp {
+	return models.ProgressOp{
+		DeviceID:       device,
+		Clock:          clock,
+		ClientTime:     time.Unix(1_700_000_000+clock, 0),
+		MangaID:        mangaID,
+		CurrentChapter: currentChapter,
+		Status:         status,
+	}
+}
+
+// applyOne applies a single op and returns its result
+func applyOne(t *testing.T, s *Service, userID string, op models.ProgressOp) models.ProgressOpResult {
+	t.Helper()
+	response, err := s.ApplyProgressOps(userID, []models.ProgressOp{op})
+	require.NoError(t, err)
+	require.Len(t, response.Results, 1)
+	return response.Results[0]
+}
+
+func TestApplyProgressOpsMergeRules(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	createTestManga(t, s.db, "m1")
+
+	result := applyOne(t, s, userID, progressOp("phone", 5, "m1", chapter(10), "reading"))
+	assert.Equal(t, ProgressOpApplied, result.Outcome)
+	assert.Equal(t, int64(5), result.Entry.Clock)
+
+	// Concurrent (at or below the entry's clock): the status further along and
+	// the highest chapter win, and what was overruled is logged
+	result = applyOne(t, s, userID, progressOp("tablet", 3, "m1", chapter(8), "dropped"))
+	assert.Equal(t, ProgressOpMerged, result.Outcome)
+	assert.Equal(t, "reading", result.Entry.Status)
+	assert.Equal(t, 10, result.Entry.CurrentChapter)
+	assert.Equal(t, int64(5), result.Entry.Clock, "a concurrent op leaves the clock alone")
+
+	conflicts, total, err := s.GetProgressConflicts(userID, "m1", 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "tablet", conflicts[0].DeviceID)
+	assert.Equal(t, "dropped", conflicts[0].RequestedStatus)
+	assert.Equal(t, "reading", conflicts[0].ResolvedStatus)
+	assert.Equal(t, 10, conflicts[0].ResolvedChapter)
+
+	// A concurrent status further along wins
+	result = applyOne(t, s, userID, progressOp("tablet", 4, "m1", nil, "completed"))
+	assert.Equal(t, ProgressOpApplied, result.Outcome)
+	assert.Equal(t, "completed", result.Entry.Status)
+
+	// An op that saw the entry sets the status, even one further back
+	result = applyOne(t, s, userID, progressOp("tablet", 6, "m1", nil, "on_hold"))
+	assert.Equal(t, ProgressOpApplied, result.Outcome)
+	assert.Equal(t, "on_hold", result.Entry.Status)
+
+	// Starting a re-read may go back in chapters; a lower chapter otherwise loses
+	result = applyOne(t, s, userID, progressOp("phone", 7, "m1", chapter(1), "re_reading"))
+	assert.Equal(t, ProgressOpApplied, result.Outcome)
+	assert.Equal(t, 1, result.Entry.CurrentChapter)
+	result = applyOne(t, s, userID, progressOp("tablet", 7, "m1", chapter(0), ""))
+	assert.Equal(t, ProgressOpMerged, result.Outcome)
+	assert.Equal(t, 1, result.Entry.CurrentChapter)
+
+	// Replays are duplicates; older ops of the device that stamped the entry are stale
+	assert.Equal(t, ProgressOpDuplicate, applyOne(t, s, userID, progressOp("phone", 7, "m1", chapter(1), "re_reading")).Outcome)
+	assert.Equal(t, ProgressOpStale, applyOne(t, s, userID, progressOp("phone", 6, "m1", chapter(50), "")).Outcome)
+
+	// Invalid ops are rejected
+	assert.Equal(t, ProgressOpRejected, applyOne(t, s, userID, progressOp("", 9, "m1", chapter(2), "")).Outcome)
+	assert.Equal(t, ProgressOpRejected, applyOne(t, s, userID, progressOp("phone", 9, "missing", chapter(2), "")).Outcome)
+}
+
+func TestApplyProgressOpsIsIndependentOfBatchOrder(t *testing.T) {
+	s := newTestService(t)
+	createTestManga(t, s.db, "m1")
+	ops := []models.ProgressOp{
+		progressOp("phone", 1, "m1", chapter(3), "reading"),
+		progressOp("tablet", 2, "m1", chapter(2), "on_hold"),
+		progressOp("laptop", 2, "m1", chapter(9), "dropped"),
+		progressOp("phone", 4, "m1", chapter(5), ""),
+	}
+	reversed := make([]models.ProgressOp, len(ops))
+	for i, op := range ops {
+		reversed[len(ops)-1-i] = op
+	}
+
+	var entries []*models.UserProgress
+	for i, batch := range [][]models.ProgressOp{ops, reversed} {
+		userID := createTestUser(t, s.db, []string{"first", "second"}[i])
+		response, err := s.ApplyProgressOps(userID, batch)
+		require.NoError(t, err)
+		entry, err := s.GetUserProgress(userID, "m1")
+		require.NoError(t, err)
+		entries = append(entries, entry)
+		assert.Equal(t, int64(4), response.Clock)
+	}
+	assert.Equal(t, entries[0].Status, entries[1].Status)
+	assert.Equal(t, entries[0].CurrentChapter, entries[1].CurrentChapter)
+	assert.Equal(t, 9, entries[0].CurrentChapter)
+}
+
+func TestApplyProgressOpsIgnoresReplaysOfPrunedOps(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	createTestManga(t, s.db, "m1")
+	createTestManga(t, s.db, "m2")
+
+	old := progressOp("phone", 3, "m1", chapter(20), "reading")
+	require.Equal(t, ProgressOpApplied, applyOne(t, s, userID, old).Outcome)
+	_, err := s.db.Exec("UPDATE progress_ops SET applied_at = ?", time.Now().UTC().Add(-progressOpRetention-time.Hour))
+	require.NoError(t, err)
+
+	// The next batch prunes the old op
+	require.Equal(t, ProgressOpApplied, applyOne(t, s, userID, progressOp("phone", 4, "m2", chapter(1), "reading")).Outcome)
+	var remembered bool
+	require.NoError(t, s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM progress_ops WHERE clock = 3)").Scan(&remembered))
+	require.False(t, remembered)
+
+	// Undo the entry with another device, then replay the forgotten op
+	require.Equal(t, ProgressOpApplied, applyOne(t, s, userID, progressOp("tablet", 10, "m1", chapter(0), "re_reading")).Outcome)
+	result := applyOne(t, s, userID, old)
+	assert.Equal(t, ProgressOpDuplicate, result.Outcome)
+	assert.Equal(t, 0, result.Entry.CurrentChapter)
+
+	// Later ops of the device still apply
+	assert.Equal(t, ProgressOpApplied, applyOne(t, s, userID, progressOp("phone", 11, "m1", chapter(2), "")).Outcome)
+}
+
+func TestProgressWritesRecordOneSyncChange(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	createTestManga(t, s.db, "m1")
+
+	countChanges := func() int {
+		var count int
+		require.NoError(t, s.db.QueryRow("SELECT COUNT(*) FROM sync_changes WHERE user_id = ?", userID).Scan(&count))
+		return count
+	}
+
+	require.NoError(t, s.AddToLibrary(userID, models.AddToLibraryRequest{MangaID: "m1", Status: "reading"}))
+	assert.Equal(t, 1, countChanges())
+
+	// A write outside progress ops bumps the clock without a second change
+	_, err := saveProgress(s.db, userID, "m1", 4, "reading")
+	require.NoError(t, err)
+	assert.Equal(t, 2, countChanges())
+	entry, err := s.GetUserProgress(userID, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), entry.Clock)
+
+	require.Equal(t, ProgressOpApplied, applyOne(t, s, userID, progressOp("phone", 5, "m1", chapter(6), "")).Outcome)
+	assert.Equal(t, 3, countChanges())
+
+	// A concurrent op that changes nothing records nothing
+	require.Equal(t, ProgressOpMerged, applyOne(t, s, userID, progressOp("tablet", 2, "m1", chapter(1), "")).Outcome)
+	assert.Equal(t, 3, countChanges())
+}
@@ -0,0 +1,432 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mangahub/pkg/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Item-item collaborative filtering settings
+const (
+	maxRatingValue    = 10   // manga_ratings holds 1-10
+	highRatingValue   = 8    // Ratings explained as "rated highly"
+	similarityShrink  = 5.0  // Damps similarities backed by few common readers
+	minSimilarity     = 0.05 // Weaker pairs are not stored
+	maxNeighbors      = 50   // Similar manga kept per manga
+	maxItemsPerReader = 500  // Most recent library entries used per reader
+	coldStartEntries  = 5.0  // Library size at which both signals weigh the same
+)
+
+// statusPreference is the implicit preference for a library entry without a
+// rating. Dropped entries carry no preference.
+var statusPreference = map[string]float64{
+	"completed":    0.8,
+	"re_reading":   0.9,
+	"reading":      0.6,
+	"on_hold":      0.4,
+	"plan_to_read": 0.2,
+}
+
+// preference is one reader's preference for one manga in [0, 1]
+type preference struct {
+	mangaID string
+	value   float64
+	rating  int
+	status  string
+}
+
+// RefreshSimilarity recomputes the manga_similarity table: the cosine similarity
+// of every pair of manga over the preferences of readers who have both, from
+// ratings when present and the library status otherwise
+func (s *Service) RefreshSimilarity() error {
+	started := time.Now()
+
+	readers, err := s.loadPreferences("")
+	if err != nil {
+		return err
+	}
+
+	norms := make(map[string]float64)
+	type pair struct{ a, b string }
+	dots := make(map[pair]float64)
+	common := make(map[pair]int)
+	for _, prefs := range readers {
+		for i, p := range prefs {
+			norms[p.mangaID] += p.value * p.value
+			for _, q := range prefs[i+1:] {
+				key := pair{p.mangaID, q.mangaID}
+				if key.b < key.a {
+					key = pair{q.mangaID, p.mangaID}
+				}
+				dots[key] += p.value * q.value
+				common[key]++
+			}
+		}
+	}
+
+	type neighbor struct {
+		id     string
+		score  float64
+		common int
+	}
+	neighbors := make(map[string][]neighbor)
+	for key, dot := range dots {
+		n := float64(common[key])
+		score := dot / math.Sqrt(norms[key.a]*norms[key.b]) * n / (n + similarityShrink)
+		if score < minSimilarity {
+			continue
+		}
+		neighbors[key.a] = append(neighbors[key.a], neighbor{key.b, score, common[key]})
+		neighbors[key.b] = append(neighbors[key.b], neighbor{key.a, score, common[key]})
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM manga_similarity"); err != nil {
+		return fmt.Errorf("failed to clear manga similarity: %w", err)
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO manga_similarity (manga_id, similar_id, score, common_readers, updated_at)
+		VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare similarity insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	pairs := 0
+	for mangaID, list := range neighbors {
+		sort.Slice(list, func(i, j int) bool { return list[i].score > list[j].score })
+		if len(list) > maxNeighbors {
+			list = list[:maxNeighbors]
+		}
+		for _, n := range list {
+			if _, err := stmt.Exec(mangaID, n.id, n.score, n.common, now); err != nil {
+				return fmt.Errorf("failed to store manga similarity: %w", err)
+			}
+			pairs++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit manga similarity: %w", err)
+	}
+
+	log.Printf("Manga similarity refreshed: %d readers, %d pairs in %v", len(readers), pairs, time.Since(started))
+	return nil
+}
+
+// StartSimilarityRefresh recomputes manga similarity now and then at every interval
+func (s *Service) StartSimilarityRefresh(interval time.Duration) {
+	if err := s.RefreshSimilarity(); err != nil {
+		log.Printf("Manga similarity refresh failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.RefreshSimilarity(); err != nil {
+			log.Printf("Manga similarity refresh failed: %v", err)
+		}
+	}
+}
+
+// GetReadingRecommendations returns manga the user has not added yet, scored by
+// the item-item similarity to what they read and rated, blended with how well
+// the genres match their library genres and personal tags. The genre signal
+// carries small libraries (cold start); collaborative filtering takes over as
+// the library grows. Every recommendation comes with the reasons it was picked.
+func (s *Service) GetReadingRecommendations(userID string, limit int) ([]models.MangaRecommendation, error) {
+	if limit <= 0 || limit > 20 {
+		limit = 10
+	}
+
+	readers, err := s.loadPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	library := readers[userID]
+
+	inLibrary := make(map[string]preference, len(library))
+	for _, p := range library {
+		inLibrary[p.mangaID] = p
+	}
+
+	// Collaborative signal: similarity to library entries, weighted by preference
+	collaborative := make(map[string]float64)
+	because := make(map[string]string)
+	bestContribution := make(map[string]float64)
+	if len(library) > 0 {
+		rows, err := s.db.Query(`
+			SELECT ms.manga_id, ms.similar_id, ms.score
+			FROM manga_similarity ms
+			JOIN user_progress up ON up.manga_id = ms.manga_id AND up.user_id = ?`, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get similar manga: %w", err)
+		}
+		for rows.Next() {
+			var sourceID, similarID string
+			var score float64
+			if err := rows.Scan(&sourceID, &similarID, &score); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan similar manga: %w", err)
+			}
+			if _, ok := inLibrary[similarID]; ok {
+				continue
+			}
+			contribution := score * inLibrary[sourceID].value
+			collaborative[similarID] += contribution
+			if contribution > bestContribution[similarID] {
+				bestContribution[similarID] = contribution
+				because[similarID] = sourceID
+			}
+		}
+		rows.Close()
+	}
+	maxCollaborative := 0.0
+	for _, score := range collaborative {
+		maxCollaborative = math.Max(maxCollaborative, score)
+	}
+
+	// Content signal: the user's genre and tag profile
+	profile, err := s.genreProfile(userID, inLibrary)
+	if err != nil {
+		return nil, err
+	}
+	profileNorm := 0.0
+	for _, weight := range profile {
+		profileNorm += weight * weight
+	}
+	profileNorm = math.Sqrt(profileNorm)
+
+	candidates, err := s.recommendationCandidates(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	alpha := float64(len(library)) / (float64(len(library)) + coldStartEntries)
+	if maxCollaborative == 0 {
+		alpha = 0
+	}
+
+	titles := make(map[string]string)
+	recommendations := make([]models.MangaRecommendation, 0, len(candidates))
+	for _, candidate := range candidates {
+		cf := 0.0
+		if maxCollaborative > 0 {
+			cf = collaborative[candidate.manga.ID] / maxCollaborative
+		}
+
+		content := 0.0
+		var matched []string
+		if profileNorm > 0 && len(candidate.manga.Genres) > 0 {
+			dot := 0.0
+			for _, genre := range candidate.manga.Genres {
+				if weight := profile[strings.ToLower(genre)]; weight > 0 {
+					dot += weight
+					matched = append(matched, genre)
+				}
+			}
+			content = dot / (profileNorm * math.Sqrt(float64(len(candidate.manga.Genres))))
+		}
+
+		// Popularity only breaks ties, and fills in for users with an empty library
+		score := alpha*cf + (1-alpha)*content + 0.05*candidate.popularity
+		if score <= 0 {
+			continue
+		}
+
+		var reasons []string
+		if sourceID, ok := because[candidate.manga.ID]; ok && cf > 0 {
+			if _, ok := titles[sourceID]; !ok {
+				titles[sourceID] = s.mangaTitle(sourceID)
+			}
+			reasons = append(reasons, explainPreference(inLibrary[sourceID], titles[sourceID]))
+		}
+		if len(matched) > 0 {
+			sort.SliceStable(matched, func(i, j int) bool {
+				return profile[strings.ToLower(matched[i])] > profile[strings.ToLower(matched[j])]
+			})
+			if len(matched) > 3 {
+				matched = matched[:3]
+			}
+			reasons = append(reasons, "Matches genres you read: "+strings.Join(matched, ", "))
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "Popular with other readers")
+		}
+
+		recommendations = append(recommendations, models.MangaRecommendation{
+			Manga:   candidate.manga,
+			Score:   math.Round(score*1000) / 1000,
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Manga.Title < recommendations[j].Manga.Title
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations, nil
+}
+
+// loadPreferences returns the preferences of every reader, or of one reader when
+// userID is set, most recently updated first
+func (s *Service) loadPreferences(userID string) (map[string][]preference, error) {
+	query := `
+		SELECT up.user_id, up.manga_id, up.status, COALESCE(r.rating, 0)
+		FROM user_progress up
+		LEFT JOIN manga_ratings r ON r.user_id = up.user_id AND r.manga_id = up.manga_id AND r.quarantined = 0`
+	var args []interface{}
+	if userID != "" {
+		query += " WHERE up.user_id = ?"
+		args = append(args, userID)
+	}
+	query += " ORDER BY up.user_id, up.last_updated DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reading preferences: %w", err)
+	}
+	defer rows.Close()
+
+	readers := make(map[string][]preference)
+	for rows.Next() {
+		var reader string
+		var p preference
+		if err := rows.Scan(&reader, &p.mangaID, &p.status, &p.rating); err != nil {
+			return nil, fmt.Errorf("failed to scan reading preference: %w", err)
+		}
+		if len(readers[reader]) >= maxItemsPerReader {
+			continue
+		}
+
+		p.value = statusPreference[p.status]
+		if p.rating > 0 {
+			p.value = float64(p.rating) / maxRatingValue
+		}
+		if p.value > 0 {
+			readers[reader] = append(readers[reader], p)
+		}
+	}
+
+	return readers, nil
+}
+
+// genreProfile weighs the genres of the user's library entries and their
+// personal tags by preference, keyed in lower case
+func (s *Service) genreProfile(userID string, inLibrary map[string]preference) (map[string]float64, error) {
+	rows, err := s.db.Query(`
+		SELECT up.manga_id, COALESCE(m.genres, '[]'), COALESCE(up.tags, '[]')
+		FROM user_progress up
+		LEFT JOIN manga m ON m.id = up.manga_id
+		WHERE up.user_id = ?`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library genres: %w", err)
+	}
+	defer rows.Close()
+
+	profile := make(map[string]float64)
+	for rows.Next() {
+		var mangaID, genresJSON, tagsJSON string
+		if err := rows.Scan(&mangaID, &genresJSON, &tagsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan library genres: %w", err)
+		}
+		weight := inLibrary[mangaID].value
+
+		var genres []string
+		json.Unmarshal([]byte(genresJSON), &genres)
+		for _, genre := range append(genres, decodeTags(tagsJSON)...) {
+			profile[strings.ToLower(genre)] += weight
+		}
+	}
+
+	return profile, nil
+}
+
+type recommendationCandidate struct {
+	manga      models.Manga
+	popularity float64 // Readers relative to the most read manga, in [0, 1]
+}
+
+// recommendationCandidates returns the local manga not in the user's library
+func (s *Service) recommendationCandidates(userID string) ([]recommendationCandidate, error) {
+	rows, err := s.db.Query(`
+		SELECT m.id, m.title, COALESCE(m.author, ''), COALESCE(m.genres, '[]'), COALESCE(m.status, ''),
+			COALESCE(m.total_chapters, 0), COALESCE(m.description, ''), COALESCE(m.cover_url, ''), m.created_at,
+			(SELECT COUNT(*) FROM user_progress p WHERE p.manga_id = m.id) AS readers
+		FROM manga m
+		WHERE m.id NOT IN (SELECT manga_id FROM user_progress WHERE user_id = ?)`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommendation candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []recommendationCandidate
+	maxReaders := 0
+	readers := []int{}
+	for rows.Next() {
+		var manga models.Manga
+		var count int
+		var createdAt sql.NullTime
+		if err := rows.Scan(&manga.ID, &manga.Title, &manga.Author, &manga.GenresJSON, &manga.Status,
+			&manga.TotalChapters, &manga.Description, &manga.CoverURL, &createdAt, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan recommendation candidate: %w", err)
+		}
+		manga.CreatedAt = createdAt.Time
+		if err := manga.GetGenres(); err != nil {
+			manga.Genres = []string{}
+		}
+
+		candidates = append(candidates, recommendationCandidate{manga: manga})
+		readers = append(readers, count)
+		if count > maxReaders {
+			maxReaders = count
+		}
+	}
+
+	if maxReaders > 0 {
+		for i := range candidates {
+			candidates[i].popularity = float64(readers[i]) / float64(maxReaders)
+		}
+	}
+	return candidates, nil
+}
+
+// mangaTitle returns the title of a local manga, or its ID when it is unknown
+func (s *Service) mangaTitle(mangaID string) string {
+	var title string
+	if err := s.db.QueryRow("SELECT title FROM manga WHERE id = ?", mangaID).Scan(&title); err != nil {
+		return mangaID
+	}
+	return title
+}
+
+// explainPreference describes why a library entry led to a recommendation
+func explainPreference(p preference, title string) string {
+	switch {
+	case p.rating >= highRatingValue:
+		return fmt.Sprintf("Because you rated %s highly", title)
+	case p.status == "completed":
+		return fmt.Sprintf("Because you completed %s", title)
+	case p.status == "reading" || p.status == "re_reading":
+		return fmt.Sprintf("Because you are reading %s", title)
+	}
+	return fmt.Sprintf("Because %s is in your library", title)
+}
@@ -0,0 +1,188 @@
+package user
+
+import (
+	"fmt"
+	"log"
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+	"time"
+)
+
+// grantableRoles are the roles an admin can grant, in listing order
+var grantableRoles = []string{auth.RoleAdmin, auth.RoleModerator, auth.RoleCurator}
+
+// GetRoles lists every role with its permissions and the users holding it
+func (s *Service) GetRoles() ([]models.RoleInfo, error) {
+	rows, err := s.db.Query(`
+		SELECT r.user_id, COALESCE(u.username, ''), r.role, COALESCE(r.granted_by, ''), r.granted_at
+		FROM user_roles r
+		LEFT JOIN users u ON u.id = r.user_id
+		ORDER BY r.granted_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	defer rows.Close()
+
+	members := map[string][]models.RoleGrant{}
+	for rows.Next() {
+		var grant models.RoleGrant
+		if err := rows.Scan(&grant.UserID, &grant.Username, &grant.Role, &grant.GrantedBy, &grant.GrantedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role grant: %w", err)
+		}
+		members[grant.Role] = append(members[grant.Role], grant)
+	}
+
+	roles := []models.RoleInfo{}
+	for _, role := range append(grantableRoles, auth.RoleUser) {
+		info := models.RoleInfo{
+			Role:        role,
+			Permissions: auth.RolePermissions(role),
+			Members:     members[role],
+		}
+		if info.Members == nil {
+			info.Members = []models.RoleGrant{}
+		}
+		roles = append(roles, info)
+	}
+
+	return roles, nil
+}
+
+// GetUserRoles returns the roles of a user
+func (s *Service) GetUserRoles(userID string) ([]string, error) {
+	if err := s.checkUserExists(userID); err != nil {
+		return nil, err
+	}
+	return auth.GetUserRoles(userID)
+}
+
+// GrantRole gives a user a role. The user's current access tokens stop working
+// and are replaced with ones carrying the role on their next refresh.
+func (s *Service) GrantRole(userID, role, grantedBy string) error {
+	if !auth.IsValidRole(role) || role == auth.RoleUser {
+		return fmt.Errorf("invalid role: %s", role)
+	}
+	if err := s.checkUserExists(userID); err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(`
+		INSERT OR IGNORE INTO user_roles (user_id, role, granted_by, granted_at)
+		VALUES (?, ?, ?, ?)`, userID, role, grantedBy, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+	if granted, _ := result.RowsAffected(); granted == 0 {
+		return fmt.Errorf("user already has the %s role", role)
+	}
+
+	log.Printf("Role %s granted to user %s by %s", role, userID, grantedBy)
+	return nil
+}
+
+// RevokeRole takes a role away from a user. The last admin cannot be revoked,
+// so there is always someone who can grant roles.
+func (s *Service) RevokeRole(userID, role string) error {
+	if !auth.IsValidRole(role) || role == auth.RoleUser {
+		return fmt.Errorf("invalid role: %s", role)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if role == auth.RoleAdmin {
+		var admins int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM user_roles WHERE role = ?", auth.RoleAdmin).Scan(&admins); err != nil {
+			return fmt.Errorf("failed to count admins: %w", err)
+		}
+		if admins <= 1 {
+			return fmt.Errorf("cannot revoke the last admin")
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM user_roles WHERE user_id = ? AND role = ?", userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("role not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Role %s revoked from user %s", role, userID)
+	return nil
+}
+
+// BootstrapAdmin makes the user with the given email an admin if there is no
+// admin yet. The email must be verified, so that nobody can claim the role by
+// registering someone else's address first, and must match exactly one account.
+// It is how the first admin is created; afterwards admins grant roles through
+// the API.
+func (s *Service) BootstrapAdmin(email string) error {
+	if email == "" {
+		return nil
+	}
+
+	var admins int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM user_roles WHERE role = ?", auth.RoleAdmin).Scan(&admins); err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if admins > 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id FROM users
+		WHERE email = ? COLLATE NOCASE AND email_verified_at IS NOT NULL`, email)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+
+	if len(userIDs) == 0 {
+		return fmt.Errorf("bootstrap admin %s not found: register the account and verify its email first", email)
+	}
+	if len(userIDs) > 1 {
+		return fmt.Errorf("bootstrap admin %s matches %d accounts", email, len(userIDs))
+	}
+	userID := userIDs[0]
+
+	if _, err := s.db.Exec("INSERT OR IGNORE INTO user_roles (user_id, role, granted_by, granted_at) VALUES (?, ?, '', ?)",
+		userID, auth.RoleAdmin, time.Now()); err != nil {
+		return fmt.Errorf("failed to grant admin role: %w", err)
+	}
+
+	log.Printf("Bootstrap admin %s (%s) created", email, userID)
+	return nil
+}
+
+// checkUserExists returns "user not found" for unknown user IDs
+func (s *Service) checkUserExists(userID string) error {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
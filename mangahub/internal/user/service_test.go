@@ -0,0 +1,40 @@
+package user
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"mangahub/pkg/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestService returns a service on a fresh database in a temporary directory
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("MAIL_OUTBOX_DIR", filepath.Join(dir, "outbox"))
+	require.NoError(t, database.OpenDatabase(filepath.Join(dir, "mangahub.db")))
+	t.Cleanup(func() { database.GetDB().Close() })
+	return NewService()
+}
+
+// createTestUser inserts a user and returns its ID
+func createTestUser(t *testing.T, db *sql.DB, username string) string {
+	t.Helper()
+	id := "user-" + username
+	_, err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, '')",
+		id, username, username+"@example.com")
+	require.NoError(t, err)
+	return id
+}
+
+// createTestManga inserts a manga
+func createTestManga(t *testing.T, db *sql.DB, id string) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO manga (id, title, author, genres, status, total_chapters, description, cover_url, publication_year)
+		VALUES (?, ?, '', '[]', 'ongoing', 100, '', '', 2020)`, id, "Manga "+id)
+	require.NoError(t, err)
+}
@@ -0,0 +1,209 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionRetention is how long revoked and expired sessions are kept before they are deleted
+const sessionRetention = 30 * 24 * time.Hour
+
+// createSession opens a login session for the user and returns its tokens
+func (s *Service) createSession(userID, username, email string, client models.SessionClient) (*models.SessionTokens, error) {
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessionID := uuid.New().String()
+	_, err = s.db.Exec(`
+		INSERT INTO sessions (id, user_id, refresh_token_hash, device_name, user_agent, ip_address,
+			created_at, last_used_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sessionID, userID, auth.HashToken(refreshToken), client.DeviceName, client.UserAgent, client.IPAddress,
+		now, now, now.Add(auth.RefreshTokenTTL()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Drop sessions that can no longer be used
+	s.db.Exec("DELETE FROM sessions WHERE user_id = ? AND COALESCE(revoked_at, expires_at) < ?",
+		userID, now.Add(-sessionRetention))
+
+	roles, err := auth.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := auth.GenerateToken(userID, username, email, sessionID, roles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &models.SessionTokens{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+		SessionID:    sessionID,
+	}, nil
+}
+
+// RefreshSession exchanges a refresh token for a new access token and a new
+// refresh token. Presenting a refresh token that was already rotated out means
+// it leaked, so the session is revoked.
+func (s *Service) RefreshSession(refreshToken string, client models.SessionClient) (*models.SessionTokens, error) {
+	hash := auth.HashToken(refreshToken)
+
+	var sessionID, userID, username, email string
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+	err := s.db.QueryRow(`
+		SELECT s.id, s.user_id, u.username, u.email, s.expires_at, s.revoked_at
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.refresh_token_hash = ?`, hash).Scan(&sessionID, &userID, &username, &email, &expiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		// A rotated-out token is being replayed
+		result, err := s.db.Exec("UPDATE sessions SET revoked_at = ? WHERE previous_token_hash = ? AND revoked_at IS NULL",
+			time.Now(), hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to revoke session: %w", err)
+		}
+		if revoked, _ := result.RowsAffected(); revoked > 0 {
+			return nil, fmt.Errorf("invalid refresh token: already used, session revoked")
+		}
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if revokedAt.Valid {
+		return nil, fmt.Errorf("invalid refresh token: session revoked")
+	}
+	if time.Now().After(expiresAt) {
+		return nil, fmt.Errorf("invalid refresh token: session expired")
+	}
+
+	newRefreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rotate only if the token is still current, so two concurrent refreshes cannot both succeed
+	now := time.Now()
+	result, err := s.db.Exec(`
+		UPDATE sessions
+		SET refresh_token_hash = ?, previous_token_hash = ?, last_used_at = ?, expires_at = ?,
+			user_agent = CASE WHEN ? != '' THEN ? ELSE user_agent END,
+			ip_address = CASE WHEN ? != '' THEN ? ELSE ip_address END
+		WHERE id = ? AND refresh_token_hash = ?`,
+		auth.HashToken(newRefreshToken), hash, now, now.Add(auth.RefreshTokenTTL()),
+		client.UserAgent, client.UserAgent, client.IPAddress, client.IPAddress, sessionID, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if rotated, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rotated == 0 {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	roles, err := auth.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	token, tokenExpiresAt, err := auth.GenerateToken(userID, username, email, sessionID, roles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &models.SessionTokens{
+		Token:        token,
+		ExpiresAt:    tokenExpiresAt,
+		RefreshToken: newRefreshToken,
+		SessionID:    sessionID,
+	}, nil
+}
+
+// GetSessions lists the user's active sessions, most recently used first
+func (s *Service) GetSessions(userID, currentSessionID string) ([]models.Session, error) {
+	rows, err := s.db.Query(`
+		SELECT id, COALESCE(device_name, ''), COALESCE(user_agent, ''), COALESCE(ip_address, ''),
+			created_at, last_used_at, expires_at
+		FROM sessions
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY last_used_at DESC`, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.DeviceName, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		session.Current = session.ID == currentSessionID
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions; its access tokens stop working right away
+func (s *Service) RevokeSession(userID, sessionID string) error {
+	result, err := s.db.Exec("UPDATE sessions SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		time.Now(), sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
+// RevokeAllSessions ends all of the user's sessions except keepSessionID, if
+// given, and returns how many were revoked
+func (s *Service) RevokeAllSessions(userID, keepSessionID string) (int, error) {
+	result, err := s.db.Exec("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id != ? AND revoked_at IS NULL",
+		time.Now(), userID, keepSessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// revokeCredentials ends the user's sessions except keepSessionID, if given,
+// and revokes their API tokens, for a password change in tx
+func revokeCredentials(tx *sql.Tx, userID, keepSessionID string) error {
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id != ? AND revoked_at IS NULL",
+		now, userID, keepSessionID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE api_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+		now, userID); err != nil {
+		return fmt.Errorf("failed to revoke API tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package user
+
+import (
+	"fmt"
+	"log"
+	"mangahub/pkg/models"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "mangahub/proto/tachiyomi"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// mangaDexSourceID is the source ID of the English MangaDex extension, used
+// when a backup does not name its sources
+const mangaDexSourceID int64 = 2499283573021220255
+
+// Tachiyomi tracker IDs
+const (
+	tachiyomiTrackerMAL     = 1
+	tachiyomiTrackerAniList = 2
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// ImportTachiyomiBackup imports the library of a Tachiyomi/Mihon backup (gzipped
+// protobuf). MangaDex entries are matched through manga_sources, other sources by
+// their MAL tracker or title. Read chapters are restored for every matched entry and
+// categories become private collections; unmatched entries are added to the import
+// review list.
+func (s *Service) ImportTachiyomiBackup(userID string, data []byte, overwrite bool) (*models.TachiyomiImportResult, error) {
+	data, err := maybeGunzip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup pb.Backup
+	if err := proto.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("invalid Tachiyomi backup: %w", err)
+	}
+
+	result := &models.TachiyomiImportResult{
+		Categories: []string{},
+		Entries:    []models.TachiyomiMatch{},
+	}
+
+	sourceNames := make(map[int64]string)
+	for _, source := range backup.GetBackupSources() {
+		sourceNames[source.GetSourceId()] = source.GetName()
+	}
+
+	// Manga reference categories by their order value
+	categoryNames := make(map[int64]string)
+	collectionIDs := make(map[string]string)
+	for _, category := range backup.GetBackupCategories() {
+		categoryNames[category.GetOrder()] = category.GetName()
+		collectionID, err := s.ensureCollection(userID, category.GetName())
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("category %s: %v", category.GetName(), err))
+			continue
+		}
+		collectionIDs[category.GetName()] = collectionID
+		result.Categories = append(result.Categories, category.GetName())
+	}
+
+	var applied []string
+	for _, manga := range backup.GetBackupManga() {
+		// Backups may include non-library manga that only have read history
+		if !manga.GetFavorite() {
+			continue
+		}
+		result.Total++
+
+		entry, match := tachiyomiEntry(manga, sourceNames)
+		for _, order := range manga.GetCategories() {
+			if name, ok := categoryNames[order]; ok {
+				match.Categories = append(match.Categories, name)
+			}
+		}
+
+		mangaID, matchedBy, ok := s.matchLibraryEntry(entry)
+		if !ok {
+			match.Result = "unmatched"
+			if err := s.addImportReviewItem(userID, entry); err != nil {
+				match.Result = "failed"
+				match.Reason = err.Error()
+				result.Failed++
+			} else {
+				match.Reason = "no local manga with this source mapping or title"
+				result.Unmatched++
+			}
+			result.Entries = append(result.Entries, match)
+			continue
+		}
+
+		result.Matched++
+		match.MangaID = mangaID
+		match.MatchedBy = matchedBy
+
+		counts := &models.LibraryImportResult{}
+		if err := s.applyLibraryEntry(userID, mangaID, entry, overwrite, counts); err != nil {
+			match.Result = "failed"
+			match.Reason = err.Error()
+			result.Failed++
+			result.Entries = append(result.Entries, match)
+			continue
+		}
+
+		switch {
+		case counts.Skipped > 0:
+			match.Result = "skipped"
+			match.Reason = "already in library"
+			result.Skipped++
+			result.Entries = append(result.Entries, match)
+			continue
+		case counts.Updated > 0:
+			match.Result = "updated"
+			result.Updated++
+		default:
+			match.Result = "imported"
+			result.Imported++
+		}
+		applied = append(applied, mangaID)
+
+		read, err := s.restoreReadChapters(userID, mangaID, manga)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", match.Title, err))
+		}
+		result.ChaptersRead += read
+
+		for _, category := range match.Categories {
+			collectionID, ok := collectionIDs[category]
+			if !ok {
+				continue
+			}
+			if err := s.addCollectionItem(collectionID, mangaID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: category %s: %v", match.Title, category, err))
+			}
+		}
+
+		result.Entries = append(result.Entries, match)
+	}
+
+	log.Printf("User %s imported Tachiyomi backup: %d of %d matched, %d chapters read",
+		userID, result.Matched, result.Total, result.ChaptersRead)
+
+	// Push the imported entries to the linked MAL account, if any
+	if len(applied) > 0 {
+		go s.PushProgressToMAL(userID, applied...)
+	}
+
+	return result, nil
+}
+
+// GetReadChapters returns the chapters of a manga the user has marked as read
+func (s *Service) GetReadChapters(userID, mangaID string) ([]models.ReadChapter, error) {
+	rows, err := s.db.Query(`
+		SELECT chapter_number, source_chapter_id, read_at
+		FROM user_read_chapters
+		WHERE user_id = ? AND manga_id = ?
+		ORDER BY chapter_number`, userID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read chapters: %w", err)
+	}
+	defer rows.Close()
+
+	chapters := []models.ReadChapter{}
+	for rows.Next() {
+		var chapter models.ReadChapter
+		if err := rows.Scan(&chapter.ChapterNumber, &chapter.SourceChapterID, &chapter.ReadAt); err != nil {
+			return nil, fmt.Errorf("failed to scan read chapter: %w", err)
+		}
+		chapters = append(chapters, chapter)
+	}
+
+	return chapters, nil
+}
+
+// restoreReadChapters marks the read chapters of a backed up manga as read
+func (s *Service) restoreReadChapters(userID, mangaID string, manga *pb.BackupManga) (int, error) {
+	lastRead := make(map[string]int64)
+	for _, history := range manga.GetHistory() {
+		lastRead[history.GetUrl()] = history.GetLastRead()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO user_read_chapters (user_id, manga_id, chapter_number, source_chapter_id, read_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, manga_id, chapter_number) DO UPDATE SET
+			source_chapter_id = excluded.source_chapter_id,
+			read_at = excluded.read_at`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	count := 0
+	for _, chapter := range manga.GetChapters() {
+		// Chapters without a recognised number (-1) cannot be matched later
+		if !chapter.GetRead() || chapter.GetChapterNumber() < 0 {
+			continue
+		}
+
+		readAt := time.Now()
+		if ms := lastRead[chapter.GetUrl()]; ms > 0 {
+			readAt = time.UnixMilli(ms)
+		} else if ms := chapter.GetLastModifiedAt(); ms > 0 {
+			// lastModifiedAt is stored in seconds
+			readAt = time.Unix(ms, 0)
+		}
+
+		if _, err := stmt.Exec(userID, mangaID, chapter.GetChapterNumber(), sourceIDFromURL(chapter.GetUrl()), readAt); err != nil {
+			return count, fmt.Errorf("failed to store read chapter: %w", err)
+		}
+		count++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit read chapters: %w", err)
+	}
+	return count, nil
+}
+
+// tachiyomiEntry converts a backed up manga into a library entry and its report line
+func tachiyomiEntry(manga *pb.BackupManga, sourceNames map[int64]string) (libraryEntry, models.TachiyomiMatch) {
+	sourceName := sourceNames[manga.GetSource()]
+	if sourceName == "" {
+		sourceName = strconv.FormatInt(manga.GetSource(), 10)
+	}
+	isMangaDex := manga.GetSource() == mangaDexSourceID || strings.HasPrefix(strings.ToLower(sourceName), "mangadex")
+
+	entry := libraryEntry{
+		Source: "tachiyomi",
+		Title:  strings.TrimSpace(manga.GetTitle()),
+		Notes:  strings.TrimSpace(manga.GetNotes()),
+	}
+	if isMangaDex {
+		if id := sourceIDFromURL(manga.GetUrl()); uuidPattern.MatchString(id) {
+			entry.Source = "mangadex"
+			entry.SourceID = id
+		}
+	}
+
+	// Chapter progress: the highest read chapter number
+	var chaptersRead, totalChapters int
+	var highest float32
+	for _, chapter := range manga.GetChapters() {
+		if chapter.GetChapterNumber() >= 0 {
+			totalChapters++
+		}
+		if chapter.GetRead() {
+			chaptersRead++
+			if chapter.GetChapterNumber() > highest {
+				highest = chapter.GetChapterNumber()
+			}
+		}
+	}
+	entry.Chapter = int(math.Floor(float64(highest)))
+
+	// Prefer the status of a tracker, it is the user's own choice
+	for _, track := range manga.GetTracking() {
+		mediaID := track.GetMediaId()
+		if mediaID == 0 {
+			mediaID = int64(track.GetMediaIdInt())
+		}
+		// Tracker dates are milliseconds since the epoch, 0 when unset
+		if date := track.GetStartedReadingDate(); date > 0 && entry.StartedAt == nil {
+			startedAt := time.UnixMilli(date).UTC()
+			entry.StartedAt = &startedAt
+		}
+		if date := track.GetFinishedReadingDate(); date > 0 && entry.FinishedAt == nil {
+			finishedAt := time.UnixMilli(date).UTC()
+			entry.FinishedAt = &finishedAt
+		}
+		switch track.GetSyncId() {
+		case tachiyomiTrackerMAL:
+			if mediaID > 0 {
+				entry.MALID = strconv.FormatInt(mediaID, 10)
+			}
+			if entry.Status == "" {
+				entry.Status = malTrackerStatusToLocal(track.GetStatus())
+			}
+			if score := int(math.Round(float64(track.GetScore()))); score > 0 && entry.Score == 0 {
+				entry.Score = clampScore(score)
+			}
+		case tachiyomiTrackerAniList:
+			if entry.Status == "" {
+				entry.Status = aniListTrackerStatusToLocal(track.GetStatus())
+			}
+		}
+	}
+	if entry.Status == "" {
+		switch {
+		case chaptersRead == 0:
+			entry.Status = "plan_to_read"
+		case chaptersRead == totalChapters && (manga.GetStatus() == 2 || manga.GetStatus() == 4):
+			entry.Status = "completed"
+		default:
+			entry.Status = "reading"
+		}
+	}
+
+	match := models.TachiyomiMatch{
+		Title:          entry.Title,
+		Source:         sourceName,
+		Status:         entry.Status,
+		CurrentChapter: entry.Chapter,
+		ChaptersRead:   chaptersRead,
+	}
+	if entry.Source == "mangadex" {
+		match.SourceMangaID = entry.SourceID
+	}
+
+	return entry, match
+}
+
+// sourceIDFromURL returns the last path segment of a source URL, e.g. the UUID of "/manga/<uuid>"
+func sourceIDFromURL(rawURL string) string {
+	rawURL, _, _ = strings.Cut(rawURL, "?")
+	return path.Base(strings.TrimRight(rawURL, "/"))
+}
+
+// malTrackerStatusToLocal converts a Tachiyomi MAL tracker status
+func malTrackerStatusToLocal(status int32) string {
+	switch status {
+	case 1:
+		return "reading"
+	case 2:
+		return "completed"
+	case 3:
+		return "on_hold"
+	case 4:
+		return "dropped"
+	case 6:
+		return "plan_to_read"
+	case 7:
+		return "re_reading"
+	}
+	return ""
+}
+
+// aniListTrackerStatusToLocal converts a Tachiyomi AniList tracker status
+func aniListTrackerStatusToLocal(status int32) string {
+	switch status {
+	case 1:
+		return "reading"
+	case 2:
+		return "completed"
+	case 3:
+		return "on_hold"
+	case 4:
+		return "dropped"
+	case 5:
+		return "plan_to_read"
+	case 6:
+		return "re_reading"
+	}
+	return ""
+}
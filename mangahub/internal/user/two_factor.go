@@ -0,0 +1,375 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mangahub/internal/auth"
+	"mangahub/internal/mailer"
+	"mangahub/pkg/models"
+	"strings"
+	"time"
+)
+
+// recoveryCodeCount is how many recovery codes are issued at a time
+const recoveryCodeCount = 10
+
+// ErrInvalidSecondFactor is returned for a wrong or already used authenticator
+// or recovery code
+var ErrInvalidSecondFactor = errors.New("invalid second factor code")
+
+// newLoginChallenge issues the token that carries a login from the password
+// step to the second factor step. It is bound to the password, so changing
+// the password ends pending logins.
+func newLoginChallenge(userID, passwordHash string) (*models.LoginChallenge, error) {
+	token, err := auth.GenerateActionToken(auth.PurposeLoginChallenge, userID,
+		auth.PasswordBinding(passwordHash), auth.LoginChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         time.Now().Add(auth.LoginChallengeTTL),
+		Methods:           []string{"totp", "recovery_code"},
+	}, nil
+}
+
+// CompleteTwoFactorLogin finishes a login challenged for a second factor and
+// opens the session
+func (s *Service) CompleteTwoFactorLogin(req models.TwoFactorLoginRequest, client models.SessionClient) (*models.LoginResponse, error) {
+	userID, binding, err := auth.ParseActionToken(req.ChallengeToken, auth.PurposeLoginChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	var passwordHash string
+	var totpSecret sql.NullString
+	var totpEnabledAt sql.NullTime
+	err = s.db.QueryRow("SELECT password_hash, totp_secret, totp_enabled_at FROM users WHERE id = ?", userID).Scan(
+		&passwordHash, &totpSecret, &totpEnabledAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invalid token: user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if auth.PasswordBinding(passwordHash) != binding {
+		return nil, fmt.Errorf("invalid token: password changed, log in again")
+	}
+
+	// Wrong codes count as failed logins, so codes cannot be guessed within a challenge
+	now := time.Now()
+	before, err := s.throttleLogin(userID, "", client, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Two-factor authentication was turned off in the meantime
+	if totpEnabledAt.Valid {
+		if err := s.verifySecondFactor(userID, totpSecret.String, req.Code); err != nil {
+			s.endSecondFactorAttempt(userID, client, before, now, err)
+			return nil, err
+		}
+	}
+
+	if client.DeviceName == "" {
+		client.DeviceName = req.DeviceName
+	}
+	response, err := s.openLoginSession(userID, client)
+	if err != nil {
+		s.releaseLoginAttempt(userID, before, now)
+		return nil, err
+	}
+	s.recordLoginSuccess(userID, "", client, before)
+	return response, nil
+}
+
+// GetTwoFactorStatus returns whether the user has two-factor authentication
+// and how many recovery codes are left
+func (s *Service) GetTwoFactorStatus(userID string) (*models.TwoFactorStatus, error) {
+	var enabledAt sql.NullTime
+	err := s.db.QueryRow("SELECT totp_enabled_at FROM users WHERE id = ?", userID).Scan(&enabledAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	status := &models.TwoFactorStatus{Enabled: enabledAt.Valid}
+	if !enabledAt.Valid {
+		return status, nil
+	}
+	status.EnabledAt = &enabledAt.Time
+
+	err = s.db.QueryRow("SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = ? AND used_at IS NULL",
+		userID).Scan(&status.RecoveryCodesRemaining)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return status, nil
+}
+
+// SetupTOTP starts enrolling an authenticator app with a new secret. Two-factor
+// authentication is only on once EnableTOTP confirms a code from the app.
+func (s *Service) SetupTOTP(userID string) (*models.TOTPSetupResponse, error) {
+	var username string
+	var enabledAt sql.NullTime
+	err := s.db.QueryRow("SELECT username, totp_enabled_at FROM users WHERE id = ?", userID).Scan(&username, &enabledAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if enabledAt.Valid {
+		return nil, fmt.Errorf("two-factor authentication already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec("UPDATE users SET totp_secret = ?, totp_last_step = 0 WHERE id = ?", secret, userID); err != nil {
+		return nil, fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+
+	return &models.TOTPSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(secret, username),
+	}, nil
+}
+
+// EnableTOTP turns on two-factor authentication once the user proves the
+// authenticator app works, and returns the first recovery codes
+func (s *Service) EnableTOTP(userID, code string) ([]string, error) {
+	var secret sql.NullString
+	var enabledAt sql.NullTime
+	err := s.db.QueryRow("SELECT totp_secret, totp_enabled_at FROM users WHERE id = ?", userID).Scan(&secret, &enabledAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if enabledAt.Valid {
+		return nil, fmt.Errorf("two-factor authentication already enabled")
+	}
+	if !secret.Valid || secret.String == "" {
+		return nil, fmt.Errorf("two-factor setup not started")
+	}
+
+	if err := s.useTOTPCode(userID, secret.String, code); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec("UPDATE users SET totp_enabled_at = ? WHERE id = ?", time.Now(), userID); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendTwoFactorEmail(userID, true)
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor authentication, confirmed with a code
+func (s *Service) DisableTOTP(userID, code string, client models.SessionClient) error {
+	if err := s.requireTwoFactorEnabled(userID, code, client); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(`
+		UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0
+		WHERE id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM totp_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	s.sendTwoFactorEmail(userID, false)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, confirmed with a code
+func (s *Service) RegenerateRecoveryCodes(userID, code string, client models.SessionClient) ([]string, error) {
+	if err := s.requireTwoFactorEnabled(userID, code, client); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+// RequireSecondFactor checks a fresh second factor for a sensitive account
+// change. Accounts without two-factor authentication pass without a code.
+// Wrong codes count as failed logins of the account, with the same backoff
+// and lockout.
+func (s *Service) RequireSecondFactor(userID, code string, client models.SessionClient) error {
+	secret, enabledAt, err := s.getSecondFactorState(userID)
+	if err != nil {
+		return err
+	}
+	if !enabledAt.Valid {
+		return nil
+	}
+	if strings.TrimSpace(code) == "" {
+		return fmt.Errorf("second factor required: send a current authenticator or recovery code as otp_code")
+	}
+
+	return s.checkSecondFactor(userID, secret.String, code, client)
+}
+
+// requireTwoFactorEnabled checks the code of a user who must have two-factor authentication on
+func (s *Service) requireTwoFactorEnabled(userID, code string, client models.SessionClient) error {
+	secret, enabledAt, err := s.getSecondFactorState(userID)
+	if err != nil {
+		return err
+	}
+	if !enabledAt.Valid {
+		return fmt.Errorf("two-factor authentication not enabled")
+	}
+
+	return s.checkSecondFactor(userID, secret.String, code, client)
+}
+
+func (s *Service) getSecondFactorState(userID string) (sql.NullString, sql.NullTime, error) {
+	var secret sql.NullString
+	var enabledAt sql.NullTime
+	err := s.db.QueryRow("SELECT totp_secret, totp_enabled_at FROM users WHERE id = ?", userID).Scan(&secret, &enabledAt)
+	if err == sql.ErrNoRows {
+		return secret, enabledAt, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return secret, enabledAt, fmt.Errorf("failed to get user: %w", err)
+	}
+	return secret, enabledAt, nil
+}
+
+// checkSecondFactor verifies the code of a signed-in user. Like codes sent to
+// complete a login, it is refused while the account or address has to wait,
+// and a wrong code is recorded as a failed login. A right code leaves the
+// failures of the account as they were.
+func (s *Service) checkSecondFactor(userID, secret, code string, client models.SessionClient) error {
+	now := time.Now()
+	before, err := s.throttleLogin(userID, "", client, now)
+	if err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(userID, secret, code); err != nil {
+		s.endSecondFactorAttempt(userID, client, before, now, err)
+		return err
+	}
+	s.releaseLoginAttempt(userID, before, now)
+	return nil
+}
+
+// endSecondFactorAttempt settles the reservation of an attempt whose code was
+// not accepted: a wrong code is a failed login, while a code that could not be
+// checked takes the reservation back
+func (s *Service) endSecondFactorAttempt(userID string, client models.SessionClient, before accountLockState, reservedAt time.Time, err error) {
+	if errors.Is(err, ErrInvalidSecondFactor) {
+		s.recordLoginFailure(userID, "", client, LoginInvalidSecondFactor)
+		return
+	}
+	s.releaseLoginAttempt(userID, before, reservedAt)
+}
+
+// verifySecondFactor accepts an authenticator code, or else uses up a recovery code
+func (s *Service) verifySecondFactor(userID, secret, code string) error {
+	if err := s.useTOTPCode(userID, secret, code); !errors.Is(err, ErrInvalidSecondFactor) {
+		return err
+	}
+
+	result, err := s.db.Exec(`
+		UPDATE totp_recovery_codes SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`,
+		time.Now(), userID, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if used, _ := result.RowsAffected(); used == 1 {
+		log.Printf("User %s used a recovery code", userID)
+		return nil
+	}
+
+	return ErrInvalidSecondFactor
+}
+
+// useTOTPCode checks an authenticator code and records its time step, so the
+// same code is not accepted twice
+func (s *Service) useTOTPCode(userID, secret, code string) error {
+	step, ok := auth.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidSecondFactor
+	}
+
+	result, err := s.db.Exec("UPDATE users SET totp_last_step = ? WHERE id = ? AND COALESCE(totp_last_step, 0) < ?",
+		step, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record TOTP code: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("%w: already used", ErrInvalidSecondFactor)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes issues new recovery codes, voiding the old ones
+func (s *Service) replaceRecoveryCodes(userID string) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM totp_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	for _, code := range codes {
+		if _, err := tx.Exec("INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)",
+			userID, auth.HashToken(auth.NormalizeRecoveryCode(code)), time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return codes, nil
+}
+
+// sendTwoFactorEmail tells the user two-factor authentication was turned on or off
+func (s *Service) sendTwoFactorEmail(userID string, enabled bool) {
+	var username, email string
+	if err := s.db.QueryRow("SELECT username, email FROM users WHERE id = ?", userID).Scan(&username, &email); err != nil {
+		log.Printf("Failed to get user %s for two-factor email: %v", userID, err)
+		return
+	}
+
+	state := "turned off"
+	if enabled {
+		state = "turned on"
+	}
+	err := s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "Two-factor authentication was " + state,
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Two-factor authentication was just %s for your MangaHub account.\n\n"+
+			"If this was not you, reset your password at %s/forgot-password.\n",
+			username, state, appURL()),
+	})
+	if err != nil {
+		log.Printf("Failed to send two-factor email to user %s: %v", userID, err)
+	}
+}
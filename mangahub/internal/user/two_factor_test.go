@@ -0,0 +1,64 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enableTestTwoFactor turns on two-factor authentication with one recovery code
+func enableTestTwoFactor(t *testing.T, s *Service, userID, recoveryCode string) {
+	t.Helper()
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+	_, err = s.db.Exec("UPDATE users SET totp_secret = ?, totp_enabled_at = ? WHERE id = ?", secret, time.Now(), userID)
+	require.NoError(t, err)
+	_, err = s.db.Exec("INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)",
+		userID, auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)), time.Now())
+	require.NoError(t, err)
+}
+
+func failedLogins(t *testing.T, s *Service, userID string) int {
+	t.Helper()
+	status, err := s.GetAccountLockStatus(userID)
+	require.NoError(t, err)
+	return status.FailedLoginCount
+}
+
+func TestRequireSecondFactorSettlesTheReservedAttempt(t *testing.T) {
+	s := newTestService(t)
+	userID := createTestUser(t, s.db, "reader")
+	enableTestTwoFactor(t, s, userID, "abcde-fghjk")
+	client := models.SessionClient{IPAddress: "203.0.113.7"}
+
+	// A wrong code is a failed login
+	err := s.RequireSecondFactor(userID, "000000", client)
+	assert.ErrorIs(t, err, ErrInvalidSecondFactor)
+	assert.Equal(t, 1, failedLogins(t, s, userID))
+	attempts, _, err := s.GetLoginAttempts(userID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, LoginInvalidSecondFactor, attempts[0].Outcome)
+
+	// A recovery code passes and is used up
+	require.NoError(t, s.RequireSecondFactor(userID, "ABCDE-FGHJK", client))
+	assert.Equal(t, 1, failedLogins(t, s, userID), "a right code leaves earlier failures alone")
+	assert.ErrorIs(t, s.RequireSecondFactor(userID, "abcde-fghjk", client), ErrInvalidSecondFactor)
+	assert.Equal(t, 2, failedLogins(t, s, userID))
+
+	// A code that cannot be checked is not a failure, and the reservation is taken back
+	_, err = s.db.Exec("DROP TABLE totp_recovery_codes")
+	require.NoError(t, err)
+	err = s.RequireSecondFactor(userID, "abcde-fghjk", client)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidSecondFactor)
+	assert.Equal(t, 2, failedLogins(t, s, userID))
+	_, total, err := s.GetLoginAttempts(userID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
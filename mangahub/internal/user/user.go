@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"mangahub/internal/activity"
 	"mangahub/internal/auth"
 	"mangahub/internal/external"
+	"mangahub/internal/mailer"
 	"mangahub/pkg/database"
 	"mangahub/pkg/models"
 	"strconv"
@@ -19,6 +21,7 @@ import (
 type Service struct {
 	db        *sql.DB
 	malClient *external.MALClient
+	mailer    mailer.Mailer
 }
 
 // NewService creates a new user service
@@ -26,11 +29,12 @@ func NewService() *Service {
 	return &Service{
 		db:        database.GetDB(),
 		malClient: external.NewMALClient(),
+		mailer:    mailer.NewFromEnv(),
 	}
 }
 
 // Register creates a new user account
-func (s *Service) Register(req models.UserRegistration) (*models.AuthResponse, error) {
+func (s *Service) Register(req models.UserRegistration, client models.SessionClient) (*models.AuthResponse, error) {
 	// Check if username or email already exists
 	var exists bool
 	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = ? OR email = ?)",
@@ -63,10 +67,20 @@ func (s *Service) Register(req models.UserRegistration) (*models.AuthResponse, e
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(userID, req.Username, req.Email)
+	// Ask the user to confirm the address
+	go func() {
+		if err := s.SendVerificationEmail(userID); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", userID, err)
+		}
+	}()
+
+	// Open a session for the new account
+	if client.DeviceName == "" {
+		client.DeviceName = req.DeviceName
+	}
+	tokens, err := s.createSession(userID, req.Username, req.Email, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
 	// Return response
@@ -74,54 +88,93 @@ func (s *Service) Register(req models.UserRegistration) (*models.AuthResponse, e
 		ID:        userID,
 		Username:  req.Username,
 		Email:     req.Email,
+		Roles:     []string{auth.RoleUser},
 		CreatedAt: time.Now(),
 	}
 
 	return &models.AuthResponse{
-		User:  userResponse,
-		Token: token,
+		User:          userResponse,
+		SessionTokens: *tokens,
 	}, nil
 }
 
-// Login authenticates a user and returns a token
-func (s *Service) Login(req models.UserLogin) (*models.LoginResponse, error) {
-	var user models.User
+// Login authenticates a user and opens a session for the client. When the
+// account has two-factor authentication, no session is opened yet: a login
+// challenge is returned instead, completed by CompleteTwoFactorLogin.
+// Every attempt is recorded; after repeated failures of the account or the
+// client's address, attempts are refused with a LoginThrottledError.
+func (s *Service) Login(req models.UserLogin, client models.SessionClient) (*models.LoginResponse, *models.LoginChallenge, error) {
+	var userID, passwordHash string
+	var totpEnabledAt sql.NullTime
 
 	// Get user from database by email or username
 	err := s.db.QueryRow(`
-		SELECT id, username, email, password_hash, created_at 
+		SELECT id, password_hash, totp_enabled_at
 		FROM users WHERE email = ? OR username = ?`, req.Email, req.Email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt)
+		&userID, &passwordHash, &totpEnabledAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
 
+	// Addresses that failed too often wait before trying any account, and
+	// locked accounts are refused before the password is checked. The attempt
+	// is counted as failed until the password turns out right, so concurrent
+	// guesses cannot get past the lockout.
+	now := time.Now()
+	before, err := s.throttleLogin(userID, req.Email, client, now)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("invalid email/username or password")
-		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, err
+	}
+	if userID == "" {
+		s.recordLoginAttempt("", req.Email, client, LoginUnknownUser)
+		return nil, nil, fmt.Errorf("invalid email/username or password")
 	}
 
 	// Verify password
-	if err := auth.VerifyPassword(user.PasswordHash, req.Password); err != nil {
-		return nil, fmt.Errorf("invalid email/username or password")
+	if err := auth.VerifyPassword(passwordHash, req.Password); err != nil {
+		s.recordLoginFailure(userID, req.Email, client, LoginInvalidPassword)
+		return nil, nil, fmt.Errorf("invalid email/username or password")
+	}
+
+	if totpEnabledAt.Valid {
+		// Not a failure, but not a login yet either: failures stay as they were
+		s.releaseLoginAttempt(userID, before, now)
+		challenge, err := newLoginChallenge(userID, passwordHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.recordLoginAttempt(userID, req.Email, client, LoginChallenged)
+		return nil, challenge, nil
+	}
+
+	if client.DeviceName == "" {
+		client.DeviceName = req.DeviceName
+	}
+	response, err := s.openLoginSession(userID, client)
+	if err != nil {
+		s.releaseLoginAttempt(userID, before, now)
+		return nil, nil, err
 	}
+	s.recordLoginSuccess(userID, req.Email, client, before)
+	return response, nil, nil
+}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Username, user.Email)
+// openLoginSession opens a session with a short-lived access token and a
+// refresh token for a user who passed every login step
+func (s *Service) openLoginSession(userID string, client models.SessionClient) (*models.LoginResponse, error) {
+	userResponse, err := s.GetProfile(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
-	// Return response
-	userResponse := models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+	tokens, err := s.createSession(userResponse.ID, userResponse.Username, userResponse.Email, client)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.LoginResponse{
-		User:  userResponse,
-		Token: token,
+		User:          *userResponse,
+		SessionTokens: *tokens,
 	}, nil
 }
 
@@ -129,10 +182,11 @@ func (s *Service) Login(req models.UserLogin) (*models.LoginResponse, error) {
 func (s *Service) GetProfile(userID string) (*models.UserResponse, error) {
 	var user models.User
 
+	var emailVerifiedAt, totpEnabledAt sql.NullTime
 	err := s.db.QueryRow(`
-		SELECT id, username, email, created_at 
+		SELECT id, username, email, created_at, email_verified_at, totp_enabled_at
 		FROM users WHERE id = ?`, userID).Scan(
-		&user.ID, &user.Username, &user.Email, &user.CreatedAt)
+		&user.ID, &user.Username, &user.Email, &user.CreatedAt, &emailVerifiedAt, &totpEnabledAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -141,19 +195,41 @@ func (s *Service) GetProfile(userID string) (*models.UserResponse, error) {
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
 
+	roles, err := auth.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Roles:            roles,
+		CreatedAt:        user.CreatedAt,
+		EmailVerified:    emailVerifiedAt.Valid,
+		TwoFactorEnabled: totpEnabledAt.Valid,
 	}, nil
 }
 
+// GetProfileByUsername returns user profile information looked up by username
+func (s *Service) GetProfileByUsername(username string) (*models.UserResponse, error) {
+	var userID string
+	err := s.db.QueryRow("SELECT id FROM users WHERE username = ?", username).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.GetProfile(userID)
+}
+
 // GetLibrary returns user's manga library organized by status
 func (s *Service) GetLibrary(userID string) (*models.UserLibrary, error) {
 	// Use LEFT JOIN to include external manga that aren't in local manga table
 	rows, err := s.db.Query(`
-		SELECT up.manga_id, up.current_chapter, up.status, up.last_updated,
+		SELECT up.manga_id, up.current_chapter, up.status, up.last_updated, `+progressDetailColumns+`,
 			   m.title, m.author, m.cover_url
 		FROM user_progress up
 		LEFT JOIN manga m ON up.manga_id = m.id
@@ -176,16 +252,19 @@ func (s *Service) GetLibrary(userID string) (*models.UserLibrary, error) {
 
 	for rows.Next() {
 		var progress models.UserProgress
+		var details progressDetails
 		var title, author, coverURL sql.NullString
 
-		err := rows.Scan(&progress.MangaID, &progress.CurrentChapter, &progress.Status,
-			&progress.LastUpdated, &title, &author, &coverURL)
+		dest := []interface{}{&progress.MangaID, &progress.CurrentChapter, &progress.Status, &progress.LastUpdated}
+		dest = append(dest, details.dest()...)
+		err := rows.Scan(append(dest, &title, &author, &coverURL)...)
 		if err != nil {
 			log.Printf("Error scanning progress row: %v", err)
 			continue
 		}
 
 		progress.UserID = userID
+		details.apply(&progress)
 
 		// If manga details are null (external manga), fetch from MAL API
 		if !title.Valid && strings.HasPrefix(progress.MangaID, "mal-") {
@@ -247,33 +326,13 @@ func (s *Service) GetLibrary(userID string) (*models.UserLibrary, error) {
 
 // AddToLibrary adds a manga to user's library
 func (s *Service) AddToLibrary(userID string, req models.AddToLibraryRequest) error {
-	// Only check if manga exists for local manga (not external like MAL or MangaDex)
-	// External manga IDs start with "mal-" or "mangadex-"
-	isExternalManga := strings.HasPrefix(req.MangaID, "mal-") || strings.HasPrefix(req.MangaID, "mangadex-")
-
-	if !isExternalManga {
-		// Check if local manga exists
-		var exists bool
-		err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", req.MangaID).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("failed to check manga existence: %w", err)
-		}
-		if !exists {
-			return fmt.Errorf("manga not found")
-		}
+	if err := checkLibraryManga(s.db, req.MangaID); err != nil {
+		return err
 	}
 
-	// Insert or update user progress
-	_, err := s.db.Exec(`
-		INSERT INTO user_progress (user_id, manga_id, status, last_updated)
-		VALUES (?, ?, ?, ?)
-		ON CONFLICT(user_id, manga_id) DO UPDATE SET
-			status = excluded.status,
-			last_updated = excluded.last_updated`,
-		userID, req.MangaID, req.Status, time.Now())
-
-	if err != nil {
-		return fmt.Errorf("failed to add manga to library: %w", err)
+	// Insert or update user progress, keeping the current chapter
+	if _, err := saveProgress(s.db, userID, req.MangaID, -1, req.Status); err != nil {
+		return err
 	}
 
 	return nil
@@ -282,37 +341,13 @@ func (s *Service) AddToLibrary(userID string, req models.AddToLibraryRequest) er
 // UpdateProgress updates user's reading progress for a manga
 // If the manga is not in the user's library, it will be added automatically
 func (s *Service) UpdateProgress(userID string, req models.UpdateProgressRequest) error {
-	// Check if user has this manga in their library
-	var exists bool
-	err := s.db.QueryRow(`
-		SELECT EXISTS(SELECT 1 FROM user_progress WHERE user_id = ? AND manga_id = ?)`,
-		userID, req.MangaID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("failed to check progress existence: %w", err)
+	// Start/finish dates and the re-read count follow the status change
+	if _, err := saveProgress(s.db, userID, req.MangaID, req.CurrentChapter, req.Status); err != nil {
+		return err
 	}
 
-	if !exists {
-		// Auto-add manga to library with the provided status
-		_, err = s.db.Exec(`
-			INSERT INTO user_progress (user_id, manga_id, current_chapter, status, added_at, last_updated)
-			VALUES (?, ?, ?, ?, ?, ?)`,
-			userID, req.MangaID, req.CurrentChapter, req.Status, time.Now(), time.Now())
-		if err != nil {
-			return fmt.Errorf("failed to add manga to library: %w", err)
-		}
-		return nil
-	}
-
-	// Update progress
-	_, err = s.db.Exec(`
-		UPDATE user_progress 
-		SET current_chapter = ?, status = ?, last_updated = ?
-		WHERE user_id = ? AND manga_id = ?`,
-		req.CurrentChapter, req.Status, time.Now(), userID, req.MangaID)
-
-	if err != nil {
-		return fmt.Errorf("failed to update progress: %w", err)
-	}
+	// Push the change to the linked MAL account, if any
+	go s.PushProgressToMAL(userID, req.MangaID)
 
 	return nil
 }
@@ -389,8 +424,24 @@ func (s *Service) GetLibraryStats(userID string) (*models.LibraryStatsResponse,
 	return &stats, nil
 }
 
+// librarySortKeys maps sort_by values to a column and its default direction
+var librarySortKeys = map[string]struct {
+	column string
+	desc   bool
+}{
+	"title":    {"m.title", false},
+	"author":   {"m.author", false},
+	"progress": {"up.current_chapter", true},
+	"updated":  {"up.last_updated", true},
+	"started":  {"up.started_at", true},
+	"finished": {"up.finished_at", true},
+	"rereads":  {"up.reread_count", true},
+	"status":   {"up.status", false},
+}
+
 // GetFilteredLibrary returns user's library with filtering and sorting
-func (s *Service) GetFilteredLibrary(userID string, status string, sortBy string, limit, offset int) ([]models.UserProgress, error) {
+func (s *Service) GetFilteredLibrary(userID string, filter models.LibraryFilterRequest) ([]models.UserProgress, error) {
+	limit, offset := filter.Limit, filter.Offset
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
@@ -399,32 +450,71 @@ func (s *Service) GetFilteredLibrary(userID string, status string, sortBy string
 	}
 
 	query := `
-		SELECT up.manga_id, up.current_chapter, up.status, up.last_updated,
-			   m.title, m.author, m.cover_url, m.total_chapters
+		SELECT up.manga_id, up.current_chapter, up.status, up.last_updated, ` + progressDetailColumns + `,
+			   m.title, COALESCE(m.author, ''), COALESCE(m.cover_url, '')
 		FROM user_progress up
 		JOIN manga m ON up.manga_id = m.id
 		WHERE up.user_id = ?`
 	args := []interface{}{userID}
 
 	// Add status filter if specified
-	if status != "" {
+	if filter.Status != "" {
 		query += ` AND up.status = ?`
-		args = append(args, status)
+		args = append(args, filter.Status)
+	}
+
+	// Personal detail filters
+	if tag := strings.TrimSpace(filter.Tag); tag != "" {
+		query += ` AND EXISTS (SELECT 1 FROM json_each(COALESCE(up.tags, '[]')) t WHERE LOWER(t.value) = LOWER(?))`
+		args = append(args, tag)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		query += ` AND (m.title LIKE ? OR up.notes LIKE ?)`
+		args = append(args, "%"+search+"%", "%"+search+"%")
+	}
+	if filter.HasNotes {
+		query += ` AND COALESCE(up.notes, '') != ''`
+	}
+	if filter.MinRereads > 0 {
+		query += ` AND up.reread_count >= ?`
+		args = append(args, filter.MinRereads)
+	}
+	dateFilters := []struct {
+		name, value, condition string
+	}{
+		{"started_after", filter.StartedAfter, `date(up.started_at) >= ?`},
+		{"started_before", filter.StartedBefore, `date(up.started_at) <= ?`},
+		{"finished_after", filter.FinishedAfter, `date(up.finished_at) >= ?`},
+		{"finished_before", filter.FinishedBefore, `date(up.finished_at) <= ?`},
+	}
+	for _, f := range dateFilters {
+		date, err := parseLibraryDate(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", f.name, err)
+		}
+		if date != nil {
+			query += ` AND ` + f.condition
+			args = append(args, date.Format(libraryDateFormat))
+		}
 	}
 
-	// Add sorting
-	switch sortBy {
-	case "title":
-		query += ` ORDER BY m.title`
-	case "author":
-		query += ` ORDER BY m.author`
-	case "progress":
-		query += ` ORDER BY up.current_chapter DESC`
-	case "updated":
-		query += ` ORDER BY up.last_updated DESC`
-	default:
-		query += ` ORDER BY up.last_updated DESC`
+	// Add sorting, entries without a value for the sort key come last
+	sortKey, ok := librarySortKeys[filter.SortBy]
+	if !ok {
+		sortKey = librarySortKeys["updated"]
+	}
+	desc := sortKey.desc
+	switch strings.ToLower(filter.Order) {
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
 	}
+	query += fmt.Sprintf(` ORDER BY %[1]s IS NULL, %[1]s %[2]s, m.title`, sortKey.column, direction)
 
 	query += ` LIMIT ? OFFSET ?`
 	args = append(args, limit, offset)
@@ -438,17 +528,18 @@ func (s *Service) GetFilteredLibrary(userID string, status string, sortBy string
 	var progressList []models.UserProgress
 	for rows.Next() {
 		var progress models.UserProgress
-		var title, author, coverURL string
-		var totalChapters int
+		var details progressDetails
 
-		err := rows.Scan(&progress.MangaID, &progress.CurrentChapter, &progress.Status,
-			&progress.LastUpdated, &title, &author, &coverURL, &totalChapters)
+		dest := []interface{}{&progress.MangaID, &progress.CurrentChapter, &progress.Status, &progress.LastUpdated}
+		dest = append(dest, details.dest()...)
+		err := rows.Scan(append(dest, &progress.Title, &progress.Author, &progress.CoverURL)...)
 		if err != nil {
 			log.Printf("Error scanning progress row: %v", err)
 			continue
 		}
 
 		progress.UserID = userID
+		details.apply(&progress)
 		progressList = append(progressList, progress)
 	}
 
@@ -463,18 +554,6 @@ func (s *Service) BatchUpdateProgress(userID string, updates []models.UpdateProg
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO user_progress (user_id, manga_id, current_chapter, status, last_updated)
-		VALUES (?, ?, ?, ?, ?)
-		ON CONFLICT(user_id, manga_id) DO UPDATE SET
-			current_chapter = excluded.current_chapter,
-			status = excluded.status,
-			last_updated = excluded.last_updated`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer stmt.Close()
-
 	for _, update := range updates {
 		// Check if manga exists
 		var exists bool
@@ -487,8 +566,7 @@ func (s *Service) BatchUpdateProgress(userID string, updates []models.UpdateProg
 		}
 
 		// Execute update
-		_, err = stmt.Exec(userID, update.MangaID, update.CurrentChapter, update.Status, time.Now())
-		if err != nil {
+		if _, err := saveProgress(tx, userID, update.MangaID, update.CurrentChapter, update.Status); err != nil {
 			return fmt.Errorf("failed to update progress for manga %s: %w", update.MangaID, err)
 		}
 	}
@@ -497,6 +575,13 @@ func (s *Service) BatchUpdateProgress(userID string, updates []models.UpdateProg
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	// Push the changes to the linked MAL account, if any
+	mangaIDs := make([]string, len(updates))
+	for i, update := range updates {
+		mangaIDs[i] = update.MangaID
+	}
+	go s.PushProgressToMAL(userID, mangaIDs...)
+
 	return nil
 }
 
@@ -516,81 +601,27 @@ func (s *Service) RemoveFromLibrary(userID, mangaID string) error {
 		return fmt.Errorf("manga not found in user's library")
 	}
 
-	return nil
-}
-
-// GetReadingRecommendations returns manga recommendations based on user's library
-func (s *Service) GetReadingRecommendations(userID string, limit int) ([]models.Manga, error) {
-	if limit <= 0 || limit > 20 {
-		limit = 10
-	}
-
-	// Simple recommendation: suggest manga with similar genres to user's completed/reading manga
-	query := `
-		SELECT DISTINCT m.id, m.title, m.author, m.genres, m.status, 
-			   m.total_chapters, m.description, m.cover_url, m.created_at
-		FROM manga m
-		WHERE m.id NOT IN (
-			SELECT manga_id FROM user_progress WHERE user_id = ?
-		)
-		AND EXISTS (
-			SELECT 1 FROM user_progress up 
-			JOIN manga um ON up.manga_id = um.id 
-			WHERE up.user_id = ? 
-			AND up.status IN ('reading', 'completed')
-			AND (
-				m.genres LIKE '%' || JSON_EXTRACT(um.genres, '$[0]') || '%' OR
-				m.genres LIKE '%' || JSON_EXTRACT(um.genres, '$[1]') || '%'
-			)
-		)
-		ORDER BY m.title
-		LIMIT ?`
-
-	rows, err := s.db.Query(query, userID, userID, limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get recommendations: %w", err)
-	}
-	defer rows.Close()
-
-	var recommendations []models.Manga
-	for rows.Next() {
-		var manga models.Manga
-		err := rows.Scan(&manga.ID, &manga.Title, &manga.Author, &manga.GenresJSON,
-			&manga.Status, &manga.TotalChapters, &manga.Description,
-			&manga.CoverURL, &manga.CreatedAt)
-		if err != nil {
-			log.Printf("Error scanning recommendation row: %v", err)
-			continue
-		}
-
-		// Parse genres
-		if err := manga.GetGenres(); err != nil {
-			log.Printf("Error parsing genres for manga %s: %v", manga.ID, err)
-			manga.Genres = []string{}
-		}
-
-		recommendations = append(recommendations, manga)
-	}
-
-	return recommendations, nil
+	return activity.RecordRemoved(s.db, userID, mangaID)
 }
 
 // GetUserProgress retrieves user's reading progress for a specific manga (for TCP endpoint)
 func (s *Service) GetUserProgress(userID, mangaID string) (*models.UserProgress, error) {
 	query := `
-		SELECT user_id, manga_id, current_chapter, status, last_updated
-		FROM user_progress
-		WHERE user_id = ? AND manga_id = ?
+		SELECT up.user_id, up.manga_id, up.current_chapter, up.status, up.last_updated, ` + progressDetailColumns + `
+		FROM user_progress up
+		WHERE up.user_id = ? AND up.manga_id = ?
 	`
 
 	var progress models.UserProgress
-	err := s.db.QueryRow(query, userID, mangaID).Scan(
+	var details progressDetails
+	dest := []interface{}{
 		&progress.UserID,
 		&progress.MangaID,
 		&progress.CurrentChapter,
 		&progress.Status,
 		&progress.LastUpdated,
-	)
+	}
+	err := s.db.QueryRow(query, userID, mangaID).Scan(append(dest, details.dest()...)...)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // User hasn't started reading yet
@@ -600,6 +631,7 @@ func (s *Service) GetUserProgress(userID, mangaID string) (*models.UserProgress,
 		return nil, fmt.Errorf("failed to get user progress: %w", err)
 	}
 
+	details.apply(&progress)
 	return &progress, nil
 }
 
@@ -635,9 +667,19 @@ func (s *Service) UpdateProfile(userID string, username, email string) (*models.
 		args = append(args, username)
 	}
 
+	emailChanged := false
 	if email != "" {
+		var currentEmail string
+		if err := s.db.QueryRow("SELECT email FROM users WHERE id = ?", userID).Scan(&currentEmail); err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
 		updates = append(updates, "email = ?")
 		args = append(args, email)
+		if !strings.EqualFold(currentEmail, email) {
+			// The new address has to be verified again
+			updates = append(updates, "email_verified_at = NULL")
+			emailChanged = true
+		}
 	}
 
 	if len(updates) == 0 {
@@ -653,12 +695,19 @@ func (s *Service) UpdateProfile(userID string, username, email string) (*models.
 		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	if emailChanged {
+		if err := s.SendVerificationEmail(userID); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", userID, err)
+		}
+	}
+
 	// Get updated profile
 	return s.GetProfile(userID)
 }
 
-// ChangePassword changes a user's password
-func (s *Service) ChangePassword(userID, oldPassword, newPassword string) error {
+// ChangePassword changes a user's password and signs out every session but
+// keepSessionID, the one the change was made from
+func (s *Service) ChangePassword(userID, oldPassword, newPassword, keepSessionID string) error {
 	// Get current user
 	var hashedPassword string
 	err := s.db.QueryRow("SELECT password_hash FROM users WHERE id = ?", userID).Scan(&hashedPassword)
@@ -680,11 +729,26 @@ func (s *Service) ChangePassword(userID, oldPassword, newPassword string) error
 		return fmt.Errorf("failed to hash new password: %w", err)
 	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Update password
-	_, err = s.db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", string(newHashedPassword), userID)
+	_, err = tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", string(newHashedPassword), userID)
 	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	// Whoever knew the old password is signed out, and loses the tokens they made
+	if err := revokeCredentials(tx, userID, keepSessionID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.sendPasswordChangedEmail(userID)
+
 	return nil
 }
@@ -24,11 +24,12 @@ type Message struct {
 	Username  string `json:"username"`
 	Message   string `json:"message"`
 	Timestamp int64  `json:"timestamp"`
-	Type      string `json:"type"` // "message", "join", "leave", "user_list", "notification", "progress_update"
+	Type      string `json:"type"` // "message", "join", "leave", "user_list", "notification", "progress_update", "goal_achieved"
 	Room      string `json:"room,omitempty"`
 	Users     []User `json:"users,omitempty"`
 	MangaID   string `json:"manga_id,omitempty"` // For notifications and progress updates
 	Chapter   int    `json:"chapter,omitempty"`  // For progress updates
+	Recipient string `json:"-"`                  // If set, only this user's clients get the message
 }
 
 // User represents user information in the chat
@@ -196,6 +197,9 @@ func (r *ChatRoom) Run() {
 			r.mu.RLock()
 			clients := make(map[*websocket.Conn]*ClientConnection)
 			for conn, client := range r.Clients {
+				if message.Recipient != "" && client.UserID != message.Recipient {
+					continue
+				}
 				clients[conn] = client
 			}
 			r.mu.RUnlock()
@@ -234,8 +238,8 @@ func (r *ChatRoom) Run() {
 				r.addToHistory(message)
 			case "progress_update":
 				log.Printf("[Room %s] Broadcasted progress update from %s (chapter %d) to %d clients", r.RoomID, message.Username, message.Chapter, successCount)
-			case "notification":
-				log.Printf("[Room %s] Broadcasted notification to %d clients: %s", r.RoomID, successCount, message.Message)
+			case "notification", "goal_achieved":
+				log.Printf("[Room %s] Broadcasted %s to %d clients: %s", r.RoomID, message.Type, successCount, message.Message)
 			case "join", "leave":
 				log.Printf("[Room %s] Broadcasted %s event for %s to %d clients", r.RoomID, message.Type, message.Username, successCount)
 			case "user_list":
@@ -493,6 +497,45 @@ func (h *ChatHub) BroadcastNotification(mangaID, notificationType, message strin
 	}
 }
 
+// SendGoalAchieved announces a reached reading goal to the user's own
+// WebSocket clients, in whichever rooms they are connected
+func (h *ChatHub) SendGoalAchieved(userID, username, message string) {
+	notification := Message{
+		Type:      "goal_achieved",
+		UserID:    userID,
+		Username:  username,
+		Message:   message,
+		Timestamp: time.Now().Unix(),
+		Recipient: userID,
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, room := range h.Rooms {
+		if !room.hasUser(userID) {
+			continue
+		}
+		select {
+		case room.Broadcast <- notification:
+			// Queued successfully - actual broadcast logged in Run()
+		default:
+			log.Printf("Warning: broadcast channel full for room %s, dropping goal notification", room.RoomID)
+		}
+	}
+}
+
+// hasUser reports whether the user has a client connected to the room
+func (r *ChatRoom) hasUser(userID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, client := range r.Clients {
+		if client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // BroadcastProgressUpdate sends a progress update to all connected WebSocket clients in the manga's chat room
 func (h *ChatHub) BroadcastProgressUpdate(userID, username, mangaID string, chapter int) {
 	// Get the specific manga's chat room if it exists
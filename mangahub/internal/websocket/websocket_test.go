@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialRoom connects a client as the given user to a room of the hub
+func dialRoom(t *testing.T, serverURL, userID, room string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(serverURL, "http") + "/ws?room=" + room + "&user=" + userID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// nextOfType returns the next message of the type, or false if none arrives in time
+func nextOfType(conn *websocket.Conn, messageType string) (Message, bool) {
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return Message{}, false
+		}
+		var message Message
+		if json.Unmarshal(data, &message) == nil && message.Type == messageType {
+			return message, true
+		}
+	}
+}
+
+func TestSendGoalAchievedReachesOnlyTheUsersClients(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := NewChatHub()
+	router := gin.New()
+	router.GET("/ws", func(c *gin.Context) {
+		c.Set("user_id", c.Query("user"))
+		c.Set("username", c.Query("user"))
+	}, HandleWebSocketChat(hub, websocket.Upgrader{}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	aliceChat := dialRoom(t, server.URL, "alice", "manga-1")
+	aliceGlobal := dialRoom(t, server.URL, "alice", "global-notifications")
+	bob := dialRoom(t, server.URL, "bob", "global-notifications")
+	time.Sleep(100 * time.Millisecond)
+
+	hub.SendGoalAchieved("alice", "alice", `You reached the goal "50 chapters" (50/50)`)
+
+	for _, conn := range []*websocket.Conn{aliceChat, aliceGlobal} {
+		message, ok := nextOfType(conn, "goal_achieved")
+		require.True(t, ok)
+		assert.Equal(t, "alice", message.UserID)
+	}
+	_, ok := nextOfType(bob, "goal_achieved")
+	assert.False(t, ok, "another user's goal must not be delivered")
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -41,25 +42,39 @@ func findProjectRoot() (string, error) {
 	}
 }
 
-// InitDatabase initializes the SQLite database connection and creates tables
-func InitDatabase() error {
+// DataDir returns the data directory at the project root (database, fixtures)
+func DataDir() (string, error) {
 	// Find project root (where go.mod is located)
 	projectRoot, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to find project root: %w", err)
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+	return filepath.Join(projectRoot, "data"), nil
+}
+
+// InitDatabase initializes the SQLite database connection and creates tables
+func InitDatabase() error {
+	dataDir, err := DataDir()
+	if err != nil {
+		return err
 	}
 
 	// Ensure data directory exists at project root
-	dataDir := filepath.Join(projectRoot, "data")
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
 	// Database file path - always at project root
-	dbPath := filepath.Join(dataDir, "mangahub.db")
+	return OpenDatabase(filepath.Join(dataDir, "mangahub.db"))
+}
+
+// OpenDatabase opens the SQLite database at dbPath, e.g. a temporary one in
+// tests, and creates or upgrades its tables
+func OpenDatabase(dbPath string) error {
 	log.Printf("Using database at: %s", dbPath)
 
 	// Open database connection
+	var err error
 	DB, err = sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -75,6 +90,21 @@ func InitDatabase() error {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	// Move ratings of older databases to the 1-10 scale
+	if err = migrateRatingScale(); err != nil {
+		return fmt.Errorf("failed to migrate ratings: %w", err)
+	}
+
+	// Add columns introduced after the tables were first created
+	if err = addMissingColumns(); err != nil {
+		return fmt.Errorf("failed to upgrade tables: %w", err)
+	}
+
+	// Record library changes for delta sync
+	if err = createSyncTriggers(); err != nil {
+		return fmt.Errorf("failed to create sync triggers: %w", err)
+	}
+
 	log.Println("Database initialized successfully")
 	return nil
 }
@@ -88,7 +118,14 @@ func createTables() error {
 			username TEXT UNIQUE NOT NULL,
 			email TEXT UNIQUE NOT NULL,
 			password_hash TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			email_verified_at TIMESTAMP, -- NULL until the emailed link is followed, reset when the email changes
+			totp_secret TEXT, -- Base32, set while enrolling and while two-factor authentication is on
+			totp_enabled_at TIMESTAMP, -- NULL while two-factor authentication is off
+			totp_last_step INTEGER DEFAULT 0, -- Time step of the last accepted code, so codes are not replayed
+			failed_login_count INTEGER DEFAULT 0, -- Failed logins since the last successful one or lockout
+			last_failed_login_at TIMESTAMP,
+			locked_until TIMESTAMP -- Logins are refused until then
 		)`,
 
 		// Manga table
@@ -102,6 +139,7 @@ func createTables() error {
 			description TEXT,
 			cover_url TEXT,
 			publication_year INTEGER,
+			tags TEXT DEFAULT '[]', -- JSON array of themes and demographics
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
@@ -112,23 +150,20 @@ func createTables() error {
 			current_chapter INTEGER DEFAULT 0,
 			status TEXT DEFAULT 'plan_to_read', -- reading, completed, plan_to_read, dropped
 			last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			notes TEXT DEFAULT '', -- private notes
+			tags TEXT DEFAULT '[]', -- JSON array of personal tags
+			started_at TIMESTAMP,
+			finished_at TIMESTAMP,
+			reread_count INTEGER DEFAULT 0,
+			clock INTEGER DEFAULT 0, -- Logical clock of the last write, see progress_ops
+			clock_device TEXT DEFAULT '', -- Device of the last progress op, '' for other writes
 			PRIMARY KEY (user_id, manga_id),
 			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
 			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
 		)`,
 
 		// Manga ratings table
-		`CREATE TABLE IF NOT EXISTS manga_ratings (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			user_id TEXT NOT NULL,
-			manga_id TEXT NOT NULL,
-			rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(user_id, manga_id),
-			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
-		)`,
+		fmt.Sprintf(mangaRatingsTable, "manga_ratings"),
 
 		// Manga chapters table - stores chapter metadata from MangaDex/MangaPlus
 		`CREATE TABLE IF NOT EXISTS manga_chapters (
@@ -158,6 +193,370 @@ func createTables() error {
 			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
 		)`,
 
+		// HTTP response cache for external API lookups (Jikan, MAL, MangaDex)
+		`CREATE TABLE IF NOT EXISTS http_cache (
+			cache_key TEXT PRIMARY KEY, -- sha256 of namespace, method and URL
+			namespace TEXT NOT NULL, -- 'jikan', 'mal', 'mangadex'
+			url TEXT NOT NULL,
+			status_code INTEGER NOT NULL,
+			headers TEXT, -- JSON object as text
+			body BLOB,
+			etag TEXT DEFAULT '',
+			last_modified TEXT DEFAULT '',
+			fetched_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		)`,
+
+		// Linked MyAnimeList accounts with OAuth2 tokens
+		`CREATE TABLE IF NOT EXISTS mal_accounts (
+			user_id TEXT PRIMARY KEY,
+			mal_user_id INTEGER NOT NULL,
+			mal_username TEXT NOT NULL,
+			access_token TEXT NOT NULL,
+			refresh_token TEXT NOT NULL,
+			token_expires_at TIMESTAMP NOT NULL,
+			linked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_import_at TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Pending MAL OAuth2 authorization requests (PKCE verifier per state)
+		`CREATE TABLE IF NOT EXISTS mal_oauth_states (
+			state TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			code_verifier TEXT NOT NULL,
+			redirect_uri TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Last known MAL state of each synced library entry
+		`CREATE TABLE IF NOT EXISTS mal_list_sync (
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			mal_id INTEGER NOT NULL,
+			mal_updated_at TIMESTAMP, -- updated_at reported by MAL at last sync
+			last_synced_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, manga_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Imported list entries (MAL XML, AniList JSON) that matched no local manga
+		`CREATE TABLE IF NOT EXISTS library_import_review (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			source TEXT NOT NULL, -- 'mal', 'anilist', 'mangadex', 'tachiyomi'
+			source_id TEXT NOT NULL DEFAULT '',
+			mal_id TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			current_chapter INTEGER DEFAULT 0,
+			score INTEGER DEFAULT 0, -- 0-10 as in the imported list, 0 when unscored
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, source, source_id, title),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// User collections: named, manually ordered lists of manga
+		`CREATE TABLE IF NOT EXISTS collections (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			description TEXT DEFAULT '',
+			visibility TEXT NOT NULL DEFAULT 'private' CHECK(visibility IN ('private', 'unlisted', 'public')),
+			share_token TEXT UNIQUE NOT NULL, -- read-only link for unlisted and public collections
+			sort_order INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, name),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS collection_items (
+			collection_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			position INTEGER NOT NULL DEFAULT 0,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (collection_id, manga_id),
+			FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
+		)`,
+
+		// Per-chapter read state
+		`CREATE TABLE IF NOT EXISTS user_read_chapters (
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			chapter_number REAL NOT NULL,
+			source_chapter_id TEXT DEFAULT '', -- e.g. MangaDex chapter UUID
+			read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, manga_id, chapter_number),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Append-only reading activity log, written by every progress, status and rating change
+		`CREATE TABLE IF NOT EXISTS reading_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			event_type TEXT NOT NULL, -- added, progress, status, removed, rating, rating_removed
+			source TEXT NOT NULL DEFAULT 'user', -- user, import, mal_sync
+			chapter_from INTEGER,
+			chapter_to INTEGER,
+			status_from TEXT,
+			status_to TEXT,
+			rating INTEGER,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// User-defined reading goals, evaluated against reading_events
+		`CREATE TABLE IF NOT EXISTS reading_goals (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			goal_type TEXT NOT NULL CHECK (goal_type IN ('chapters', 'completed', 'genre')),
+			genre TEXT,
+			target INTEGER NOT NULL CHECK (target > 0),
+			starts_on TEXT NOT NULL,
+			ends_on TEXT NOT NULL,
+			achieved_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Item-item similarity for recommendations, recomputed periodically from
+		// user_progress and manga_ratings
+		`CREATE TABLE IF NOT EXISTS manga_similarity (
+			manga_id TEXT NOT NULL,
+			similar_id TEXT NOT NULL,
+			score REAL NOT NULL,
+			common_readers INTEGER NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (manga_id, similar_id)
+		)`,
+
+		// Content-based nearest neighbours, rebuilt after each catalog sync
+		`CREATE TABLE IF NOT EXISTS similar_manga (
+			manga_id TEXT NOT NULL,
+			similar_id TEXT NOT NULL,
+			score REAL NOT NULL,
+			PRIMARY KEY (manga_id, similar_id)
+		)`,
+
+		// Popularity and time-decayed trending scores, materialized on a schedule
+		// from user_progress, manga_ratings and reading_events
+		`CREATE TABLE IF NOT EXISTS manga_popularity (
+			manga_id TEXT PRIMARY KEY,
+			library_count INTEGER NOT NULL DEFAULT 0,
+			active_readers INTEGER NOT NULL DEFAULT 0,
+			rating_count INTEGER NOT NULL DEFAULT 0,
+			average_rating REAL NOT NULL DEFAULT 0,
+			chapters_read INTEGER NOT NULL DEFAULT 0,
+			popularity REAL NOT NULL DEFAULT 0,
+			trending_24h REAL NOT NULL DEFAULT 0,
+			trending_7d REAL NOT NULL DEFAULT 0,
+			trending_30d REAL NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Written reviews, one per user and manga, attached to the user's rating
+		`CREATE TABLE IF NOT EXISTS manga_reviews (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			spoiler INTEGER NOT NULL DEFAULT 0,
+			chapter_read INTEGER NOT NULL DEFAULT 0, -- user_progress.current_chapter when written
+			status TEXT NOT NULL DEFAULT 'published', -- published, hidden
+			helpful_count INTEGER NOT NULL DEFAULT 0,
+			unhelpful_count INTEGER NOT NULL DEFAULT 0,
+			edit_count INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, manga_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+		)`,
+
+		// Helpful/unhelpful votes on reviews
+		`CREATE TABLE IF NOT EXISTS review_votes (
+			review_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			helpful INTEGER NOT NULL, -- 1 helpful, 0 unhelpful
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (review_id, user_id),
+			FOREIGN KEY (review_id) REFERENCES manga_reviews(id) ON DELETE CASCADE
+		)`,
+
+		// Previous versions of edited reviews
+		`CREATE TABLE IF NOT EXISTS review_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			review_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			spoiler INTEGER NOT NULL DEFAULT 0,
+			chapter_read INTEGER NOT NULL DEFAULT 0,
+			written_at TIMESTAMP NOT NULL, -- when this version was written
+			FOREIGN KEY (review_id) REFERENCES manga_reviews(id) ON DELETE CASCADE
+		)`,
+
+		// User reports on reviews, the admin moderation queue
+		`CREATE TABLE IF NOT EXISTS review_reports (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			review_id TEXT NOT NULL,
+			reporter_id TEXT NOT NULL,
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			resolved_at TIMESTAMP,
+			resolved_by TEXT,
+			resolution TEXT, -- dismiss, hide, restore
+			UNIQUE(review_id, reporter_id),
+			FOREIGN KEY (review_id) REFERENCES manga_reviews(id) ON DELETE CASCADE
+		)`,
+
+		// Ratings flagged by the brigading detector. Quarantined ratings
+		// (manga_ratings.quarantined) stay out of aggregates until an admin clears them.
+		`CREATE TABLE IF NOT EXISTS rating_flags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			rating INTEGER NOT NULL, -- Value when flagged
+			signals TEXT NOT NULL DEFAULT '[]', -- JSON array: new_account, burst, rating_only, correlated
+			status TEXT NOT NULL DEFAULT 'quarantined' CHECK(status IN ('quarantined', 'cleared', 'confirmed')),
+			flagged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			reviewed_at TIMESTAMP,
+			reviewed_by TEXT,
+			UNIQUE(user_id, manga_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+		)`,
+
+		// Change feed of user_progress, manga_ratings and collections for delta
+		// sync, written by the triggers of createSyncTriggers. Only the latest change
+		// of an entity is needed, so older ones are pruned.
+		`CREATE TABLE IF NOT EXISTS sync_changes (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT, -- Sync tokens point into this sequence
+			user_id TEXT NOT NULL,
+			entity TEXT NOT NULL CHECK(entity IN ('library', 'rating', 'collection')),
+			entity_id TEXT NOT NULL, -- manga_id for library and rating, collection ID
+			deleted INTEGER NOT NULL DEFAULT 0, -- Tombstone
+			changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Client-stamped progress operations already applied, so that replays
+		// from offline clients are ignored
+		`CREATE TABLE IF NOT EXISTS progress_ops (
+			user_id TEXT NOT NULL,
+			device_id TEXT NOT NULL,
+			clock INTEGER NOT NULL, -- Logical (Lamport) clock of the device
+			manga_id TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, device_id, clock)
+		)`,
+
+		// Highest clock of each device's pruned progress ops. Device clocks only
+		// go up, so ops at or below it are replays of ops no longer remembered.
+		`CREATE TABLE IF NOT EXISTS progress_op_horizons (
+			user_id TEXT NOT NULL,
+			device_id TEXT NOT NULL,
+			clock INTEGER NOT NULL,
+			PRIMARY KEY (user_id, device_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Conflicts found while merging progress operations, shown to the user
+		`CREATE TABLE IF NOT EXISTS progress_conflicts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			device_id TEXT NOT NULL,
+			clock INTEGER NOT NULL,
+			client_time TIMESTAMP,
+			requested_chapter INTEGER, -- NULL when the op left the chapter alone
+			requested_status TEXT NOT NULL DEFAULT '',
+			resolved_chapter INTEGER NOT NULL,
+			resolved_status TEXT NOT NULL,
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Login sessions. Access tokens name their session, so revoking it
+		// invalidates them; the refresh token rotates on every use.
+		`CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			refresh_token_hash TEXT NOT NULL UNIQUE,
+			previous_token_hash TEXT, -- Rotated-out refresh token; reusing it revokes the session
+			device_name TEXT DEFAULT '',
+			user_agent TEXT DEFAULT '',
+			ip_address TEXT DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Roles granted to users beyond the default user role (admin, moderator, curator)
+		`CREATE TABLE IF NOT EXISTS user_roles (
+			user_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			granted_by TEXT DEFAULT '', -- Granting admin, empty for the bootstrap admin
+			granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, role),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Personal access tokens for scripts and bots. Only the token hash is
+		// stored; token_prefix identifies it in listings.
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			token_prefix TEXT NOT NULL,
+			scopes TEXT NOT NULL, -- Space-separated
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP, -- NULL never expires
+			last_used_at TIMESTAMP,
+			revoked_at TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Single-use recovery codes for two-factor authentication, hashed
+		`CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+			user_id TEXT NOT NULL,
+			code_hash TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			used_at TIMESTAMP,
+			PRIMARY KEY (user_id, code_hash),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
+		// Keys that sign JWTs; the one not retired signs, retired ones still
+		// verify until they expire
+		`CREATE TABLE IF NOT EXISTS jwt_keys (
+			kid TEXT PRIMARY KEY,
+			algorithm TEXT NOT NULL,
+			private_key TEXT NOT NULL,
+			public_key TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			retired_at TIMESTAMP,
+			expires_at TIMESTAMP -- NULL while signing
+		)`,
+
+		// Audit trail of login attempts, also counts failures per address
+		`CREATE TABLE IF NOT EXISTS login_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT, -- NULL when no account matched
+			identifier TEXT NOT NULL DEFAULT '', -- Email or username as entered
+			ip_address TEXT NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			success INTEGER NOT NULL DEFAULT 0,
+			outcome TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+
 		// Create indexes for better performance
 		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
@@ -171,6 +570,28 @@ func createTables() error {
 		`CREATE INDEX IF NOT EXISTS idx_chapters_number ON manga_chapters(chapter_number)`,
 		`CREATE INDEX IF NOT EXISTS idx_sources_manga ON manga_sources(manga_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sources_source ON manga_sources(source)`,
+		`CREATE INDEX IF NOT EXISTS idx_http_cache_namespace ON http_cache(namespace)`,
+		`CREATE INDEX IF NOT EXISTS idx_http_cache_expires ON http_cache(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sources_source_id ON manga_sources(source, source_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_collections_visibility ON collections(visibility)`,
+		`CREATE INDEX IF NOT EXISTS idx_collection_items_position ON collection_items(collection_id, position)`,
+		`CREATE INDEX IF NOT EXISTS idx_reading_events_user ON reading_events(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_reading_goals_user ON reading_goals(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_reviews_manga ON manga_reviews(manga_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_review_revisions_review ON review_revisions(review_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_review_reports_review ON review_reports(review_id, resolved_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_rating_flags_status ON rating_flags(status, flagged_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_changes_user ON sync_changes(user_id, seq)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_changes_entity ON sync_changes(user_id, entity, entity_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_progress_ops_applied ON progress_ops(applied_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_progress_conflicts_user ON progress_conflicts(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_sessions_previous_token ON sessions(previous_token_hash)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_attempts_user ON login_attempts(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts(ip_address, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_attempts_created ON login_attempts(created_at)`,
 	}
 
 	for _, query := range queries {
@@ -182,6 +603,180 @@ func createTables() error {
 	return nil
 }
 
+// mangaRatingsTable creates a manga ratings table with the given name. Ratings
+// and the optional story, art and characters sub-scores are on a 1-10 scale.
+const mangaRatingsTable = `CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 10),
+			story INTEGER CHECK(story IS NULL OR (story >= 1 AND story <= 10)),
+			art INTEGER CHECK(art IS NULL OR (art >= 1 AND art <= 10)),
+			characters INTEGER CHECK(characters IS NULL OR (characters >= 1 AND characters <= 10)),
+			quarantined INTEGER NOT NULL DEFAULT 0, -- Flagged as manipulation, left out of aggregates
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, manga_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+		)`
+
+// migrateRatingScale moves ratings of older databases from the 1-5 scale to 1-10.
+// SQLite cannot change a CHECK constraint, so manga_ratings is rebuilt with the
+// new schema and existing ratings, including those in reading_events, are doubled.
+func migrateRatingScale() error {
+	var schema string
+	err := DB.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'manga_ratings'").Scan(&schema)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table manga_ratings: %w", err)
+	}
+	if !strings.Contains(schema, "rating <= 5") {
+		return nil
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		fmt.Sprintf(mangaRatingsTable, "manga_ratings_v2"),
+		`INSERT INTO manga_ratings_v2 (id, user_id, manga_id, rating, created_at, updated_at)
+			SELECT id, user_id, manga_id, rating * 2, created_at, updated_at FROM manga_ratings`,
+		`DROP TABLE manga_ratings`,
+		`ALTER TABLE manga_ratings_v2 RENAME TO manga_ratings`,
+		`CREATE INDEX IF NOT EXISTS idx_ratings_manga ON manga_ratings(manga_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_ratings_user ON manga_ratings(user_id)`,
+		`UPDATE reading_events SET rating = rating * 2 WHERE rating IS NOT NULL`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute query: %s, error: %w", query, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rating migration: %w", err)
+	}
+	log.Println("Migrated manga ratings to the 1-10 scale")
+	return nil
+}
+
+// syncTriggers record every write to the synced tables in sync_changes, whichever
+// code path makes it (REST, gRPC, TCP sync, imports). Changes to collection items
+// are recorded as changes of their collection. They also keep the logical clock of
+// library entries used to merge offline progress ops.
+var syncTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS sync_library_insert AFTER INSERT ON user_progress BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'library', NEW.manga_id);
+	END`,
+	// Updates of the clock alone (the bump of sync_library_clock, progress ops
+	// stamping an entry) follow a change already recorded, so they are skipped.
+	// Databases created before the condition get the trigger replaced.
+	`DROP TRIGGER IF EXISTS sync_library_update`,
+	`CREATE TRIGGER sync_library_update AFTER UPDATE ON user_progress
+	WHEN NEW.clock IS OLD.clock AND NEW.clock_device IS OLD.clock_device BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'library', NEW.manga_id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_library_delete AFTER DELETE ON user_progress BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id, deleted) VALUES (OLD.user_id, 'library', OLD.manga_id, 1);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_rating_insert AFTER INSERT ON manga_ratings BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'rating', NEW.manga_id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_rating_update AFTER UPDATE OF rating, story, art, characters ON manga_ratings BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'rating', NEW.manga_id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_rating_delete AFTER DELETE ON manga_ratings BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id, deleted) VALUES (OLD.user_id, 'rating', OLD.manga_id, 1);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_insert AFTER INSERT ON collections BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'collection', NEW.id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_update AFTER UPDATE ON collections BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id) VALUES (NEW.user_id, 'collection', NEW.id);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_delete AFTER DELETE ON collections BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id, deleted) VALUES (OLD.user_id, 'collection', OLD.id, 1);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_item_insert AFTER INSERT ON collection_items BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id)
+			SELECT user_id, 'collection', id FROM collections WHERE id = NEW.collection_id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_item_update AFTER UPDATE ON collection_items BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id)
+			SELECT user_id, 'collection', id FROM collections WHERE id = NEW.collection_id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS sync_collection_item_delete AFTER DELETE ON collection_items BEGIN
+		INSERT INTO sync_changes (user_id, entity, entity_id)
+			SELECT user_id, 'collection', id FROM collections WHERE id = OLD.collection_id;
+	END`,
+	// Progress changes not made by a progress op (REST, TCP sync, imports) advance
+	// the entry's clock, so queued offline ops are merged against them
+	`CREATE TRIGGER IF NOT EXISTS sync_library_clock AFTER UPDATE OF current_chapter, status ON user_progress
+	WHEN NEW.clock IS OLD.clock BEGIN
+		UPDATE user_progress SET clock = COALESCE(OLD.clock, 0) + 1, clock_device = ''
+		WHERE user_id = NEW.user_id AND manga_id = NEW.manga_id;
+	END`,
+}
+
+// createSyncTriggers creates the triggers of syncTriggers. It runs after the
+// migrations, since rebuilding a table drops its triggers.
+func createSyncTriggers() error {
+	for _, query := range syncTriggers {
+		if _, err := DB.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute query: %s, error: %w", query, err)
+		}
+	}
+	return nil
+}
+
+// addedColumns lists columns added to existing tables. CREATE TABLE IF NOT EXISTS
+// leaves tables of older databases unchanged, so they are added here.
+var addedColumns = []struct {
+	table, column, definition string
+}{
+	{"user_progress", "notes", "TEXT DEFAULT ''"},
+	{"user_progress", "tags", "TEXT DEFAULT '[]'"},
+	{"user_progress", "started_at", "TIMESTAMP"},
+	{"user_progress", "finished_at", "TIMESTAMP"},
+	{"user_progress", "reread_count", "INTEGER DEFAULT 0"},
+	{"user_progress", "clock", "INTEGER DEFAULT 0"},
+	{"user_progress", "clock_device", "TEXT DEFAULT ''"},
+	{"manga", "tags", "TEXT DEFAULT '[]'"},
+	{"manga_ratings", "quarantined", "INTEGER NOT NULL DEFAULT 0"},
+	{"users", "email_verified_at", "TIMESTAMP"},
+	{"users", "totp_secret", "TEXT"},
+	{"users", "totp_enabled_at", "TIMESTAMP"},
+	{"users", "totp_last_step", "INTEGER DEFAULT 0"},
+	{"users", "failed_login_count", "INTEGER DEFAULT 0"},
+	{"users", "last_failed_login_at", "TIMESTAMP"},
+	{"users", "locked_until", "TIMESTAMP"},
+}
+
+// addMissingColumns adds the columns of addedColumns that a table does not have yet
+func addMissingColumns() error {
+	for _, c := range addedColumns {
+		var exists bool
+		err := DB.QueryRow(
+			"SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = ?)", c.table, c.column).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", c.table, err)
+		}
+		if exists {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)
+		if _, err := DB.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute query: %s, error: %w", query, err)
+		}
+		log.Printf("Added column %s.%s", c.table, c.column)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func Close() error {
 	if DB != nil {
@@ -18,8 +18,9 @@ type Manga struct {
 	CoverURL        string    `json:"cover_url" db:"cover_url"`
 	PublicationYear int       `json:"publication_year" db:"publication_year"`
 	Rating          float64   `json:"rating" db:"rating"`           // Average rating from users
+	WeightedRating  float64   `json:"weighted_rating" db:"-"`       // Bayesian-weighted rating, 0 when unrated
 	RatingCount     int       `json:"rating_count" db:"-"`          // Number of ratings
-	UserRating      *int      `json:"user_rating,omitempty" db:"-"` // Current user's rating (0-10)
+	UserRating      *int      `json:"user_rating,omitempty" db:"-"` // Current user's rating (1-10)
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -75,6 +76,14 @@ type UserProgress struct {
 	CurrentChapter int       `json:"current_chapter" db:"current_chapter"`
 	Status         string    `json:"status" db:"status"` // reading, completed, plan_to_read, dropped
 	LastUpdated    time.Time `json:"last_updated" db:"last_updated"`
+	// Personal details, only visible to the owner
+	Notes       string     `json:"notes" db:"notes"`
+	Tags        []string   `json:"tags" db:"tags"`
+	StartedAt   *time.Time `json:"started_at,omitempty" db:"started_at"`   // Set when the status first becomes reading
+	FinishedAt  *time.Time `json:"finished_at,omitempty" db:"finished_at"` // Set when the status becomes completed
+	RereadCount int        `json:"reread_count" db:"reread_count"`
+	// Logical clock of the last change; offline clients stamp progress ops past it
+	Clock int64 `json:"clock" db:"clock"`
 	// Manga details (populated from local DB or external API)
 	Title    string `json:"title,omitempty"`
 	Author   string `json:"author,omitempty"`
@@ -109,6 +118,75 @@ type UpdateProgressRequest struct {
 	Status         string `json:"status" binding:"required,oneof=reading completed plan_to_read dropped on_hold re_reading"`
 }
 
+// ProgressOp is a progress or status change stamped by the client, possibly made
+// while offline. Clock is the device's logical clock: it increases with every op
+// and is moved past the clock of the entries the device has seen. At least one of
+// CurrentChapter and Status is required.
+type ProgressOp struct {
+	DeviceID       string    `json:"device_id" binding:"required,max=100"`
+	Clock          int64     `json:"clock" binding:"required,min=1"`
+	ClientTime     time.Time `json:"client_time" binding:"required"`
+	MangaID        string    `json:"manga_id" binding:"required"`
+	CurrentChapter *int      `json:"current_chapter" binding:"omitempty,min=0"`
+	Status         string    `json:"status" binding:"omitempty,oneof=reading completed plan_to_read dropped on_hold re_reading"`
+}
+
+// ProgressOpsRequest is a batch of queued progress ops
+type ProgressOpsRequest struct {
+	Ops []ProgressOp `json:"ops" binding:"required,min=1,max=500,dive"`
+}
+
+// ProgressOpResult tells how an op was merged: applied, merged (partly overruled,
+// see the conflict log), duplicate (already applied), stale (superseded by a later
+// op of the same device) or rejected
+type ProgressOpResult struct {
+	DeviceID string        `json:"device_id"`
+	Clock    int64         `json:"clock"`
+	MangaID  string        `json:"manga_id"`
+	Outcome  string        `json:"outcome"`
+	Error    string        `json:"error,omitempty"`
+	Entry    *UserProgress `json:"entry,omitempty"` // Library entry after the op
+}
+
+// ProgressOpsResponse holds the result of every op in the order they were sent
+type ProgressOpsResponse struct {
+	Results   []ProgressOpResult `json:"results"`
+	Conflicts int                `json:"conflicts"`
+	Clock     int64              `json:"clock"` // Highest entry clock seen; stamp the next ops past it
+}
+
+// ProgressConflict records an op whose chapter or status was overruled by the merge
+type ProgressConflict struct {
+	ID               int       `json:"id"`
+	MangaID          string    `json:"manga_id"`
+	Title            string    `json:"title,omitempty"`
+	DeviceID         string    `json:"device_id"`
+	Clock            int64     `json:"clock"`
+	ClientTime       time.Time `json:"client_time"`
+	RequestedChapter *int      `json:"requested_chapter,omitempty"`
+	RequestedStatus  string    `json:"requested_status,omitempty"`
+	ResolvedChapter  int       `json:"resolved_chapter"`
+	ResolvedStatus   string    `json:"resolved_status"`
+	Reason           string    `json:"reason"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// UpdateLibraryEntryRequest updates the personal details of a library entry.
+// Omitted fields are left unchanged; an empty date clears it.
+type UpdateLibraryEntryRequest struct {
+	Notes       *string  `json:"notes" binding:"omitempty,max=5000"`
+	Tags        []string `json:"tags" binding:"omitempty,max=50,dive,max=50"`
+	StartedAt   *string  `json:"started_at"`  // YYYY-MM-DD
+	FinishedAt  *string  `json:"finished_at"` // YYYY-MM-DD
+	RereadCount *int     `json:"reread_count" binding:"omitempty,min=0"`
+}
+
+// LibraryTag is a personal tag with the number of library entries using it
+type LibraryTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
 // AddToLibraryRequest represents a request to add manga to user's library
 type AddToLibraryRequest struct {
 	MangaID string `json:"manga_id" binding:"required"`
@@ -208,32 +286,167 @@ type BatchUpdateRequest struct {
 // LibraryFilterRequest represents filtering parameters for user library
 type LibraryFilterRequest struct {
 	Status string `json:"status" form:"status"`
-	SortBy string `json:"sort_by" form:"sort_by"` // title, author, progress, updated
+	SortBy string `json:"sort_by" form:"sort_by"` // title, author, progress, updated, started, finished, rereads, status
+	Order  string `json:"order" form:"order"`     // asc or desc, default depends on sort_by
 	Limit  int    `json:"limit" form:"limit"`
 	Offset int    `json:"offset" form:"offset"`
+	// Personal detail filters
+	Tag            string `json:"tag" form:"tag"`
+	Search         string `json:"q" form:"q"` // Matches title and notes
+	HasNotes       bool   `json:"has_notes" form:"has_notes"`
+	StartedAfter   string `json:"started_after" form:"started_after"` // YYYY-MM-DD, inclusive
+	StartedBefore  string `json:"started_before" form:"started_before"`
+	FinishedAfter  string `json:"finished_after" form:"finished_after"`
+	FinishedBefore string `json:"finished_before" form:"finished_before"`
+	MinRereads     int    `json:"min_rereads" form:"min_rereads"`
 }
 
 // MangaRating represents a user's rating for a manga
 type MangaRating struct {
-	ID        int       `json:"id" db:"id"`
-	UserID    string    `json:"user_id" db:"user_id"`
-	MangaID   string    `json:"manga_id" db:"manga_id"`
-	Rating    int       `json:"rating" db:"rating"` // 0-10
+	ID      int    `json:"id" db:"id"`
+	UserID  string `json:"user_id" db:"user_id"`
+	MangaID string `json:"manga_id" db:"manga_id"`
+	Rating  int    `json:"rating" db:"rating"` // 1-10
+	RatingSubScores
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// RatingSubScores are the optional story, art and characters scores (1-10) of a rating
+type RatingSubScores struct {
+	Story      *int `json:"story,omitempty" binding:"omitempty,min=1,max=10"`
+	Art        *int `json:"art,omitempty" binding:"omitempty,min=1,max=10"`
+	Characters *int `json:"characters,omitempty" binding:"omitempty,min=1,max=10"`
+}
+
 // RateMangaRequest represents a request to rate a manga
 type RateMangaRequest struct {
 	MangaID string `json:"manga_id" binding:"required"`
-	Rating  int    `json:"rating" binding:"required,min=0,max=10"`
+	Rating  int    `json:"rating" binding:"required,min=1,max=10"`
+	RatingSubScores
+}
+
+// Review is a written review attached to a user's rating of a manga
+type Review struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	MangaID    string `json:"manga_id"`
+	MangaTitle string `json:"manga_title,omitempty"`
+	Rating     int    `json:"rating"` // The author's rating, 1-10
+	RatingSubScores
+	Title          string         `json:"title"`
+	Body           string         `json:"body"`
+	Spoiler        bool           `json:"spoiler"`
+	ChapterRead    int            `json:"chapter_read"` // Author's chapter when the review was written
+	Status         string         `json:"status"`       // published, hidden
+	HelpfulCount   int            `json:"helpful_count"`
+	UnhelpfulCount int            `json:"unhelpful_count"`
+	UserVote       string         `json:"user_vote,omitempty"` // Current user's vote: helpful or unhelpful
+	EditCount      int            `json:"edit_count"`
+	Reports        []ReviewReport `json:"reports,omitempty"` // Open reports, moderation queue only
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// ReviewRevision is a previous version of an edited review
+type ReviewRevision struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	Spoiler     bool      `json:"spoiler"`
+	ChapterRead int       `json:"chapter_read"`
+	WrittenAt   time.Time `json:"written_at"`
+}
+
+// ReviewReport is a user's report of a review to the moderators
+type ReviewReport struct {
+	ID         int       `json:"id"`
+	ReporterID string    `json:"reporter_id"`
+	Reporter   string    `json:"reporter"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WriteReviewRequest creates or edits the user's review of a manga
+type WriteReviewRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	Body    string `json:"body" binding:"required,max=20000"`
+	Spoiler bool   `json:"spoiler"`
+}
+
+// ReviewVoteRequest marks a review as helpful or unhelpful
+type ReviewVoteRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+// ReportReviewRequest reports a review to the moderators
+type ReportReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ModerateReviewRequest resolves the open reports of a review
+type ModerateReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss hide restore"`
+}
+
+// MangaRecommendation is a recommended manga with the reasons it was picked
+type MangaRecommendation struct {
+	Manga   Manga    `json:"manga"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"` // e.g. "Because you rated X highly"
+}
+
+// SimilarManga is a manga close in content (description, genres, tags) to another
+type SimilarManga struct {
+	Manga        Manga    `json:"manga"`
+	Score        float64  `json:"score"` // Cosine similarity, 0-1
+	SharedGenres []string `json:"shared_genres"`
+}
+
+// TrendingManga is a manga ranked by its popularity or trending score
+type TrendingManga struct {
+	Manga         Manga   `json:"manga"`
+	Score         float64 `json:"score"`
+	Window        string  `json:"window"` // 24h, 7d, 30d, or all for all-time popularity
+	LibraryCount  int     `json:"library_count"`
+	ActiveReaders int     `json:"active_readers"`
+	RatingCount   int     `json:"rating_count"`
+	AverageRating float64 `json:"average_rating"`
+	ChaptersRead  int     `json:"chapters_read"`
 }
 
 // MangaRatingStats represents rating statistics for a manga
 type MangaRatingStats struct {
-	MangaID            string      `json:"manga_id"`
-	AverageRating      float64     `json:"average_rating"`
-	TotalRatings       int         `json:"total_ratings"`
-	UserRating         *int        `json:"user_rating,omitempty"` // Current user's rating if authenticated
-	RatingDistribution map[int]int `json:"rating_distribution"`   // Distribution of ratings 1-5
+	MangaID            string             `json:"manga_id"`
+	AverageRating      float64            `json:"average_rating"`
+	WeightedRating     float64            `json:"weighted_rating"` // Bayesian-weighted, used for sorting; 0 when unrated
+	TotalRatings       int                `json:"total_ratings"`
+	SubScores          map[string]float64 `json:"sub_scores"`                // Average story, art and characters scores given
+	UserRating         *int               `json:"user_rating,omitempty"`     // Current user's rating if authenticated
+	UserSubScores      *RatingSubScores   `json:"user_sub_scores,omitempty"` // Current user's sub-scores if authenticated
+	RatingDistribution map[int]int        `json:"rating_distribution"`       // Distribution of ratings 1-10
+}
+
+// RatingFlag is a rating the brigading detector flagged, with the signals that
+// tripped it. Quarantined ratings are left out of aggregates.
+type RatingFlag struct {
+	ID               int        `json:"id"`
+	UserID           string     `json:"user_id"`
+	Username         string     `json:"username"`
+	AccountCreatedAt time.Time  `json:"account_created_at"`
+	MangaID          string     `json:"manga_id"`
+	MangaTitle       string     `json:"manga_title"`
+	Rating           int        `json:"rating"` // Value when flagged
+	Signals          []string   `json:"signals"`
+	Status           string     `json:"status"` // quarantined, cleared, confirmed
+	FlaggedAt        time.Time  `json:"flagged_at"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy       string     `json:"reviewed_by,omitempty"`
+}
+
+// ReviewRatingFlagRequest settles a flagged rating: clear puts it back into
+// aggregates, confirm keeps it quarantined
+type ReviewRatingFlagRequest struct {
+	Action string `json:"action" binding:"required,oneof=clear confirm"`
 }
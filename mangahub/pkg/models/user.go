@@ -15,15 +15,17 @@ type User struct {
 
 // UserRegistration represents the data needed for user registration
 type UserRegistration struct {
-	Username string `json:"username" binding:"required,min=3,max=30"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Username   string `json:"username" binding:"required,min=3,max=30"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6"`
+	DeviceName string `json:"device_name" binding:"max=100"` // Names the login session
 }
 
 // UserLogin represents the data needed for user login
 type UserLogin struct {
-	Email    string `json:"email" binding:"required"` // Can be email or username
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required"` // Can be email or username
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name" binding:"max=100"` // Names the login session
 }
 
 // UserResponse represents the public user data returned in API responses
@@ -31,17 +33,434 @@ type UserResponse struct {
 	ID        string    `json:"id"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
+	Roles     []string  `json:"roles,omitempty"` // On the user's own profile and login
 	CreatedAt time.Time `json:"created_at"`
+	// Whether the emailed verification link was followed
+	EmailVerified    bool `json:"email_verified"`
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
 }
 
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token"`
+	User UserResponse `json:"user"`
+	SessionTokens
 }
 
 // LoginResponse represents the login response
 type LoginResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token"`
+	User UserResponse `json:"user"`
+	SessionTokens
+}
+
+// LoginChallenge is the login response of an account with two-factor
+// authentication: the password was right, and the challenge token is exchanged
+// with a code at POST /auth/login/2fa for the session tokens
+type LoginChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	Methods           []string  `json:"methods"` // totp, recovery_code
+}
+
+// TwoFactorLoginRequest completes a login with the second factor
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // Authenticator code or recovery code
+	DeviceName     string `json:"device_name" binding:"max=100"`
+}
+
+// TwoFactorStatus is the two-factor authentication state of an account
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TOTPSetupResponse starts the enrollment of an authenticator app. The
+// provisioning URI is what the app scans as a QR code; the secret is for manual entry.
+type TOTPSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorCodeRequest confirms a two-factor settings change with a code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // Authenticator code, or recovery code where accepted
+}
+
+// RecoveryCodesResponse returns new recovery codes. They are only shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// SessionTokens are the tokens of a login session: a short-lived access token,
+// sent as the Bearer token, and the refresh token that renews it
+type SessionTokens struct {
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"` // Of the access token
+	RefreshToken string    `json:"refresh_token"`
+	SessionID    string    `json:"session_id"`
+}
+
+// VerifyEmailRequest confirms an email address with the token from the verification email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest asks for a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest sets a new password with the token from the reset email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// RefreshRequest exchanges a refresh token for new session tokens. The refresh
+// token rotates: the old one must not be used again.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// SessionClient describes the client a session is opened from
+type SessionClient struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// Session is a login session of a user, one per device
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // Last refresh
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session of the request
+}
+
+// APIToken is a personal access token for scripts and bots. The token itself
+// is only returned when it is created.
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Start of the token, to recognize it
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // Null when the token never expires
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Expired    bool       `json:"expired"`
+}
+
+// CreateAPITokenRequest represents a request to create a personal access token.
+// Tokens expire after 90 days unless expires_in_days or no_expiry is given.
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+	NoExpiry      bool     `json:"no_expiry"`
+}
+
+// CreateAPITokenResponse returns a new personal access token; it cannot be shown again
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+// RoleGrant is a role granted to a user
+type RoleGrant struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	GrantedBy string    `json:"granted_by,omitempty"` // User ID of the granting admin, empty for the bootstrap admin
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// RoleInfo describes a role, what it allows and who holds it
+type RoleInfo struct {
+	Role        string      `json:"role"`
+	Permissions []string    `json:"permissions"`
+	Members     []RoleGrant `json:"members"` // Empty for the user role, which everyone has
+}
+
+// GrantRoleRequest represents a request to grant a role to a user
+type GrantRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin moderator curator"`
+}
+
+// LoginAttempt is an entry of the login audit trail
+type LoginAttempt struct {
+	ID         int64     `json:"id"`
+	UserID     string    `json:"user_id,omitempty"` // Empty when no account matched
+	Identifier string    `json:"identifier"`        // Email or username as entered
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Success    bool      `json:"success"`
+	Outcome    string    `json:"outcome"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LoginAttemptFilter selects login attempts for admins
+type LoginAttemptFilter struct {
+	UserID    string
+	IPAddress string
+	Failed    bool // Only failed attempts
+	Limit     int
+	Offset    int
+}
+
+// AccountLockStatus describes the failed logins of an account
+type AccountLockStatus struct {
+	UserID            string     `json:"user_id"`
+	Locked            bool       `json:"locked"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+	FailedLoginCount  int        `json:"failed_login_count"`
+	LastFailedLoginAt *time.Time `json:"last_failed_login_at,omitempty"`
+}
+
+// MALLinkStatus describes a user's linked MyAnimeList account
+type MALLinkStatus struct {
+	Linked         bool       `json:"linked"`
+	MALUserID      int        `json:"mal_user_id,omitempty"`
+	MALUsername    string     `json:"mal_username,omitempty"`
+	LinkedAt       *time.Time `json:"linked_at,omitempty"`
+	LastImportAt   *time.Time `json:"last_import_at,omitempty"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+}
+
+// MALSyncConflict records an entry changed both locally and on MAL, and which side won
+type MALSyncConflict struct {
+	MangaID       string    `json:"manga_id"`
+	MALID         int       `json:"mal_id"`
+	Title         string    `json:"title,omitempty"`
+	Resolution    string    `json:"resolution"` // local_newer, remote_newer
+	LocalUpdated  time.Time `json:"local_updated"`
+	RemoteUpdated time.Time `json:"remote_updated"`
+}
+
+// MALImportResult summarizes a MAL list import
+type MALImportResult struct {
+	TotalRemote int               `json:"total_remote"`
+	Imported    int               `json:"imported"`  // New library entries created from MAL
+	Updated     int               `json:"updated"`   // Local entries overwritten by newer MAL data
+	Pushed      int               `json:"pushed"`    // Local entries sent to MAL
+	Unchanged   int               `json:"unchanged"` // Already in sync
+	Failed      int               `json:"failed"`
+	Conflicts   []MALSyncConflict `json:"conflicts"`
+	Errors      []string          `json:"errors,omitempty"`
+}
+
+// LibraryImportResult summarizes a library file import (MAL XML, AniList JSON)
+type LibraryImportResult struct {
+	Format    string   `json:"format"`
+	Total     int      `json:"total"`
+	Imported  int      `json:"imported"`  // New library entries
+	Updated   int      `json:"updated"`   // Existing entries overwritten
+	Skipped   int      `json:"skipped"`   // Existing entries kept
+	Ratings   int      `json:"ratings"`   // Ratings imported
+	Unmatched int      `json:"unmatched"` // Entries added to the review list
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// LibraryImportReviewItem is an imported entry that could not be matched to a local manga
+type LibraryImportReviewItem struct {
+	ID             int64     `json:"id"`
+	Source         string    `json:"source"` // mal, anilist, mangadex, tachiyomi
+	SourceID       string    `json:"source_id,omitempty"`
+	MALID          string    `json:"mal_id,omitempty"`
+	Title          string    `json:"title"`
+	Status         string    `json:"status"`
+	CurrentChapter int       `json:"current_chapter"`
+	Score          int       `json:"score"` // 0-10, 0 when unscored
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ResolveImportReviewRequest maps a review list entry to a local manga
+type ResolveImportReviewRequest struct {
+	MangaID string `json:"manga_id" binding:"required"`
+}
+
+// TachiyomiImportResult is the match report of a Tachiyomi/Mihon backup import
+type TachiyomiImportResult struct {
+	Total        int              `json:"total"` // Library entries in the backup
+	Matched      int              `json:"matched"`
+	Unmatched    int              `json:"unmatched"` // Added to the import review list
+	Imported     int              `json:"imported"`
+	Updated      int              `json:"updated"`
+	Skipped      int              `json:"skipped"` // Already in the library (use overwrite to replace)
+	Failed       int              `json:"failed"`
+	ChaptersRead int              `json:"chapters_read"` // Read chapters restored
+	Categories   []string         `json:"categories"`    // Imported as private collections
+	Entries      []TachiyomiMatch `json:"entries"`
+	Errors       []string         `json:"errors,omitempty"`
+}
+
+// TachiyomiMatch reports how one backed up manga was imported
+type TachiyomiMatch struct {
+	Title          string   `json:"title"`
+	Source         string   `json:"source"`                    // Source name from the backup, e.g. "MangaDex"
+	SourceMangaID  string   `json:"source_manga_id,omitempty"` // MangaDex manga UUID
+	MangaID        string   `json:"manga_id,omitempty"`
+	MatchedBy      string   `json:"matched_by,omitempty"` // mangadex, mal, title
+	Status         string   `json:"status"`
+	CurrentChapter int      `json:"current_chapter"`
+	ChaptersRead   int      `json:"chapters_read"`
+	Categories     []string `json:"categories,omitempty"`
+	Result         string   `json:"result"` // imported, updated, skipped, unmatched, failed
+	Reason         string   `json:"reason,omitempty"`
+}
+
+// ReadChapter is a chapter the user has marked as read
+type ReadChapter struct {
+	ChapterNumber   float64   `json:"chapter_number"`
+	SourceChapterID string    `json:"source_chapter_id,omitempty"`
+	ReadAt          time.Time `json:"read_at"`
+}
+
+// Collection is a named, manually ordered list of manga curated by a user
+type Collection struct {
+	ID          string           `json:"id"`
+	UserID      string           `json:"user_id"`
+	Owner       string           `json:"owner,omitempty"` // Username of the owner
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Visibility  string           `json:"visibility"` // private, unlisted, public
+	ShareToken  string           `json:"share_token,omitempty"`
+	ShareURL    string           `json:"share_url,omitempty"`
+	SortOrder   int              `json:"sort_order"`
+	ItemCount   int              `json:"item_count"`
+	Items       []CollectionItem `json:"items,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CollectionItem is a manga in a collection
+type CollectionItem struct {
+	MangaID  string    `json:"manga_id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+	// Manga details (populated from local DB)
+	Title    string `json:"title,omitempty"`
+	Author   string `json:"author,omitempty"`
+	CoverURL string `json:"cover_url,omitempty"`
+}
+
+// CreateCollectionRequest represents a request to create a collection
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+// UpdateCollectionRequest represents a request to update a collection
+type UpdateCollectionRequest struct {
+	Name        string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+	Visibility  string  `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+// AddCollectionItemRequest represents a request to add a manga to a collection
+type AddCollectionItemRequest struct {
+	MangaID string `json:"manga_id" binding:"required"`
+}
+
+// ReorderCollectionRequest sets the order of all manga in a collection
+type ReorderCollectionRequest struct {
+	MangaIDs []string `json:"manga_ids" binding:"required,min=1"`
+}
+
+// ReorderCollectionsRequest sets the order of all of a user's collections
+type ReorderCollectionsRequest struct {
+	CollectionIDs []string `json:"collection_ids" binding:"required,min=1"`
+}
+
+// ReadingEvent is an entry of the append-only reading activity log
+type ReadingEvent struct {
+	ID          int64     `json:"id"`
+	UserID      string    `json:"user_id"`
+	MangaID     string    `json:"manga_id"`
+	Type        string    `json:"type"`   // added, progress, status, removed, rating, rating_removed
+	Source      string    `json:"source"` // user, import, mal_sync
+	ChapterFrom *int      `json:"chapter_from,omitempty"`
+	ChapterTo   *int      `json:"chapter_to,omitempty"`
+	StatusFrom  string    `json:"status_from,omitempty"`
+	StatusTo    string    `json:"status_to,omitempty"`
+	Rating      *int      `json:"rating,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Manga details (populated from local DB)
+	Title string `json:"title,omitempty"`
+}
+
+// ReadingStats is the reading activity of a user as a time series for charts
+type ReadingStats struct {
+	Period              string              `json:"period"` // day, week, month
+	From                string              `json:"from"`   // YYYY-MM-DD, first day of the first bucket
+	To                  string              `json:"to"`     // YYYY-MM-DD, inclusive
+	Series              []ReadingStatsPoint `json:"series"`
+	TotalChapters       int                 `json:"total_chapters"`
+	TotalCompleted      int                 `json:"total_completed"`
+	CurrentStreak       int                 `json:"current_streak"` // Consecutive days with chapters read, up to today or yesterday
+	LongestStreak       int                 `json:"longest_streak"`
+	AverageDaysToFinish float64             `json:"average_days_to_finish"` // Entries finished in the range, 0 when none
+	FinishedWithDates   int                 `json:"finished_with_dates"`    // Entries the average is based on
+}
+
+// ReadingStatsPoint is one bucket of the reading stats series
+type ReadingStatsPoint struct {
+	Period    string         `json:"period"` // YYYY-MM-DD for days and weeks (Monday), YYYY-MM for months
+	Chapters  int            `json:"chapters"`
+	Completed int            `json:"completed"`
+	Genres    map[string]int `json:"genres"` // Chapters read per genre
+}
+
+// ReadingGoal is a user-defined reading challenge over a date window
+type ReadingGoal struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Title      string     `json:"title"`
+	Type       string     `json:"type"`            // chapters, completed, genre
+	Genre      string     `json:"genre,omitempty"` // For genre goals
+	Target     int        `json:"target"`
+	StartsOn   string     `json:"starts_on"` // YYYY-MM-DD
+	EndsOn     string     `json:"ends_on"`   // YYYY-MM-DD, inclusive
+	Progress   int        `json:"progress"`
+	Percent    float64    `json:"percent"`
+	Status     string     `json:"status"` // upcoming, active, achieved, expired
+	AchievedAt *time.Time `json:"achieved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateReadingGoalRequest represents a request to create a reading goal. The
+// window defaults to the current calendar year.
+type CreateReadingGoalRequest struct {
+	Title    string `json:"title" binding:"max=100"`
+	Type     string `json:"type" binding:"required,oneof=chapters completed genre"`
+	Genre    string `json:"genre" binding:"max=50"`
+	Target   int    `json:"target" binding:"required,min=1"`
+	StartsOn string `json:"starts_on"`
+	EndsOn   string `json:"ends_on"`
+}
+
+// LibraryChanges is the change feed of a user's library, ratings and collections
+// since a sync token
+type LibraryChanges struct {
+	SyncToken   string          `json:"sync_token"` // Send as since in the next request
+	Full        bool            `json:"full"`       // No token was sent: everything is included, replace the local copy
+	HasMore     bool            `json:"has_more"`   // More changes are waiting, request again with sync_token
+	Library     []UserProgress  `json:"library"`
+	Ratings     []MangaRating   `json:"ratings"`
+	Collections []Collection    `json:"collections"` // With their items
+	Deleted     []SyncTombstone `json:"deleted"`
+}
+
+// SyncTombstone marks a library entry, rating or collection removed since the sync token
+type SyncTombstone struct {
+	Entity    string    `json:"entity"` // library, rating, collection
+	ID        string    `json:"id"`     // Manga ID for library entries and ratings, collection ID
+	DeletedAt time.Time `json:"deleted_at"`
 }
@@ -2,7 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"log"
+	"os"
 	"strings"
+	"time"
 )
 
 // String Validation Functions
@@ -148,6 +151,21 @@ func DefaultInt(value, defaultValue int) int {
 	return value
 }
 
+// DurationFromEnv returns the duration in the environment variable name (e.g.
+// "15m"), or fallback when it is unset or not a positive duration
+func DurationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s %q, using %v", name, value, fallback)
+		return fallback
+	}
+	return duration
+}
+
 // Slice Helper Functions
 
 // StringInSlice checks if a string exists in a slice of strings
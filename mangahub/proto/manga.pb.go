@@ -125,7 +125,7 @@ type SearchRequest struct {
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Sort          string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"` // Sort field: title, author, rating, popular, etc.
+	Sort          string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"` // Sort field: title, relevant, newest, popular, trending (7d), trending_24h, trending_7d, trending_30d
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -393,6 +393,7 @@ type Manga struct {
 	PublicationYear int32                  `protobuf:"varint,9,opt,name=publication_year,json=publicationYear,proto3" json:"publication_year,omitempty"`
 	Rating          float64                `protobuf:"fixed64,10,opt,name=rating,proto3" json:"rating,omitempty"`
 	CreatedAt       string                 `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	WeightedRating  float64                `protobuf:"fixed64,12,opt,name=weighted_rating,json=weightedRating,proto3" json:"weighted_rating,omitempty"` // Bayesian-weighted rating, 0 when unrated
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -504,6 +505,13 @@ func (x *Manga) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Manga) GetWeightedRating() float64 {
+	if x != nil {
+		return x.WeightedRating
+	}
+	return 0
+}
+
 type LibraryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -557,6 +565,12 @@ type UserProgress struct {
 	Title          string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
 	Author         string                 `protobuf:"bytes,6,opt,name=author,proto3" json:"author,omitempty"`
 	CoverUrl       string                 `protobuf:"bytes,7,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	Notes          string                 `protobuf:"bytes,8,opt,name=notes,proto3" json:"notes,omitempty"`
+	Tags           []string               `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	StartedAt      string                 `protobuf:"bytes,10,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`    // Empty when unknown
+	FinishedAt     string                 `protobuf:"bytes,11,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"` // Empty when unknown
+	RereadCount    int32                  `protobuf:"varint,12,opt,name=reread_count,json=rereadCount,proto3" json:"reread_count,omitempty"`
+	Clock          int64                  `protobuf:"varint,13,opt,name=clock,proto3" json:"clock,omitempty"` // Logical clock of the last change, for progress ops
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -640,6 +654,48 @@ func (x *UserProgress) GetCoverUrl() string {
 	return ""
 }
 
+func (x *UserProgress) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *UserProgress) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UserProgress) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *UserProgress) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *UserProgress) GetRereadCount() int32 {
+	if x != nil {
+		return x.RereadCount
+	}
+	return 0
+}
+
+func (x *UserProgress) GetClock() int64 {
+	if x != nil {
+		return x.Clock
+	}
+	return 0
+}
+
 type LibraryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Reading       []*UserProgress        `protobuf:"bytes,1,rep,name=reading,proto3" json:"reading,omitempty"`
@@ -1116,29 +1172,33 @@ func (x *LibraryStatsResponse) GetError() string {
 	return ""
 }
 
-type RatingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
-	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"` // 1-10
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ProgressOp is a client-stamped progress change, possibly made offline
+type ProgressOp struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId       string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Clock          int64                  `protobuf:"varint,2,opt,name=clock,proto3" json:"clock,omitempty"`                            // Logical clock of the device, past the clocks it has seen
+	ClientTime     string                 `protobuf:"bytes,3,opt,name=client_time,json=clientTime,proto3" json:"client_time,omitempty"` // RFC 3339
+	MangaId        string                 `protobuf:"bytes,4,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	CurrentChapter *int32                 `protobuf:"varint,5,opt,name=current_chapter,json=currentChapter,proto3,oneof" json:"current_chapter,omitempty"`
+	Status         string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // Empty leaves the status alone
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *RatingRequest) Reset() {
-	*x = RatingRequest{}
+func (x *ProgressOp) Reset() {
+	*x = ProgressOp{}
 	mi := &file_proto_manga_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RatingRequest) String() string {
+func (x *ProgressOp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RatingRequest) ProtoMessage() {}
+func (*ProgressOp) ProtoMessage() {}
 
-func (x *RatingRequest) ProtoReflect() protoreflect.Message {
+func (x *ProgressOp) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1150,57 +1210,75 @@ func (x *RatingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RatingRequest.ProtoReflect.Descriptor instead.
-func (*RatingRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressOp.ProtoReflect.Descriptor instead.
+func (*ProgressOp) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *RatingRequest) GetUserId() string {
+func (x *ProgressOp) GetDeviceId() string {
 	if x != nil {
-		return x.UserId
+		return x.DeviceId
 	}
 	return ""
 }
 
-func (x *RatingRequest) GetMangaId() string {
+func (x *ProgressOp) GetClock() int64 {
 	if x != nil {
-		return x.MangaId
+		return x.Clock
+	}
+	return 0
+}
+
+func (x *ProgressOp) GetClientTime() string {
+	if x != nil {
+		return x.ClientTime
 	}
 	return ""
 }
 
-func (x *RatingRequest) GetRating() int32 {
+func (x *ProgressOp) GetMangaId() string {
 	if x != nil {
-		return x.Rating
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *ProgressOp) GetCurrentChapter() int32 {
+	if x != nil && x.CurrentChapter != nil {
+		return *x.CurrentChapter
 	}
 	return 0
 }
 
-type RatingResponse struct {
+func (x *ProgressOp) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ProgressOpsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	AverageRating float64                `protobuf:"fixed64,3,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"` // Updated average
-	TotalRatings  int32                  `protobuf:"varint,4,opt,name=total_ratings,json=totalRatings,proto3" json:"total_ratings,omitempty"`
-	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Ops           []*ProgressOp          `protobuf:"bytes,2,rep,name=ops,proto3" json:"ops,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RatingResponse) Reset() {
-	*x = RatingResponse{}
+func (x *ProgressOpsRequest) Reset() {
+	*x = ProgressOpsRequest{}
 	mi := &file_proto_manga_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RatingResponse) String() string {
+func (x *ProgressOpsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RatingResponse) ProtoMessage() {}
+func (*ProgressOpsRequest) ProtoMessage() {}
 
-func (x *RatingResponse) ProtoReflect() protoreflect.Message {
+func (x *ProgressOpsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1212,68 +1290,51 @@ func (x *RatingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RatingResponse.ProtoReflect.Descriptor instead.
-func (*RatingResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressOpsRequest.ProtoReflect.Descriptor instead.
+func (*ProgressOpsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *RatingResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-func (x *RatingResponse) GetMessage() string {
+func (x *ProgressOpsRequest) GetUserId() string {
 	if x != nil {
-		return x.Message
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *RatingResponse) GetAverageRating() float64 {
-	if x != nil {
-		return x.AverageRating
-	}
-	return 0
-}
-
-func (x *RatingResponse) GetTotalRatings() int32 {
-	if x != nil {
-		return x.TotalRatings
-	}
-	return 0
-}
-
-func (x *RatingResponse) GetError() string {
+func (x *ProgressOpsRequest) GetOps() []*ProgressOp {
 	if x != nil {
-		return x.Error
+		return x.Ops
 	}
-	return ""
+	return nil
 }
 
-type MangaRatingRequest struct {
+type ProgressOpResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Optional: to get user's specific rating
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Clock         int64                  `protobuf:"varint,2,opt,name=clock,proto3" json:"clock,omitempty"`
+	MangaId       string                 `protobuf:"bytes,3,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Outcome       string                 `protobuf:"bytes,4,opt,name=outcome,proto3" json:"outcome,omitempty"` // applied, merged, duplicate, stale or rejected
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Entry         *UserProgress          `protobuf:"bytes,6,opt,name=entry,proto3" json:"entry,omitempty"` // Library entry after the op
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MangaRatingRequest) Reset() {
-	*x = MangaRatingRequest{}
+func (x *ProgressOpResult) Reset() {
+	*x = ProgressOpResult{}
 	mi := &file_proto_manga_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MangaRatingRequest) String() string {
+func (x *ProgressOpResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MangaRatingRequest) ProtoMessage() {}
+func (*ProgressOpResult) ProtoMessage() {}
 
-func (x *MangaRatingRequest) ProtoReflect() protoreflect.Message {
+func (x *ProgressOpResult) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1285,50 +1346,77 @@ func (x *MangaRatingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MangaRatingRequest.ProtoReflect.Descriptor instead.
-func (*MangaRatingRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressOpResult.ProtoReflect.Descriptor instead.
+func (*ProgressOpResult) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *MangaRatingRequest) GetMangaId() string {
+func (x *ProgressOpResult) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ProgressOpResult) GetClock() int64 {
+	if x != nil {
+		return x.Clock
+	}
+	return 0
+}
+
+func (x *ProgressOpResult) GetMangaId() string {
 	if x != nil {
 		return x.MangaId
 	}
 	return ""
 }
 
-func (x *MangaRatingRequest) GetUserId() string {
+func (x *ProgressOpResult) GetOutcome() string {
 	if x != nil {
-		return x.UserId
+		return x.Outcome
 	}
 	return ""
 }
 
-type MangaRatingResponse struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	AverageRating      float64                `protobuf:"fixed64,1,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
-	TotalRatings       int32                  `protobuf:"varint,2,opt,name=total_ratings,json=totalRatings,proto3" json:"total_ratings,omitempty"`
-	UserRating         int32                  `protobuf:"varint,3,opt,name=user_rating,json=userRating,proto3" json:"user_rating,omitempty"`                                                                                                    // 0 if user hasn't rated
-	RatingDistribution map[int32]int32        `protobuf:"bytes,4,rep,name=rating_distribution,json=ratingDistribution,proto3" json:"rating_distribution,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Count of each rating (1-5)
-	Error              string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+func (x *ProgressOpResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
 }
 
-func (x *MangaRatingResponse) Reset() {
-	*x = MangaRatingResponse{}
+func (x *ProgressOpResult) GetEntry() *UserProgress {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type ProgressOpsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ProgressOpResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // In the order the ops were sent
+	Conflicts     int32                  `protobuf:"varint,2,opt,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Clock         int64                  `protobuf:"varint,3,opt,name=clock,proto3" json:"clock,omitempty"` // Highest entry clock seen
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgressOpsResponse) Reset() {
+	*x = ProgressOpsResponse{}
 	mi := &file_proto_manga_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MangaRatingResponse) String() string {
+func (x *ProgressOpsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MangaRatingResponse) ProtoMessage() {}
+func (*ProgressOpsResponse) ProtoMessage() {}
 
-func (x *MangaRatingResponse) ProtoReflect() protoreflect.Message {
+func (x *ProgressOpsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1340,68 +1428,63 @@ func (x *MangaRatingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MangaRatingResponse.ProtoReflect.Descriptor instead.
-func (*MangaRatingResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressOpsResponse.ProtoReflect.Descriptor instead.
+func (*ProgressOpsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *MangaRatingResponse) GetAverageRating() float64 {
+func (x *ProgressOpsResponse) GetResults() []*ProgressOpResult {
 	if x != nil {
-		return x.AverageRating
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-func (x *MangaRatingResponse) GetTotalRatings() int32 {
+func (x *ProgressOpsResponse) GetConflicts() int32 {
 	if x != nil {
-		return x.TotalRatings
+		return x.Conflicts
 	}
 	return 0
 }
 
-func (x *MangaRatingResponse) GetUserRating() int32 {
+func (x *ProgressOpsResponse) GetClock() int64 {
 	if x != nil {
-		return x.UserRating
+		return x.Clock
 	}
 	return 0
 }
 
-func (x *MangaRatingResponse) GetRatingDistribution() map[int32]int32 {
-	if x != nil {
-		return x.RatingDistribution
-	}
-	return nil
-}
-
-func (x *MangaRatingResponse) GetError() string {
+func (x *ProgressOpsResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type DeleteRatingRequest struct {
+type ProgressConflictsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"` // Optional filter
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRatingRequest) Reset() {
-	*x = DeleteRatingRequest{}
+func (x *ProgressConflictsRequest) Reset() {
+	*x = ProgressConflictsRequest{}
 	mi := &file_proto_manga_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRatingRequest) String() string {
+func (x *ProgressConflictsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRatingRequest) ProtoMessage() {}
+func (*ProgressConflictsRequest) ProtoMessage() {}
 
-func (x *DeleteRatingRequest) ProtoReflect() protoreflect.Message {
+func (x *ProgressConflictsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1413,48 +1496,71 @@ func (x *DeleteRatingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRatingRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRatingRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressConflictsRequest.ProtoReflect.Descriptor instead.
+func (*ProgressConflictsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *DeleteRatingRequest) GetUserId() string {
+func (x *ProgressConflictsRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *DeleteRatingRequest) GetMangaId() string {
+func (x *ProgressConflictsRequest) GetMangaId() string {
 	if x != nil {
 		return x.MangaId
 	}
 	return ""
 }
 
-type DeleteRatingResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProgressConflictsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
 }
 
-func (x *DeleteRatingResponse) Reset() {
-	*x = DeleteRatingResponse{}
+func (x *ProgressConflictsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ProgressConflict struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MangaId          string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Title            string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	DeviceId         string                 `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Clock            int64                  `protobuf:"varint,5,opt,name=clock,proto3" json:"clock,omitempty"`
+	ClientTime       string                 `protobuf:"bytes,6,opt,name=client_time,json=clientTime,proto3" json:"client_time,omitempty"`
+	RequestedChapter *int32                 `protobuf:"varint,7,opt,name=requested_chapter,json=requestedChapter,proto3,oneof" json:"requested_chapter,omitempty"`
+	RequestedStatus  string                 `protobuf:"bytes,8,opt,name=requested_status,json=requestedStatus,proto3" json:"requested_status,omitempty"`
+	ResolvedChapter  int32                  `protobuf:"varint,9,opt,name=resolved_chapter,json=resolvedChapter,proto3" json:"resolved_chapter,omitempty"`
+	ResolvedStatus   string                 `protobuf:"bytes,10,opt,name=resolved_status,json=resolvedStatus,proto3" json:"resolved_status,omitempty"`
+	Reason           string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt        string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ProgressConflict) Reset() {
+	*x = ProgressConflict{}
 	mi := &file_proto_manga_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRatingResponse) String() string {
+func (x *ProgressConflict) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRatingResponse) ProtoMessage() {}
+func (*ProgressConflict) ProtoMessage() {}
 
-func (x *DeleteRatingResponse) ProtoReflect() protoreflect.Message {
+func (x *ProgressConflict) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_manga_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1466,54 +1572,2579 @@ func (x *DeleteRatingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRatingResponse.ProtoReflect.Descriptor instead.
-func (*DeleteRatingResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProgressConflict.ProtoReflect.Descriptor instead.
+func (*ProgressConflict) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *DeleteRatingResponse) GetSuccess() bool {
+func (x *ProgressConflict) GetId() int32 {
 	if x != nil {
-		return x.Success
+		return x.Id
 	}
-	return false
+	return 0
 }
 
-func (x *DeleteRatingResponse) GetMessage() string {
+func (x *ProgressConflict) GetMangaId() string {
 	if x != nil {
-		return x.Message
+		return x.MangaId
 	}
 	return ""
 }
 
-func (x *DeleteRatingResponse) GetError() string {
+func (x *ProgressConflict) GetTitle() string {
 	if x != nil {
-		return x.Error
+		return x.Title
 	}
 	return ""
 }
 
-type GetUserProfileRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProgressConflict) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
 }
 
-func (x *GetUserProfileRequest) Reset() {
-	*x = GetUserProfileRequest{}
-	mi := &file_proto_manga_proto_msgTypes[22]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ProgressConflict) GetClock() int64 {
+	if x != nil {
+		return x.Clock
+	}
+	return 0
 }
 
-func (x *GetUserProfileRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ProgressConflict) GetClientTime() string {
+	if x != nil {
+		return x.ClientTime
+	}
+	return ""
 }
 
-func (*GetUserProfileRequest) ProtoMessage() {}
+func (x *ProgressConflict) GetRequestedChapter() int32 {
+	if x != nil && x.RequestedChapter != nil {
+		return *x.RequestedChapter
+	}
+	return 0
+}
 
-func (x *GetUserProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[22]
+func (x *ProgressConflict) GetRequestedStatus() string {
+	if x != nil {
+		return x.RequestedStatus
+	}
+	return ""
+}
+
+func (x *ProgressConflict) GetResolvedChapter() int32 {
+	if x != nil {
+		return x.ResolvedChapter
+	}
+	return 0
+}
+
+func (x *ProgressConflict) GetResolvedStatus() string {
+	if x != nil {
+		return x.ResolvedStatus
+	}
+	return ""
+}
+
+func (x *ProgressConflict) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProgressConflict) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ProgressConflictsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conflicts     []*ProgressConflict    `protobuf:"bytes,1,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgressConflictsResponse) Reset() {
+	*x = ProgressConflictsResponse{}
+	mi := &file_proto_manga_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgressConflictsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgressConflictsResponse) ProtoMessage() {}
+
+func (x *ProgressConflictsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgressConflictsResponse.ProtoReflect.Descriptor instead.
+func (*ProgressConflictsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ProgressConflictsResponse) GetConflicts() []*ProgressConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *ProgressConflictsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ProgressConflictsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// LibraryChangesRequest asks for library, rating and collection changes since a
+// sync token; an empty token returns everything
+type LibraryChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SyncToken     string                 `protobuf:"bytes,2,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // Max changed entries, default 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LibraryChangesRequest) Reset() {
+	*x = LibraryChangesRequest{}
+	mi := &file_proto_manga_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LibraryChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LibraryChangesRequest) ProtoMessage() {}
+
+func (x *LibraryChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LibraryChangesRequest.ProtoReflect.Descriptor instead.
+func (*LibraryChangesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LibraryChangesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LibraryChangesRequest) GetSyncToken() string {
+	if x != nil {
+		return x.SyncToken
+	}
+	return ""
+}
+
+func (x *LibraryChangesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UserRating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Rating        int32                  `protobuf:"varint,2,opt,name=rating,proto3" json:"rating,omitempty"`
+	Story         int32                  `protobuf:"varint,3,opt,name=story,proto3" json:"story,omitempty"` // 0 when not given
+	Art           int32                  `protobuf:"varint,4,opt,name=art,proto3" json:"art,omitempty"`
+	Characters    int32                  `protobuf:"varint,5,opt,name=characters,proto3" json:"characters,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRating) Reset() {
+	*x = UserRating{}
+	mi := &file_proto_manga_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRating) ProtoMessage() {}
+
+func (x *UserRating) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRating.ProtoReflect.Descriptor instead.
+func (*UserRating) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UserRating) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *UserRating) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *UserRating) GetStory() int32 {
+	if x != nil {
+		return x.Story
+	}
+	return 0
+}
+
+func (x *UserRating) GetArt() int32 {
+	if x != nil {
+		return x.Art
+	}
+	return 0
+}
+
+func (x *UserRating) GetCharacters() int32 {
+	if x != nil {
+		return x.Characters
+	}
+	return 0
+}
+
+func (x *UserRating) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// SyncTombstone marks a removed library entry, rating or collection
+type SyncTombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entity        string                 `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"` // library, rating or collection
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`         // Manga ID, or collection ID for collections
+	DeletedAt     string                 `protobuf:"bytes,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncTombstone) Reset() {
+	*x = SyncTombstone{}
+	mi := &file_proto_manga_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncTombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncTombstone) ProtoMessage() {}
+
+func (x *SyncTombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncTombstone.ProtoReflect.Descriptor instead.
+func (*SyncTombstone) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SyncTombstone) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+func (x *SyncTombstone) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SyncTombstone) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+type LibraryChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SyncToken     string                 `protobuf:"bytes,1,opt,name=sync_token,json=syncToken,proto3" json:"sync_token,omitempty"` // Send back on the next request
+	Full          bool                   `protobuf:"varint,2,opt,name=full,proto3" json:"full,omitempty"`                           // Full state: replace the local copy
+	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`      // More changes are waiting; ask again right away
+	Library       []*UserProgress        `protobuf:"bytes,4,rep,name=library,proto3" json:"library,omitempty"`
+	Ratings       []*UserRating          `protobuf:"bytes,5,rep,name=ratings,proto3" json:"ratings,omitempty"`
+	Collections   []*Collection          `protobuf:"bytes,6,rep,name=collections,proto3" json:"collections,omitempty"`
+	Deleted       []*SyncTombstone       `protobuf:"bytes,7,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LibraryChangesResponse) Reset() {
+	*x = LibraryChangesResponse{}
+	mi := &file_proto_manga_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LibraryChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LibraryChangesResponse) ProtoMessage() {}
+
+func (x *LibraryChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LibraryChangesResponse.ProtoReflect.Descriptor instead.
+func (*LibraryChangesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *LibraryChangesResponse) GetSyncToken() string {
+	if x != nil {
+		return x.SyncToken
+	}
+	return ""
+}
+
+func (x *LibraryChangesResponse) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *LibraryChangesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *LibraryChangesResponse) GetLibrary() []*UserProgress {
+	if x != nil {
+		return x.Library
+	}
+	return nil
+}
+
+func (x *LibraryChangesResponse) GetRatings() []*UserRating {
+	if x != nil {
+		return x.Ratings
+	}
+	return nil
+}
+
+func (x *LibraryChangesResponse) GetCollections() []*Collection {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+func (x *LibraryChangesResponse) GetDeleted() []*SyncTombstone {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *LibraryChangesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RatingRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Rating  int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"` // 1-10
+	// Optional sub-scores, 1-10; 0 keeps the previous value
+	Story         int32 `protobuf:"varint,4,opt,name=story,proto3" json:"story,omitempty"`
+	Art           int32 `protobuf:"varint,5,opt,name=art,proto3" json:"art,omitempty"`
+	Characters    int32 `protobuf:"varint,6,opt,name=characters,proto3" json:"characters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatingRequest) Reset() {
+	*x = RatingRequest{}
+	mi := &file_proto_manga_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingRequest) ProtoMessage() {}
+
+func (x *RatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingRequest.ProtoReflect.Descriptor instead.
+func (*RatingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RatingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RatingRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *RatingRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *RatingRequest) GetStory() int32 {
+	if x != nil {
+		return x.Story
+	}
+	return 0
+}
+
+func (x *RatingRequest) GetArt() int32 {
+	if x != nil {
+		return x.Art
+	}
+	return 0
+}
+
+func (x *RatingRequest) GetCharacters() int32 {
+	if x != nil {
+		return x.Characters
+	}
+	return 0
+}
+
+type RatingResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AverageRating  float64                `protobuf:"fixed64,3,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"` // Updated average
+	TotalRatings   int32                  `protobuf:"varint,4,opt,name=total_ratings,json=totalRatings,proto3" json:"total_ratings,omitempty"`
+	Error          string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	WeightedRating float64                `protobuf:"fixed64,6,opt,name=weighted_rating,json=weightedRating,proto3" json:"weighted_rating,omitempty"` // Updated Bayesian-weighted rating
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RatingResponse) Reset() {
+	*x = RatingResponse{}
+	mi := &file_proto_manga_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingResponse) ProtoMessage() {}
+
+func (x *RatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingResponse.ProtoReflect.Descriptor instead.
+func (*RatingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RatingResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RatingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RatingResponse) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *RatingResponse) GetTotalRatings() int32 {
+	if x != nil {
+		return x.TotalRatings
+	}
+	return 0
+}
+
+func (x *RatingResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RatingResponse) GetWeightedRating() float64 {
+	if x != nil {
+		return x.WeightedRating
+	}
+	return 0
+}
+
+type MangaRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Optional: to get user's specific rating
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MangaRatingRequest) Reset() {
+	*x = MangaRatingRequest{}
+	mi := &file_proto_manga_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MangaRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MangaRatingRequest) ProtoMessage() {}
+
+func (x *MangaRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MangaRatingRequest.ProtoReflect.Descriptor instead.
+func (*MangaRatingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MangaRatingRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *MangaRatingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type MangaRatingResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AverageRating      float64                `protobuf:"fixed64,1,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	TotalRatings       int32                  `protobuf:"varint,2,opt,name=total_ratings,json=totalRatings,proto3" json:"total_ratings,omitempty"`
+	UserRating         int32                  `protobuf:"varint,3,opt,name=user_rating,json=userRating,proto3" json:"user_rating,omitempty"`                                                                                                    // 0 if user hasn't rated
+	RatingDistribution map[int32]int32        `protobuf:"bytes,4,rep,name=rating_distribution,json=ratingDistribution,proto3" json:"rating_distribution,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Count of each rating (1-10)
+	Error              string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	WeightedRating     float64                `protobuf:"fixed64,6,opt,name=weighted_rating,json=weightedRating,proto3" json:"weighted_rating,omitempty"`                                                            // Bayesian-weighted rating, 0 when unrated
+	SubScores          map[string]float64     `protobuf:"bytes,7,rep,name=sub_scores,json=subScores,proto3" json:"sub_scores,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Average story, art and characters scores given
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *MangaRatingResponse) Reset() {
+	*x = MangaRatingResponse{}
+	mi := &file_proto_manga_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MangaRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MangaRatingResponse) ProtoMessage() {}
+
+func (x *MangaRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MangaRatingResponse.ProtoReflect.Descriptor instead.
+func (*MangaRatingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MangaRatingResponse) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *MangaRatingResponse) GetTotalRatings() int32 {
+	if x != nil {
+		return x.TotalRatings
+	}
+	return 0
+}
+
+func (x *MangaRatingResponse) GetUserRating() int32 {
+	if x != nil {
+		return x.UserRating
+	}
+	return 0
+}
+
+func (x *MangaRatingResponse) GetRatingDistribution() map[int32]int32 {
+	if x != nil {
+		return x.RatingDistribution
+	}
+	return nil
+}
+
+func (x *MangaRatingResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *MangaRatingResponse) GetWeightedRating() float64 {
+	if x != nil {
+		return x.WeightedRating
+	}
+	return 0
+}
+
+func (x *MangaRatingResponse) GetSubScores() map[string]float64 {
+	if x != nil {
+		return x.SubScores
+	}
+	return nil
+}
+
+type DeleteRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingRequest) Reset() {
+	*x = DeleteRatingRequest{}
+	mi := &file_proto_manga_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingRequest) ProtoMessage() {}
+
+func (x *DeleteRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRatingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteRatingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteRatingRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+type DeleteRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingResponse) Reset() {
+	*x = DeleteRatingResponse{}
+	mi := &file_proto_manga_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingResponse) ProtoMessage() {}
+
+func (x *DeleteRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRatingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteRatingResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteRatingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteRatingResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetUserProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserProfileRequest) Reset() {
+	*x = GetUserProfileRequest{}
+	mi := &file_proto_manga_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserProfileRequest) ProtoMessage() {}
+
+func (x *GetUserProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetUserProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetUserProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UserProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	mi := &file_proto_manga_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
+func (*UserProfile) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UserProfile) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserProfile) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserProfile) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserProfile) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type UserProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *UserProfile           `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserProfileResponse) Reset() {
+	*x = UserProfileResponse{}
+	mi := &file_proto_manga_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfileResponse) ProtoMessage() {}
+
+func (x *UserProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfileResponse.ProtoReflect.Descriptor instead.
+func (*UserProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UserProfileResponse) GetProfile() *UserProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *UserProfileResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UpdateUserProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	OtpCode       string                 `protobuf:"bytes,4,opt,name=otp_code,json=otpCode,proto3" json:"otp_code,omitempty"` // Current authenticator or recovery code, required with two-factor authentication
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserProfileRequest) Reset() {
+	*x = UpdateUserProfileRequest{}
+	mi := &file_proto_manga_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserProfileRequest) ProtoMessage() {}
+
+func (x *UpdateUserProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UpdateUserProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserProfileRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UpdateUserProfileRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserProfileRequest) GetOtpCode() string {
+	if x != nil {
+		return x.OtpCode
+	}
+	return ""
+}
+
+type UpdateUserProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Profile       *UserProfile           `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserProfileResponse) Reset() {
+	*x = UpdateUserProfileResponse{}
+	mi := &file_proto_manga_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserProfileResponse) ProtoMessage() {}
+
+func (x *UpdateUserProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserProfileResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UpdateUserProfileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateUserProfileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateUserProfileResponse) GetProfile() *UserProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *UpdateUserProfileResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ChangePasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OldPassword   string                 `protobuf:"bytes,2,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	OtpCode       string                 `protobuf:"bytes,4,opt,name=otp_code,json=otpCode,proto3" json:"otp_code,omitempty"` // Current authenticator or recovery code, required with two-factor authentication
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_proto_manga_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ChangePasswordRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetOtpCode() string {
+	if x != nil {
+		return x.OtpCode
+	}
+	return ""
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_proto_manga_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ChangePasswordResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ChangePasswordResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ChangePasswordResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CollectionItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	AddedAt       string                 `protobuf:"bytes,3,opt,name=added_at,json=addedAt,proto3" json:"added_at,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	CoverUrl      string                 `protobuf:"bytes,6,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionItem) Reset() {
+	*x = CollectionItem{}
+	mi := &file_proto_manga_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionItem) ProtoMessage() {}
+
+func (x *CollectionItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionItem.ProtoReflect.Descriptor instead.
+func (*CollectionItem) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CollectionItem) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *CollectionItem) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *CollectionItem) GetAddedAt() string {
+	if x != nil {
+		return x.AddedAt
+	}
+	return ""
+}
+
+func (x *CollectionItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CollectionItem) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *CollectionItem) GetCoverUrl() string {
+	if x != nil {
+		return x.CoverUrl
+	}
+	return ""
+}
+
+type Collection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"` // Username of the owner
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Visibility    string                 `protobuf:"bytes,6,opt,name=visibility,proto3" json:"visibility,omitempty"`                   // private, unlisted, public
+	ShareToken    string                 `protobuf:"bytes,7,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"` // Empty for shared lookups
+	SortOrder     int32                  `protobuf:"varint,8,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	ItemCount     int32                  `protobuf:"varint,9,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
+	Items         []*CollectionItem      `protobuf:"bytes,10,rep,name=items,proto3" json:"items,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Collection) Reset() {
+	*x = Collection{}
+	mi := &file_proto_manga_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Collection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Collection) ProtoMessage() {}
+
+func (x *Collection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Collection.ProtoReflect.Descriptor instead.
+func (*Collection) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Collection) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Collection) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Collection) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Collection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Collection) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Collection) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *Collection) GetShareToken() string {
+	if x != nil {
+		return x.ShareToken
+	}
+	return ""
+}
+
+func (x *Collection) GetSortOrder() int32 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+func (x *Collection) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *Collection) GetItems() []*CollectionItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Collection) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Collection) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ListCollectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCollectionsRequest) Reset() {
+	*x = ListCollectionsRequest{}
+	mi := &file_proto_manga_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCollectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCollectionsRequest) ProtoMessage() {}
+
+func (x *ListCollectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCollectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListCollectionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListCollectionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListCollectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Collections   []*Collection          `protobuf:"bytes,1,rep,name=collections,proto3" json:"collections,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCollectionsResponse) Reset() {
+	*x = ListCollectionsResponse{}
+	mi := &file_proto_manga_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCollectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCollectionsResponse) ProtoMessage() {}
+
+func (x *ListCollectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCollectionsResponse.ProtoReflect.Descriptor instead.
+func (*ListCollectionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListCollectionsResponse) GetCollections() []*Collection {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+func (x *ListCollectionsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId  string                 `protobuf:"bytes,2,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCollectionRequest) Reset() {
+	*x = GetCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCollectionRequest) ProtoMessage() {}
+
+func (x *GetCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCollectionRequest.ProtoReflect.Descriptor instead.
+func (*GetCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetCollectionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetCollectionRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+type CollectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Collection    *Collection            `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionResponse) Reset() {
+	*x = CollectionResponse{}
+	mi := &file_proto_manga_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionResponse) ProtoMessage() {}
+
+func (x *CollectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionResponse.ProtoReflect.Descriptor instead.
+func (*CollectionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CollectionResponse) GetCollection() *Collection {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+func (x *CollectionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CreateCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Visibility    string                 `protobuf:"bytes,4,opt,name=visibility,proto3" json:"visibility,omitempty"` // Defaults to private
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCollectionRequest) Reset() {
+	*x = CreateCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCollectionRequest) ProtoMessage() {}
+
+func (x *CreateCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCollectionRequest.ProtoReflect.Descriptor instead.
+func (*CreateCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateCollectionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateCollectionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCollectionRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateCollectionRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type UpdateCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId  string                 `protobuf:"bytes,2,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                     // Unchanged if empty
+	Description   *string                `protobuf:"bytes,4,opt,name=description,proto3,oneof" json:"description,omitempty"` // Unchanged if unset
+	Visibility    string                 `protobuf:"bytes,5,opt,name=visibility,proto3" json:"visibility,omitempty"`         // Unchanged if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCollectionRequest) Reset() {
+	*x = UpdateCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCollectionRequest) ProtoMessage() {}
+
+func (x *UpdateCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCollectionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *UpdateCollectionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type DeleteCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId  string                 `protobuf:"bytes,2,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCollectionRequest) Reset() {
+	*x = DeleteCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCollectionRequest) ProtoMessage() {}
+
+func (x *DeleteCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCollectionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *DeleteCollectionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteCollectionRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+type DeleteCollectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCollectionResponse) Reset() {
+	*x = DeleteCollectionResponse{}
+	mi := &file_proto_manga_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCollectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCollectionResponse) ProtoMessage() {}
+
+func (x *DeleteCollectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCollectionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCollectionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DeleteCollectionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteCollectionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteCollectionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CollectionItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId  string                 `protobuf:"bytes,2,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,3,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionItemRequest) Reset() {
+	*x = CollectionItemRequest{}
+	mi := &file_proto_manga_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionItemRequest) ProtoMessage() {}
+
+func (x *CollectionItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionItemRequest.ProtoReflect.Descriptor instead.
+func (*CollectionItemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CollectionItemRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CollectionItemRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *CollectionItemRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+type ReorderCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CollectionId  string                 `protobuf:"bytes,2,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	MangaIds      []string               `protobuf:"bytes,3,rep,name=manga_ids,json=mangaIds,proto3" json:"manga_ids,omitempty"` // Every manga in the collection, in the new order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderCollectionRequest) Reset() {
+	*x = ReorderCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderCollectionRequest) ProtoMessage() {}
+
+func (x *ReorderCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderCollectionRequest.ProtoReflect.Descriptor instead.
+func (*ReorderCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ReorderCollectionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReorderCollectionRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *ReorderCollectionRequest) GetMangaIds() []string {
+	if x != nil {
+		return x.MangaIds
+	}
+	return nil
+}
+
+type GetSharedCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShareToken    string                 `protobuf:"bytes,1,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSharedCollectionRequest) Reset() {
+	*x = GetSharedCollectionRequest{}
+	mi := &file_proto_manga_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSharedCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSharedCollectionRequest) ProtoMessage() {}
+
+func (x *GetSharedCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSharedCollectionRequest.ProtoReflect.Descriptor instead.
+func (*GetSharedCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetSharedCollectionRequest) GetShareToken() string {
+	if x != nil {
+		return x.ShareToken
+	}
+	return ""
+}
+
+type RecommendationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendationsRequest) Reset() {
+	*x = RecommendationsRequest{}
+	mi := &file_proto_manga_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendationsRequest) ProtoMessage() {}
+
+func (x *RecommendationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendationsRequest.ProtoReflect.Descriptor instead.
+func (*RecommendationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RecommendationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RecommendationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Recommendation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manga         *Manga                 `protobuf:"bytes,1,opt,name=manga,proto3" json:"manga,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons       []string               `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"` // e.g. "Because you rated X highly"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recommendation) Reset() {
+	*x = Recommendation{}
+	mi := &file_proto_manga_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recommendation) ProtoMessage() {}
+
+func (x *Recommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recommendation.ProtoReflect.Descriptor instead.
+func (*Recommendation) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *Recommendation) GetManga() *Manga {
+	if x != nil {
+		return x.Manga
+	}
+	return nil
+}
+
+func (x *Recommendation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Recommendation) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type RecommendationsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Recommendations []*Recommendation      `protobuf:"bytes,1,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	Error           string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RecommendationsResponse) Reset() {
+	*x = RecommendationsResponse{}
+	mi := &file_proto_manga_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendationsResponse) ProtoMessage() {}
+
+func (x *RecommendationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendationsResponse.ProtoReflect.Descriptor instead.
+func (*RecommendationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RecommendationsResponse) GetRecommendations() []*Recommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+func (x *RecommendationsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Review struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username       string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	MangaId        string                 `protobuf:"bytes,4,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Rating         int32                  `protobuf:"varint,5,opt,name=rating,proto3" json:"rating,omitempty"` // The author's rating, 1-10
+	Title          string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Body           string                 `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	Spoiler        bool                   `protobuf:"varint,8,opt,name=spoiler,proto3" json:"spoiler,omitempty"`
+	ChapterRead    int32                  `protobuf:"varint,9,opt,name=chapter_read,json=chapterRead,proto3" json:"chapter_read,omitempty"` // Chapter the author was on when writing
+	Status         string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`                              // published, hidden
+	HelpfulCount   int32                  `protobuf:"varint,11,opt,name=helpful_count,json=helpfulCount,proto3" json:"helpful_count,omitempty"`
+	UnhelpfulCount int32                  `protobuf:"varint,12,opt,name=unhelpful_count,json=unhelpfulCount,proto3" json:"unhelpful_count,omitempty"`
+	EditCount      int32                  `protobuf:"varint,13,opt,name=edit_count,json=editCount,proto3" json:"edit_count,omitempty"`
+	UserVote       string                 `protobuf:"bytes,14,opt,name=user_vote,json=userVote,proto3" json:"user_vote,omitempty"` // Requesting user's vote: helpful, unhelpful or empty
+	CreatedAt      string                 `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_proto_manga_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Review) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Review) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Review) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Review) GetSpoiler() bool {
+	if x != nil {
+		return x.Spoiler
+	}
+	return false
+}
+
+func (x *Review) GetChapterRead() int32 {
+	if x != nil {
+		return x.ChapterRead
+	}
+	return 0
+}
+
+func (x *Review) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Review) GetHelpfulCount() int32 {
+	if x != nil {
+		return x.HelpfulCount
+	}
+	return 0
+}
+
+func (x *Review) GetUnhelpfulCount() int32 {
+	if x != nil {
+		return x.UnhelpfulCount
+	}
+	return 0
+}
+
+func (x *Review) GetEditCount() int32 {
+	if x != nil {
+		return x.EditCount
+	}
+	return 0
+}
+
+func (x *Review) GetUserVote() string {
+	if x != nil {
+		return x.UserVote
+	}
+	return ""
+}
+
+func (x *Review) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Review) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type MangaReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Optional: to get the user's votes
+	Sort          string                 `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`                   // helpful (default), recent
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MangaReviewsRequest) Reset() {
+	*x = MangaReviewsRequest{}
+	mi := &file_proto_manga_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MangaReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MangaReviewsRequest) ProtoMessage() {}
+
+func (x *MangaReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MangaReviewsRequest.ProtoReflect.Descriptor instead.
+func (*MangaReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *MangaReviewsRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *MangaReviewsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MangaReviewsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *MangaReviewsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *MangaReviewsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type MangaReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MangaReviewsResponse) Reset() {
+	*x = MangaReviewsResponse{}
+	mi := &file_proto_manga_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MangaReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MangaReviewsResponse) ProtoMessage() {}
+
+func (x *MangaReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1524,43 +4155,58 @@ func (x *GetUserProfileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserProfileRequest.ProtoReflect.Descriptor instead.
-func (*GetUserProfileRequest) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use MangaReviewsResponse.ProtoReflect.Descriptor instead.
+func (*MangaReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *GetUserProfileRequest) GetUserId() string {
+func (x *MangaReviewsResponse) GetReviews() []*Review {
 	if x != nil {
-		return x.UserId
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *MangaReviewsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *MangaReviewsResponse) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-type UserProfile struct {
+type WriteReviewRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Spoiler       bool                   `protobuf:"varint,5,opt,name=spoiler,proto3" json:"spoiler,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserProfile) Reset() {
-	*x = UserProfile{}
-	mi := &file_proto_manga_proto_msgTypes[23]
+func (x *WriteReviewRequest) Reset() {
+	*x = WriteReviewRequest{}
+	mi := &file_proto_manga_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserProfile) String() string {
+func (x *WriteReviewRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserProfile) ProtoMessage() {}
+func (*WriteReviewRequest) ProtoMessage() {}
 
-func (x *UserProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[23]
+func (x *WriteReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1571,62 +4217,70 @@ func (x *UserProfile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
-func (*UserProfile) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use WriteReviewRequest.ProtoReflect.Descriptor instead.
+func (*WriteReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *UserProfile) GetId() string {
+func (x *WriteReviewRequest) GetUserId() string {
 	if x != nil {
-		return x.Id
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *UserProfile) GetUsername() string {
+func (x *WriteReviewRequest) GetMangaId() string {
 	if x != nil {
-		return x.Username
+		return x.MangaId
 	}
 	return ""
 }
 
-func (x *UserProfile) GetEmail() string {
+func (x *WriteReviewRequest) GetTitle() string {
 	if x != nil {
-		return x.Email
+		return x.Title
 	}
 	return ""
 }
 
-func (x *UserProfile) GetCreatedAt() string {
+func (x *WriteReviewRequest) GetBody() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Body
 	}
 	return ""
 }
 
-type UserProfileResponse struct {
+func (x *WriteReviewRequest) GetSpoiler() bool {
+	if x != nil {
+		return x.Spoiler
+	}
+	return false
+}
+
+type VoteReviewRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Profile       *UserProfile           `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReviewId      string                 `protobuf:"bytes,2,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Helpful       bool                   `protobuf:"varint,3,opt,name=helpful,proto3" json:"helpful,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserProfileResponse) Reset() {
-	*x = UserProfileResponse{}
-	mi := &file_proto_manga_proto_msgTypes[24]
+func (x *VoteReviewRequest) Reset() {
+	*x = VoteReviewRequest{}
+	mi := &file_proto_manga_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserProfileResponse) String() string {
+func (x *VoteReviewRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserProfileResponse) ProtoMessage() {}
+func (*VoteReviewRequest) ProtoMessage() {}
 
-func (x *UserProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[24]
+func (x *VoteReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1637,49 +4291,55 @@ func (x *UserProfileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserProfileResponse.ProtoReflect.Descriptor instead.
-func (*UserProfileResponse) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use VoteReviewRequest.ProtoReflect.Descriptor instead.
+func (*VoteReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *UserProfileResponse) GetProfile() *UserProfile {
+func (x *VoteReviewRequest) GetUserId() string {
 	if x != nil {
-		return x.Profile
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-func (x *UserProfileResponse) GetError() string {
+func (x *VoteReviewRequest) GetReviewId() string {
 	if x != nil {
-		return x.Error
+		return x.ReviewId
 	}
 	return ""
 }
 
-type UpdateUserProfileRequest struct {
+func (x *VoteReviewRequest) GetHelpful() bool {
+	if x != nil {
+		return x.Helpful
+	}
+	return false
+}
+
+type ReviewResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserProfileRequest) Reset() {
-	*x = UpdateUserProfileRequest{}
-	mi := &file_proto_manga_proto_msgTypes[25]
+func (x *ReviewResponse) Reset() {
+	*x = ReviewResponse{}
+	mi := &file_proto_manga_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserProfileRequest) String() string {
+func (x *ReviewResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserProfileRequest) ProtoMessage() {}
+func (*ReviewResponse) ProtoMessage() {}
 
-func (x *UpdateUserProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[25]
+func (x *ReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1690,57 +4350,101 @@ func (x *UpdateUserProfileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserProfileRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserProfileRequest) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use ReviewResponse.ProtoReflect.Descriptor instead.
+func (*ReviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *UpdateUserProfileRequest) GetUserId() string {
+func (x *ReviewResponse) GetReview() *Review {
 	if x != nil {
-		return x.UserId
+		return x.Review
+	}
+	return nil
+}
+
+func (x *ReviewResponse) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-func (x *UpdateUserProfileRequest) GetUsername() string {
+type DeleteReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewRequest) Reset() {
+	*x = DeleteReviewRequest{}
+	mi := &file_proto_manga_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewRequest) ProtoMessage() {}
+
+func (x *DeleteReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[62]
 	if x != nil {
-		return x.Username
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DeleteReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *UpdateUserProfileRequest) GetEmail() string {
+func (x *DeleteReviewRequest) GetMangaId() string {
 	if x != nil {
-		return x.Email
+		return x.MangaId
 	}
 	return ""
 }
 
-type UpdateUserProfileResponse struct {
+type DeleteReviewResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Profile       *UserProfile           `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
-	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserProfileResponse) Reset() {
-	*x = UpdateUserProfileResponse{}
-	mi := &file_proto_manga_proto_msgTypes[26]
+func (x *DeleteReviewResponse) Reset() {
+	*x = DeleteReviewResponse{}
+	mi := &file_proto_manga_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserProfileResponse) String() string {
+func (x *DeleteReviewResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserProfileResponse) ProtoMessage() {}
+func (*DeleteReviewResponse) ProtoMessage() {}
 
-func (x *UpdateUserProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[26]
+func (x *DeleteReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1751,63 +4455,56 @@ func (x *UpdateUserProfileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserProfileResponse.ProtoReflect.Descriptor instead.
-func (*UpdateUserProfileResponse) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use DeleteReviewResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *UpdateUserProfileResponse) GetSuccess() bool {
+func (x *DeleteReviewResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *UpdateUserProfileResponse) GetMessage() string {
+func (x *DeleteReviewResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *UpdateUserProfileResponse) GetProfile() *UserProfile {
-	if x != nil {
-		return x.Profile
-	}
-	return nil
-}
-
-func (x *UpdateUserProfileResponse) GetError() string {
+func (x *DeleteReviewResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type ChangePasswordRequest struct {
+// RoleRequest grants or revokes a role (admin, moderator, curator) of another user
+type RoleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	OldPassword   string                 `protobuf:"bytes,2,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
-	NewPassword   string                 `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	TargetUserId  string                 `protobuf:"bytes,1,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChangePasswordRequest) Reset() {
-	*x = ChangePasswordRequest{}
-	mi := &file_proto_manga_proto_msgTypes[27]
+func (x *RoleRequest) Reset() {
+	*x = RoleRequest{}
+	mi := &file_proto_manga_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChangePasswordRequest) String() string {
+func (x *RoleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChangePasswordRequest) ProtoMessage() {}
+func (*RoleRequest) ProtoMessage() {}
 
-func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[27]
+func (x *RoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1818,56 +4515,50 @@ func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
-func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{27}
-}
-
-func (x *ChangePasswordRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use RoleRequest.ProtoReflect.Descriptor instead.
+func (*RoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *ChangePasswordRequest) GetOldPassword() string {
+func (x *RoleRequest) GetTargetUserId() string {
 	if x != nil {
-		return x.OldPassword
+		return x.TargetUserId
 	}
 	return ""
 }
 
-func (x *ChangePasswordRequest) GetNewPassword() string {
+func (x *RoleRequest) GetRole() string {
 	if x != nil {
-		return x.NewPassword
+		return x.Role
 	}
 	return ""
 }
 
-type ChangePasswordResponse struct {
+// RoleResponse returns the target user's roles after the change
+type RoleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
 	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChangePasswordResponse) Reset() {
-	*x = ChangePasswordResponse{}
-	mi := &file_proto_manga_proto_msgTypes[28]
+func (x *RoleResponse) Reset() {
+	*x = RoleResponse{}
+	mi := &file_proto_manga_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChangePasswordResponse) String() string {
+func (x *RoleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChangePasswordResponse) ProtoMessage() {}
+func (*RoleResponse) ProtoMessage() {}
 
-func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_manga_proto_msgTypes[28]
+func (x *RoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1878,26 +4569,26 @@ func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
-func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
-	return file_proto_manga_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use RoleResponse.ProtoReflect.Descriptor instead.
+func (*RoleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *ChangePasswordResponse) GetSuccess() bool {
+func (x *RoleResponse) GetUserId() string {
 	if x != nil {
-		return x.Success
+		return x.UserId
 	}
-	return false
+	return ""
 }
 
-func (x *ChangePasswordResponse) GetMessage() string {
+func (x *RoleResponse) GetRoles() []string {
 	if x != nil {
-		return x.Message
+		return x.Roles
 	}
-	return ""
+	return nil
 }
 
-func (x *ChangePasswordResponse) GetError() string {
+func (x *RoleResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
@@ -1931,7 +4622,7 @@ const file_proto_manga_proto_rawDesc = "" +
 	"\x10ProgressResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"\xbd\x02\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xe6\x02\n" +
 	"\x05Manga\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
@@ -1945,9 +4636,10 @@ const file_proto_manga_proto_rawDesc = "" +
 	"\x06rating\x18\n" +
 	" \x01(\x01R\x06rating\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\v \x01(\tR\tcreatedAt\")\n" +
+	"created_at\x18\v \x01(\tR\tcreatedAt\x12'\n" +
+	"\x0fweighted_rating\x18\f \x01(\x01R\x0eweightedRating\")\n" +
 	"\x0eLibraryRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xd8\x01\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xfb\x02\n" +
 	"\fUserProgress\x12\x19\n" +
 	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12'\n" +
 	"\x0fcurrent_chapter\x18\x02 \x01(\x05R\x0ecurrentChapter\x12\x16\n" +
@@ -1955,7 +4647,16 @@ const file_proto_manga_proto_rawDesc = "" +
 	"\flast_updated\x18\x04 \x01(\tR\vlastUpdated\x12\x14\n" +
 	"\x05title\x18\x05 \x01(\tR\x05title\x12\x16\n" +
 	"\x06author\x18\x06 \x01(\tR\x06author\x12\x1b\n" +
-	"\tcover_url\x18\a \x01(\tR\bcoverUrl\"\xd1\x02\n" +
+	"\tcover_url\x18\a \x01(\tR\bcoverUrl\x12\x14\n" +
+	"\x05notes\x18\b \x01(\tR\x05notes\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\n" +
+	" \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\v \x01(\tR\n" +
+	"finishedAt\x12!\n" +
+	"\freread_count\x18\f \x01(\x05R\vrereadCount\x12\x14\n" +
+	"\x05clock\x18\r \x01(\x03R\x05clock\"\xd1\x02\n" +
 	"\x0fLibraryResponse\x12-\n" +
 	"\areading\x18\x01 \x03(\v2\x13.manga.UserProgressR\areading\x121\n" +
 	"\tcompleted\x18\x02 \x03(\v2\x13.manga.UserProgressR\tcompleted\x125\n" +
@@ -1995,30 +4696,124 @@ const file_proto_manga_proto_rawDesc = "" +
 	"\n" +
 	"re_reading\x18\a \x01(\x05R\treReading\x12.\n" +
 	"\x13total_chapters_read\x18\b \x01(\x05R\x11totalChaptersRead\x12\x14\n" +
-	"\x05error\x18\t \x01(\tR\x05error\"[\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"\xd5\x01\n" +
+	"\n" +
+	"ProgressOp\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05clock\x18\x02 \x01(\x03R\x05clock\x12\x1f\n" +
+	"\vclient_time\x18\x03 \x01(\tR\n" +
+	"clientTime\x12\x19\n" +
+	"\bmanga_id\x18\x04 \x01(\tR\amangaId\x12,\n" +
+	"\x0fcurrent_chapter\x18\x05 \x01(\x05H\x00R\x0ecurrentChapter\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06statusB\x12\n" +
+	"\x10_current_chapter\"R\n" +
+	"\x12ProgressOpsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\x03ops\x18\x02 \x03(\v2\x11.manga.ProgressOpR\x03ops\"\xbb\x01\n" +
+	"\x10ProgressOpResult\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05clock\x18\x02 \x01(\x03R\x05clock\x12\x19\n" +
+	"\bmanga_id\x18\x03 \x01(\tR\amangaId\x12\x18\n" +
+	"\aoutcome\x18\x04 \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12)\n" +
+	"\x05entry\x18\x06 \x01(\v2\x13.manga.UserProgressR\x05entry\"\x92\x01\n" +
+	"\x13ProgressOpsResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.manga.ProgressOpResultR\aresults\x12\x1c\n" +
+	"\tconflicts\x18\x02 \x01(\x05R\tconflicts\x12\x14\n" +
+	"\x05clock\x18\x03 \x01(\x03R\x05clock\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"|\n" +
+	"\x18ProgressConflictsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\xa5\x03\n" +
+	"\x10ProgressConflict\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05clock\x18\x05 \x01(\x03R\x05clock\x12\x1f\n" +
+	"\vclient_time\x18\x06 \x01(\tR\n" +
+	"clientTime\x120\n" +
+	"\x11requested_chapter\x18\a \x01(\x05H\x00R\x10requestedChapter\x88\x01\x01\x12)\n" +
+	"\x10requested_status\x18\b \x01(\tR\x0frequestedStatus\x12)\n" +
+	"\x10resolved_chapter\x18\t \x01(\x05R\x0fresolvedChapter\x12'\n" +
+	"\x0fresolved_status\x18\n" +
+	" \x01(\tR\x0eresolvedStatus\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAtB\x14\n" +
+	"\x12_requested_chapter\"~\n" +
+	"\x19ProgressConflictsResponse\x125\n" +
+	"\tconflicts\x18\x01 \x03(\v2\x17.manga.ProgressConflictR\tconflicts\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"e\n" +
+	"\x15LibraryChangesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x02 \x01(\tR\tsyncToken\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xa6\x01\n" +
+	"\n" +
+	"UserRating\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12\x16\n" +
+	"\x06rating\x18\x02 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05story\x18\x03 \x01(\x05R\x05story\x12\x10\n" +
+	"\x03art\x18\x04 \x01(\x05R\x03art\x12\x1e\n" +
+	"\n" +
+	"characters\x18\x05 \x01(\x05R\n" +
+	"characters\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"V\n" +
+	"\rSyncTombstone\x12\x16\n" +
+	"\x06entity\x18\x01 \x01(\tR\x06entity\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x03 \x01(\tR\tdeletedAt\"\xbd\x02\n" +
+	"\x16LibraryChangesResponse\x12\x1d\n" +
+	"\n" +
+	"sync_token\x18\x01 \x01(\tR\tsyncToken\x12\x12\n" +
+	"\x04full\x18\x02 \x01(\bR\x04full\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMore\x12-\n" +
+	"\alibrary\x18\x04 \x03(\v2\x13.manga.UserProgressR\alibrary\x12+\n" +
+	"\aratings\x18\x05 \x03(\v2\x11.manga.UserRatingR\aratings\x123\n" +
+	"\vcollections\x18\x06 \x03(\v2\x11.manga.CollectionR\vcollections\x12.\n" +
+	"\adeleted\x18\a \x03(\v2\x14.manga.SyncTombstoneR\adeleted\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\xa3\x01\n" +
 	"\rRatingRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
 	"\bmanga_id\x18\x02 \x01(\tR\amangaId\x12\x16\n" +
-	"\x06rating\x18\x03 \x01(\x05R\x06rating\"\xa6\x01\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05story\x18\x04 \x01(\x05R\x05story\x12\x10\n" +
+	"\x03art\x18\x05 \x01(\x05R\x03art\x12\x1e\n" +
+	"\n" +
+	"characters\x18\x06 \x01(\x05R\n" +
+	"characters\"\xcf\x01\n" +
 	"\x0eRatingResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
 	"\x0eaverage_rating\x18\x03 \x01(\x01R\raverageRating\x12#\n" +
 	"\rtotal_ratings\x18\x04 \x01(\x05R\ftotalRatings\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error\"H\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12'\n" +
+	"\x0fweighted_rating\x18\x06 \x01(\x01R\x0eweightedRating\"H\n" +
 	"\x12MangaRatingRequest\x12\x19\n" +
 	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xc4\x02\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xf5\x03\n" +
 	"\x13MangaRatingResponse\x12%\n" +
 	"\x0eaverage_rating\x18\x01 \x01(\x01R\raverageRating\x12#\n" +
 	"\rtotal_ratings\x18\x02 \x01(\x05R\ftotalRatings\x12\x1f\n" +
 	"\vuser_rating\x18\x03 \x01(\x05R\n" +
 	"userRating\x12c\n" +
 	"\x13rating_distribution\x18\x04 \x03(\v22.manga.MangaRatingResponse.RatingDistributionEntryR\x12ratingDistribution\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error\x1aE\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12'\n" +
+	"\x0fweighted_rating\x18\x06 \x01(\x01R\x0eweightedRating\x12H\n" +
+	"\n" +
+	"sub_scores\x18\a \x03(\v2).manga.MangaRatingResponse.SubScoresEntryR\tsubScores\x1aE\n" +
 	"\x17RatingDistributionEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"I\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a<\n" +
+	"\x0eSubScoresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"I\n" +
 	"\x13DeleteRatingRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
 	"\bmanga_id\x18\x02 \x01(\tR\amangaId\"`\n" +
@@ -2036,24 +4831,170 @@ const file_proto_manga_proto_rawDesc = "" +
 	"created_at\x18\x04 \x01(\tR\tcreatedAt\"Y\n" +
 	"\x13UserProfileResponse\x12,\n" +
 	"\aprofile\x18\x01 \x01(\v2\x12.manga.UserProfileR\aprofile\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"e\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x80\x01\n" +
 	"\x18UpdateUserProfileRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"\x93\x01\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x19\n" +
+	"\botp_code\x18\x04 \x01(\tR\aotpCode\"\x93\x01\n" +
 	"\x19UpdateUserProfileResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
 	"\aprofile\x18\x03 \x01(\v2\x12.manga.UserProfileR\aprofile\x12\x14\n" +
-	"\x05error\x18\x04 \x01(\tR\x05error\"v\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x91\x01\n" +
 	"\x15ChangePasswordRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
 	"\fold_password\x18\x02 \x01(\tR\voldPassword\x12!\n" +
-	"\fnew_password\x18\x03 \x01(\tR\vnewPassword\"b\n" +
+	"\fnew_password\x18\x03 \x01(\tR\vnewPassword\x12\x19\n" +
+	"\botp_code\x18\x04 \x01(\tR\aotpCode\"b\n" +
 	"\x16ChangePasswordResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error2\xb1\a\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xad\x01\n" +
+	"\x0eCollectionItem\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x12\x19\n" +
+	"\badded_at\x18\x03 \x01(\tR\aaddedAt\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x05 \x01(\tR\x06author\x12\x1b\n" +
+	"\tcover_url\x18\x06 \x01(\tR\bcoverUrl\"\xeb\x02\n" +
+	"\n" +
+	"Collection\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x06 \x01(\tR\n" +
+	"visibility\x12\x1f\n" +
+	"\vshare_token\x18\a \x01(\tR\n" +
+	"shareToken\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\b \x01(\x05R\tsortOrder\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\t \x01(\x05R\titemCount\x12+\n" +
+	"\x05items\x18\n" +
+	" \x03(\v2\x15.manga.CollectionItemR\x05items\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\tR\tupdatedAt\"1\n" +
+	"\x16ListCollectionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"d\n" +
+	"\x17ListCollectionsResponse\x123\n" +
+	"\vcollections\x18\x01 \x03(\v2\x11.manga.CollectionR\vcollections\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"T\n" +
+	"\x14GetCollectionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x02 \x01(\tR\fcollectionId\"]\n" +
+	"\x12CollectionResponse\x121\n" +
+	"\n" +
+	"collection\x18\x01 \x01(\v2\x11.manga.CollectionR\n" +
+	"collection\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x88\x01\n" +
+	"\x17CreateCollectionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x04 \x01(\tR\n" +
+	"visibility\"\xc2\x01\n" +
+	"\x17UpdateCollectionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x02 \x01(\tR\fcollectionId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12%\n" +
+	"\vdescription\x18\x04 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x05 \x01(\tR\n" +
+	"visibilityB\x0e\n" +
+	"\f_description\"W\n" +
+	"\x17DeleteCollectionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x02 \x01(\tR\fcollectionId\"d\n" +
+	"\x18DeleteCollectionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"p\n" +
+	"\x15CollectionItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x02 \x01(\tR\fcollectionId\x12\x19\n" +
+	"\bmanga_id\x18\x03 \x01(\tR\amangaId\"u\n" +
+	"\x18ReorderCollectionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcollection_id\x18\x02 \x01(\tR\fcollectionId\x12\x1b\n" +
+	"\tmanga_ids\x18\x03 \x03(\tR\bmangaIds\"=\n" +
+	"\x1aGetSharedCollectionRequest\x12\x1f\n" +
+	"\vshare_token\x18\x01 \x01(\tR\n" +
+	"shareToken\"G\n" +
+	"\x16RecommendationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"d\n" +
+	"\x0eRecommendation\x12\"\n" +
+	"\x05manga\x18\x01 \x01(\v2\f.manga.MangaR\x05manga\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x18\n" +
+	"\areasons\x18\x03 \x03(\tR\areasons\"p\n" +
+	"\x17RecommendationsResponse\x12?\n" +
+	"\x0frecommendations\x18\x01 \x03(\v2\x15.manga.RecommendationR\x0frecommendations\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xc7\x03\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x19\n" +
+	"\bmanga_id\x18\x04 \x01(\tR\amangaId\x12\x16\n" +
+	"\x06rating\x18\x05 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\a \x01(\tR\x04body\x12\x18\n" +
+	"\aspoiler\x18\b \x01(\bR\aspoiler\x12!\n" +
+	"\fchapter_read\x18\t \x01(\x05R\vchapterRead\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12#\n" +
+	"\rhelpful_count\x18\v \x01(\x05R\fhelpfulCount\x12'\n" +
+	"\x0funhelpful_count\x18\f \x01(\x05R\x0eunhelpfulCount\x12\x1d\n" +
+	"\n" +
+	"edit_count\x18\r \x01(\x05R\teditCount\x12\x1b\n" +
+	"\tuser_vote\x18\x0e \x01(\tR\buserVote\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x10 \x01(\tR\tupdatedAt\"\x8b\x01\n" +
+	"\x13MangaReviewsRequest\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"k\n" +
+	"\x14MangaReviewsResponse\x12'\n" +
+	"\areviews\x18\x01 \x03(\v2\r.manga.ReviewR\areviews\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x8c\x01\n" +
+	"\x12WriteReviewRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x18\n" +
+	"\aspoiler\x18\x05 \x01(\bR\aspoiler\"c\n" +
+	"\x11VoteReviewRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\treview_id\x18\x02 \x01(\tR\breviewId\x12\x18\n" +
+	"\ahelpful\x18\x03 \x01(\bR\ahelpful\"M\n" +
+	"\x0eReviewResponse\x12%\n" +
+	"\x06review\x18\x01 \x01(\v2\r.manga.ReviewR\x06review\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"I\n" +
+	"\x13DeleteReviewRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\"`\n" +
+	"\x14DeleteReviewResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"G\n" +
+	"\vRoleRequest\x12$\n" +
+	"\x0etarget_user_id\x18\x01 \x01(\tR\ftargetUserId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"S\n" +
+	"\fRoleResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error2\xd1\x12\n" +
 	"\fMangaService\x128\n" +
 	"\bGetManga\x12\x16.manga.GetMangaRequest\x1a\x14.manga.MangaResponse\x12:\n" +
 	"\vSearchManga\x12\x14.manga.SearchRequest\x1a\x15.manga.SearchResponse\x12A\n" +
@@ -2062,13 +5003,34 @@ const file_proto_manga_proto_rawDesc = "" +
 	"GetLibrary\x12\x15.manga.LibraryRequest\x1a\x16.manga.LibraryResponse\x12G\n" +
 	"\fAddToLibrary\x12\x1a.manga.AddToLibraryRequest\x1a\x1b.manga.AddToLibraryResponse\x12V\n" +
 	"\x11RemoveFromLibrary\x12\x1f.manga.RemoveFromLibraryRequest\x1a .manga.RemoveFromLibraryResponse\x12J\n" +
-	"\x0fGetLibraryStats\x12\x1a.manga.LibraryStatsRequest\x1a\x1b.manga.LibraryStatsResponse\x128\n" +
+	"\x0fGetLibraryStats\x12\x1a.manga.LibraryStatsRequest\x1a\x1b.manga.LibraryStatsResponse\x12P\n" +
+	"\x11GetLibraryChanges\x12\x1c.manga.LibraryChangesRequest\x1a\x1d.manga.LibraryChangesResponse\x12I\n" +
+	"\x10ApplyProgressOps\x12\x19.manga.ProgressOpsRequest\x1a\x1a.manga.ProgressOpsResponse\x12Y\n" +
+	"\x14GetProgressConflicts\x12\x1f.manga.ProgressConflictsRequest\x1a .manga.ProgressConflictsResponse\x128\n" +
 	"\tRateManga\x12\x14.manga.RatingRequest\x1a\x15.manga.RatingResponse\x12H\n" +
 	"\x0fGetMangaRatings\x12\x19.manga.MangaRatingRequest\x1a\x1a.manga.MangaRatingResponse\x12G\n" +
 	"\fDeleteRating\x12\x1a.manga.DeleteRatingRequest\x1a\x1b.manga.DeleteRatingResponse\x12J\n" +
 	"\x0eGetUserProfile\x12\x1c.manga.GetUserProfileRequest\x1a\x1a.manga.UserProfileResponse\x12V\n" +
 	"\x11UpdateUserProfile\x12\x1f.manga.UpdateUserProfileRequest\x1a .manga.UpdateUserProfileResponse\x12M\n" +
-	"\x0eChangePassword\x12\x1c.manga.ChangePasswordRequest\x1a\x1d.manga.ChangePasswordResponseB\x16Z\x14mangahub/proto/mangab\x06proto3"
+	"\x0eChangePassword\x12\x1c.manga.ChangePasswordRequest\x1a\x1d.manga.ChangePasswordResponse\x12P\n" +
+	"\x0fListCollections\x12\x1d.manga.ListCollectionsRequest\x1a\x1e.manga.ListCollectionsResponse\x12G\n" +
+	"\rGetCollection\x12\x1b.manga.GetCollectionRequest\x1a\x19.manga.CollectionResponse\x12M\n" +
+	"\x10CreateCollection\x12\x1e.manga.CreateCollectionRequest\x1a\x19.manga.CollectionResponse\x12M\n" +
+	"\x10UpdateCollection\x12\x1e.manga.UpdateCollectionRequest\x1a\x19.manga.CollectionResponse\x12S\n" +
+	"\x10DeleteCollection\x12\x1e.manga.DeleteCollectionRequest\x1a\x1f.manga.DeleteCollectionResponse\x12J\n" +
+	"\x0fAddToCollection\x12\x1c.manga.CollectionItemRequest\x1a\x19.manga.CollectionResponse\x12O\n" +
+	"\x14RemoveFromCollection\x12\x1c.manga.CollectionItemRequest\x1a\x19.manga.CollectionResponse\x12O\n" +
+	"\x11ReorderCollection\x12\x1f.manga.ReorderCollectionRequest\x1a\x19.manga.CollectionResponse\x12S\n" +
+	"\x13GetSharedCollection\x12!.manga.GetSharedCollectionRequest\x1a\x19.manga.CollectionResponse\x12S\n" +
+	"\x12GetRecommendations\x12\x1d.manga.RecommendationsRequest\x1a\x1e.manga.RecommendationsResponse\x12J\n" +
+	"\x0fGetMangaReviews\x12\x1a.manga.MangaReviewsRequest\x1a\x1b.manga.MangaReviewsResponse\x12?\n" +
+	"\vWriteReview\x12\x19.manga.WriteReviewRequest\x1a\x15.manga.ReviewResponse\x12=\n" +
+	"\n" +
+	"VoteReview\x12\x18.manga.VoteReviewRequest\x1a\x15.manga.ReviewResponse\x12G\n" +
+	"\fDeleteReview\x12\x1a.manga.DeleteReviewRequest\x1a\x1b.manga.DeleteReviewResponse\x124\n" +
+	"\tGrantRole\x12\x12.manga.RoleRequest\x1a\x13.manga.RoleResponse\x125\n" +
+	"\n" +
+	"RevokeRole\x12\x12.manga.RoleRequest\x1a\x13.manga.RoleResponseB\x16Z\x14mangahub/proto/mangab\x06proto3"
 
 var (
 	file_proto_manga_proto_rawDescOnce sync.Once
@@ -2082,38 +5044,76 @@ func file_proto_manga_proto_rawDescGZIP() []byte {
 	return file_proto_manga_proto_rawDescData
 }
 
-var file_proto_manga_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_proto_manga_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
 var file_proto_manga_proto_goTypes = []any{
-	(*GetMangaRequest)(nil),           // 0: manga.GetMangaRequest
-	(*MangaResponse)(nil),             // 1: manga.MangaResponse
-	(*SearchRequest)(nil),             // 2: manga.SearchRequest
-	(*SearchResponse)(nil),            // 3: manga.SearchResponse
-	(*ProgressRequest)(nil),           // 4: manga.ProgressRequest
-	(*ProgressResponse)(nil),          // 5: manga.ProgressResponse
-	(*Manga)(nil),                     // 6: manga.Manga
-	(*LibraryRequest)(nil),            // 7: manga.LibraryRequest
-	(*UserProgress)(nil),              // 8: manga.UserProgress
-	(*LibraryResponse)(nil),           // 9: manga.LibraryResponse
-	(*AddToLibraryRequest)(nil),       // 10: manga.AddToLibraryRequest
-	(*AddToLibraryResponse)(nil),      // 11: manga.AddToLibraryResponse
-	(*RemoveFromLibraryRequest)(nil),  // 12: manga.RemoveFromLibraryRequest
-	(*RemoveFromLibraryResponse)(nil), // 13: manga.RemoveFromLibraryResponse
-	(*LibraryStatsRequest)(nil),       // 14: manga.LibraryStatsRequest
-	(*LibraryStatsResponse)(nil),      // 15: manga.LibraryStatsResponse
-	(*RatingRequest)(nil),             // 16: manga.RatingRequest
-	(*RatingResponse)(nil),            // 17: manga.RatingResponse
-	(*MangaRatingRequest)(nil),        // 18: manga.MangaRatingRequest
-	(*MangaRatingResponse)(nil),       // 19: manga.MangaRatingResponse
-	(*DeleteRatingRequest)(nil),       // 20: manga.DeleteRatingRequest
-	(*DeleteRatingResponse)(nil),      // 21: manga.DeleteRatingResponse
-	(*GetUserProfileRequest)(nil),     // 22: manga.GetUserProfileRequest
-	(*UserProfile)(nil),               // 23: manga.UserProfile
-	(*UserProfileResponse)(nil),       // 24: manga.UserProfileResponse
-	(*UpdateUserProfileRequest)(nil),  // 25: manga.UpdateUserProfileRequest
-	(*UpdateUserProfileResponse)(nil), // 26: manga.UpdateUserProfileResponse
-	(*ChangePasswordRequest)(nil),     // 27: manga.ChangePasswordRequest
-	(*ChangePasswordResponse)(nil),    // 28: manga.ChangePasswordResponse
-	nil,                               // 29: manga.MangaRatingResponse.RatingDistributionEntry
+	(*GetMangaRequest)(nil),            // 0: manga.GetMangaRequest
+	(*MangaResponse)(nil),              // 1: manga.MangaResponse
+	(*SearchRequest)(nil),              // 2: manga.SearchRequest
+	(*SearchResponse)(nil),             // 3: manga.SearchResponse
+	(*ProgressRequest)(nil),            // 4: manga.ProgressRequest
+	(*ProgressResponse)(nil),           // 5: manga.ProgressResponse
+	(*Manga)(nil),                      // 6: manga.Manga
+	(*LibraryRequest)(nil),             // 7: manga.LibraryRequest
+	(*UserProgress)(nil),               // 8: manga.UserProgress
+	(*LibraryResponse)(nil),            // 9: manga.LibraryResponse
+	(*AddToLibraryRequest)(nil),        // 10: manga.AddToLibraryRequest
+	(*AddToLibraryResponse)(nil),       // 11: manga.AddToLibraryResponse
+	(*RemoveFromLibraryRequest)(nil),   // 12: manga.RemoveFromLibraryRequest
+	(*RemoveFromLibraryResponse)(nil),  // 13: manga.RemoveFromLibraryResponse
+	(*LibraryStatsRequest)(nil),        // 14: manga.LibraryStatsRequest
+	(*LibraryStatsResponse)(nil),       // 15: manga.LibraryStatsResponse
+	(*ProgressOp)(nil),                 // 16: manga.ProgressOp
+	(*ProgressOpsRequest)(nil),         // 17: manga.ProgressOpsRequest
+	(*ProgressOpResult)(nil),           // 18: manga.ProgressOpResult
+	(*ProgressOpsResponse)(nil),        // 19: manga.ProgressOpsResponse
+	(*ProgressConflictsRequest)(nil),   // 20: manga.ProgressConflictsRequest
+	(*ProgressConflict)(nil),           // 21: manga.ProgressConflict
+	(*ProgressConflictsResponse)(nil),  // 22: manga.ProgressConflictsResponse
+	(*LibraryChangesRequest)(nil),      // 23: manga.LibraryChangesRequest
+	(*UserRating)(nil),                 // 24: manga.UserRating
+	(*SyncTombstone)(nil),              // 25: manga.SyncTombstone
+	(*LibraryChangesResponse)(nil),     // 26: manga.LibraryChangesResponse
+	(*RatingRequest)(nil),              // 27: manga.RatingRequest
+	(*RatingResponse)(nil),             // 28: manga.RatingResponse
+	(*MangaRatingRequest)(nil),         // 29: manga.MangaRatingRequest
+	(*MangaRatingResponse)(nil),        // 30: manga.MangaRatingResponse
+	(*DeleteRatingRequest)(nil),        // 31: manga.DeleteRatingRequest
+	(*DeleteRatingResponse)(nil),       // 32: manga.DeleteRatingResponse
+	(*GetUserProfileRequest)(nil),      // 33: manga.GetUserProfileRequest
+	(*UserProfile)(nil),                // 34: manga.UserProfile
+	(*UserProfileResponse)(nil),        // 35: manga.UserProfileResponse
+	(*UpdateUserProfileRequest)(nil),   // 36: manga.UpdateUserProfileRequest
+	(*UpdateUserProfileResponse)(nil),  // 37: manga.UpdateUserProfileResponse
+	(*ChangePasswordRequest)(nil),      // 38: manga.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),     // 39: manga.ChangePasswordResponse
+	(*CollectionItem)(nil),             // 40: manga.CollectionItem
+	(*Collection)(nil),                 // 41: manga.Collection
+	(*ListCollectionsRequest)(nil),     // 42: manga.ListCollectionsRequest
+	(*ListCollectionsResponse)(nil),    // 43: manga.ListCollectionsResponse
+	(*GetCollectionRequest)(nil),       // 44: manga.GetCollectionRequest
+	(*CollectionResponse)(nil),         // 45: manga.CollectionResponse
+	(*CreateCollectionRequest)(nil),    // 46: manga.CreateCollectionRequest
+	(*UpdateCollectionRequest)(nil),    // 47: manga.UpdateCollectionRequest
+	(*DeleteCollectionRequest)(nil),    // 48: manga.DeleteCollectionRequest
+	(*DeleteCollectionResponse)(nil),   // 49: manga.DeleteCollectionResponse
+	(*CollectionItemRequest)(nil),      // 50: manga.CollectionItemRequest
+	(*ReorderCollectionRequest)(nil),   // 51: manga.ReorderCollectionRequest
+	(*GetSharedCollectionRequest)(nil), // 52: manga.GetSharedCollectionRequest
+	(*RecommendationsRequest)(nil),     // 53: manga.RecommendationsRequest
+	(*Recommendation)(nil),             // 54: manga.Recommendation
+	(*RecommendationsResponse)(nil),    // 55: manga.RecommendationsResponse
+	(*Review)(nil),                     // 56: manga.Review
+	(*MangaReviewsRequest)(nil),        // 57: manga.MangaReviewsRequest
+	(*MangaReviewsResponse)(nil),       // 58: manga.MangaReviewsResponse
+	(*WriteReviewRequest)(nil),         // 59: manga.WriteReviewRequest
+	(*VoteReviewRequest)(nil),          // 60: manga.VoteReviewRequest
+	(*ReviewResponse)(nil),             // 61: manga.ReviewResponse
+	(*DeleteReviewRequest)(nil),        // 62: manga.DeleteReviewRequest
+	(*DeleteReviewResponse)(nil),       // 63: manga.DeleteReviewResponse
+	(*RoleRequest)(nil),                // 64: manga.RoleRequest
+	(*RoleResponse)(nil),               // 65: manga.RoleResponse
+	nil,                                // 66: manga.MangaRatingResponse.RatingDistributionEntry
+	nil,                                // 67: manga.MangaRatingResponse.SubScoresEntry
 }
 var file_proto_manga_proto_depIdxs = []int32{
 	6,  // 0: manga.MangaResponse.manga:type_name -> manga.Manga
@@ -2124,40 +5124,94 @@ var file_proto_manga_proto_depIdxs = []int32{
 	8,  // 5: manga.LibraryResponse.dropped:type_name -> manga.UserProgress
 	8,  // 6: manga.LibraryResponse.on_hold:type_name -> manga.UserProgress
 	8,  // 7: manga.LibraryResponse.re_reading:type_name -> manga.UserProgress
-	29, // 8: manga.MangaRatingResponse.rating_distribution:type_name -> manga.MangaRatingResponse.RatingDistributionEntry
-	23, // 9: manga.UserProfileResponse.profile:type_name -> manga.UserProfile
-	23, // 10: manga.UpdateUserProfileResponse.profile:type_name -> manga.UserProfile
-	0,  // 11: manga.MangaService.GetManga:input_type -> manga.GetMangaRequest
-	2,  // 12: manga.MangaService.SearchManga:input_type -> manga.SearchRequest
-	4,  // 13: manga.MangaService.UpdateProgress:input_type -> manga.ProgressRequest
-	7,  // 14: manga.MangaService.GetLibrary:input_type -> manga.LibraryRequest
-	10, // 15: manga.MangaService.AddToLibrary:input_type -> manga.AddToLibraryRequest
-	12, // 16: manga.MangaService.RemoveFromLibrary:input_type -> manga.RemoveFromLibraryRequest
-	14, // 17: manga.MangaService.GetLibraryStats:input_type -> manga.LibraryStatsRequest
-	16, // 18: manga.MangaService.RateManga:input_type -> manga.RatingRequest
-	18, // 19: manga.MangaService.GetMangaRatings:input_type -> manga.MangaRatingRequest
-	20, // 20: manga.MangaService.DeleteRating:input_type -> manga.DeleteRatingRequest
-	22, // 21: manga.MangaService.GetUserProfile:input_type -> manga.GetUserProfileRequest
-	25, // 22: manga.MangaService.UpdateUserProfile:input_type -> manga.UpdateUserProfileRequest
-	27, // 23: manga.MangaService.ChangePassword:input_type -> manga.ChangePasswordRequest
-	1,  // 24: manga.MangaService.GetManga:output_type -> manga.MangaResponse
-	3,  // 25: manga.MangaService.SearchManga:output_type -> manga.SearchResponse
-	5,  // 26: manga.MangaService.UpdateProgress:output_type -> manga.ProgressResponse
-	9,  // 27: manga.MangaService.GetLibrary:output_type -> manga.LibraryResponse
-	11, // 28: manga.MangaService.AddToLibrary:output_type -> manga.AddToLibraryResponse
-	13, // 29: manga.MangaService.RemoveFromLibrary:output_type -> manga.RemoveFromLibraryResponse
-	15, // 30: manga.MangaService.GetLibraryStats:output_type -> manga.LibraryStatsResponse
-	17, // 31: manga.MangaService.RateManga:output_type -> manga.RatingResponse
-	19, // 32: manga.MangaService.GetMangaRatings:output_type -> manga.MangaRatingResponse
-	21, // 33: manga.MangaService.DeleteRating:output_type -> manga.DeleteRatingResponse
-	24, // 34: manga.MangaService.GetUserProfile:output_type -> manga.UserProfileResponse
-	26, // 35: manga.MangaService.UpdateUserProfile:output_type -> manga.UpdateUserProfileResponse
-	28, // 36: manga.MangaService.ChangePassword:output_type -> manga.ChangePasswordResponse
-	24, // [24:37] is the sub-list for method output_type
-	11, // [11:24] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	16, // 8: manga.ProgressOpsRequest.ops:type_name -> manga.ProgressOp
+	8,  // 9: manga.ProgressOpResult.entry:type_name -> manga.UserProgress
+	18, // 10: manga.ProgressOpsResponse.results:type_name -> manga.ProgressOpResult
+	21, // 11: manga.ProgressConflictsResponse.conflicts:type_name -> manga.ProgressConflict
+	8,  // 12: manga.LibraryChangesResponse.library:type_name -> manga.UserProgress
+	24, // 13: manga.LibraryChangesResponse.ratings:type_name -> manga.UserRating
+	41, // 14: manga.LibraryChangesResponse.collections:type_name -> manga.Collection
+	25, // 15: manga.LibraryChangesResponse.deleted:type_name -> manga.SyncTombstone
+	66, // 16: manga.MangaRatingResponse.rating_distribution:type_name -> manga.MangaRatingResponse.RatingDistributionEntry
+	67, // 17: manga.MangaRatingResponse.sub_scores:type_name -> manga.MangaRatingResponse.SubScoresEntry
+	34, // 18: manga.UserProfileResponse.profile:type_name -> manga.UserProfile
+	34, // 19: manga.UpdateUserProfileResponse.profile:type_name -> manga.UserProfile
+	40, // 20: manga.Collection.items:type_name -> manga.CollectionItem
+	41, // 21: manga.ListCollectionsResponse.collections:type_name -> manga.Collection
+	41, // 22: manga.CollectionResponse.collection:type_name -> manga.Collection
+	6,  // 23: manga.Recommendation.manga:type_name -> manga.Manga
+	54, // 24: manga.RecommendationsResponse.recommendations:type_name -> manga.Recommendation
+	56, // 25: manga.MangaReviewsResponse.reviews:type_name -> manga.Review
+	56, // 26: manga.ReviewResponse.review:type_name -> manga.Review
+	0,  // 27: manga.MangaService.GetManga:input_type -> manga.GetMangaRequest
+	2,  // 28: manga.MangaService.SearchManga:input_type -> manga.SearchRequest
+	4,  // 29: manga.MangaService.UpdateProgress:input_type -> manga.ProgressRequest
+	7,  // 30: manga.MangaService.GetLibrary:input_type -> manga.LibraryRequest
+	10, // 31: manga.MangaService.AddToLibrary:input_type -> manga.AddToLibraryRequest
+	12, // 32: manga.MangaService.RemoveFromLibrary:input_type -> manga.RemoveFromLibraryRequest
+	14, // 33: manga.MangaService.GetLibraryStats:input_type -> manga.LibraryStatsRequest
+	23, // 34: manga.MangaService.GetLibraryChanges:input_type -> manga.LibraryChangesRequest
+	17, // 35: manga.MangaService.ApplyProgressOps:input_type -> manga.ProgressOpsRequest
+	20, // 36: manga.MangaService.GetProgressConflicts:input_type -> manga.ProgressConflictsRequest
+	27, // 37: manga.MangaService.RateManga:input_type -> manga.RatingRequest
+	29, // 38: manga.MangaService.GetMangaRatings:input_type -> manga.MangaRatingRequest
+	31, // 39: manga.MangaService.DeleteRating:input_type -> manga.DeleteRatingRequest
+	33, // 40: manga.MangaService.GetUserProfile:input_type -> manga.GetUserProfileRequest
+	36, // 41: manga.MangaService.UpdateUserProfile:input_type -> manga.UpdateUserProfileRequest
+	38, // 42: manga.MangaService.ChangePassword:input_type -> manga.ChangePasswordRequest
+	42, // 43: manga.MangaService.ListCollections:input_type -> manga.ListCollectionsRequest
+	44, // 44: manga.MangaService.GetCollection:input_type -> manga.GetCollectionRequest
+	46, // 45: manga.MangaService.CreateCollection:input_type -> manga.CreateCollectionRequest
+	47, // 46: manga.MangaService.UpdateCollection:input_type -> manga.UpdateCollectionRequest
+	48, // 47: manga.MangaService.DeleteCollection:input_type -> manga.DeleteCollectionRequest
+	50, // 48: manga.MangaService.AddToCollection:input_type -> manga.CollectionItemRequest
+	50, // 49: manga.MangaService.RemoveFromCollection:input_type -> manga.CollectionItemRequest
+	51, // 50: manga.MangaService.ReorderCollection:input_type -> manga.ReorderCollectionRequest
+	52, // 51: manga.MangaService.GetSharedCollection:input_type -> manga.GetSharedCollectionRequest
+	53, // 52: manga.MangaService.GetRecommendations:input_type -> manga.RecommendationsRequest
+	57, // 53: manga.MangaService.GetMangaReviews:input_type -> manga.MangaReviewsRequest
+	59, // 54: manga.MangaService.WriteReview:input_type -> manga.WriteReviewRequest
+	60, // 55: manga.MangaService.VoteReview:input_type -> manga.VoteReviewRequest
+	62, // 56: manga.MangaService.DeleteReview:input_type -> manga.DeleteReviewRequest
+	64, // 57: manga.MangaService.GrantRole:input_type -> manga.RoleRequest
+	64, // 58: manga.MangaService.RevokeRole:input_type -> manga.RoleRequest
+	1,  // 59: manga.MangaService.GetManga:output_type -> manga.MangaResponse
+	3,  // 60: manga.MangaService.SearchManga:output_type -> manga.SearchResponse
+	5,  // 61: manga.MangaService.UpdateProgress:output_type -> manga.ProgressResponse
+	9,  // 62: manga.MangaService.GetLibrary:output_type -> manga.LibraryResponse
+	11, // 63: manga.MangaService.AddToLibrary:output_type -> manga.AddToLibraryResponse
+	13, // 64: manga.MangaService.RemoveFromLibrary:output_type -> manga.RemoveFromLibraryResponse
+	15, // 65: manga.MangaService.GetLibraryStats:output_type -> manga.LibraryStatsResponse
+	26, // 66: manga.MangaService.GetLibraryChanges:output_type -> manga.LibraryChangesResponse
+	19, // 67: manga.MangaService.ApplyProgressOps:output_type -> manga.ProgressOpsResponse
+	22, // 68: manga.MangaService.GetProgressConflicts:output_type -> manga.ProgressConflictsResponse
+	28, // 69: manga.MangaService.RateManga:output_type -> manga.RatingResponse
+	30, // 70: manga.MangaService.GetMangaRatings:output_type -> manga.MangaRatingResponse
+	32, // 71: manga.MangaService.DeleteRating:output_type -> manga.DeleteRatingResponse
+	35, // 72: manga.MangaService.GetUserProfile:output_type -> manga.UserProfileResponse
+	37, // 73: manga.MangaService.UpdateUserProfile:output_type -> manga.UpdateUserProfileResponse
+	39, // 74: manga.MangaService.ChangePassword:output_type -> manga.ChangePasswordResponse
+	43, // 75: manga.MangaService.ListCollections:output_type -> manga.ListCollectionsResponse
+	45, // 76: manga.MangaService.GetCollection:output_type -> manga.CollectionResponse
+	45, // 77: manga.MangaService.CreateCollection:output_type -> manga.CollectionResponse
+	45, // 78: manga.MangaService.UpdateCollection:output_type -> manga.CollectionResponse
+	49, // 79: manga.MangaService.DeleteCollection:output_type -> manga.DeleteCollectionResponse
+	45, // 80: manga.MangaService.AddToCollection:output_type -> manga.CollectionResponse
+	45, // 81: manga.MangaService.RemoveFromCollection:output_type -> manga.CollectionResponse
+	45, // 82: manga.MangaService.ReorderCollection:output_type -> manga.CollectionResponse
+	45, // 83: manga.MangaService.GetSharedCollection:output_type -> manga.CollectionResponse
+	55, // 84: manga.MangaService.GetRecommendations:output_type -> manga.RecommendationsResponse
+	58, // 85: manga.MangaService.GetMangaReviews:output_type -> manga.MangaReviewsResponse
+	61, // 86: manga.MangaService.WriteReview:output_type -> manga.ReviewResponse
+	61, // 87: manga.MangaService.VoteReview:output_type -> manga.ReviewResponse
+	63, // 88: manga.MangaService.DeleteReview:output_type -> manga.DeleteReviewResponse
+	65, // 89: manga.MangaService.GrantRole:output_type -> manga.RoleResponse
+	65, // 90: manga.MangaService.RevokeRole:output_type -> manga.RoleResponse
+	59, // [59:91] is the sub-list for method output_type
+	27, // [27:59] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_proto_manga_proto_init() }
@@ -2165,13 +5219,16 @@ func file_proto_manga_proto_init() {
 	if File_proto_manga_proto != nil {
 		return
 	}
+	file_proto_manga_proto_msgTypes[16].OneofWrappers = []any{}
+	file_proto_manga_proto_msgTypes[21].OneofWrappers = []any{}
+	file_proto_manga_proto_msgTypes[47].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_manga_proto_rawDesc), len(file_proto_manga_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   30,
+			NumMessages:   68,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
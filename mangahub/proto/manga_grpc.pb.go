@@ -19,19 +19,38 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MangaService_GetManga_FullMethodName          = "/manga.MangaService/GetManga"
-	MangaService_SearchManga_FullMethodName       = "/manga.MangaService/SearchManga"
-	MangaService_UpdateProgress_FullMethodName    = "/manga.MangaService/UpdateProgress"
-	MangaService_GetLibrary_FullMethodName        = "/manga.MangaService/GetLibrary"
-	MangaService_AddToLibrary_FullMethodName      = "/manga.MangaService/AddToLibrary"
-	MangaService_RemoveFromLibrary_FullMethodName = "/manga.MangaService/RemoveFromLibrary"
-	MangaService_GetLibraryStats_FullMethodName   = "/manga.MangaService/GetLibraryStats"
-	MangaService_RateManga_FullMethodName         = "/manga.MangaService/RateManga"
-	MangaService_GetMangaRatings_FullMethodName   = "/manga.MangaService/GetMangaRatings"
-	MangaService_DeleteRating_FullMethodName      = "/manga.MangaService/DeleteRating"
-	MangaService_GetUserProfile_FullMethodName    = "/manga.MangaService/GetUserProfile"
-	MangaService_UpdateUserProfile_FullMethodName = "/manga.MangaService/UpdateUserProfile"
-	MangaService_ChangePassword_FullMethodName    = "/manga.MangaService/ChangePassword"
+	MangaService_GetManga_FullMethodName             = "/manga.MangaService/GetManga"
+	MangaService_SearchManga_FullMethodName          = "/manga.MangaService/SearchManga"
+	MangaService_UpdateProgress_FullMethodName       = "/manga.MangaService/UpdateProgress"
+	MangaService_GetLibrary_FullMethodName           = "/manga.MangaService/GetLibrary"
+	MangaService_AddToLibrary_FullMethodName         = "/manga.MangaService/AddToLibrary"
+	MangaService_RemoveFromLibrary_FullMethodName    = "/manga.MangaService/RemoveFromLibrary"
+	MangaService_GetLibraryStats_FullMethodName      = "/manga.MangaService/GetLibraryStats"
+	MangaService_GetLibraryChanges_FullMethodName    = "/manga.MangaService/GetLibraryChanges"
+	MangaService_ApplyProgressOps_FullMethodName     = "/manga.MangaService/ApplyProgressOps"
+	MangaService_GetProgressConflicts_FullMethodName = "/manga.MangaService/GetProgressConflicts"
+	MangaService_RateManga_FullMethodName            = "/manga.MangaService/RateManga"
+	MangaService_GetMangaRatings_FullMethodName      = "/manga.MangaService/GetMangaRatings"
+	MangaService_DeleteRating_FullMethodName         = "/manga.MangaService/DeleteRating"
+	MangaService_GetUserProfile_FullMethodName       = "/manga.MangaService/GetUserProfile"
+	MangaService_UpdateUserProfile_FullMethodName    = "/manga.MangaService/UpdateUserProfile"
+	MangaService_ChangePassword_FullMethodName       = "/manga.MangaService/ChangePassword"
+	MangaService_ListCollections_FullMethodName      = "/manga.MangaService/ListCollections"
+	MangaService_GetCollection_FullMethodName        = "/manga.MangaService/GetCollection"
+	MangaService_CreateCollection_FullMethodName     = "/manga.MangaService/CreateCollection"
+	MangaService_UpdateCollection_FullMethodName     = "/manga.MangaService/UpdateCollection"
+	MangaService_DeleteCollection_FullMethodName     = "/manga.MangaService/DeleteCollection"
+	MangaService_AddToCollection_FullMethodName      = "/manga.MangaService/AddToCollection"
+	MangaService_RemoveFromCollection_FullMethodName = "/manga.MangaService/RemoveFromCollection"
+	MangaService_ReorderCollection_FullMethodName    = "/manga.MangaService/ReorderCollection"
+	MangaService_GetSharedCollection_FullMethodName  = "/manga.MangaService/GetSharedCollection"
+	MangaService_GetRecommendations_FullMethodName   = "/manga.MangaService/GetRecommendations"
+	MangaService_GetMangaReviews_FullMethodName      = "/manga.MangaService/GetMangaReviews"
+	MangaService_WriteReview_FullMethodName          = "/manga.MangaService/WriteReview"
+	MangaService_VoteReview_FullMethodName           = "/manga.MangaService/VoteReview"
+	MangaService_DeleteReview_FullMethodName         = "/manga.MangaService/DeleteReview"
+	MangaService_GrantRole_FullMethodName            = "/manga.MangaService/GrantRole"
+	MangaService_RevokeRole_FullMethodName           = "/manga.MangaService/RevokeRole"
 )
 
 // MangaServiceClient is the client API for MangaService service.
@@ -51,6 +70,9 @@ type MangaServiceClient interface {
 	AddToLibrary(ctx context.Context, in *AddToLibraryRequest, opts ...grpc.CallOption) (*AddToLibraryResponse, error)
 	RemoveFromLibrary(ctx context.Context, in *RemoveFromLibraryRequest, opts ...grpc.CallOption) (*RemoveFromLibraryResponse, error)
 	GetLibraryStats(ctx context.Context, in *LibraryStatsRequest, opts ...grpc.CallOption) (*LibraryStatsResponse, error)
+	GetLibraryChanges(ctx context.Context, in *LibraryChangesRequest, opts ...grpc.CallOption) (*LibraryChangesResponse, error)
+	ApplyProgressOps(ctx context.Context, in *ProgressOpsRequest, opts ...grpc.CallOption) (*ProgressOpsResponse, error)
+	GetProgressConflicts(ctx context.Context, in *ProgressConflictsRequest, opts ...grpc.CallOption) (*ProgressConflictsResponse, error)
 	// Rating System
 	RateManga(ctx context.Context, in *RatingRequest, opts ...grpc.CallOption) (*RatingResponse, error)
 	GetMangaRatings(ctx context.Context, in *MangaRatingRequest, opts ...grpc.CallOption) (*MangaRatingResponse, error)
@@ -59,6 +81,26 @@ type MangaServiceClient interface {
 	GetUserProfile(ctx context.Context, in *GetUserProfileRequest, opts ...grpc.CallOption) (*UserProfileResponse, error)
 	UpdateUserProfile(ctx context.Context, in *UpdateUserProfileRequest, opts ...grpc.CallOption) (*UpdateUserProfileResponse, error)
 	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
+	// Collections
+	ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error)
+	GetCollection(ctx context.Context, in *GetCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	UpdateCollection(ctx context.Context, in *UpdateCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	DeleteCollection(ctx context.Context, in *DeleteCollectionRequest, opts ...grpc.CallOption) (*DeleteCollectionResponse, error)
+	AddToCollection(ctx context.Context, in *CollectionItemRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	RemoveFromCollection(ctx context.Context, in *CollectionItemRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	ReorderCollection(ctx context.Context, in *ReorderCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	GetSharedCollection(ctx context.Context, in *GetSharedCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Recommendations
+	GetRecommendations(ctx context.Context, in *RecommendationsRequest, opts ...grpc.CallOption) (*RecommendationsResponse, error)
+	// Reviews (moderation is REST-only, for moderators)
+	GetMangaReviews(ctx context.Context, in *MangaReviewsRequest, opts ...grpc.CallOption) (*MangaReviewsResponse, error)
+	WriteReview(ctx context.Context, in *WriteReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*DeleteReviewResponse, error)
+	// Roles (admin only: the caller's token must grant roles:manage)
+	GrantRole(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*RoleResponse, error)
+	RevokeRole(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*RoleResponse, error)
 }
 
 type mangaServiceClient struct {
@@ -139,6 +181,36 @@ func (c *mangaServiceClient) GetLibraryStats(ctx context.Context, in *LibrarySta
 	return out, nil
 }
 
+func (c *mangaServiceClient) GetLibraryChanges(ctx context.Context, in *LibraryChangesRequest, opts ...grpc.CallOption) (*LibraryChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LibraryChangesResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetLibraryChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) ApplyProgressOps(ctx context.Context, in *ProgressOpsRequest, opts ...grpc.CallOption) (*ProgressOpsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProgressOpsResponse)
+	err := c.cc.Invoke(ctx, MangaService_ApplyProgressOps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GetProgressConflicts(ctx context.Context, in *ProgressConflictsRequest, opts ...grpc.CallOption) (*ProgressConflictsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProgressConflictsResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetProgressConflicts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mangaServiceClient) RateManga(ctx context.Context, in *RatingRequest, opts ...grpc.CallOption) (*RatingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RatingResponse)
@@ -199,6 +271,166 @@ func (c *mangaServiceClient) ChangePassword(ctx context.Context, in *ChangePassw
 	return out, nil
 }
 
+func (c *mangaServiceClient) ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCollectionsResponse)
+	err := c.cc.Invoke(ctx, MangaService_ListCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GetCollection(ctx context.Context, in *GetCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_CreateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) UpdateCollection(ctx context.Context, in *UpdateCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_UpdateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) DeleteCollection(ctx context.Context, in *DeleteCollectionRequest, opts ...grpc.CallOption) (*DeleteCollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_DeleteCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) AddToCollection(ctx context.Context, in *CollectionItemRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_AddToCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) RemoveFromCollection(ctx context.Context, in *CollectionItemRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_RemoveFromCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) ReorderCollection(ctx context.Context, in *ReorderCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_ReorderCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GetSharedCollection(ctx context.Context, in *GetSharedCollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetSharedCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GetRecommendations(ctx context.Context, in *RecommendationsRequest, opts ...grpc.CallOption) (*RecommendationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendationsResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetRecommendations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GetMangaReviews(ctx context.Context, in *MangaReviewsRequest, opts ...grpc.CallOption) (*MangaReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MangaReviewsResponse)
+	err := c.cc.Invoke(ctx, MangaService_GetMangaReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) WriteReview(ctx context.Context, in *WriteReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, MangaService_WriteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, MangaService_VoteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*DeleteReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteReviewResponse)
+	err := c.cc.Invoke(ctx, MangaService_DeleteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) GrantRole(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*RoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoleResponse)
+	err := c.cc.Invoke(ctx, MangaService_GrantRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) RevokeRole(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*RoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoleResponse)
+	err := c.cc.Invoke(ctx, MangaService_RevokeRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MangaServiceServer is the server API for MangaService service.
 // All implementations must embed UnimplementedMangaServiceServer
 // for forward compatibility.
@@ -216,6 +448,9 @@ type MangaServiceServer interface {
 	AddToLibrary(context.Context, *AddToLibraryRequest) (*AddToLibraryResponse, error)
 	RemoveFromLibrary(context.Context, *RemoveFromLibraryRequest) (*RemoveFromLibraryResponse, error)
 	GetLibraryStats(context.Context, *LibraryStatsRequest) (*LibraryStatsResponse, error)
+	GetLibraryChanges(context.Context, *LibraryChangesRequest) (*LibraryChangesResponse, error)
+	ApplyProgressOps(context.Context, *ProgressOpsRequest) (*ProgressOpsResponse, error)
+	GetProgressConflicts(context.Context, *ProgressConflictsRequest) (*ProgressConflictsResponse, error)
 	// Rating System
 	RateManga(context.Context, *RatingRequest) (*RatingResponse, error)
 	GetMangaRatings(context.Context, *MangaRatingRequest) (*MangaRatingResponse, error)
@@ -224,6 +459,26 @@ type MangaServiceServer interface {
 	GetUserProfile(context.Context, *GetUserProfileRequest) (*UserProfileResponse, error)
 	UpdateUserProfile(context.Context, *UpdateUserProfileRequest) (*UpdateUserProfileResponse, error)
 	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	// Collections
+	ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error)
+	GetCollection(context.Context, *GetCollectionRequest) (*CollectionResponse, error)
+	CreateCollection(context.Context, *CreateCollectionRequest) (*CollectionResponse, error)
+	UpdateCollection(context.Context, *UpdateCollectionRequest) (*CollectionResponse, error)
+	DeleteCollection(context.Context, *DeleteCollectionRequest) (*DeleteCollectionResponse, error)
+	AddToCollection(context.Context, *CollectionItemRequest) (*CollectionResponse, error)
+	RemoveFromCollection(context.Context, *CollectionItemRequest) (*CollectionResponse, error)
+	ReorderCollection(context.Context, *ReorderCollectionRequest) (*CollectionResponse, error)
+	GetSharedCollection(context.Context, *GetSharedCollectionRequest) (*CollectionResponse, error)
+	// Recommendations
+	GetRecommendations(context.Context, *RecommendationsRequest) (*RecommendationsResponse, error)
+	// Reviews (moderation is REST-only, for moderators)
+	GetMangaReviews(context.Context, *MangaReviewsRequest) (*MangaReviewsResponse, error)
+	WriteReview(context.Context, *WriteReviewRequest) (*ReviewResponse, error)
+	VoteReview(context.Context, *VoteReviewRequest) (*ReviewResponse, error)
+	DeleteReview(context.Context, *DeleteReviewRequest) (*DeleteReviewResponse, error)
+	// Roles (admin only: the caller's token must grant roles:manage)
+	GrantRole(context.Context, *RoleRequest) (*RoleResponse, error)
+	RevokeRole(context.Context, *RoleRequest) (*RoleResponse, error)
 	mustEmbedUnimplementedMangaServiceServer()
 }
 
@@ -255,6 +510,15 @@ func (UnimplementedMangaServiceServer) RemoveFromLibrary(context.Context, *Remov
 func (UnimplementedMangaServiceServer) GetLibraryStats(context.Context, *LibraryStatsRequest) (*LibraryStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLibraryStats not implemented")
 }
+func (UnimplementedMangaServiceServer) GetLibraryChanges(context.Context, *LibraryChangesRequest) (*LibraryChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLibraryChanges not implemented")
+}
+func (UnimplementedMangaServiceServer) ApplyProgressOps(context.Context, *ProgressOpsRequest) (*ProgressOpsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyProgressOps not implemented")
+}
+func (UnimplementedMangaServiceServer) GetProgressConflicts(context.Context, *ProgressConflictsRequest) (*ProgressConflictsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProgressConflicts not implemented")
+}
 func (UnimplementedMangaServiceServer) RateManga(context.Context, *RatingRequest) (*RatingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RateManga not implemented")
 }
@@ -273,6 +537,54 @@ func (UnimplementedMangaServiceServer) UpdateUserProfile(context.Context, *Updat
 func (UnimplementedMangaServiceServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
 }
+func (UnimplementedMangaServiceServer) ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollections not implemented")
+}
+func (UnimplementedMangaServiceServer) GetCollection(context.Context, *GetCollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) CreateCollection(context.Context, *CreateCollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) UpdateCollection(context.Context, *UpdateCollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) DeleteCollection(context.Context, *DeleteCollectionRequest) (*DeleteCollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) AddToCollection(context.Context, *CollectionItemRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddToCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) RemoveFromCollection(context.Context, *CollectionItemRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFromCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) ReorderCollection(context.Context, *ReorderCollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) GetSharedCollection(context.Context, *GetSharedCollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharedCollection not implemented")
+}
+func (UnimplementedMangaServiceServer) GetRecommendations(context.Context, *RecommendationsRequest) (*RecommendationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendations not implemented")
+}
+func (UnimplementedMangaServiceServer) GetMangaReviews(context.Context, *MangaReviewsRequest) (*MangaReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMangaReviews not implemented")
+}
+func (UnimplementedMangaServiceServer) WriteReview(context.Context, *WriteReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteReview not implemented")
+}
+func (UnimplementedMangaServiceServer) VoteReview(context.Context, *VoteReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReview not implemented")
+}
+func (UnimplementedMangaServiceServer) DeleteReview(context.Context, *DeleteReviewRequest) (*DeleteReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedMangaServiceServer) GrantRole(context.Context, *RoleRequest) (*RoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantRole not implemented")
+}
+func (UnimplementedMangaServiceServer) RevokeRole(context.Context, *RoleRequest) (*RoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
 func (UnimplementedMangaServiceServer) mustEmbedUnimplementedMangaServiceServer() {}
 func (UnimplementedMangaServiceServer) testEmbeddedByValue()                      {}
 
@@ -420,6 +732,60 @@ func _MangaService_GetLibraryStats_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MangaService_GetLibraryChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LibraryChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetLibraryChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetLibraryChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetLibraryChanges(ctx, req.(*LibraryChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_ApplyProgressOps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProgressOpsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).ApplyProgressOps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_ApplyProgressOps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).ApplyProgressOps(ctx, req.(*ProgressOpsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GetProgressConflicts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProgressConflictsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetProgressConflicts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetProgressConflicts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetProgressConflicts(ctx, req.(*ProgressConflictsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MangaService_RateManga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RatingRequest)
 	if err := dec(in); err != nil {
@@ -528,6 +894,294 @@ func _MangaService_ChangePassword_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MangaService_ListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).ListCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_ListCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).ListCollections(ctx, req.(*ListCollectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GetCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetCollection(ctx, req.(*GetCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).CreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_CreateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).CreateCollection(ctx, req.(*CreateCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_UpdateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).UpdateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_UpdateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).UpdateCollection(ctx, req.(*UpdateCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_DeleteCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).DeleteCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_DeleteCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).DeleteCollection(ctx, req.(*DeleteCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_AddToCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).AddToCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_AddToCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).AddToCollection(ctx, req.(*CollectionItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_RemoveFromCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).RemoveFromCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_RemoveFromCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).RemoveFromCollection(ctx, req.(*CollectionItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_ReorderCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).ReorderCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_ReorderCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).ReorderCollection(ctx, req.(*ReorderCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GetSharedCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSharedCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetSharedCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetSharedCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetSharedCollection(ctx, req.(*GetSharedCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GetRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecommendationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetRecommendations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetRecommendations(ctx, req.(*RecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GetMangaReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MangaReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GetMangaReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GetMangaReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GetMangaReviews(ctx, req.(*MangaReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_WriteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).WriteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_WriteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).WriteReview(ctx, req.(*WriteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_VoteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).VoteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_VoteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).VoteReview(ctx, req.(*VoteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).DeleteReview(ctx, req.(*DeleteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_GrantRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).GrantRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_GrantRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).GrantRole(ctx, req.(*RoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_RevokeRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).RevokeRole(ctx, req.(*RoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MangaService_ServiceDesc is the grpc.ServiceDesc for MangaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -563,6 +1217,18 @@ var MangaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLibraryStats",
 			Handler:    _MangaService_GetLibraryStats_Handler,
 		},
+		{
+			MethodName: "GetLibraryChanges",
+			Handler:    _MangaService_GetLibraryChanges_Handler,
+		},
+		{
+			MethodName: "ApplyProgressOps",
+			Handler:    _MangaService_ApplyProgressOps_Handler,
+		},
+		{
+			MethodName: "GetProgressConflicts",
+			Handler:    _MangaService_GetProgressConflicts_Handler,
+		},
 		{
 			MethodName: "RateManga",
 			Handler:    _MangaService_RateManga_Handler,
@@ -587,6 +1253,70 @@ var MangaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ChangePassword",
 			Handler:    _MangaService_ChangePassword_Handler,
 		},
+		{
+			MethodName: "ListCollections",
+			Handler:    _MangaService_ListCollections_Handler,
+		},
+		{
+			MethodName: "GetCollection",
+			Handler:    _MangaService_GetCollection_Handler,
+		},
+		{
+			MethodName: "CreateCollection",
+			Handler:    _MangaService_CreateCollection_Handler,
+		},
+		{
+			MethodName: "UpdateCollection",
+			Handler:    _MangaService_UpdateCollection_Handler,
+		},
+		{
+			MethodName: "DeleteCollection",
+			Handler:    _MangaService_DeleteCollection_Handler,
+		},
+		{
+			MethodName: "AddToCollection",
+			Handler:    _MangaService_AddToCollection_Handler,
+		},
+		{
+			MethodName: "RemoveFromCollection",
+			Handler:    _MangaService_RemoveFromCollection_Handler,
+		},
+		{
+			MethodName: "ReorderCollection",
+			Handler:    _MangaService_ReorderCollection_Handler,
+		},
+		{
+			MethodName: "GetSharedCollection",
+			Handler:    _MangaService_GetSharedCollection_Handler,
+		},
+		{
+			MethodName: "GetRecommendations",
+			Handler:    _MangaService_GetRecommendations_Handler,
+		},
+		{
+			MethodName: "GetMangaReviews",
+			Handler:    _MangaService_GetMangaReviews_Handler,
+		},
+		{
+			MethodName: "WriteReview",
+			Handler:    _MangaService_WriteReview_Handler,
+		},
+		{
+			MethodName: "VoteReview",
+			Handler:    _MangaService_VoteReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _MangaService_DeleteReview_Handler,
+		},
+		{
+			MethodName: "GrantRole",
+			Handler:    _MangaService_GrantRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _MangaService_RevokeRole_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/manga.proto",